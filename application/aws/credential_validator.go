@@ -17,15 +17,21 @@ func NewCredentialValidator(configuration application.Configuration) CredentialV
 }
 
 func (c CredentialValidator) Validate() error {
-	if c.configuration.State.AWS.AccessKeyID == "" {
-		return errors.New("AWS access key ID must be provided")
+	awsState := c.configuration.State.AWS
+
+	// A profile or role resolves its credentials from the shared aws files
+	// or the environment, so no keys are stored alongside it.
+	if awsState.Profile == "" && awsState.AssumeRoleARN == "" {
+		if awsState.AccessKeyID == "" {
+			return errors.New("AWS access key ID must be provided")
+		}
+
+		if awsState.SecretAccessKey == "" {
+			return errors.New("AWS secret access key must be provided")
+		}
 	}
 
-	if c.configuration.State.AWS.SecretAccessKey == "" {
-		return errors.New("AWS secret access key must be provided")
-	}
-
-	if c.configuration.State.AWS.Region == "" {
+	if awsState.Region == "" {
 		return errors.New("AWS region must be provided")
 	}
 
@@ -26,6 +26,32 @@ var _ = Describe("CredentialValidator", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
+		It("does not require keys when a profile has been set", func() {
+			credentialValidator = aws.NewCredentialValidator(application.Configuration{
+				State: storage.State{
+					AWS: storage.AWS{
+						Profile: "some-profile",
+						Region:  "some-region",
+					},
+				},
+			})
+			err := credentialValidator.Validate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("does not require keys when a role to assume has been set", func() {
+			credentialValidator = aws.NewCredentialValidator(application.Configuration{
+				State: storage.State{
+					AWS: storage.AWS{
+						AssumeRoleARN: "some-role-arn",
+						Region:        "some-region",
+					},
+				},
+			})
+			err := credentialValidator.Validate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
 		Context("failure cases", func() {
 			It("returns an error when the access key id is missing", func() {
 				credentialValidator = aws.NewCredentialValidator(application.Configuration{
@@ -51,6 +77,17 @@ var _ = Describe("CredentialValidator", func() {
 				Expect(credentialValidator.Validate()).To(MatchError("AWS secret access key must be provided"))
 			})
 
+			It("returns an error when the region is missing with a profile", func() {
+				credentialValidator = aws.NewCredentialValidator(application.Configuration{
+					State: storage.State{
+						AWS: storage.AWS{
+							Profile: "some-profile",
+						},
+					},
+				})
+				Expect(credentialValidator.Validate()).To(MatchError("AWS region must be provided"))
+			})
+
 			It("returns an error when the region is missing", func() {
 				credentialValidator = aws.NewCredentialValidator(application.Configuration{
 					State: storage.State{
@@ -69,6 +69,7 @@ var _ = Describe("EnvironmentValidator", func() {
 					DirectorAddress:  "some-director-address",
 					DirectorUsername: "some-director-username",
 					DirectorPassword: "some-director-password",
+					DirectorSSLCA:    "some-director-ca-cert",
 				},
 			}
 		})
@@ -93,6 +94,7 @@ var _ = Describe("EnvironmentValidator", func() {
 			Expect(boshClientProvider.ClientCall.Receives.DirectorAddress).To(Equal("some-director-address"))
 			Expect(boshClientProvider.ClientCall.Receives.DirectorUsername).To(Equal("some-director-username"))
 			Expect(boshClientProvider.ClientCall.Receives.DirectorPassword).To(Equal("some-director-password"))
+			Expect(boshClientProvider.ClientCall.Receives.DirectorCACert).To(Equal("some-director-ca-cert"))
 			Expect(err).To(MatchError(application.BBLNotFound))
 		})
 
@@ -117,6 +119,7 @@ var _ = Describe("EnvironmentValidator", func() {
 					DirectorAddress:  "some-director-address",
 					DirectorUsername: "some-director-username",
 					DirectorPassword: "some-director-password",
+					DirectorSSLCA:    "some-director-ca-cert",
 				},
 			}
 		})
@@ -132,6 +135,7 @@ var _ = Describe("EnvironmentValidator", func() {
 			Expect(boshClientProvider.ClientCall.Receives.DirectorAddress).To(Equal("some-director-address"))
 			Expect(boshClientProvider.ClientCall.Receives.DirectorUsername).To(Equal("some-director-username"))
 			Expect(boshClientProvider.ClientCall.Receives.DirectorPassword).To(Equal("some-director-password"))
+			Expect(boshClientProvider.ClientCall.Receives.DirectorCACert).To(Equal("some-director-ca-cert"))
 			Expect(err).To(MatchError(application.BBLNotFound))
 		})
 	})
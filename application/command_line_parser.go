@@ -12,11 +12,12 @@ import (
 var getwd func() (string, error) = os.Getwd
 
 type CommandLineConfiguration struct {
-	Command          string
-	SubcommandFlags  []string
-	EndpointOverride string
-	StateDir         string
-	Debug            bool
+	Command            string
+	SubcommandFlags    []string
+	EndpointOverride   string
+	StateDir           string
+	Debug              bool
+	ConfirmCloudConfig bool
 
 	help    bool
 	version bool
@@ -98,6 +99,7 @@ func (c CommandLineParser) parseGlobalFlags(commandLineConfiguration CommandLine
 	globalFlags.String(&commandLineConfiguration.EndpointOverride, "endpoint-override", "")
 	globalFlags.String(&commandLineConfiguration.StateDir, "state-dir", "")
 	globalFlags.Bool(&commandLineConfiguration.Debug, "d", "debug", (debugEnv == "true"))
+	globalFlags.Bool(&commandLineConfiguration.ConfirmCloudConfig, "", "confirm-cloud-config", false)
 
 	globalFlags.Bool(&commandLineConfiguration.help, "h", "help", false)
 	globalFlags.Bool(&commandLineConfiguration.version, "v", "version", false)
@@ -40,6 +40,7 @@ var _ = Describe("CommandLineParser", func() {
 				"--endpoint-override=some-endpoint-override",
 				"--state-dir", "some/state/dir",
 				"--debug",
+				"--confirm-cloud-config",
 				"up",
 				"--subcommand-flag", "some-value",
 			}
@@ -49,6 +50,7 @@ var _ = Describe("CommandLineParser", func() {
 			Expect(commandLineConfiguration.EndpointOverride).To(Equal("some-endpoint-override"))
 			Expect(commandLineConfiguration.StateDir).To(Equal("some/state/dir"))
 			Expect(commandLineConfiguration.Debug).To(BeTrue())
+			Expect(commandLineConfiguration.ConfirmCloudConfig).To(BeTrue())
 		})
 
 		It("returns a command line configuration with correct command with subcommand flags based on arguments passed in", func() {
@@ -3,9 +3,10 @@ package application
 import "github.com/cloudfoundry/bosh-bootloader/storage"
 
 type GlobalConfiguration struct {
-	EndpointOverride string
-	StateDir         string
-	Debug            bool
+	EndpointOverride   string
+	StateDir           string
+	Debug              bool
+	ConfirmCloudConfig bool
 }
 
 type StringSlice []string
@@ -30,9 +30,10 @@ func (p ConfigurationParser) Parse(arguments []string) (Configuration, error) {
 
 	configuration := Configuration{
 		Global: GlobalConfiguration{
-			StateDir:         commandLineConfiguration.StateDir,
-			EndpointOverride: commandLineConfiguration.EndpointOverride,
-			Debug:            commandLineConfiguration.Debug,
+			StateDir:           commandLineConfiguration.StateDir,
+			EndpointOverride:   commandLineConfiguration.EndpointOverride,
+			Debug:              commandLineConfiguration.Debug,
+			ConfirmCloudConfig: commandLineConfiguration.ConfirmCloudConfig,
 		},
 		Command:         commandLineConfiguration.Command,
 		SubcommandFlags: commandLineConfiguration.SubcommandFlags,
@@ -11,7 +11,7 @@ type EnvironmentValidator struct {
 }
 
 type boshClientProvider interface {
-	Client(directorAddress, directorUsername, directorPassword string) bosh.Client
+	Client(directorAddress, directorUsername, directorPassword, directorCACert string) bosh.Client
 }
 
 func NewEnvironmentValidator(boshClientProvider boshClientProvider) EnvironmentValidator {
@@ -22,7 +22,7 @@ func NewEnvironmentValidator(boshClientProvider boshClientProvider) EnvironmentV
 
 func (e EnvironmentValidator) Validate(state storage.State) error {
 	if !state.NoDirector {
-		boshClient := e.boshClientProvider.Client(state.BOSH.DirectorAddress, state.BOSH.DirectorUsername, state.BOSH.DirectorPassword)
+		boshClient := e.boshClientProvider.Client(state.BOSH.DirectorAddress, state.BOSH.DirectorUsername, state.BOSH.DirectorPassword, state.BOSH.DirectorSSLCA)
 		_, err := boshClient.Info()
 		if err != nil {
 			return application.BBLNotFound
@@ -39,6 +39,7 @@ var _ = Describe("EnvironmentValidator", func() {
 				DirectorAddress:  "some-director-address",
 				DirectorUsername: "some-director-username",
 				DirectorPassword: "some-director-password",
+				DirectorSSLCA:    "some-director-ca-cert",
 			},
 		})
 
@@ -46,6 +47,7 @@ var _ = Describe("EnvironmentValidator", func() {
 		Expect(boshClientProvider.ClientCall.Receives.DirectorAddress).To(Equal("some-director-address"))
 		Expect(boshClientProvider.ClientCall.Receives.DirectorUsername).To(Equal("some-director-username"))
 		Expect(boshClientProvider.ClientCall.Receives.DirectorPassword).To(Equal("some-director-password"))
+		Expect(boshClientProvider.ClientCall.Receives.DirectorCACert).To(Equal("some-director-ca-cert"))
 		Expect(boshClient.InfoCall.CallCount).To(Equal(1))
 
 		Expect(err).To(MatchError(application.BBLNotFound))
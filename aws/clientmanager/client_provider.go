@@ -9,6 +9,7 @@ import (
 
 type ClientProvider struct {
 	EndpointOverride     string
+	config               aws.Config
 	ec2Client            ec2.Client
 	cloudformationClient cloudformation.Client
 	iamClient            iam.Client
@@ -16,6 +17,9 @@ type ClientProvider struct {
 
 func (c *ClientProvider) SetConfig(config aws.Config) {
 	config.EndpointOverride = c.EndpointOverride
+	config = config.Resolve()
+
+	c.config = config
 	c.ec2Client = ec2.NewClient(config)
 	c.cloudformationClient = cloudformation.NewClient(config)
 	c.iamClient = iam.NewClient(config)
@@ -32,3 +36,7 @@ func (c *ClientProvider) GetCloudFormationClient() cloudformation.Client {
 func (c *ClientProvider) GetIAMClient() iam.Client {
 	return c.iamClient
 }
+
+func (c *ClientProvider) GetCredentials() (aws.Credentials, error) {
+	return c.config.Credentials()
+}
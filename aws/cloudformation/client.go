@@ -13,6 +13,7 @@ type Client interface {
 	DescribeStacks(input *awscloudformation.DescribeStacksInput) (*awscloudformation.DescribeStacksOutput, error)
 	DeleteStack(input *awscloudformation.DeleteStackInput) (*awscloudformation.DeleteStackOutput, error)
 	DescribeStackResource(input *awscloudformation.DescribeStackResourceInput) (*awscloudformation.DescribeStackResourceOutput, error)
+	DescribeStackEvents(input *awscloudformation.DescribeStackEventsInput) (*awscloudformation.DescribeStackEventsOutput, error)
 }
 
 func NewClient(config aws.Config) Client {
@@ -11,7 +11,7 @@ import (
 const bblTagKey = "bbl-env-id"
 
 type templateBuilder interface {
-	Build(keypairName string, azs []string, lbType string, lbCertificateARN string, iamUserName string, envID string, boshAZ string) templates.Template
+	Build(keypairName string, azs []string, lbType string, lbCertificateARN string, iamUserName string, envID string, boshAZ string, directorAllowedCIDRs, lbAllowedCIDRs []string, natType string) templates.Template
 }
 
 type stackManager interface {
@@ -36,7 +36,7 @@ func NewInfrastructureManager(builder templateBuilder, stackManager stackManager
 }
 
 func (m InfrastructureManager) Create(keyPairName string, azs []string, stackName, boshAZ,
-	lbType, lbCertificateARN, envID string) (Stack, error) {
+	lbType, lbCertificateARN, envID string, directorAllowedCIDRs, lbAllowedCIDRs []string, natType string, tags map[string]string) (Stack, error) {
 
 	iamUserName := generateIAMUserName(envID)
 
@@ -52,15 +52,8 @@ func (m InfrastructureManager) Create(keyPairName string, azs []string, stackNam
 		}
 	}
 
-	template := m.templateBuilder.Build(keyPairName, azs, lbType, lbCertificateARN, iamUserName, envID, boshAZ)
-	tags := Tags{
-		{
-			Key:   bblTagKey,
-			Value: envID,
-		},
-	}
-
-	if err := m.stackManager.CreateOrUpdate(stackName, template, tags); err != nil {
+	template := m.templateBuilder.Build(keyPairName, azs, lbType, lbCertificateARN, iamUserName, envID, boshAZ, directorAllowedCIDRs, lbAllowedCIDRs, natType)
+	if err := m.stackManager.CreateOrUpdate(stackName, template, stackTags(envID, tags)); err != nil {
 		return Stack{}, err
 	}
 
@@ -72,16 +65,16 @@ func (m InfrastructureManager) Create(keyPairName string, azs []string, stackNam
 }
 
 func (m InfrastructureManager) Update(keyPairName string, azs []string, stackName, boshAZ, lbType,
-	lbCertificateARN, envID string) (Stack, error) {
+	lbCertificateARN, envID string, directorAllowedCIDRs, lbAllowedCIDRs []string, natType string, tags map[string]string) (Stack, error) {
 
 	iamUserName, err := m.stackManager.GetPhysicalIDForResource(stackName, "BOSHUser")
 	if err != nil {
 		return Stack{}, err
 	}
 
-	template := m.templateBuilder.Build(keyPairName, azs, lbType, lbCertificateARN, iamUserName, envID, boshAZ)
+	template := m.templateBuilder.Build(keyPairName, azs, lbType, lbCertificateARN, iamUserName, envID, boshAZ, directorAllowedCIDRs, lbAllowedCIDRs, natType)
 
-	if err := m.stackManager.Update(stackName, template, Tags{{Key: bblTagKey, Value: envID}}); err != nil {
+	if err := m.stackManager.Update(stackName, template, stackTags(envID, tags)); err != nil {
 		return Stack{}, err
 	}
 
@@ -123,6 +116,34 @@ func (m InfrastructureManager) Delete(stackName string) error {
 	return nil
 }
 
+// DeleteRetainingResources removes the stack but leaves its resources in
+// place, so that they can be managed by something else. The access key of
+// the bosh user is deleted with the stack, since it cannot be taken over.
+func (m InfrastructureManager) DeleteRetainingResources(keyPairName string, azs []string, stackName, boshAZ, lbType,
+	lbCertificateARN, envID string, directorAllowedCIDRs, lbAllowedCIDRs []string, natType string, tags map[string]string) error {
+
+	iamUserName, err := m.stackManager.GetPhysicalIDForResource(stackName, "BOSHUser")
+	if err != nil {
+		return err
+	}
+
+	template := m.templateBuilder.Build(keyPairName, azs, lbType, lbCertificateARN, iamUserName, envID, boshAZ, directorAllowedCIDRs, lbAllowedCIDRs, natType)
+
+	if err := m.stackManager.Update(stackName, template.Retain("BOSHUserAccessKey"), stackTags(envID, tags)); err != nil {
+		return err
+	}
+
+	if err := m.stackManager.WaitForCompletion(stackName, 15*time.Second, "retaining cloudformation resources"); err != nil {
+		return err
+	}
+
+	return m.Delete(stackName)
+}
+
+func (m InfrastructureManager) GetPhysicalIDForResource(stackName string, logicalResourceID string) (string, error) {
+	return m.stackManager.GetPhysicalIDForResource(stackName, logicalResourceID)
+}
+
 func generateIAMUserName(envID string) string {
 	return fmt.Sprintf("bosh-iam-user-%s", strings.Replace(envID, ":", "-", -1))
 }
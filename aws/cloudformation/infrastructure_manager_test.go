@@ -52,17 +52,22 @@ var _ = Describe("InfrastructureManager", func() {
 			}
 
 			stack, err := infrastructureManager.Create("some-key-pair-name", azs, "some-stack-name", "some-bosh-az",
-				"some-lb-type", "some-lb-certificate-arn", "some-env-id-time-stamp")
+				"some-lb-type", "some-lb-certificate-arn", "some-env-id-time-stamp", []string{"10.1.0.0/16"}, []string{"10.2.0.0/16"}, "gateway", map[string]string{"owner": "some-owner", "cost-center": "some-cost-center"})
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(stack).To(Equal(cloudformation.Stack{Name: "some-stack-name"}))
 			Expect(builder.BuildCall.Receives.KeyPairName).To(Equal("some-key-pair-name"))
 			Expect(builder.BuildCall.Receives.AZs).To(Equal(azs))
 			Expect(builder.BuildCall.Receives.BOSHAZ).To(Equal("some-bosh-az"))
+			Expect(builder.BuildCall.Receives.DirectorAllowedCIDRs).To(Equal([]string{"10.1.0.0/16"}))
+			Expect(builder.BuildCall.Receives.LBAllowedCIDRs).To(Equal([]string{"10.2.0.0/16"}))
 			Expect(builder.BuildCall.Receives.LBType).To(Equal("some-lb-type"))
 			Expect(builder.BuildCall.Receives.LBCertificateARN).To(Equal("some-lb-certificate-arn"))
 			Expect(builder.BuildCall.Receives.IAMUserName).To(Equal("bosh-iam-user-some-env-id-time-stamp"))
-			Expect(builder.BuildCall.Receives.EnvID).To(Equal("some-env-id-time-stamp"))
+			Expect(builder.BuildCall.Receives.EnvID).To(Equal("some-env-id-time-stamp", nil, nil))
+			Expect(builder.BuildCall.Receives.DirectorAllowedCIDRs).To(Equal([]string{"10.1.0.0/16"}))
+			Expect(builder.BuildCall.Receives.LBAllowedCIDRs).To(Equal([]string{"10.2.0.0/16"}))
+			Expect(builder.BuildCall.Receives.NATType).To(Equal("gateway"))
 
 			Expect(stackManager.CreateOrUpdateCall.Receives.StackName).To(Equal("some-stack-name"))
 			Expect(stackManager.CreateOrUpdateCall.Receives.Template).To(Equal(templates.Template{
@@ -74,6 +79,14 @@ var _ = Describe("InfrastructureManager", func() {
 					Key:   "bbl-env-id",
 					Value: "some-env-id-time-stamp",
 				},
+				{
+					Key:   "cost-center",
+					Value: "some-cost-center",
+				},
+				{
+					Key:   "owner",
+					Value: "some-owner",
+				},
 			}))
 
 			Expect(stackManager.WaitForCompletionCall.Receives.StackName).To(Equal("some-stack-name"))
@@ -87,7 +100,7 @@ var _ = Describe("InfrastructureManager", func() {
 			stackManager.GetPhysicalIDForResourceCall.Returns.PhysicalResourceID = "some-bosh-user-id"
 
 			_, err := infrastructureManager.Create("some-key-pair-name", azs, "some-stack-name", "some-bosh-az",
-				"some-lb-type", "some-lb-certificate-arn", "some-env-id-time:stamp")
+				"some-lb-type", "some-lb-certificate-arn", "some-env-id-time:stamp", nil, nil, "", nil)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(stackManager.GetPhysicalIDForResourceCall.Receives.StackName).To(Equal("some-stack-name"))
@@ -100,14 +113,14 @@ var _ = Describe("InfrastructureManager", func() {
 			It("returns an error when stack can't be created or updated", func() {
 				stackManager.CreateOrUpdateCall.Returns.Error = errors.New("stack create or update failed")
 
-				_, err := infrastructureManager.Create("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "", "", "")
+				_, err := infrastructureManager.Create("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "", "", "", nil, nil, "", nil)
 				Expect(err).To(MatchError("stack create or update failed"))
 			})
 
 			It("returns an error when waiting for stack completion fails", func() {
 				stackManager.WaitForCompletionCall.Returns.Error = errors.New("stack wait for completion failed")
 
-				_, err := infrastructureManager.Create("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "", "", "")
+				_, err := infrastructureManager.Create("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "", "", "", nil, nil, "", nil)
 				Expect(err).To(MatchError("stack wait for completion failed"))
 			})
 
@@ -115,7 +128,7 @@ var _ = Describe("InfrastructureManager", func() {
 				stackManager.GetPhysicalIDForResourceCall.Returns.Error = errors.New("get physical id for resource failed")
 
 				_, err := infrastructureManager.Create("some-key-pair-name", azs, "some-stack-name", "some-bosh-az",
-					"some-lb-type", "some-lb-certificate-arn", "some-env-id-time:stamp")
+					"some-lb-type", "some-lb-certificate-arn", "some-env-id-time:stamp", nil, nil, "", nil)
 				Expect(err).To(MatchError("get physical id for resource failed"))
 
 			})
@@ -124,7 +137,7 @@ var _ = Describe("InfrastructureManager", func() {
 				It("returns an error when describing the stack fails", func() {
 					stackManager.DescribeCall.Returns.Error = errors.New("stack describe failed")
 
-					_, err := infrastructureManager.Create("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "", "", "")
+					_, err := infrastructureManager.Create("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "", "", "", nil, nil, "", nil)
 					Expect(err).To(MatchError("stack describe failed"))
 				})
 			})
@@ -140,7 +153,7 @@ var _ = Describe("InfrastructureManager", func() {
 						return cloudformation.Stack{}, errors.New("stack describe failed")
 					}
 
-					_, err := infrastructureManager.Create("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "", "", "")
+					_, err := infrastructureManager.Create("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "", "", "", nil, nil, "", nil)
 					Expect(err).To(MatchError("stack describe failed"))
 				})
 			})
@@ -155,7 +168,7 @@ var _ = Describe("InfrastructureManager", func() {
 		It("updates the stack and returns the stack", func() {
 			stackManager.GetPhysicalIDForResourceCall.Returns.PhysicalResourceID = "some-bosh-user-id"
 
-			stack, err := infrastructureManager.Update("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "some-lb-type", "some-lb-certificate-arn", "some-env-id-time:stamp")
+			stack, err := infrastructureManager.Update("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "some-lb-type", "some-lb-certificate-arn", "some-env-id-time:stamp", []string{"10.1.0.0/16"}, []string{"10.2.0.0/16"}, "gateway", map[string]string{"owner": "some-owner", "cost-center": "some-cost-center"})
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(stackManager.GetPhysicalIDForResourceCall.Receives.StackName).To(Equal("some-stack-name"))
@@ -167,8 +180,11 @@ var _ = Describe("InfrastructureManager", func() {
 			Expect(builder.BuildCall.Receives.LBType).To(Equal("some-lb-type"))
 			Expect(builder.BuildCall.Receives.LBCertificateARN).To(Equal("some-lb-certificate-arn"))
 			Expect(builder.BuildCall.Receives.IAMUserName).To(Equal("some-bosh-user-id"))
-			Expect(builder.BuildCall.Receives.EnvID).To(Equal("some-env-id-time:stamp"))
+			Expect(builder.BuildCall.Receives.EnvID).To(Equal("some-env-id-time:stamp", nil, nil))
 			Expect(builder.BuildCall.Receives.BOSHAZ).To(Equal("some-bosh-az"))
+			Expect(builder.BuildCall.Receives.DirectorAllowedCIDRs).To(Equal([]string{"10.1.0.0/16"}))
+			Expect(builder.BuildCall.Receives.LBAllowedCIDRs).To(Equal([]string{"10.2.0.0/16"}))
+			Expect(builder.BuildCall.Receives.NATType).To(Equal("gateway"))
 
 			Expect(stackManager.UpdateCall.Receives.StackName).To(Equal("some-stack-name"))
 			Expect(stackManager.UpdateCall.Receives.Template).To(Equal(templates.Template{
@@ -180,6 +196,14 @@ var _ = Describe("InfrastructureManager", func() {
 					Key:   "bbl-env-id",
 					Value: "some-env-id-time:stamp",
 				},
+				{
+					Key:   "cost-center",
+					Value: "some-cost-center",
+				},
+				{
+					Key:   "owner",
+					Value: "some-owner",
+				},
 			}))
 
 			Expect(stackManager.WaitForCompletionCall.Receives.StackName).To(Equal("some-stack-name"))
@@ -193,21 +217,21 @@ var _ = Describe("InfrastructureManager", func() {
 			It("returns an error when it cannot get physical id for BOSHUser", func() {
 				stackManager.GetPhysicalIDForResourceCall.Returns.Error = errors.New("failed to get physical id for resource")
 
-				_, err := infrastructureManager.Update("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "some-lb-type", "some-lb-certificate-arn", "some-env-id-time:stamp")
+				_, err := infrastructureManager.Update("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "some-lb-type", "some-lb-certificate-arn", "some-env-id-time:stamp", nil, nil, "", nil)
 				Expect(err).To(MatchError("failed to get physical id for resource"))
 			})
 
 			It("returns an error when the update stack call fails", func() {
 				stackManager.UpdateCall.Returns.Error = errors.New("stack update call failed")
 
-				_, err := infrastructureManager.Update("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "some-lb-type", "some-lb-certificate-arn", "some-env-id-time:stamp")
+				_, err := infrastructureManager.Update("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "some-lb-type", "some-lb-certificate-arn", "some-env-id-time:stamp", nil, nil, "", nil)
 				Expect(err).To(MatchError("stack update call failed"))
 			})
 
 			It("returns an error when the wait for completion call fails", func() {
 				stackManager.WaitForCompletionCall.Returns.Error = errors.New("failed to wait for completion")
 
-				_, err := infrastructureManager.Update("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "some-lb-type", "some-lb-certificate-arn", "some-env-id-time:stamp")
+				_, err := infrastructureManager.Update("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "some-lb-type", "some-lb-certificate-arn", "some-env-id-time:stamp", nil, nil, "", nil)
 				Expect(err).To(MatchError("failed to wait for completion"))
 			})
 		})
@@ -282,6 +306,60 @@ var _ = Describe("InfrastructureManager", func() {
 		})
 	})
 
+	Describe("DeleteRetainingResources", func() {
+		BeforeEach(func() {
+			stackManager.GetPhysicalIDForResourceCall.Returns.PhysicalResourceID = "some-bosh-user-id"
+			builder.BuildCall.Returns.Template = templates.Template{
+				Resources: map[string]templates.Resource{
+					"VPC":               {Type: "AWS::EC2::VPC"},
+					"BOSHUserAccessKey": {Type: "AWS::IAM::AccessKey"},
+				},
+			}
+		})
+
+		It("retains every resource but the bosh user access key before deleting the stack", func() {
+			err := infrastructureManager.DeleteRetainingResources("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "", "", "some-env-id", []string{"10.1.0.0/16"}, nil, "gateway", map[string]string{"owner": "some-owner", "cost-center": "some-cost-center"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(builder.BuildCall.Receives.IAMUserName).To(Equal("some-bosh-user-id"))
+			Expect(builder.BuildCall.Receives.DirectorAllowedCIDRs).To(Equal([]string{"10.1.0.0/16"}))
+			Expect(builder.BuildCall.Receives.NATType).To(Equal("gateway"))
+
+			Expect(stackManager.UpdateCall.Receives.StackName).To(Equal("some-stack-name"))
+			Expect(stackManager.UpdateCall.Receives.Template).To(Equal(templates.Template{
+				Resources: map[string]templates.Resource{
+					"VPC":               {Type: "AWS::EC2::VPC", DeletionPolicy: "Retain"},
+					"BOSHUserAccessKey": {Type: "AWS::IAM::AccessKey"},
+				},
+			}))
+			Expect(stackManager.UpdateCall.Receives.Tags).To(Equal(cloudformation.Tags{
+				{Key: "bbl-env-id", Value: "some-env-id"},
+				{Key: "cost-center", Value: "some-cost-center"},
+				{Key: "owner", Value: "some-owner"},
+			}))
+
+			Expect(stackManager.DeleteCall.Receives.StackName).To(Equal("some-stack-name"))
+			Expect(stackManager.WaitForCompletionCall.Receives.Action).To(Equal("deleting cloudformation stack"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when it cannot get physical id for BOSHUser", func() {
+				stackManager.GetPhysicalIDForResourceCall.Returns.Error = errors.New("failed to get physical id for resource")
+
+				err := infrastructureManager.DeleteRetainingResources("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "", "", "some-env-id", nil, nil, "", nil)
+				Expect(err).To(MatchError("failed to get physical id for resource"))
+			})
+
+			It("does not delete the stack when the update fails", func() {
+				stackManager.UpdateCall.Returns.Error = errors.New("failed to update stack")
+
+				err := infrastructureManager.DeleteRetainingResources("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "", "", "some-env-id", nil, nil, "", nil)
+				Expect(err).To(MatchError("failed to update stack"))
+				Expect(stackManager.DeleteCall.Receives.StackName).To(BeEmpty())
+			})
+		})
+	})
+
 	Describe("Describe", func() {
 		It("returns a stack with a given name", func() {
 			expectedStack := cloudformation.Stack{
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -110,38 +111,105 @@ func (s StackManager) Describe(name string) (Stack, error) {
 }
 
 func (s StackManager) WaitForCompletion(name string, sleepInterval time.Duration, action string) error {
-	stack, err := s.Describe(name)
-	if err != nil {
-		if err == StackNotFound {
+	seenEvents := map[string]bool{}
+
+	for {
+		stack, err := s.Describe(name)
+		if err != nil {
+			if err == StackNotFound {
+				s.logger.Step(fmt.Sprintf("finished %s", action))
+				return nil
+			}
+
+			return err
+		}
+
+		events := s.operationEvents(name)
+		for _, event := range events {
+			if seenEvents[aws.StringValue(event.EventId)] {
+				continue
+			}
+			seenEvents[aws.StringValue(event.EventId)] = true
+
+			s.logger.Step(fmt.Sprintf("%s (%s) %s", aws.StringValue(event.LogicalResourceId),
+				aws.StringValue(event.ResourceType), aws.StringValue(event.ResourceStatus)))
+		}
+
+		switch stack.Status {
+		case cloudformation.StackStatusCreateComplete,
+			cloudformation.StackStatusUpdateComplete,
+			cloudformation.StackStatusDeleteComplete:
 			s.logger.Step(fmt.Sprintf("finished %s", action))
 			return nil
+		case cloudformation.StackStatusCreateFailed,
+			cloudformation.StackStatusRollbackComplete,
+			cloudformation.StackStatusRollbackFailed,
+			cloudformation.StackStatusUpdateRollbackComplete,
+			cloudformation.StackStatusUpdateRollbackFailed,
+			cloudformation.StackStatusDeleteFailed:
+			return fmt.Errorf(`CloudFormation failure on stack '%s'.
+%sCheck the AWS console for error events related to this stack,
+and/or open a GitHub issue at https://github.com/cloudfoundry/bosh-bootloader/issues.`, name, failureReasons(name, events))
+		default:
+			s.logger.Dot()
+			time.Sleep(sleepInterval)
 		}
+	}
+}
 
-		return err
+// operationEvents returns the events of the latest operation on the stack,
+// oldest first. The events are only used to report progress, so failing to
+// describe them does not fail the wait.
+func (s StackManager) operationEvents(name string) []*cloudformation.StackEvent {
+	var events []*cloudformation.StackEvent
+
+	input := &cloudformation.DescribeStackEventsInput{
+		StackName: aws.String(name),
 	}
 
-	switch stack.Status {
-	case cloudformation.StackStatusCreateComplete,
-		cloudformation.StackStatusUpdateComplete,
-		cloudformation.StackStatusDeleteComplete:
-		s.logger.Step(fmt.Sprintf("finished %s", action))
-		return nil
-	case cloudformation.StackStatusCreateFailed,
-		cloudformation.StackStatusRollbackComplete,
-		cloudformation.StackStatusRollbackFailed,
-		cloudformation.StackStatusUpdateRollbackComplete,
-		cloudformation.StackStatusUpdateRollbackFailed,
-		cloudformation.StackStatusDeleteFailed:
-		return fmt.Errorf(`CloudFormation failure on stack '%s'.
-Check the AWS console for error events related to this stack,
-and/or open a GitHub issue at https://github.com/cloudfoundry/bosh-bootloader/issues.`, name)
-	default:
-		s.logger.Dot()
-		time.Sleep(sleepInterval)
-		return s.WaitForCompletion(name, sleepInterval, action)
+	for {
+		output, err := s.cloudFormationClient().DescribeStackEvents(input)
+		if err != nil || output == nil {
+			return events
+		}
+
+		// Events are returned newest first, so the operation started with the
+		// first create, update or delete event of the stack itself that is
+		// found. Rollback events of the stack belong to the same operation.
+		for _, event := range output.StackEvents {
+			events = append([]*cloudformation.StackEvent{event}, events...)
+
+			if aws.StringValue(event.LogicalResourceId) != name {
+				continue
+			}
+
+			switch aws.StringValue(event.ResourceStatus) {
+			case cloudformation.ResourceStatusCreateInProgress,
+				cloudformation.ResourceStatusUpdateInProgress,
+				cloudformation.ResourceStatusDeleteInProgress:
+				return events
+			}
+		}
+
+		if output.NextToken == nil {
+			return events
+		}
+		input.NextToken = output.NextToken
 	}
+}
 
-	return nil
+func failureReasons(name string, events []*cloudformation.StackEvent) string {
+	var reasons string
+	for _, event := range events {
+		if aws.StringValue(event.LogicalResourceId) == name || !strings.HasSuffix(aws.StringValue(event.ResourceStatus), "_FAILED") {
+			continue
+		}
+
+		reasons += fmt.Sprintf("  %s (%s) %s: %s\n", aws.StringValue(event.LogicalResourceId), aws.StringValue(event.ResourceType),
+			aws.StringValue(event.ResourceStatus), aws.StringValue(event.ResourceStatusReason))
+	}
+
+	return reasons
 }
 
 func (s StackManager) Delete(name string) error {
@@ -507,6 +507,75 @@ and/or open a GitHub issue at https://github.com/cloudfoundry/bosh-bootloader/is
 				awscloudformation.StackStatusDeleteInProgress, awscloudformation.StackStatusDeleteFailed, "deleting stack"),
 		)
 
+		Context("when the stack has events", func() {
+			var stackEvent = func(id, logicalResourceID, resourceType, status, reason string) *awscloudformation.StackEvent {
+				return &awscloudformation.StackEvent{
+					EventId:              aws.String(id),
+					LogicalResourceId:    aws.String(logicalResourceID),
+					ResourceType:         aws.String(resourceType),
+					ResourceStatus:       aws.String(status),
+					ResourceStatusReason: aws.String(reason),
+				}
+			}
+
+			BeforeEach(func() {
+				cloudFormationClient.DescribeStackEventsCall.Stub = func(input *awscloudformation.DescribeStackEventsInput) (*awscloudformation.DescribeStackEventsOutput, error) {
+					events := []*awscloudformation.StackEvent{
+						stackEvent("event-2", "BOSHEIP", "AWS::EC2::EIP", "CREATE_IN_PROGRESS", ""),
+						stackEvent("event-1", "some-stack-name", "AWS::CloudFormation::Stack", "CREATE_IN_PROGRESS", "User Initiated"),
+						stackEvent("event-0", "some-stack-name", "AWS::CloudFormation::Stack", "DELETE_COMPLETE", ""),
+					}
+
+					if cloudFormationClient.DescribeStacksCall.CallCount > 2 {
+						events = append([]*awscloudformation.StackEvent{
+							stackEvent("event-4", "some-stack-name", "AWS::CloudFormation::Stack", "ROLLBACK_IN_PROGRESS", "The following resource(s) failed to create: [BOSHEIP]."),
+							stackEvent("event-3", "BOSHEIP", "AWS::EC2::EIP", "CREATE_FAILED", "The maximum number of addresses has been reached."),
+						}, events...)
+					}
+
+					return &awscloudformation.DescribeStackEventsOutput{StackEvents: events}, nil
+				}
+			})
+
+			It("logs each resource status of the current operation once", func() {
+				stubDescribeStacksCall(awscloudformation.StackStatusCreateInProgress, awscloudformation.StackStatusCreateComplete)
+
+				err := manager.WaitForCompletion("some-stack-name", 0*time.Millisecond, "creating stack")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(cloudFormationClient.DescribeStackEventsCall.Receives.Input).To(Equal(&awscloudformation.DescribeStackEventsInput{
+					StackName: aws.String("some-stack-name"),
+				}))
+				Expect(logger.StepCall.Messages).To(Equal([]string{
+					"some-stack-name (AWS::CloudFormation::Stack) CREATE_IN_PROGRESS",
+					"BOSHEIP (AWS::EC2::EIP) CREATE_IN_PROGRESS",
+					"BOSHEIP (AWS::EC2::EIP) CREATE_FAILED",
+					"some-stack-name (AWS::CloudFormation::Stack) ROLLBACK_IN_PROGRESS",
+					"finished creating stack",
+				}))
+			})
+
+			It("returns the reasons of the failed resources", func() {
+				stubDescribeStacksCall(awscloudformation.StackStatusCreateInProgress, awscloudformation.StackStatusRollbackComplete)
+
+				err := manager.WaitForCompletion("some-stack-name", 0*time.Millisecond, "creating stack")
+				Expect(err).To(MatchError(`CloudFormation failure on stack 'some-stack-name'.
+  BOSHEIP (AWS::EC2::EIP) CREATE_FAILED: The maximum number of addresses has been reached.
+Check the AWS console for error events related to this stack,
+and/or open a GitHub issue at https://github.com/cloudfoundry/bosh-bootloader/issues.`))
+			})
+
+			It("keeps waiting when the events cannot be described", func() {
+				cloudFormationClient.DescribeStackEventsCall.Stub = nil
+				cloudFormationClient.DescribeStackEventsCall.Returns.Error = errors.New("failed to describe stack events")
+				stubDescribeStacksCall(awscloudformation.StackStatusCreateInProgress, awscloudformation.StackStatusCreateComplete)
+
+				err := manager.WaitForCompletion("some-stack-name", 0*time.Millisecond, "creating stack")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logger.StepCall.Messages).To(Equal([]string{"finished creating stack"}))
+			})
+		})
+
 		Context("when the stack does not exist", func() {
 			It("does not error", func() {
 				cloudFormationClient.DescribeStacksCall.Returns.Error = cloudformation.StackNotFound
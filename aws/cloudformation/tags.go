@@ -1,6 +1,8 @@
 package cloudformation
 
 import (
+	"sort"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 )
@@ -12,6 +14,23 @@ type Tag struct {
 	Value string
 }
 
+// stackTags tags the stack with its env id followed by the user tags in key
+// order. Cloudformation propagates stack tags to every resource in the stack.
+func stackTags(envID string, userTags map[string]string) Tags {
+	keys := []string{}
+	for key := range userTags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tags := Tags{{Key: bblTagKey, Value: envID}}
+	for _, key := range keys {
+		tags = append(tags, Tag{Key: key, Value: userTags[key]})
+	}
+
+	return tags
+}
+
 func (t Tags) toAWSTags() []*cloudformation.Tag {
 	awsTags := []*cloudformation.Tag{}
 	for _, tag := range t {
@@ -8,13 +8,39 @@ func NewInternalSubnetTemplateBuilder() InternalSubnetTemplateBuilder {
 	return InternalSubnetTemplateBuilder{}
 }
 
-func (s InternalSubnetTemplateBuilder) InternalSubnet(az, suffix, cidrBlock string) Template {
+func (s InternalSubnetTemplateBuilder) InternalSubnet(az, suffix, cidrBlock, natType string) Template {
 	subnetName := fmt.Sprintf("InternalSubnet%s", suffix)
 	subnetTag := fmt.Sprintf("Internal%s", suffix)
 	subnetCIDRName := fmt.Sprintf("%sCIDR", subnetName)
 	cidrDescription := fmt.Sprintf("CIDR block for %s.", subnetName)
 	subnetRouteTableAssociationName := fmt.Sprintf("%sRouteTableAssociation", subnetName)
 
+	routeTableName := "InternalRouteTable"
+	routeName := "InternalRoute"
+	route := Resource{
+		Type:      "AWS::EC2::Route",
+		DependsOn: "NATInstance",
+		Properties: Route{
+			DestinationCidrBlock: "0.0.0.0/0",
+			RouteTableId:         Ref{routeTableName},
+			InstanceId:           Ref{"NATInstance"},
+		},
+	}
+
+	// Each subnet routes through the nat gateway in its own zone.
+	if natType == "gateway" {
+		routeTableName = fmt.Sprintf("InternalRouteTable%s", suffix)
+		routeName = fmt.Sprintf("InternalRoute%s", suffix)
+		route = Resource{
+			Type: "AWS::EC2::Route",
+			Properties: Route{
+				DestinationCidrBlock: "0.0.0.0/0",
+				RouteTableId:         Ref{routeTableName},
+				NatGatewayId:         Ref{fmt.Sprintf("NATGateway%s", suffix)},
+			},
+		}
+	}
+
 	return Template{
 		Outputs: map[string]Output{
 			fmt.Sprintf("%sName", subnetName): Output{
@@ -54,25 +80,17 @@ func (s InternalSubnetTemplateBuilder) InternalSubnet(az, suffix, cidrBlock stri
 					},
 				},
 			},
-			"InternalRouteTable": {
+			routeTableName: {
 				Type: "AWS::EC2::RouteTable",
 				Properties: RouteTable{
 					VpcId: Ref{"VPC"},
 				},
 			},
-			"InternalRoute": {
-				Type:      "AWS::EC2::Route",
-				DependsOn: "NATInstance",
-				Properties: Route{
-					DestinationCidrBlock: "0.0.0.0/0",
-					RouteTableId:         Ref{"InternalRouteTable"},
-					InstanceId:           Ref{"NATInstance"},
-				},
-			},
+			routeName: route,
 			subnetRouteTableAssociationName: Resource{
 				Type: "AWS::EC2::SubnetRouteTableAssociation",
 				Properties: SubnetRouteTableAssociation{
-					RouteTableId: Ref{"InternalRouteTable"},
+					RouteTableId: Ref{routeTableName},
 					SubnetId:     Ref{subnetName},
 				},
 			},
@@ -15,7 +15,7 @@ var _ = Describe("InternalSubnetTemplateBuilder", func() {
 
 	Describe("InternalSubnet", func() {
 		It("returns a template with parameters for the internal subnet", func() {
-			subnet := builder.InternalSubnet("some-zone-1", "1", "10.0.16.0/20")
+			subnet := builder.InternalSubnet("some-zone-1", "1", "10.0.16.0/20", "instance")
 
 			Expect(subnet.Parameters).To(HaveLen(1))
 			Expect(subnet.Parameters).To(HaveKeyWithValue("InternalSubnet1CIDR", templates.Parameter{
@@ -26,7 +26,7 @@ var _ = Describe("InternalSubnetTemplateBuilder", func() {
 		})
 
 		It("returns a template with resources for the internal subnet", func() {
-			subnet := builder.InternalSubnet("some-zone-1", "1", "10.0.16.0/20")
+			subnet := builder.InternalSubnet("some-zone-1", "1", "10.0.16.0/20", "instance")
 
 			Expect(subnet.Resources).To(HaveLen(4))
 			Expect(subnet.Resources).To(HaveKeyWithValue("InternalSubnet1", templates.Resource{
@@ -70,8 +70,39 @@ var _ = Describe("InternalSubnetTemplateBuilder", func() {
 			}))
 		})
 
+		Context("when the nat type is gateway", func() {
+			It("routes the subnet through the nat gateway in its zone", func() {
+				subnet := builder.InternalSubnet("some-zone-1", "1", "10.0.16.0/20", "gateway")
+
+				Expect(subnet.Resources).To(HaveLen(4))
+				Expect(subnet.Resources).To(HaveKeyWithValue("InternalRouteTable1", templates.Resource{
+					Type: "AWS::EC2::RouteTable",
+					Properties: templates.RouteTable{
+						VpcId: templates.Ref{"VPC"},
+					},
+				}))
+
+				Expect(subnet.Resources).To(HaveKeyWithValue("InternalRoute1", templates.Resource{
+					Type: "AWS::EC2::Route",
+					Properties: templates.Route{
+						DestinationCidrBlock: "0.0.0.0/0",
+						RouteTableId:         templates.Ref{"InternalRouteTable1"},
+						NatGatewayId:         templates.Ref{"NATGateway1"},
+					},
+				}))
+
+				Expect(subnet.Resources).To(HaveKeyWithValue("InternalSubnet1RouteTableAssociation", templates.Resource{
+					Type: "AWS::EC2::SubnetRouteTableAssociation",
+					Properties: templates.SubnetRouteTableAssociation{
+						RouteTableId: templates.Ref{"InternalRouteTable1"},
+						SubnetId:     templates.Ref{"InternalSubnet1"},
+					},
+				}))
+			})
+		})
+
 		It("returns a template with outputs for the internal subnet", func() {
-			subnet := builder.InternalSubnet("some-zone-1", "1", "10.0.16.0/20")
+			subnet := builder.InternalSubnet("some-zone-1", "1", "10.0.16.0/20", "instance")
 
 			Expect(subnet.Outputs).To(HaveLen(3))
 			Expect(subnet.Outputs).To(HaveKeyWithValue("InternalSubnet1CIDR", templates.Output{
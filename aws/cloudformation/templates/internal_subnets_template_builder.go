@@ -8,7 +8,7 @@ func NewInternalSubnetsTemplateBuilder() InternalSubnetsTemplateBuilder {
 	return InternalSubnetsTemplateBuilder{}
 }
 
-func (InternalSubnetsTemplateBuilder) InternalSubnets(availabilityZones []string) Template {
+func (InternalSubnetsTemplateBuilder) InternalSubnets(availabilityZones []string, natType string) Template {
 	internalSubnetTemplateBuilder := NewInternalSubnetTemplateBuilder()
 
 	template := Template{}
@@ -17,6 +17,7 @@ func (InternalSubnetsTemplateBuilder) InternalSubnets(availabilityZones []string
 			az,
 			fmt.Sprintf("%d", index+1),
 			fmt.Sprintf("10.0.%d.0/20", 16*(index+1)),
+			natType,
 		))
 	}
 
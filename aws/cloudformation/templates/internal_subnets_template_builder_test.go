@@ -22,7 +22,7 @@ var _ = Describe("InternalSubnetsTemplateBuilder", func() {
 			template := internalSubnetsTemplateBuilder.InternalSubnets([]string{
 				"some-zone-1",
 				"some-zone-2",
-			})
+			}, "instance")
 
 			Expect(template.Parameters).To(HaveLen(2))
 			Expect(template.Parameters["InternalSubnet1CIDR"].Default).To(Equal("10.0.16.0/20"))
@@ -1,5 +1,7 @@
 package templates
 
+import "fmt"
+
 type NATTemplateBuilder struct{}
 
 func NewNATTemplateBuilder() NATTemplateBuilder {
@@ -88,3 +90,57 @@ func (t NATTemplateBuilder) NAT() Template {
 		},
 	}
 }
+
+// NATGateways places a managed nat gateway in a small public subnet of every
+// availability zone.
+func (t NATTemplateBuilder) NATGateways(availabilityZones []string) Template {
+	template := Template{}
+
+	for index, az := range availabilityZones {
+		suffix := fmt.Sprintf("%d", index+1)
+		subnetName := fmt.Sprintf("NATSubnet%s", suffix)
+		eipName := fmt.Sprintf("NATEIP%s", suffix)
+
+		template = template.Merge(Template{
+			Resources: map[string]Resource{
+				subnetName: Resource{
+					Type: "AWS::EC2::Subnet",
+					Properties: Subnet{
+						AvailabilityZone: az,
+						CidrBlock:        fmt.Sprintf("10.0.1.%d/28", 16*index),
+						VpcId:            Ref{"VPC"},
+						Tags: []Tag{
+							{
+								Key:   "Name",
+								Value: fmt.Sprintf("NAT%s", suffix),
+							},
+						},
+					},
+				},
+				fmt.Sprintf("%sRouteTableAssociation", subnetName): Resource{
+					Type: "AWS::EC2::SubnetRouteTableAssociation",
+					Properties: SubnetRouteTableAssociation{
+						RouteTableId: Ref{"BOSHRouteTable"},
+						SubnetId:     Ref{subnetName},
+					},
+				},
+				eipName: Resource{
+					DependsOn: "VPCGatewayAttachment",
+					Type:      "AWS::EC2::EIP",
+					Properties: EIP{
+						Domain: "vpc",
+					},
+				},
+				fmt.Sprintf("NATGateway%s", suffix): Resource{
+					Type: "AWS::EC2::NatGateway",
+					Properties: NatGateway{
+						AllocationId: FnGetAtt{[]string{eipName, "AllocationId"}},
+						SubnetId:     Ref{subnetName},
+					},
+				},
+			},
+		})
+	}
+
+	return template
+}
@@ -98,4 +98,47 @@ var _ = Describe("NATTemplateBuilder", func() {
 			}))
 		})
 	})
+
+	Describe("NATGateways", func() {
+		It("returns a template with a nat gateway in a public subnet of every zone", func() {
+			nat := builder.NATGateways([]string{"some-zone-1", "some-zone-2"})
+
+			Expect(nat.Resources).To(HaveLen(8))
+			Expect(nat.Resources).To(HaveKeyWithValue("NATSubnet2", templates.Resource{
+				Type: "AWS::EC2::Subnet",
+				Properties: templates.Subnet{
+					AvailabilityZone: "some-zone-2",
+					CidrBlock:        "10.0.1.16/28",
+					VpcId:            templates.Ref{"VPC"},
+					Tags: []templates.Tag{
+						{
+							Key:   "Name",
+							Value: "NAT2",
+						},
+					},
+				},
+			}))
+			Expect(nat.Resources).To(HaveKeyWithValue("NATSubnet2RouteTableAssociation", templates.Resource{
+				Type: "AWS::EC2::SubnetRouteTableAssociation",
+				Properties: templates.SubnetRouteTableAssociation{
+					RouteTableId: templates.Ref{"BOSHRouteTable"},
+					SubnetId:     templates.Ref{"NATSubnet2"},
+				},
+			}))
+			Expect(nat.Resources).To(HaveKeyWithValue("NATEIP2", templates.Resource{
+				Type:      "AWS::EC2::EIP",
+				DependsOn: "VPCGatewayAttachment",
+				Properties: templates.EIP{
+					Domain: "vpc",
+				},
+			}))
+			Expect(nat.Resources).To(HaveKeyWithValue("NATGateway2", templates.Resource{
+				Type: "AWS::EC2::NatGateway",
+				Properties: templates.NatGateway{
+					AllocationId: templates.FnGetAtt{[]string{"NATEIP2", "AllocationId"}},
+					SubnetId:     templates.Ref{"NATSubnet2"},
+				},
+			}))
+		})
+	})
 })
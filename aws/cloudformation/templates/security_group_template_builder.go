@@ -7,19 +7,25 @@ func NewSecurityGroupTemplateBuilder() SecurityGroupTemplateBuilder {
 }
 
 func (s SecurityGroupTemplateBuilder) LBSecurityGroup(securityGroupName, securityGroupDescription,
-	loadBalancerName string, template Template) Template {
+	loadBalancerName string, template Template, allowedCIDRs []string) Template {
 	securityGroupIngress := []SecurityGroupIngress{}
 
+	if len(allowedCIDRs) == 0 {
+		allowedCIDRs = []string{"0.0.0.0/0"}
+	}
+
 	properties := template.Resources[loadBalancerName].Properties.(ElasticLoadBalancingLoadBalancer)
 
 	for _, listener := range properties.Listeners {
-		securityGroupIngress = append(securityGroupIngress, s.securityGroupIngress(
-			"0.0.0.0/0",
-			s.determineSecurityGroupProtocol(listener.Protocol),
-			listener.LoadBalancerPort,
-			listener.LoadBalancerPort,
-			nil,
-		))
+		for _, cidr := range allowedCIDRs {
+			securityGroupIngress = append(securityGroupIngress, s.securityGroupIngress(
+				cidr,
+				s.determineSecurityGroupProtocol(listener.Protocol),
+				listener.LoadBalancerPort,
+				listener.LoadBalancerPort,
+				nil,
+			))
+		}
 	}
 
 	return Template{
@@ -105,7 +111,26 @@ func (s SecurityGroupTemplateBuilder) InternalSecurityGroup() Template {
 	}
 }
 
-func (s SecurityGroupTemplateBuilder) BOSHSecurityGroup() Template {
+func (s SecurityGroupTemplateBuilder) BOSHSecurityGroup(allowedCIDRs []string) Template {
+	inboundCIDRs := []interface{}{Ref{"BOSHInboundCIDR"}}
+	if len(allowedCIDRs) > 0 {
+		inboundCIDRs = []interface{}{}
+		for _, cidr := range allowedCIDRs {
+			inboundCIDRs = append(inboundCIDRs, cidr)
+		}
+	}
+
+	securityGroupIngress := []SecurityGroupIngress{}
+	for _, port := range []string{"22", "6868", "25555"} {
+		for _, cidr := range inboundCIDRs {
+			securityGroupIngress = append(securityGroupIngress, s.securityGroupIngress(cidr, "tcp", port, port, nil))
+		}
+	}
+	securityGroupIngress = append(securityGroupIngress,
+		s.securityGroupIngress(nil, "tcp", "0", "65535", Ref{"InternalSecurityGroup"}),
+		s.securityGroupIngress(nil, "udp", "0", "65535", Ref{"InternalSecurityGroup"}),
+	)
+
 	return Template{
 		Parameters: map[string]Parameter{
 			"BOSHInboundCIDR": Parameter{
@@ -118,16 +143,10 @@ func (s SecurityGroupTemplateBuilder) BOSHSecurityGroup() Template {
 			"BOSHSecurityGroup": Resource{
 				Type: "AWS::EC2::SecurityGroup",
 				Properties: SecurityGroup{
-					VpcId:               Ref{"VPC"},
-					GroupDescription:    "BOSH",
-					SecurityGroupEgress: []SecurityGroupEgress{},
-					SecurityGroupIngress: []SecurityGroupIngress{
-						s.securityGroupIngress(Ref{"BOSHInboundCIDR"}, "tcp", "22", "22", nil),
-						s.securityGroupIngress(Ref{"BOSHInboundCIDR"}, "tcp", "6868", "6868", nil),
-						s.securityGroupIngress(Ref{"BOSHInboundCIDR"}, "tcp", "25555", "25555", nil),
-						s.securityGroupIngress(nil, "tcp", "0", "65535", Ref{"InternalSecurityGroup"}),
-						s.securityGroupIngress(nil, "udp", "0", "65535", Ref{"InternalSecurityGroup"}),
-					},
+					VpcId:                Ref{"VPC"},
+					GroupDescription:     "BOSH",
+					SecurityGroupEgress:  []SecurityGroupEgress{},
+					SecurityGroupIngress: securityGroupIngress,
 				},
 			},
 		},
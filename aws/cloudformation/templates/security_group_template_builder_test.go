@@ -1,9 +1,9 @@
 package templates_test
 
 import (
+	"github.com/cloudfoundry/bosh-bootloader/aws/cloudformation/templates"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"github.com/cloudfoundry/bosh-bootloader/aws/cloudformation/templates"
 )
 
 var _ = Describe("SecurityGroupTemplateBuilder", func() {
@@ -98,7 +98,7 @@ var _ = Describe("SecurityGroupTemplateBuilder", func() {
 
 	Describe("BOSHSecurityGroup", func() {
 		It("returns a template containing the bosh security group", func() {
-			securityGroup := builder.BOSHSecurityGroup()
+			securityGroup := builder.BOSHSecurityGroup(nil)
 
 			Expect(securityGroup.Parameters).To(HaveLen(1))
 			Expect(securityGroup.Parameters).To(HaveKeyWithValue("BOSHInboundCIDR", templates.Parameter{
@@ -155,6 +155,21 @@ var _ = Describe("SecurityGroupTemplateBuilder", func() {
 				},
 			}))
 		})
+
+		It("restricts ingress to the director allowed cidrs when they are provided", func() {
+			securityGroup := builder.BOSHSecurityGroup([]string{"10.1.0.0/16", "192.168.0.1/32"})
+
+			properties := securityGroup.Resources["BOSHSecurityGroup"].Properties.(templates.SecurityGroup)
+			Expect(properties.SecurityGroupIngress[:6]).To(Equal([]templates.SecurityGroupIngress{
+				{CidrIp: "10.1.0.0/16", IpProtocol: "tcp", FromPort: "22", ToPort: "22"},
+				{CidrIp: "192.168.0.1/32", IpProtocol: "tcp", FromPort: "22", ToPort: "22"},
+				{CidrIp: "10.1.0.0/16", IpProtocol: "tcp", FromPort: "6868", ToPort: "6868"},
+				{CidrIp: "192.168.0.1/32", IpProtocol: "tcp", FromPort: "6868", ToPort: "6868"},
+				{CidrIp: "10.1.0.0/16", IpProtocol: "tcp", FromPort: "25555", ToPort: "25555"},
+				{CidrIp: "192.168.0.1/32", IpProtocol: "tcp", FromPort: "25555", ToPort: "25555"},
+			}))
+			Expect(properties.SecurityGroupIngress).To(HaveLen(8))
+		})
 	})
 
 	Context("when building security groups for load balancers", func() {
@@ -204,7 +219,7 @@ var _ = Describe("SecurityGroupTemplateBuilder", func() {
 		Describe("LBSecurityGroup", func() {
 			It("returns a load balancer security group based on load balancer template", func() {
 				securityGroup := builder.LBSecurityGroup("some-security-group", "some-group-description",
-					"some-load-balancer", loadBalancerTemplate)
+					"some-load-balancer", loadBalancerTemplate, nil)
 
 				Expect(securityGroup.Resources).To(HaveLen(1))
 				Expect(securityGroup.Resources).To(HaveKeyWithValue("some-security-group", templates.Resource{
@@ -242,6 +257,18 @@ var _ = Describe("SecurityGroupTemplateBuilder", func() {
 					},
 				}))
 			})
+
+			It("restricts load balancer ingress to the lb allowed cidrs when they are provided", func() {
+				securityGroup := builder.LBSecurityGroup("some-security-group", "some-group-description",
+					"some-load-balancer", loadBalancerTemplate, []string{"10.2.0.0/16", "10.3.0.0/16"})
+
+				properties := securityGroup.Resources["some-security-group"].Properties.(templates.SecurityGroup)
+				Expect(properties.SecurityGroupIngress).To(HaveLen(8))
+				Expect(properties.SecurityGroupIngress[:2]).To(Equal([]templates.SecurityGroupIngress{
+					{CidrIp: "10.2.0.0/16", IpProtocol: "tcp", FromPort: "1000", ToPort: "1000"},
+					{CidrIp: "10.3.0.0/16", IpProtocol: "tcp", FromPort: "1000", ToPort: "1000"},
+				}))
+			})
 		})
 
 		Describe("LBInternalSecurityGroup", func() {
@@ -77,6 +77,24 @@ func (t Template) Merge(templates ...Template) Template {
 	return t
 }
 
+// Retain returns a copy of the template whose resources are kept when the
+// stack is deleted, except for the deleted ones.
+func (t Template) Retain(deleted ...string) Template {
+	resources := map[string]Resource{}
+	for name, resource := range t.Resources {
+		resource.DeletionPolicy = "Retain"
+		for _, deletedName := range deleted {
+			if name == deletedName {
+				resource.DeletionPolicy = ""
+			}
+		}
+		resources[name] = resource
+	}
+	t.Resources = resources
+
+	return t
+}
+
 type Output struct {
 	Value interface{}
 }
@@ -129,6 +147,12 @@ type Route struct {
 	GatewayId            interface{} `json:",omitempty"`
 	RouteTableId         interface{} `json:",omitempty"`
 	InstanceId           interface{} `json:",omitempty"`
+	NatGatewayId         interface{} `json:",omitempty"`
+}
+
+type NatGateway struct {
+	AllocationId interface{} `json:",omitempty"`
+	SubnetId     interface{} `json:",omitempty"`
 }
 
 type Instance struct {
@@ -15,7 +15,7 @@ func NewTemplateBuilder(logger logger) TemplateBuilder {
 	}
 }
 
-func (t TemplateBuilder) Build(keyPairName string, availablityZones []string, lbType, lbCertificateARN string, iamUserName string, envID string, boshAZ string) Template {
+func (t TemplateBuilder) Build(keyPairName string, availablityZones []string, lbType, lbCertificateARN string, iamUserName string, envID string, boshAZ string, directorAllowedCIDRs, lbAllowedCIDRs []string, natType string) Template {
 	t.logger.Step("generating cloudformation template")
 
 	boshIAMTemplateBuilder := NewBOSHIAMTemplateBuilder()
@@ -29,18 +29,23 @@ func (t TemplateBuilder) Build(keyPairName string, availablityZones []string, lb
 	loadBalancerSubnetsTemplateBuilder := NewLoadBalancerSubnetsTemplateBuilder()
 	loadBalancerTemplateBuilder := NewLoadBalancerTemplateBuilder()
 
+	natTemplate := natTemplateBuilder.NAT()
+	if natType == "gateway" {
+		natTemplate = natTemplateBuilder.NATGateways(availablityZones)
+	}
+
 	template := Template{
 		AWSTemplateFormatVersion: "2010-09-09",
 		Description:              "Infrastructure for a BOSH deployment.",
 	}.Merge(
-		internalSubnetsTemplateBuilder.InternalSubnets(availablityZones),
+		internalSubnetsTemplateBuilder.InternalSubnets(availablityZones, natType),
 		sshKeyPairTemplateBuilder.SSHKeyPairName(keyPairName),
 		boshIAMTemplateBuilder.BOSHIAMUser(iamUserName),
-		natTemplateBuilder.NAT(),
+		natTemplate,
 		vpcTemplateBuilder.VPC(envID),
 		boshSubnetTemplateBuilder.BOSHSubnet(boshAZ),
 		securityGroupTemplateBuilder.InternalSecurityGroup(),
-		securityGroupTemplateBuilder.BOSHSecurityGroup(),
+		securityGroupTemplateBuilder.BOSHSecurityGroup(directorAllowedCIDRs),
 		boshEIPTemplateBuilder.BOSHEIP(),
 	)
 
@@ -51,7 +56,7 @@ func (t TemplateBuilder) Build(keyPairName string, availablityZones []string, lb
 		template.Merge(
 			loadBalancerSubnetsTemplateBuilder.LoadBalancerSubnets(availablityZones),
 			lbTemplate,
-			securityGroupTemplateBuilder.LBSecurityGroup("ConcourseSecurityGroup", "Concourse", "ConcourseLoadBalancer", lbTemplate),
+			securityGroupTemplateBuilder.LBSecurityGroup("ConcourseSecurityGroup", "Concourse", "ConcourseLoadBalancer", lbTemplate, lbAllowedCIDRs),
 			securityGroupTemplateBuilder.LBInternalSecurityGroup("ConcourseInternalSecurityGroup", "ConcourseSecurityGroup", "ConcourseInternal", "ConcourseLoadBalancer", lbTemplate),
 		)
 	}
@@ -64,11 +69,11 @@ func (t TemplateBuilder) Build(keyPairName string, availablityZones []string, lb
 			loadBalancerSubnetsTemplateBuilder.LoadBalancerSubnets(availablityZones),
 
 			routerLBTemplate,
-			securityGroupTemplateBuilder.LBSecurityGroup("CFRouterSecurityGroup", "Router", "CFRouterLoadBalancer", routerLBTemplate),
+			securityGroupTemplateBuilder.LBSecurityGroup("CFRouterSecurityGroup", "Router", "CFRouterLoadBalancer", routerLBTemplate, lbAllowedCIDRs),
 			securityGroupTemplateBuilder.LBInternalSecurityGroup("CFRouterInternalSecurityGroup", "CFRouterSecurityGroup", "CFRouterInternal", "CFRouterLoadBalancer", routerLBTemplate),
 
 			sshLBTemplate,
-			securityGroupTemplateBuilder.LBSecurityGroup("CFSSHProxySecurityGroup", "CFSSHProxy", "CFSSHProxyLoadBalancer", sshLBTemplate),
+			securityGroupTemplateBuilder.LBSecurityGroup("CFSSHProxySecurityGroup", "CFSSHProxy", "CFSSHProxyLoadBalancer", sshLBTemplate, lbAllowedCIDRs),
 			securityGroupTemplateBuilder.LBInternalSecurityGroup("CFSSHProxyInternalSecurityGroup", "CFSSHProxySecurityGroup", "CFSSHProxyInternal", "CFSSHProxyLoadBalancer", sshLBTemplate),
 		)
 	}
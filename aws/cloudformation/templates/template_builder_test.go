@@ -33,7 +33,7 @@ var _ = Describe("TemplateBuilder", func() {
 	Describe("Build", func() {
 		Context("concourse elb template", func() {
 			It("builds a cloudformation template", func() {
-				template := builder.Build("keypair-name", azs, "concourse", "", "", "", "")
+				template := builder.Build("keypair-name", azs, "concourse", "", "", "", "", nil, nil, "instance")
 				Expect(template.AWSTemplateFormatVersion).To(Equal("2010-09-09"))
 				Expect(template.Description).To(Equal("Infrastructure for a BOSH deployment with a Concourse ELB."))
 
@@ -61,7 +61,7 @@ var _ = Describe("TemplateBuilder", func() {
 
 		Context("cf elb template", func() {
 			It("builds a cloudformation template", func() {
-				template := builder.Build("keypair-name", azs, "cf", "", "", "", "")
+				template := builder.Build("keypair-name", azs, "cf", "", "", "", "", nil, nil, "instance")
 				Expect(template.AWSTemplateFormatVersion).To(Equal("2010-09-09"))
 				Expect(template.Description).To(Equal("Infrastructure for a BOSH deployment with a CloudFoundry ELB."))
 
@@ -92,7 +92,7 @@ var _ = Describe("TemplateBuilder", func() {
 
 		Context("no elb template", func() {
 			It("builds a cloudformation template", func() {
-				template := builder.Build("keypair-name", azs, "", "", "", "", "")
+				template := builder.Build("keypair-name", azs, "", "", "", "", "", nil, nil, "instance")
 				Expect(template.AWSTemplateFormatVersion).To(Equal("2010-09-09"))
 				Expect(template.Description).To(Equal("Infrastructure for a BOSH deployment."))
 
@@ -122,8 +122,24 @@ var _ = Describe("TemplateBuilder", func() {
 			})
 		})
 
+		Context("nat gateway template", func() {
+			It("builds a cloudformation template with a nat gateway per availability zone", func() {
+				template := builder.Build("keypair-name", azs, "", "", "", "", "", nil, nil, "gateway")
+
+				Expect(template.Resources).To(HaveKey("NATGateway1"))
+				Expect(template.Resources).To(HaveKey("NATGateway4"))
+				Expect(template.Resources).To(HaveKey("InternalRouteTable1"))
+				Expect(template.Resources).To(HaveKey("InternalRouteTable4"))
+
+				Expect(template.Mappings).NotTo(HaveKey("AWSNATAMI"))
+				Expect(template.Resources).NotTo(HaveKey("NATInstance"))
+				Expect(template.Resources).NotTo(HaveKey("NATSecurityGroup"))
+				Expect(template.Resources).NotTo(HaveKey("InternalRouteTable"))
+			})
+		})
+
 		It("logs that the cloudformation template is being generated", func() {
-			builder.Build("keypair-name", []string{}, "", "", "", "", "")
+			builder.Build("keypair-name", []string{}, "", "", "", "", "", nil, nil, "instance")
 
 			Expect(logger.StepCall.Receives.Message).To(Equal("generating cloudformation template"))
 		})
@@ -131,7 +147,7 @@ var _ = Describe("TemplateBuilder", func() {
 
 	Describe("template marshaling", func() {
 		DescribeTable("marshals template to JSON", func(lbType string, fixture string) {
-			template := builder.Build("keypair-name", azs, lbType, "some-certificate-arn", "bosh-iam-user-some-env-id", "bbl-env-id", "us-east-1a")
+			template := builder.Build("keypair-name", azs, lbType, "some-certificate-arn", "bosh-iam-user-some-env-id", "bbl-env-id", "us-east-1a", nil, nil, "instance")
 
 			buf, err := ioutil.ReadFile("fixtures/" + fixture)
 			Expect(err).NotTo(HaveOccurred())
@@ -3,18 +3,57 @@ package aws
 import (
 	goaws "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
 )
 
 type Config struct {
 	AccessKeyID      string
 	SecretAccessKey  string
+	SessionToken     string
+	Profile          string
+	AssumeRoleARN    string
+	MFASerial        string
 	Region           string
 	EndpointOverride string
+
+	resolved *credentials.Credentials
+}
+
+// Credentials are the access keys that a Config resolves to, which are
+// temporary when a profile or a role is used.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// Resolve returns a copy of the config whose clients share a single set of
+// credentials, so that a role is assumed and an MFA token is asked for only
+// once.
+func (c Config) Resolve() Config {
+	c.resolved = c.credentials()
+	return c
+}
+
+// Credentials returns the access keys the config resolves to, so that tools
+// run by bbl can use them without knowing about profiles or roles.
+func (c Config) Credentials() (Credentials, error) {
+	value, err := c.clientCredentials().Get()
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	return Credentials{
+		AccessKeyID:     value.AccessKeyID,
+		SecretAccessKey: value.SecretAccessKey,
+		SessionToken:    value.SessionToken,
+	}, nil
 }
 
 func (c Config) ClientConfig() *goaws.Config {
 	awsConfig := &goaws.Config{
-		Credentials: credentials.NewStaticCredentials(c.AccessKeyID, c.SecretAccessKey, ""),
+		Credentials: c.clientCredentials(),
 		Region:      goaws.String(c.Region),
 	}
 
@@ -24,3 +63,69 @@ func (c Config) ClientConfig() *goaws.Config {
 
 	return awsConfig
 }
+
+func (c Config) clientCredentials() *credentials.Credentials {
+	if c.resolved != nil {
+		return c.resolved
+	}
+
+	return c.credentials()
+}
+
+// credentials prefers explicit keys and otherwise falls back to the shared
+// config and credential files, which may themselves assume a role. An
+// explicit role is assumed on top of whichever credentials were found,
+// prompting on stdin for a token when the role requires MFA.
+func (c Config) credentials() *credentials.Credentials {
+	var creds *credentials.Credentials
+	if c.AccessKeyID != "" {
+		creds = credentials.NewStaticCredentials(c.AccessKeyID, c.SecretAccessKey, c.SessionToken)
+	} else {
+		creds = credentials.NewCredentials(&sharedConfigProvider{profile: c.Profile})
+	}
+
+	if c.AssumeRoleARN == "" {
+		return creds
+	}
+
+	stsConfig := &goaws.Config{
+		Credentials: creds,
+		Region:      goaws.String(c.Region),
+	}
+
+	return stscreds.NewCredentials(session.New(stsConfig), c.AssumeRoleARN, func(p *stscreds.AssumeRoleProvider) {
+		if c.MFASerial != "" {
+			p.SerialNumber = goaws.String(c.MFASerial)
+			p.TokenProvider = stscreds.StdinTokenProvider
+		}
+	})
+}
+
+// sharedConfigProvider loads a profile from the shared aws files the first
+// time credentials are needed, prompting on stdin when the profile assumes a
+// role that requires an MFA token.
+type sharedConfigProvider struct {
+	profile     string
+	credentials *credentials.Credentials
+}
+
+func (p *sharedConfigProvider) Retrieve() (credentials.Value, error) {
+	if p.credentials == nil {
+		sess, err := session.NewSessionWithOptions(session.Options{
+			Profile:                 p.profile,
+			SharedConfigState:       session.SharedConfigEnable,
+			AssumeRoleTokenProvider: stscreds.StdinTokenProvider,
+		})
+		if err != nil {
+			return credentials.Value{}, err
+		}
+
+		p.credentials = sess.Config.Credentials
+	}
+
+	return p.credentials.Get()
+}
+
+func (p *sharedConfigProvider) IsExpired() bool {
+	return p.credentials == nil || p.credentials.IsExpired()
+}
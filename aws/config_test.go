@@ -1,6 +1,9 @@
 package aws_test
 
 import (
+	"io/ioutil"
+	"os"
+
 	goaws "github.com/aws/aws-sdk-go/aws"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -27,5 +30,92 @@ var _ = Describe("Config", func() {
 
 			Expect(config.ClientConfig()).To(Equal(awsConfig))
 		})
+
+		It("uses the session token with the access keys", func() {
+			config := aws.Config{
+				AccessKeyID:     "some-access-key-id",
+				SecretAccessKey: "some-secret-access-key",
+				SessionToken:    "some-session-token",
+				Region:          "some-region",
+			}
+
+			Expect(config.ClientConfig().Credentials).To(Equal(credentials.NewStaticCredentials("some-access-key-id", "some-secret-access-key", "some-session-token")))
+		})
+
+		Context("when a profile is provided", func() {
+			var previousCredentialsFile string
+
+			BeforeEach(func() {
+				credentialsFile, err := ioutil.TempFile("", "credentials")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = credentialsFile.WriteString("[some-profile]\naws_access_key_id = profile-access-key-id\naws_secret_access_key = profile-secret-access-key\n")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(credentialsFile.Close()).To(Succeed())
+
+				previousCredentialsFile = os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+				os.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentialsFile.Name())
+			})
+
+			AfterEach(func() {
+				os.Remove(os.Getenv("AWS_SHARED_CREDENTIALS_FILE"))
+				os.Setenv("AWS_SHARED_CREDENTIALS_FILE", previousCredentialsFile)
+			})
+
+			It("reads the credentials of the profile from the shared credentials file", func() {
+				config := aws.Config{
+					Profile: "some-profile",
+					Region:  "some-region",
+				}
+
+				value, err := config.ClientConfig().Credentials.Get()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(value.AccessKeyID).To(Equal("profile-access-key-id"))
+				Expect(value.SecretAccessKey).To(Equal("profile-secret-access-key"))
+			})
+
+			It("returns an error when the profile does not exist", func() {
+				config := aws.Config{
+					Profile: "some-missing-profile",
+					Region:  "some-region",
+				}
+
+				_, err := config.ClientConfig().Credentials.Get()
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("Credentials", func() {
+		It("returns the access keys the config resolves to", func() {
+			config := aws.Config{
+				AccessKeyID:     "some-access-key-id",
+				SecretAccessKey: "some-secret-access-key",
+				SessionToken:    "some-session-token",
+				Region:          "some-region",
+			}
+
+			credentials, err := config.Credentials()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credentials).To(Equal(aws.Credentials{
+				AccessKeyID:     "some-access-key-id",
+				SecretAccessKey: "some-secret-access-key",
+				SessionToken:    "some-session-token",
+			}))
+		})
+	})
+
+	Describe("Resolve", func() {
+		It("shares the credentials between every client config", func() {
+			config := aws.Config{
+				AccessKeyID:     "some-access-key-id",
+				SecretAccessKey: "some-secret-access-key",
+				AssumeRoleARN:   "some-role-arn",
+				MFASerial:       "some-mfa-serial",
+				Region:          "some-region",
+			}.Resolve()
+
+			Expect(config.ClientConfig().Credentials).To(BeIdenticalTo(config.ClientConfig().Credentials))
+		})
 	})
 })
@@ -22,7 +22,7 @@ func NewVPCStatusChecker(ec2ClientProvider ec2ClientProvider) VPCStatusChecker {
 	}
 }
 
-func (v VPCStatusChecker) ValidateSafeToDelete(vpcID, envID string) error {
+func (v VPCStatusChecker) ValidateSafeToDelete(vpcID, envID, natType string) error {
 	output, err := v.ec2ClientProvider.GetEC2Client().DescribeInstances(&awsec2.DescribeInstancesInput{
 		Filters: []*awsec2.Filter{{
 			Name:   aws.String("vpc-id"),
@@ -34,10 +34,14 @@ func (v VPCStatusChecker) ValidateSafeToDelete(vpcID, envID string) error {
 	}
 
 	vms := v.flattenVMs(output.Reservations)
-	if envID != "" {
-		vms = v.removeOneVM(vms, fmt.Sprintf("%s-nat", envID))
+
+	// Nat gateways are not vms, so only a nat instance is expected.
+	if natType != "gateway" {
+		if envID != "" {
+			vms = v.removeOneVM(vms, fmt.Sprintf("%s-nat", envID))
+		}
+		vms = v.removeOneVM(vms, "NAT")
 	}
-	vms = v.removeOneVM(vms, "NAT")
 	vms = v.removeOneVM(vms, "bosh/0")
 
 	if len(vms) > 0 {
@@ -47,7 +47,7 @@ var _ = Describe("VPCStatusChecker", func() {
 				},
 			}
 
-			err := vpcStatusChecker.ValidateSafeToDelete("some-vpc-id", "")
+			err := vpcStatusChecker.ValidateSafeToDelete("some-vpc-id", "", "")
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(ec2Client.DescribeInstancesCall.Receives.Input).To(Equal(&awsec2.DescribeInstancesInput{
@@ -67,7 +67,7 @@ var _ = Describe("VPCStatusChecker", func() {
 					},
 				}
 
-				err := vpcStatusChecker.ValidateSafeToDelete("some-vpc-id", "example-env-id")
+				err := vpcStatusChecker.ValidateSafeToDelete("some-vpc-id", "example-env-id", "")
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(ec2Client.DescribeInstancesCall.Receives.Input).To(Equal(&awsec2.DescribeInstancesInput{
@@ -79,12 +79,26 @@ var _ = Describe("VPCStatusChecker", func() {
 			})
 		})
 
+		Context("when the environment uses nat gateways", func() {
+			It("returns an error when a vm is tagged as the nat", func() {
+				ec2Client.DescribeInstancesCall.Returns.Output = &awsec2.DescribeInstancesOutput{
+					Reservations: []*awsec2.Reservation{
+						reservationContainingInstance("example-env-id-nat"),
+						reservationContainingInstance("bosh/0"),
+					},
+				}
+
+				err := vpcStatusChecker.ValidateSafeToDelete("some-vpc-id", "example-env-id", "gateway")
+				Expect(err).To(MatchError("vpc some-vpc-id is not safe to delete; vms still exist: [example-env-id-nat]"))
+			})
+		})
+
 		It("returns nil when there are no instances at all", func() {
 			ec2Client.DescribeInstancesCall.Returns.Output = &awsec2.DescribeInstancesOutput{
 				Reservations: []*awsec2.Reservation{},
 			}
 
-			err := vpcStatusChecker.ValidateSafeToDelete("some-vpc-id", "")
+			err := vpcStatusChecker.ValidateSafeToDelete("some-vpc-id", "", "")
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -98,7 +112,7 @@ var _ = Describe("VPCStatusChecker", func() {
 				},
 			}
 
-			err := vpcStatusChecker.ValidateSafeToDelete("some-vpc-id", "")
+			err := vpcStatusChecker.ValidateSafeToDelete("some-vpc-id", "", "")
 			Expect(err).To(MatchError("vpc some-vpc-id is not safe to delete; vms still exist: [first-bosh-deployed-vm, second-bosh-deployed-vm]"))
 		})
 
@@ -110,7 +124,7 @@ var _ = Describe("VPCStatusChecker", func() {
 				},
 			}
 
-			err := vpcStatusChecker.ValidateSafeToDelete("some-vpc-id", "")
+			err := vpcStatusChecker.ValidateSafeToDelete("some-vpc-id", "", "")
 			Expect(err).To(MatchError("vpc some-vpc-id is not safe to delete; vms still exist: [not-bosh, not-nat]"))
 		})
 
@@ -125,7 +139,7 @@ var _ = Describe("VPCStatusChecker", func() {
 				},
 			}
 
-			err := vpcStatusChecker.ValidateSafeToDelete("some-vpc-id", "")
+			err := vpcStatusChecker.ValidateSafeToDelete("some-vpc-id", "", "")
 			Expect(err).To(MatchError("vpc some-vpc-id is not safe to delete; vms still exist: [NAT, bosh/0, bosh/0]"))
 		})
 
@@ -143,14 +157,14 @@ var _ = Describe("VPCStatusChecker", func() {
 				},
 			}
 
-			err := vpcStatusChecker.ValidateSafeToDelete("some-vpc-id", "")
+			err := vpcStatusChecker.ValidateSafeToDelete("some-vpc-id", "", "")
 			Expect(err).To(MatchError("vpc some-vpc-id is not safe to delete; vms still exist: [unnamed, unnamed, unnamed]"))
 		})
 
 		Describe("failure cases", func() {
 			It("returns an error when the describe instances call fails", func() {
 				ec2Client.DescribeInstancesCall.Returns.Error = errors.New("failed to describe instances")
-				err := vpcStatusChecker.ValidateSafeToDelete("some-vpc-id", "")
+				err := vpcStatusChecker.ValidateSafeToDelete("some-vpc-id", "", "")
 				Expect(err).To(MatchError("failed to describe instances"))
 			})
 		})
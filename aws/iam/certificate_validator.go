@@ -1,6 +1,8 @@
 package iam
 
 import (
+	"crypto"
+	"crypto/ecdsa"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
@@ -58,9 +60,9 @@ func (c CertificateValidator) Validate(command, certPath, keyPath, chainPath str
 		validateErrors.Add(err)
 	}
 
-	var certPool *x509.CertPool
+	var chain []*x509.Certificate
 	if chainPath != "" {
-		certPool, err = c.parseChain(chainData)
+		chain, err = c.parseChain(chainData)
 		if err != nil {
 			validateErrors.Add(err)
 		}
@@ -72,8 +74,8 @@ func (c CertificateValidator) Validate(command, certPath, keyPath, chainPath str
 		}
 	}
 
-	if certPool != nil && certificate != nil {
-		if err := c.validateCertAndChain(certificate, certPool); err != nil {
+	if chain != nil && certificate != nil {
+		if err := c.validateCertAndChain(certificate, chain); err != nil {
 			validateErrors.Add(err)
 		}
 	}
@@ -119,18 +121,40 @@ func (CertificateValidator) validateFileAndFormat(propertyName string, flagName
 	return fileData, nil
 }
 
-func (c CertificateValidator) validateCertAndKey(certificate *x509.Certificate, privateKey *rsa.PrivateKey) error {
-	publicKey := certificate.PublicKey.(*rsa.PublicKey)
-	if privateKey.PublicKey.N.Cmp(publicKey.N) != 0 || privateKey.PublicKey.E != publicKey.E {
-		return errors.New("certificate and key mismatch")
+func (c CertificateValidator) validateCertAndKey(certificate *x509.Certificate, privateKey crypto.PrivateKey) error {
+	mismatch := errors.New("certificate and key mismatch")
+
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		publicKey, ok := certificate.PublicKey.(*rsa.PublicKey)
+		if !ok || key.PublicKey.N.Cmp(publicKey.N) != 0 || key.PublicKey.E != publicKey.E {
+			return mismatch
+		}
+	case *ecdsa.PrivateKey:
+		publicKey, ok := certificate.PublicKey.(*ecdsa.PublicKey)
+		if !ok || key.Curve != publicKey.Curve || key.X.Cmp(publicKey.X) != 0 || key.Y.Cmp(publicKey.Y) != 0 {
+			return mismatch
+		}
+	default:
+		return mismatch
 	}
 
 	return nil
 }
 
-func (CertificateValidator) validateCertAndChain(certificate *x509.Certificate, certPool *x509.CertPool) error {
+// validateCertAndChain verifies that the chain links the certificate to the
+// last certificate of the chain. That certificate is trusted as the root even
+// when it is an intermediate, so chains of private certificate authorities
+// validate without their root.
+func (CertificateValidator) validateCertAndChain(certificate *x509.Certificate, chain []*x509.Certificate) error {
 	opts := x509.VerifyOptions{
-		Roots: certPool,
+		Roots:         x509.NewCertPool(),
+		Intermediates: x509.NewCertPool(),
+	}
+
+	opts.Roots.AddCert(chain[len(chain)-1])
+	for _, chainCertificate := range chain[:len(chain)-1] {
+		opts.Intermediates.AddCert(chainCertificate)
 	}
 
 	if _, err := certificate.Verify(opts); err != nil {
@@ -140,14 +164,40 @@ func (CertificateValidator) validateCertAndChain(certificate *x509.Certificate,
 	return nil
 }
 
-func (CertificateValidator) parsePrivateKey(keyData []byte) (*rsa.PrivateKey, error) {
-	pemKeyData, _ := pem.Decode(keyData)
-	privateKey, err := x509.ParsePKCS1PrivateKey(pemKeyData.Bytes)
+// parsePrivateKey accepts RSA keys in PKCS#1 or PKCS#8 encoding and ECDSA
+// keys in SEC1 or PKCS#8 encoding.
+func (CertificateValidator) parsePrivateKey(keyData []byte) (crypto.PrivateKey, error) {
+	pemKeyData, rest := pem.Decode(keyData)
+	for pemKeyData != nil && pemKeyData.Type == "EC PARAMETERS" {
+		pemKeyData, rest = pem.Decode(rest)
+	}
+
+	if pemKeyData == nil {
+		return nil, errors.New("failed to parse private key: no private key found")
+	}
+
+	var privateKey crypto.PrivateKey
+	var err error
+	switch pemKeyData.Type {
+	case "RSA PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS1PrivateKey(pemKeyData.Bytes)
+	case "EC PRIVATE KEY":
+		privateKey, err = x509.ParseECPrivateKey(pemKeyData.Bytes)
+	case "PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS8PrivateKey(pemKeyData.Bytes)
+	default:
+		return nil, fmt.Errorf("failed to parse private key: unsupported PEM block type %q", pemKeyData.Type)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse private key: %s", err)
 	}
 
-	return privateKey, nil
+	switch privateKey.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey:
+		return privateKey, nil
+	default:
+		return nil, errors.New("failed to parse private key: only RSA and ECDSA keys are supported")
+	}
 }
 
 func (CertificateValidator) parseCertificate(certificateData []byte) (*x509.Certificate, error) {
@@ -160,12 +210,29 @@ func (CertificateValidator) parseCertificate(certificateData []byte) (*x509.Cert
 	return cert, nil
 }
 
-func (CertificateValidator) parseChain(chainData []byte) (*x509.CertPool, error) {
-	roots := x509.NewCertPool()
-	ok := roots.AppendCertsFromPEM(chainData)
-	if !ok {
+func (CertificateValidator) parseChain(chainData []byte) ([]*x509.Certificate, error) {
+	var chain []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, chainData = pem.Decode(chainData)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse chain")
+		}
+		chain = append(chain, certificate)
+	}
+
+	if len(chain) == 0 {
 		return nil, fmt.Errorf("failed to parse chain")
 	}
 
-	return roots, nil
+	return chain, nil
 }
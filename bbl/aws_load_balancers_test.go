@@ -157,7 +157,7 @@ var _ = Describe("load balancers", func() {
 					session := createLBs(fakeAWSServer.URL, tempDirectory, lbCertPath, lbKeyPath, lbChainPath, "some-fake-lb-type", 1, false)
 					stderr := session.Err.Contents()
 
-					Expect(stderr).To(ContainSubstring("\"some-fake-lb-type\" is not a valid lb type, valid lb types are: concourse and cf"))
+					Expect(stderr).To(ContainSubstring("\"some-fake-lb-type\" is not a valid lb type, valid lb types are: concourse, cf and custom"))
 				})
 
 				Context("when the environment has not been provisioned", func() {
@@ -278,11 +278,11 @@ var _ = Describe("load balancers", func() {
 					Expect(fakeBOSH.GetCloudConfig()).To(MatchYAML(string(expectedCloudConfig)))
 
 					state := readStateJson(tempDirectory)
-					Expect(state.LB).NotTo(BeNil())
-					Expect(state.LB.Type).To(Equal("cf"))
-					Expect(state.LB.Cert).To(Equal(testhelpers.BBL_CERT))
-					Expect(state.LB.Key).To(Equal(testhelpers.BBL_KEY))
-					Expect(state.LB.Domain).To(Equal("cf.example.com"))
+					Expect(state.LBs).To(HaveLen(1))
+					Expect(state.LBs[0].Type).To(Equal("cf"))
+					Expect(state.LBs[0].Cert).To(Equal(testhelpers.BBL_CERT))
+					Expect(state.LBs[0].Key).To(Equal(testhelpers.BBL_KEY))
+					Expect(state.LBs[0].Domain).To(Equal("cf.example.com"))
 				})
 			})
 		})
@@ -330,9 +330,9 @@ var _ = Describe("load balancers", func() {
 
 				if terraform {
 					state := readStateJson(tempDirectory)
-					Expect(state.LB.Cert).To(Equal(testhelpers.OTHER_BBL_CERT))
-					Expect(state.LB.Key).To(Equal(testhelpers.OTHER_BBL_KEY))
-					Expect(state.LB.Chain).To(Equal(testhelpers.OTHER_BBL_CHAIN))
+					Expect(state.LBs[0].Cert).To(Equal(testhelpers.OTHER_BBL_CERT))
+					Expect(state.LBs[0].Key).To(Equal(testhelpers.OTHER_BBL_KEY))
+					Expect(state.LBs[0].Chain).To(Equal(testhelpers.OTHER_BBL_CHAIN))
 				} else {
 					certificates := fakeAWS.Certificates.All()
 					Expect(certificates).To(HaveLen(1))
@@ -387,8 +387,8 @@ var _ = Describe("load balancers", func() {
 
 				if terraform {
 					state := readStateJson(tempDirectory)
-					Expect(state.LB.Cert).To(Equal(testhelpers.BBL_CERT))
-					Expect(state.LB.Key).To(Equal(testhelpers.BBL_KEY))
+					Expect(state.LBs[0].Cert).To(Equal(testhelpers.BBL_CERT))
+					Expect(state.LBs[0].Key).To(Equal(testhelpers.BBL_KEY))
 				} else {
 					Expect(stdout).To(ContainSubstring("no updates are to be performed"))
 					stack, ok := fakeAWS.Stacks.Get("some-stack-name")
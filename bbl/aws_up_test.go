@@ -72,6 +72,21 @@ func (b *fakeBOSHDirector) ServeHTTP(responseWriter http.ResponseWriter, request
 			responseWriter.WriteHeader(0)
 			return
 		}
+
+		if request.Method == "GET" {
+			configs := []map[string]string{}
+			if cloudConfig := b.GetCloudConfig(); len(cloudConfig) > 0 {
+				configs = append(configs, map[string]string{"properties": string(cloudConfig)})
+			}
+
+			err := json.NewEncoder(responseWriter).Encode(configs)
+			if err != nil {
+				panic(err)
+			}
+
+			return
+		}
+
 		buf, err := ioutil.ReadAll(request.Body)
 		if err != nil {
 			panic(err)
@@ -700,6 +715,31 @@ var _ = Describe("bbl up aws", func() {
 				})
 			})
 
+			Context("when a resource of the stack fails to create", func() {
+				It("prints the stack events and returns the failure reason", func() {
+					fakeAWS.Stacks.SetCreateStackFailureEvents([]awsbackend.StackEvent{
+						{
+							LogicalResourceID: "BOSHEIP",
+							ResourceType:      "AWS::EC2::EIP",
+							ResourceStatus:    "CREATE_IN_PROGRESS",
+						},
+						{
+							LogicalResourceID:    "BOSHEIP",
+							ResourceType:         "AWS::EC2::EIP",
+							ResourceStatus:       "CREATE_FAILED",
+							ResourceStatusReason: "The maximum number of addresses has been reached.",
+						},
+					})
+					session := upAWS(fakeAWSServer.URL, tempDirectory, 1)
+					stdout := session.Out.Contents()
+					stderr := session.Err.Contents()
+
+					Expect(stdout).To(ContainSubstring("step: BOSHEIP (AWS::EC2::EIP) CREATE_IN_PROGRESS"))
+					Expect(stdout).To(ContainSubstring("step: BOSHEIP (AWS::EC2::EIP) CREATE_FAILED"))
+					Expect(stderr).To(ContainSubstring("  BOSHEIP (AWS::EC2::EIP) CREATE_FAILED: The maximum number of addresses has been reached."))
+				})
+			})
+
 			Context("when the bosh cli fails to create", func() {
 				It("does not re-provision stack", func() {
 					fakeBOSHCLIBackendServer.SetCreateEnvFastFail(true)
@@ -122,7 +122,25 @@ func (b *Backend) CreateStack(input *cloudformation.CreateStackInput) (*cloudfor
 	stack := Stack{
 		Name:     *input.StackName,
 		Template: *input.TemplateBody,
+		Status:   "CREATE_COMPLETE",
+		Events: []StackEvent{{
+			LogicalResourceID:    *input.StackName,
+			ResourceType:         "AWS::CloudFormation::Stack",
+			ResourceStatus:       "CREATE_IN_PROGRESS",
+			ResourceStatusReason: "User Initiated",
+		}},
+	}
+
+	if failureEvents := b.Stacks.CreateStackFailureEvents(); failureEvents != nil {
+		stack.Status = "ROLLBACK_COMPLETE"
+		stack.Events = append(stack.Events, failureEvents...)
 	}
+
+	stack.Events = append(stack.Events, StackEvent{
+		LogicalResourceID: *input.StackName,
+		ResourceType:      "AWS::CloudFormation::Stack",
+		ResourceStatus:    stack.Status,
+	})
 	atomic.AddInt64(&b.CreateStackCallCount, 1)
 	b.Stacks.Set(stack)
 
@@ -146,6 +164,17 @@ func (b *Backend) UpdateStack(input *cloudformation.UpdateStackInput) (*cloudfor
 
 	stack.WasUpdated = true
 	stack.Template = *input.TemplateBody
+	stack.Status = "UPDATE_COMPLETE"
+	stack.Events = append(stack.Events, StackEvent{
+		LogicalResourceID:    name,
+		ResourceType:         "AWS::CloudFormation::Stack",
+		ResourceStatus:       "UPDATE_IN_PROGRESS",
+		ResourceStatusReason: "User Initiated",
+	}, StackEvent{
+		LogicalResourceID: name,
+		ResourceType:      "AWS::CloudFormation::Stack",
+		ResourceStatus:    stack.Status,
+	})
 	b.Stacks.Set(stack)
 
 	return &cloudformation.UpdateStackOutput{}, nil
@@ -220,11 +249,16 @@ func (b *Backend) DescribeStacks(input *cloudformation.DescribeStacksInput) (*cl
 		}
 	}
 
+	status := stack.Status
+	if status == "" {
+		status = "CREATE_COMPLETE"
+	}
+
 	stackOutput := &cloudformation.DescribeStacksOutput{
 		Stacks: []*cloudformation.Stack{
 			{
 				StackName:   aws.String(stack.Name),
-				StackStatus: aws.String("CREATE_COMPLETE"),
+				StackStatus: aws.String(status),
 				Outputs: []*cloudformation.Output{
 					{
 						OutputKey:   aws.String("BOSHEIP"),
@@ -352,6 +386,33 @@ func (b *Backend) DescribeStacks(input *cloudformation.DescribeStacksInput) (*cl
 	return stackOutput, nil
 }
 
+func (b *Backend) DescribeStackEvents(input *cloudformation.DescribeStackEventsInput) (*cloudformation.DescribeStackEventsOutput, error) {
+	name := *input.StackName
+	stack, ok := b.Stacks.Get(name)
+	if !ok {
+		return nil, &awsfaker.ErrorResponse{
+			HTTPStatusCode:  http.StatusBadRequest,
+			AWSErrorCode:    "ValidationError",
+			AWSErrorMessage: fmt.Sprintf("Stack [%s] does not exist", name),
+		}
+	}
+
+	output := &cloudformation.DescribeStackEventsOutput{}
+	for i := len(stack.Events) - 1; i >= 0; i-- {
+		event := stack.Events[i]
+		output.StackEvents = append(output.StackEvents, &cloudformation.StackEvent{
+			EventId:              aws.String(fmt.Sprintf("%s-event-%d", name, i)),
+			StackName:            aws.String(name),
+			LogicalResourceId:    aws.String(event.LogicalResourceID),
+			ResourceType:         aws.String(event.ResourceType),
+			ResourceStatus:       aws.String(event.ResourceStatus),
+			ResourceStatusReason: aws.String(event.ResourceStatusReason),
+		})
+	}
+
+	return output, nil
+}
+
 func (b *Backend) DescribeStackResource(input *cloudformation.DescribeStackResourceInput) (*cloudformation.DescribeStackResourceOutput, error) {
 	return &cloudformation.DescribeStackResourceOutput{
 		StackResourceDetail: &cloudformation.StackResourceDetail{
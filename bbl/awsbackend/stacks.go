@@ -10,6 +10,15 @@ type Stack struct {
 	Name       string
 	Template   string
 	WasUpdated bool
+	Status     string
+	Events     []StackEvent
+}
+
+type StackEvent struct {
+	LogicalResourceID    string
+	ResourceType         string
+	ResourceStatus       string
+	ResourceStatusReason string
 }
 
 type Stacks struct {
@@ -17,7 +26,8 @@ type Stacks struct {
 	store map[string]Stack
 
 	createStack struct {
-		returns struct {
+		failureEvents []StackEvent
+		returns       struct {
 			err *awsfaker.ErrorResponse
 		}
 	}
@@ -84,3 +94,17 @@ func (s *Stacks) DeleteStackReturnError() *awsfaker.ErrorResponse {
 
 	return s.deleteStack.returns.err
 }
+
+func (s *Stacks) SetCreateStackFailureEvents(events []StackEvent) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.createStack.failureEvents = events
+}
+
+func (s *Stacks) CreateStackFailureEvents() []StackEvent {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.createStack.failureEvents
+}
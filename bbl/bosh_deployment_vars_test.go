@@ -18,17 +18,18 @@ import (
 )
 
 type gcpBOSHDeploymentVars struct {
-	InternalCIDR       string   `yaml:"internal_cidr"`
-	InternalGateway    string   `yaml:"internal_gw"`
-	InternalIP         string   `yaml:"internal_ip"`
-	DirectorName       string   `yaml:"director_name"`
-	ExternalIP         string   `yaml:"external_ip"`
-	Zone               string   `yaml:"zone"`
-	Network            string   `yaml:"network"`
-	Subnetwork         string   `yaml:"subnetwork"`
-	Tags               []string `yaml:"tags"`
-	ProjectID          string   `yaml:"project_id"`
-	GCPCredentialsJSON string   `yaml:"gcp_credentials_json"`
+	InternalCIDR           string   `yaml:"internal_cidr"`
+	InternalGateway        string   `yaml:"internal_gw"`
+	InternalIP             string   `yaml:"internal_ip"`
+	DirectorName           string   `yaml:"director_name"`
+	ExternalIP             string   `yaml:"external_ip"`
+	Zone                   string   `yaml:"zone"`
+	Network                string   `yaml:"network"`
+	Subnetwork             string   `yaml:"subnetwork"`
+	Tags                   []string `yaml:"tags"`
+	ProjectID              string   `yaml:"project_id"`
+	GCPCredentialsJSON     string   `yaml:"gcp_credentials_json"`
+	DirectorServiceAccount string   `yaml:"director_service_account"`
 }
 
 type awsBOSHDeploymentVars struct {
@@ -82,8 +83,10 @@ var _ = Describe("bosh-deployment-vars", func() {
 	})
 
 	Context("GCP", func() {
+		var upArgs []string
+
 		BeforeEach(func() {
-			args := []string{
+			upArgs = []string{
 				"--state-dir", tempDirectory,
 				"--debug",
 				"up",
@@ -94,10 +97,11 @@ var _ = Describe("bosh-deployment-vars", func() {
 				"--gcp-zone", "some-zone",
 				"--gcp-region", "some-region",
 			}
-			executeCommand(args, 0)
 		})
 
 		It("prints a bosh create-env compatible vars-file", func() {
+			executeCommand(upArgs, 0)
+
 			args := []string{
 				"--state-dir", tempDirectory,
 				"bosh-deployment-vars",
@@ -107,11 +111,6 @@ var _ = Describe("bosh-deployment-vars", func() {
 			var vars gcpBOSHDeploymentVars
 			yaml.Unmarshal(session.Out.Contents(), &vars)
 
-			var realAccountKey map[interface{}]interface{}
-			var returnedAccountKey map[interface{}]interface{}
-			yaml.Unmarshal([]byte(serviceAccountKey), &realAccountKey)
-			yaml.Unmarshal([]byte(vars.GCPCredentialsJSON), &returnedAccountKey)
-
 			Expect(vars.InternalCIDR).To(Equal("10.0.0.0/24"))
 			Expect(vars.InternalGateway).To(Equal("10.0.0.1"))
 			Expect(vars.InternalIP).To(Equal("10.0.0.6"))
@@ -122,7 +121,32 @@ var _ = Describe("bosh-deployment-vars", func() {
 			Expect(vars.Subnetwork).To(Equal("some-subnetwork-name"))
 			Expect(vars.Tags).To(Equal([]string{"some-bosh-tag", "some-internal-tag"}))
 			Expect(vars.ProjectID).To(Equal("some-project-id"))
-			Expect(returnedAccountKey).To(Equal(realAccountKey))
+			Expect(vars.GCPCredentialsJSON).To(BeEmpty())
+			Expect(vars.DirectorServiceAccount).To(Equal("some-director-service-account-email"))
+		})
+
+		Context("when the director does not have a service account", func() {
+			It("prints the json key", func() {
+				executeCommand(append(upArgs, "--gcp-no-director-service-account"), 0)
+
+				args := []string{
+					"--state-dir", tempDirectory,
+					"bosh-deployment-vars",
+				}
+				session := executeCommand(args, 0)
+
+				var vars gcpBOSHDeploymentVars
+				yaml.Unmarshal(session.Out.Contents(), &vars)
+
+				var realAccountKey map[interface{}]interface{}
+				var returnedAccountKey map[interface{}]interface{}
+				yaml.Unmarshal([]byte(serviceAccountKey), &realAccountKey)
+				yaml.Unmarshal([]byte(vars.GCPCredentialsJSON), &returnedAccountKey)
+
+				Expect(vars.ProjectID).To(Equal("some-project-id"))
+				Expect(returnedAccountKey).To(Equal(realAccountKey))
+				Expect(vars.DirectorServiceAccount).To(BeEmpty())
+			})
 		})
 	})
 
@@ -8,7 +8,7 @@ import (
 )
 
 const (
-	defaultVersion = "0.8.6"
+	defaultVersion = "0.10.0"
 )
 
 type dataBackend struct {
@@ -75,6 +75,8 @@ func (b *Backend) defaultHandler(responseWriter http.ResponseWriter, request *ht
 		b.handleOutput(responseWriter, "some-internal-tag")
 	case "/output/bosh_open_tag_name":
 		b.handleOutput(responseWriter, "some-bosh-tag")
+	case "/output/director_service_account_email":
+		b.handleOutput(responseWriter, "some-director-service-account-email")
 	case "/output/concourse_target_pool":
 		b.handleOutput(responseWriter, "concourse-target-pool")
 	case "/output/router_backend_service":
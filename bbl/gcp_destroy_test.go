@@ -244,9 +244,9 @@ director_ssl:
 		})
 	})
 
-	Context("when the terraform version is <0.8.5", func() {
+	Context("when the terraform version is <0.10.0", func() {
 		BeforeEach(func() {
-			fakeTerraformBackendServer.SetVersion("0.8.4")
+			fakeTerraformBackendServer.SetVersion("0.9.11")
 		})
 
 		It("fast fails with a helpful error message", func() {
@@ -257,7 +257,7 @@ director_ssl:
 
 			session := executeCommand(args, 1)
 
-			Expect(session.Err.Contents()).To(ContainSubstring("Terraform version must be at least v0.8.5"))
+			Expect(session.Err.Contents()).To(ContainSubstring("Terraform version must be at least v0.10.0"))
 		})
 	})
 
@@ -84,10 +84,10 @@ var _ = Describe("load balancers", func() {
 			Expect(fakeBOSH.GetCloudConfig()).To(MatchYAML(string(contents)))
 
 			state := readStateJson(tempDirectory)
-			Expect(state.LB).NotTo(BeNil())
-			Expect(state.LB.Type).To(Equal("concourse"))
-			Expect(state.LB.Cert).To(Equal(""))
-			Expect(state.LB.Key).To(Equal(""))
+			Expect(state.LBs).To(HaveLen(1))
+			Expect(state.LBs[0].Type).To(Equal("concourse"))
+			Expect(state.LBs[0].Cert).To(Equal(""))
+			Expect(state.LBs[0].Key).To(Equal(""))
 		})
 
 		Context("cf lb", func() {
@@ -132,11 +132,11 @@ var _ = Describe("load balancers", func() {
 				Expect(fakeBOSH.GetCloudConfig()).To(MatchYAML(string(contents)))
 
 				state := readStateJson(tempDirectory)
-				Expect(state.LB).NotTo(BeNil())
-				Expect(state.LB.Type).To(Equal("cf"))
-				Expect(state.LB.Cert).To(Equal("cert-contents"))
-				Expect(state.LB.Key).To(Equal("key-contents"))
-				Expect(state.LB.Domain).To(Equal("cf.example.com"))
+				Expect(state.LBs).To(HaveLen(1))
+				Expect(state.LBs[0].Type).To(Equal("cf"))
+				Expect(state.LBs[0].Cert).To(Equal("cert-contents"))
+				Expect(state.LBs[0].Key).To(Equal("key-contents"))
+				Expect(state.LBs[0].Domain).To(Equal("cf.example.com"))
 			})
 
 			It("creates and attaches only a cf lb type when domain is not provided", func() {
@@ -153,17 +153,17 @@ var _ = Describe("load balancers", func() {
 				Expect(fakeBOSH.GetCloudConfig()).To(MatchYAML(string(contents)))
 
 				state := readStateJson(tempDirectory)
-				Expect(state.LB).NotTo(BeNil())
-				Expect(state.LB.Type).To(Equal("cf"))
-				Expect(state.LB.Cert).To(Equal("cert-contents"))
-				Expect(state.LB.Key).To(Equal("key-contents"))
-				Expect(state.LB.Domain).To(Equal(""))
+				Expect(state.LBs).To(HaveLen(1))
+				Expect(state.LBs[0].Type).To(Equal("cf"))
+				Expect(state.LBs[0].Cert).To(Equal("cert-contents"))
+				Expect(state.LBs[0].Key).To(Equal("key-contents"))
+				Expect(state.LBs[0].Domain).To(Equal(""))
 			})
 
 			Describe("failure cases", func() {
-				Context("when the terraform version is <0.8.5", func() {
+				Context("when the terraform version is <0.10.0", func() {
 					BeforeEach(func() {
-						fakeTerraformBackendServer.SetVersion("0.8.4")
+						fakeTerraformBackendServer.SetVersion("0.9.11")
 					})
 
 					It("fast fails with a helpful error message", func() {
@@ -177,7 +177,7 @@ var _ = Describe("load balancers", func() {
 
 						session := executeCommand(args, 1)
 
-						Expect(session.Err.Contents()).To(ContainSubstring("Terraform version must be at least v0.8.5"))
+						Expect(session.Err.Contents()).To(ContainSubstring("Terraform version must be at least v0.10.0"))
 					})
 				})
 
@@ -385,8 +385,8 @@ var _ = Describe("load balancers", func() {
 				executeCommand(args, 0)
 
 				state := readStateJson(tempDirectory)
-				Expect(state.LB.Cert).To(Equal(string(newCert)))
-				Expect(state.LB.Key).To(Equal(string(newKey)))
+				Expect(state.LBs[0].Cert).To(Equal(string(newCert)))
+				Expect(state.LBs[0].Key).To(Equal(string(newKey)))
 			})
 
 			It("does nothing if the certificate is unchanged", func() {
@@ -400,8 +400,8 @@ var _ = Describe("load balancers", func() {
 				executeCommand(args, 0)
 
 				state := readStateJson(tempDirectory)
-				Expect(state.LB.Cert).To(Equal(string(cert)))
-				Expect(state.LB.Key).To(Equal(string(key)))
+				Expect(state.LBs[0].Cert).To(Equal(string(cert)))
+				Expect(state.LBs[0].Key).To(Equal(string(key)))
 			})
 		})
 
@@ -488,8 +488,10 @@ var _ = Describe("load balancers", func() {
 						BOSH: storage.BOSH{
 							DirectorAddress: "127.2.5.4",
 						},
-						LB: storage.LB{
-							Type: "cf",
+						LBs: []storage.LB{
+							{
+								Type: "cf",
+							},
 						},
 					}, tempDirectory)
 
@@ -778,10 +780,10 @@ var _ = Describe("load balancers", func() {
 			executeCommand(args, 0)
 
 			state := readStateJson(tempDirectory)
-			Expect(state.LB).NotTo(BeNil())
-			Expect(state.LB.Type).To(Equal("concourse"))
-			Expect(state.LB.Cert).To(Equal(""))
-			Expect(state.LB.Key).To(Equal(""))
+			Expect(state.LBs).To(HaveLen(1))
+			Expect(state.LBs[0].Type).To(Equal("concourse"))
+			Expect(state.LBs[0].Cert).To(Equal(""))
+			Expect(state.LBs[0].Key).To(Equal(""))
 		})
 
 		Context("when a cf lb exists", func() {
@@ -846,8 +848,8 @@ var _ = Describe("load balancers", func() {
 				executeCommand(args, 0)
 
 				state := readStateJson(tempDirectory)
-				Expect(state.LB.Cert).To(Equal(string(newCert)))
-				Expect(state.LB.Key).To(Equal(string(newKey)))
+				Expect(state.LBs[0].Cert).To(Equal(string(newCert)))
+				Expect(state.LBs[0].Key).To(Equal(string(newKey)))
 			})
 
 			It("does nothing if the certificate is unchanged", func() {
@@ -861,8 +863,8 @@ var _ = Describe("load balancers", func() {
 				executeCommand(args, 0)
 
 				state := readStateJson(tempDirectory)
-				Expect(state.LB.Cert).To(Equal(string(cert)))
-				Expect(state.LB.Key).To(Equal(string(key)))
+				Expect(state.LBs[0].Cert).To(Equal(string(cert)))
+				Expect(state.LBs[0].Key).To(Equal(string(key)))
 			})
 		})
 
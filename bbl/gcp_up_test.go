@@ -144,9 +144,9 @@ var _ = Describe("bbl up gcp", func() {
 		})
 	})
 
-	Context("when the terraform version is <0.8.5", func() {
+	Context("when the terraform version is <0.10.0", func() {
 		BeforeEach(func() {
-			fakeTerraformBackendServer.SetVersion("0.8.4")
+			fakeTerraformBackendServer.SetVersion("0.9.11")
 		})
 
 		It("fast fails with a helpful error message", func() {
@@ -163,31 +163,7 @@ var _ = Describe("bbl up gcp", func() {
 
 			session := executeCommand(args, 1)
 
-			Expect(session.Err.Contents()).To(ContainSubstring("Terraform version must be at least v0.8.5"))
-		})
-	})
-
-	Context("when the terraform version is 0.9.0", func() {
-		BeforeEach(func() {
-			fakeTerraformBackendServer.SetVersion("0.9.0")
-		})
-
-		It("fast fails with a helpful error message", func() {
-			args := []string{
-				"--state-dir", tempDirectory,
-				"--debug",
-				"up",
-				"--iaas", "gcp",
-				"--name", "some-bbl-env",
-				"--gcp-service-account-key", serviceAccountKeyPath,
-				"--gcp-project-id", "some-project-id",
-				"--gcp-zone", "some-zone",
-				"--gcp-region", "us-west1",
-			}
-
-			session := executeCommand(args, 1)
-
-			Expect(session.Err.Contents()).To(ContainSubstring("Version 0.9.0 of terraform is incompatible with bbl, please try a later version."))
+			Expect(session.Err.Contents()).To(ContainSubstring("Terraform version must be at least v0.10.0"))
 		})
 	})
 
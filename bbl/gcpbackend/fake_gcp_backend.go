@@ -67,6 +67,10 @@ func (g *GCPBackend) StartFakeGCPBackend() (*httptest.Server, string) {
 				w.Write([]byte(`{}`))
 			}
 			return
+		case "/some-project-id/zones":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(zonesOutput(req.URL.Query().Get("filter"))))
+			return
 		case "/some-project-id/global/networks":
 			w.WriteHeader(http.StatusOK)
 			networkName := strings.Split(req.URL.Query().Get("filter"), " ")[2]
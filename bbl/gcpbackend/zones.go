@@ -0,0 +1,46 @@
+package gcpbackend
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type zone struct {
+	Name   string `json:"name"`
+	Region string `json:"region"`
+	Status string `json:"status"`
+}
+
+var regionZones = map[string][]zone{
+	"us-east1": {
+		{Name: "us-east1-a", Status: "DOWN"},
+		{Name: "us-east1-b", Status: "UP"},
+		{Name: "us-east1-c", Status: "UP"},
+		{Name: "us-east1-d", Status: "UP"},
+	},
+}
+
+// zonesOutput serves zones.list for the region named in the filter. Regions
+// without an explicit entry get three zones suffixed a to c.
+func zonesOutput(filter string) string {
+	region := filter[strings.LastIndex(filter, "/")+1:]
+
+	zones, ok := regionZones[region]
+	if !ok {
+		for _, suffix := range []string{"a", "b", "c"} {
+			zones = append(zones, zone{Name: fmt.Sprintf("%s-%s", region, suffix), Status: "UP"})
+		}
+	}
+
+	for i := range zones {
+		zones[i].Region = fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/some-project-id/regions/%s", region)
+	}
+
+	output, err := json.Marshal(map[string]interface{}{"items": zones})
+	if err != nil {
+		panic(err)
+	}
+
+	return string(output)
+}
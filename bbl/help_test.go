@@ -111,6 +111,8 @@ var _ = Describe("bbl", func() {
 			Entry("Director Password", "director-password", "Prints BOSH director password", []string{"director-password", "--help"}),
 			Entry("Director CA Cert", "director-ca-cert", "Prints BOSH director CA certificate", []string{"help", "director-ca-cert"}),
 			Entry("Director CA Cert", "director-ca-cert", "Prints BOSH director CA certificate", []string{"director-ca-cert", "--help"}),
+			Entry("LB CA", "lb-ca", "Prints the CA of self-signed load balancer certificates", []string{"help", "lb-ca"}),
+			Entry("LB CA", "lb-ca", "Prints the CA of self-signed load balancer certificates", []string{"lb-ca", "--help"}),
 			Entry("ENV ID", "env-id", "environment ID", []string{"help", "env-id"}),
 			Entry("ENV ID", "env-id", "environment ID", []string{"env-id", "--help"}),
 			Entry("Help", "help", "Prints helpful message for the given command", []string{"help", "help"}),
@@ -119,6 +121,8 @@ var _ = Describe("bbl", func() {
 			Entry("Latest Error", "latest-error", "Prints the output from the latest call to terraform", []string{"latest-error", "--help"}),
 			Entry("LBs", "lbs", "Prints attached load balancer(s)", []string{"help", "lbs"}),
 			Entry("LBs", "lbs", "Prints attached load balancer(s)", []string{"lbs", "--help"}),
+			Entry("Certs", "certs", "Prints certificates managed by bbl", []string{"help", "certs"}),
+			Entry("Certs", "certs", "Prints certificates managed by bbl", []string{"certs", "--help"}),
 			Entry("SSH Key", "ssh-key", "Prints SSH private key", []string{"help", "ssh-key"}),
 			Entry("SSH Key", "ssh-key", "Prints SSH private key", []string{"ssh-key", "--help"}),
 		)
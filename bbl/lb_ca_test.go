@@ -0,0 +1,73 @@
+package main_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/onsi/gomega/gexec"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("lb-ca", func() {
+	var (
+		tempDirectory string
+		args          []string
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		tempDirectory, err = ioutil.TempDir("", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		args = []string{
+			"--state-dir", tempDirectory,
+			"lb-ca",
+		}
+	})
+
+	Context("when an lb has a self-signed certificate", func() {
+		BeforeEach(func() {
+			state := []byte(`{
+				"version": 3,
+				"lbs": [{
+					"type": "cf",
+					"cert": "some-cert",
+					"key": "some-key",
+					"ca": "some-lb-ca-contents"
+				}]
+			}`)
+			err := ioutil.WriteFile(filepath.Join(tempDirectory, storage.StateFileName), state, os.ModePerm)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("prints the CA used to sign the lb certificate", func() {
+			session, err := gexec.Start(exec.Command(pathToBBL, args...), GinkgoWriter, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session.Out.Contents()).To(ContainSubstring("some-lb-ca-contents"))
+		})
+	})
+
+	Context("when no lb has a self-signed certificate", func() {
+		BeforeEach(func() {
+			state := []byte(`{"version":3}`)
+			err := ioutil.WriteFile(filepath.Join(tempDirectory, storage.StateFileName), state, os.ModePerm)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns a non zero exit code and prints a helpful error message", func() {
+			session, err := gexec.Start(exec.Command(pathToBBL, args...), GinkgoWriter, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(session.Err.Contents()).To(ContainSubstring("Could not retrieve lb ca"))
+		})
+	})
+})
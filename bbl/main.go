@@ -11,6 +11,7 @@ import (
 
 	yaml "gopkg.in/yaml.v2"
 
+	"github.com/square/certstrap/pkix"
 	"golang.org/x/crypto/ssh"
 
 	"github.com/cloudfoundry/bosh-bootloader/application"
@@ -26,6 +27,7 @@ import (
 	"github.com/cloudfoundry/bosh-bootloader/gcp"
 	"github.com/cloudfoundry/bosh-bootloader/helpers"
 	"github.com/cloudfoundry/bosh-bootloader/keypair"
+	"github.com/cloudfoundry/bosh-bootloader/ssl"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 	"github.com/cloudfoundry/bosh-bootloader/terraform"
 
@@ -56,17 +58,22 @@ func main() {
 		commands.DirectorUsernameCommand:   nil,
 		commands.DirectorPasswordCommand:   nil,
 		commands.DirectorCACertCommand:     nil,
+		commands.LBCACommand:               nil,
 		commands.SSHKeyCommand:             nil,
 		commands.CreateLBsCommand:          nil,
 		commands.UpdateLBsCommand:          nil,
 		commands.DeleteLBsCommand:          nil,
 		commands.LBsCommand:                nil,
+		commands.CertsCommand:              nil,
 		commands.EnvIDCommand:              nil,
 		commands.LatestErrorCommand:        nil,
 		commands.PrintEnvCommand:           nil,
 		commands.CloudConfigCommand:        nil,
+		commands.RuntimeConfigCommand:      nil,
+		commands.CPIConfigCommand:          nil,
 		commands.BOSHDeploymentVarsCommand: nil,
 		commands.RotateCommand:             nil,
+		commands.MigrateToTerraformCommand: nil,
 	}
 
 	// Utilities
@@ -95,6 +102,10 @@ func main() {
 	awsConfiguration := aws.Config{
 		AccessKeyID:      configuration.State.AWS.AccessKeyID,
 		SecretAccessKey:  configuration.State.AWS.SecretAccessKey,
+		SessionToken:     configuration.State.AWS.SessionToken,
+		Profile:          configuration.State.AWS.Profile,
+		AssumeRoleARN:    configuration.State.AWS.AssumeRoleARN,
+		MFASerial:        configuration.State.AWS.MFASerial,
 		Region:           configuration.State.AWS.Region,
 		EndpointOverride: configuration.Global.EndpointOverride,
 	}
@@ -125,7 +136,7 @@ func main() {
 	gcpKeyPairDeleter := gcp.NewKeyPairDeleter(gcpClientProvider, logger)
 	gcpNetworkInstancesChecker := gcp.NewNetworkInstancesChecker(gcpClientProvider)
 	gcpKeyPairManager := gcpkeypair.NewManager(gcpKeyPairUpdater, gcpKeyPairDeleter, gcpClientProvider)
-	zones := gcp.NewZones()
+	zones := gcp.NewZones(gcpClientProvider)
 
 	// EnvID
 	envIDManager := helpers.NewEnvIDManager(envIDGenerator, gcpClientProvider, infrastructureManager)
@@ -138,11 +149,11 @@ func main() {
 
 	terraformCmd := terraform.NewCmd(os.Stderr, terraformOutputBuffer)
 	terraformExecutor := terraform.NewExecutor(terraformCmd, configuration.Global.Debug)
-	gcpTemplateGenerator := gcpterraform.NewTemplateGenerator(zones)
+	gcpTemplateGenerator := gcpterraform.NewTemplateGenerator()
 	gcpInputGenerator := gcpterraform.NewInputGenerator()
 	gcpOutputGenerator := gcpterraform.NewOutputGenerator(terraformExecutor)
 	awsTemplateGenerator := awsterraform.NewTemplateGenerator()
-	awsInputGenerator := awsterraform.NewInputGenerator(availabilityZoneRetriever)
+	awsInputGenerator := awsterraform.NewInputGenerator(availabilityZoneRetriever, clientProvider)
 	awsOutputGenerator := awsterraform.NewOutputGenerator(terraformExecutor)
 	templateGenerator := terraform.NewTemplateGenerator(gcpTemplateGenerator, awsTemplateGenerator)
 	inputGenerator := terraform.NewInputGenerator(gcpInputGenerator, awsInputGenerator)
@@ -160,7 +171,7 @@ func main() {
 	boshCommand := bosh.NewCmd(os.Stderr)
 	boshExecutor := bosh.NewExecutor(boshCommand, ioutil.TempDir, ioutil.ReadFile, yaml.Unmarshal, json.Unmarshal,
 		json.Marshal, ioutil.WriteFile)
-	boshManager := bosh.NewManager(boshExecutor, terraformManager, stackManager, logger)
+	boshManager := bosh.NewManager(boshExecutor, terraformManager, stackManager, clientProvider, logger)
 	boshClientProvider := bosh.NewClientProvider()
 
 	// Environment Validators
@@ -173,13 +184,14 @@ func main() {
 	awsTerraformOpsGenerator := awscloudconfig.NewTerraformOpsGenerator(availabilityZoneRetriever, terraformManager)
 	gcpOpsGenerator := gcpcloudconfig.NewOpsGenerator(terraformManager, zones)
 	cloudConfigOpsGenerator := cloudconfig.NewOpsGenerator(awsCloudFormationOpsGenerator, awsTerraformOpsGenerator, gcpOpsGenerator)
-	cloudConfigManager := cloudconfig.NewManager(logger, boshCommand, cloudConfigOpsGenerator, boshClientProvider)
+	cloudConfigManager := cloudconfig.NewManager(logger, cloudConfigOpsGenerator, boshClientProvider, os.Stdin, configuration.Global.ConfirmCloudConfig)
 
 	// Subcommands
 	awsUp := commands.NewAWSUp(
 		awsCredentialValidator, infrastructureManager, keyPairManager, boshManager,
 		availabilityZoneRetriever, certificateDescriber,
-		cloudConfigManager, stateStore, clientProvider, envIDManager, terraformManager, awsBrokenEnvironmentValidator)
+		cloudConfigManager, stateStore, clientProvider, envIDManager, terraformManager, awsBrokenEnvironmentValidator,
+		logger)
 
 	awsCreateLBs := commands.NewAWSCreateLBs(
 		logger, awsCredentialValidator, certificateManager, infrastructureManager,
@@ -209,9 +221,10 @@ func main() {
 		Logger:             logger,
 		EnvIDManager:       envIDManager,
 		CloudConfigManager: cloudConfigManager,
+		Zones:              zones,
 	})
 
-	gcpCreateLBs := commands.NewGCPCreateLBs(terraformManager, cloudConfigManager, stateStore, logger, gcpEnvironmentValidator)
+	gcpCreateLBs := commands.NewGCPCreateLBs(terraformManager, cloudConfigManager, stateStore, logger, gcpEnvironmentValidator, zones, uuidGenerator)
 
 	gcpLBs := commands.NewGCPLBs(terraformManager, logger)
 
@@ -227,21 +240,28 @@ func main() {
 		stateStore, stateValidator, terraformManager, gcpNetworkInstancesChecker,
 	)
 	commandSet[commands.DownCommand] = commandSet[commands.DestroyCommand]
-	commandSet[commands.CreateLBsCommand] = commands.NewCreateLBs(awsCreateLBs, gcpCreateLBs, stateValidator, boshManager)
+	keyPairGenerator := ssl.NewKeyPairGenerator(rsa.GenerateKey, pkix.CreateCertificateAuthority, pkix.CreateCertificateSigningRequest, pkix.CreateCertificateHost)
+	commandSet[commands.CreateLBsCommand] = commands.NewCreateLBs(awsCreateLBs, gcpCreateLBs, stateValidator, boshManager, keyPairGenerator)
 	commandSet[commands.UpdateLBsCommand] = commands.NewUpdateLBs(awsUpdateLBs, gcpUpdateLBs, certificateValidator, stateValidator, logger, boshManager)
 	commandSet[commands.DeleteLBsCommand] = commands.NewDeleteLBs(gcpDeleteLBs, awsDeleteLBs, logger, stateValidator, boshManager)
 	commandSet[commands.LBsCommand] = commands.NewLBs(gcpLBs, awsLBs, stateValidator, logger)
+	commandSet[commands.CertsCommand] = commands.NewCerts(logger, stateValidator, certificateDescriber)
 	commandSet[commands.DirectorAddressCommand] = commands.NewStateQuery(logger, stateValidator, terraformManager, infrastructureManager, commands.DirectorAddressPropertyName)
 	commandSet[commands.DirectorUsernameCommand] = commands.NewStateQuery(logger, stateValidator, terraformManager, infrastructureManager, commands.DirectorUsernamePropertyName)
 	commandSet[commands.DirectorPasswordCommand] = commands.NewStateQuery(logger, stateValidator, terraformManager, infrastructureManager, commands.DirectorPasswordPropertyName)
 	commandSet[commands.DirectorCACertCommand] = commands.NewStateQuery(logger, stateValidator, terraformManager, infrastructureManager, commands.DirectorCACertPropertyName)
+	commandSet[commands.LBCACommand] = commands.NewStateQuery(logger, stateValidator, terraformManager, infrastructureManager, commands.LBCAPropertyName)
 	commandSet[commands.SSHKeyCommand] = commands.NewStateQuery(logger, stateValidator, terraformManager, infrastructureManager, commands.SSHKeyPropertyName)
 	commandSet[commands.EnvIDCommand] = commands.NewStateQuery(logger, stateValidator, terraformManager, infrastructureManager, commands.EnvIDPropertyName)
 	commandSet[commands.LatestErrorCommand] = commands.NewLatestError(logger)
 	commandSet[commands.PrintEnvCommand] = commands.NewPrintEnv(logger, stateValidator, terraformManager, infrastructureManager)
 	commandSet[commands.CloudConfigCommand] = commands.NewCloudConfig(logger, stateValidator, cloudConfigManager)
+	commandSet[commands.RuntimeConfigCommand] = commands.NewRuntimeConfig(logger, stateValidator)
+	commandSet[commands.CPIConfigCommand] = commands.NewCPIConfig(logger, stateValidator)
 	commandSet[commands.BOSHDeploymentVarsCommand] = commands.NewBOSHDeploymentVars(logger, boshManager)
 	commandSet[commands.RotateCommand] = commands.NewRotate(stateStore, keyPairManager, boshManager)
+	commandSet[commands.MigrateToTerraformCommand] = commands.NewMigrateToTerraform(awsCredentialValidator, infrastructureManager,
+		terraformManager, boshManager, certificateDescriber, certificateValidator, availabilityZoneRetriever, stateStore, logger)
 
 	app := application.New(commandSet, configuration, stateStore, usage)
 
@@ -3,48 +3,155 @@ package bosh
 import (
 	"bytes"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	taskPollInterval = 2 * time.Second
+	taskTimeout      = 30 * time.Minute
 )
 
 type Client interface {
-	UpdateCloudConfig(yaml []byte) error
 	Info() (Info, error)
+	UpdateCloudConfig(yaml []byte) error
+	CloudConfig() (string, error)
+	UpdateRuntimeConfig(name string, yaml []byte) error
+	RuntimeConfig(name string) (string, error)
+	UpdateCPIConfig(yaml []byte) error
+	CPIConfig() (string, error)
+	Deployments() ([]Deployment, error)
+	DeleteDeployment(name string) error
+	Task(id int) (Task, error)
+	WaitForTask(id int) (Task, error)
+	Stemcells() ([]Stemcell, error)
+	Releases() ([]Release, error)
 }
 
 type Info struct {
+	Name               string             `json:"name"`
+	UUID               string             `json:"uuid"`
+	Version            string             `json:"version"`
+	UserAuthentication UserAuthentication `json:"user_authentication"`
+}
+
+type UserAuthentication struct {
+	Type    string `json:"type"`
+	Options struct {
+		URL string `json:"url"`
+	} `json:"options"`
+}
+
+type Deployment struct {
+	Name        string        `json:"name"`
+	CloudConfig string        `json:"cloud_config"`
+	Releases    []NameVersion `json:"releases"`
+	Stemcells   []NameVersion `json:"stemcells"`
+}
+
+type NameVersion struct {
 	Name    string `json:"name"`
-	UUID    string `json:"uuid"`
 	Version string `json:"version"`
 }
 
+type Task struct {
+	ID          int    `json:"id"`
+	State       string `json:"state"`
+	Description string `json:"description"`
+	Result      string `json:"result"`
+	User        string `json:"user"`
+	Deployment  string `json:"deployment"`
+}
+
+type Stemcell struct {
+	Name            string `json:"name"`
+	OperatingSystem string `json:"operating_system"`
+	Version         string `json:"version"`
+	CID             string `json:"cid"`
+	Deployments     []struct {
+		Name string `json:"name"`
+	} `json:"deployments"`
+}
+
+type Release struct {
+	Name            string           `json:"name"`
+	ReleaseVersions []ReleaseVersion `json:"release_versions"`
+}
+
+type ReleaseVersion struct {
+	Version            string `json:"version"`
+	CommitHash         string `json:"commit_hash"`
+	UncommittedChanges bool   `json:"uncommitted_changes"`
+	CurrentlyDeployed  bool   `json:"currently_deployed"`
+}
+
+type config struct {
+	Properties string `json:"properties"`
+}
+
+type uaaToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
 type client struct {
 	directorAddress string
 	username        string
 	password        string
 	httpClient      *http.Client
+	caCertErr       error
+
+	mutex          sync.Mutex
+	info           *Info
+	token          string
+	tokenExpiresAt time.Time
 }
 
-func NewClient(directorAddress, username, password string) Client {
+func NewClient(directorAddress, username, password, caCert string) Client {
+	var caCertErr error
+
+	tlsConfig := &tls.Config{}
+	if caCert != "" {
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM([]byte(caCert)) {
+			caCertErr = errors.New("director ca cert does not contain a valid PEM certificate")
+		}
+		tlsConfig.RootCAs = certPool
+	}
+
 	httpClient := &http.Client{
 		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
+			TLSClientConfig: tlsConfig,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
 		},
 	}
 
-	return client{
+	return &client{
 		directorAddress: directorAddress,
 		username:        username,
 		password:        password,
 		httpClient:      httpClient,
+		caCertErr:       caCertErr,
 	}
 }
 
-func (c client) Info() (Info, error) {
+func (c *client) Info() (Info, error) {
+	if c.caCertErr != nil {
+		return Info{}, c.caCertErr
+	}
+
 	request, err := http.NewRequest("GET", fmt.Sprintf("%s/info", c.directorAddress), strings.NewReader(""))
 	if err != nil {
 		return Info{}, err
@@ -54,9 +161,10 @@ func (c client) Info() (Info, error) {
 	if err != nil {
 		return Info{}, err
 	}
+	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		return Info{}, fmt.Errorf("unexpected http response %d %s", response.StatusCode, http.StatusText(response.StatusCode))
+		return Info{}, unexpectedResponse(response)
 	}
 
 	var info Info
@@ -67,22 +175,259 @@ func (c client) Info() (Info, error) {
 	return info, nil
 }
 
-func (c client) UpdateCloudConfig(yaml []byte) error {
-	request, err := http.NewRequest("POST", fmt.Sprintf("%s/cloud_configs", c.directorAddress), bytes.NewBuffer(yaml))
+func (c *client) UpdateCloudConfig(yaml []byte) error {
+	return c.updateConfig("/cloud_configs", yaml)
+}
+
+func (c *client) CloudConfig() (string, error) {
+	return c.latestConfig("/cloud_configs?limit=1")
+}
+
+func (c *client) UpdateRuntimeConfig(name string, yaml []byte) error {
+	path := "/runtime_configs"
+	if name != "" {
+		path = fmt.Sprintf("%s?name=%s", path, url.QueryEscape(name))
+	}
+
+	return c.updateConfig(path, yaml)
+}
+
+func (c *client) RuntimeConfig(name string) (string, error) {
+	path := "/runtime_configs?limit=1"
+	if name != "" {
+		path = fmt.Sprintf("%s&name=%s", path, url.QueryEscape(name))
+	}
+
+	return c.latestConfig(path)
+}
+
+func (c *client) UpdateCPIConfig(yaml []byte) error {
+	return c.updateConfig("/cpi_configs", yaml)
+}
+
+func (c *client) CPIConfig() (string, error) {
+	return c.latestConfig("/cpi_configs?limit=1")
+}
+
+func (c *client) Deployments() ([]Deployment, error) {
+	var deployments []Deployment
+	if err := c.getJSON("/deployments", &deployments); err != nil {
+		return nil, err
+	}
+
+	return deployments, nil
+}
+
+func (c *client) DeleteDeployment(name string) error {
+	response, err := c.do("DELETE", fmt.Sprintf("/deployments/%s", url.PathEscape(name)), "", nil)
 	if err != nil {
 		return err
 	}
-	request.Header.Set("Content-Type", "text/yaml")
-	request.SetBasicAuth(c.username, c.password)
+	defer response.Body.Close()
 
-	response, err := c.httpClient.Do(request)
+	if response.StatusCode != http.StatusFound {
+		return unexpectedResponse(response)
+	}
+
+	taskID, err := taskIDFromLocation(response.Header.Get("Location"))
+	if err != nil {
+		return err
+	}
+
+	task, err := c.WaitForTask(taskID)
 	if err != nil {
 		return err
 	}
 
+	if task.State != "done" {
+		return fmt.Errorf("task %d %s: %s", task.ID, task.State, task.Result)
+	}
+
+	return nil
+}
+
+func (c *client) Task(id int) (Task, error) {
+	var task Task
+	if err := c.getJSON(fmt.Sprintf("/tasks/%d", id), &task); err != nil {
+		return Task{}, err
+	}
+
+	return task, nil
+}
+
+func (c *client) WaitForTask(id int) (Task, error) {
+	deadline := time.Now().Add(taskTimeout)
+
+	for {
+		task, err := c.Task(id)
+		if err != nil {
+			return Task{}, err
+		}
+
+		switch task.State {
+		case "done", "error", "cancelled", "timeout":
+			return task, nil
+		}
+
+		if time.Now().After(deadline) {
+			return Task{}, fmt.Errorf("timed out after %s waiting for task %d to finish, it is still %s", taskTimeout, id, task.State)
+		}
+
+		time.Sleep(taskPollInterval)
+	}
+}
+
+func (c *client) Stemcells() ([]Stemcell, error) {
+	var stemcells []Stemcell
+	if err := c.getJSON("/stemcells", &stemcells); err != nil {
+		return nil, err
+	}
+
+	return stemcells, nil
+}
+
+func (c *client) Releases() ([]Release, error) {
+	var releases []Release
+	if err := c.getJSON("/releases", &releases); err != nil {
+		return nil, err
+	}
+
+	return releases, nil
+}
+
+func (c *client) updateConfig(path string, yaml []byte) error {
+	response, err := c.do("POST", path, "text/yaml", bytes.NewBuffer(yaml))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
 	if response.StatusCode != http.StatusCreated {
-		return fmt.Errorf("unexpected http response %d %s", response.StatusCode, http.StatusText(response.StatusCode))
+		return unexpectedResponse(response)
+	}
+
+	return nil
+}
+
+func (c *client) latestConfig(path string) (string, error) {
+	var configs []config
+	if err := c.getJSON(path, &configs); err != nil {
+		return "", err
+	}
+
+	if len(configs) == 0 {
+		return "", nil
+	}
+
+	return configs[0].Properties, nil
+}
+
+func (c *client) getJSON(path string, v interface{}) error {
+	response, err := c.do("GET", path, "", nil)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return unexpectedResponse(response)
+	}
+
+	return json.NewDecoder(response.Body).Decode(v)
+}
+
+func (c *client) do(method, path, contentType string, body io.Reader) (*http.Response, error) {
+	if c.caCertErr != nil {
+		return nil, c.caCertErr
+	}
+
+	request, err := http.NewRequest(method, fmt.Sprintf("%s%s", c.directorAddress, path), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	if err := c.authorize(request); err != nil {
+		return nil, err
+	}
+
+	return c.httpClient.Do(request)
+}
+
+func (c *client) authorize(request *http.Request) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.info == nil {
+		info, err := c.Info()
+		if err != nil {
+			return err
+		}
+		c.info = &info
+	}
+
+	if c.info.UserAuthentication.Type != "uaa" {
+		request.SetBasicAuth(c.username, c.password)
+		return nil
 	}
 
+	if c.token == "" || time.Now().After(c.tokenExpiresAt) {
+		token, err := c.fetchUAAToken(c.info.UserAuthentication.Options.URL)
+		if err != nil {
+			return err
+		}
+
+		c.token = token.AccessToken
+		// Refresh a little early so that a token never expires mid-request.
+		c.tokenExpiresAt = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - 30*time.Second)
+	}
+
+	request.Header.Set("Authorization", fmt.Sprintf("bearer %s", c.token))
 	return nil
 }
+
+func (c *client) fetchUAAToken(uaaURL string) (uaaToken, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+
+	request, err := http.NewRequest("POST", fmt.Sprintf("%s/oauth/token", uaaURL), strings.NewReader(form.Encode()))
+	if err != nil {
+		return uaaToken{}, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	request.SetBasicAuth(c.username, c.password)
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return uaaToken{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return uaaToken{}, fmt.Errorf("failed to get uaa token: %s", unexpectedResponse(response))
+	}
+
+	var token uaaToken
+	if err := json.NewDecoder(response.Body).Decode(&token); err != nil {
+		return uaaToken{}, err
+	}
+
+	return token, nil
+}
+
+func taskIDFromLocation(location string) (int, error) {
+	parts := strings.Split(strings.TrimSuffix(location, "/"), "/")
+	taskID, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return 0, fmt.Errorf("could not determine task id from location %q", location)
+	}
+
+	return taskID, nil
+}
+
+func unexpectedResponse(response *http.Response) error {
+	return fmt.Errorf("unexpected http response %d %s", response.StatusCode, http.StatusText(response.StatusCode))
+}
@@ -1,10 +1,12 @@
 package bosh_test
 
 import (
+	"encoding/pem"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"time"
 
 	"github.com/cloudfoundry/bosh-bootloader/bosh"
 
@@ -13,6 +15,13 @@ import (
 )
 
 var _ = Describe("Client", func() {
+	var caCertFor = func(server *httptest.Server) string {
+		return string(pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: server.Certificate().Raw,
+		}))
+	}
+
 	Describe("Info", func() {
 		It("returns the director info", func() {
 			fakeBOSH := httptest.NewTLSServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
@@ -23,7 +32,7 @@ var _ = Describe("Client", func() {
 				}`))
 			}))
 
-			client := bosh.NewClient(fakeBOSH.URL, "some-username", "some-password")
+			client := bosh.NewClient(fakeBOSH.URL, "some-username", "some-password", caCertFor(fakeBOSH))
 			info, err := client.Info()
 			Expect(err).NotTo(HaveOccurred())
 			Expect(info).To(Equal(bosh.Info{
@@ -39,19 +48,19 @@ var _ = Describe("Client", func() {
 					responseWriter.WriteHeader(http.StatusNotFound)
 				}))
 
-				client := bosh.NewClient(fakeBOSH.URL, "some-username", "some-password")
+				client := bosh.NewClient(fakeBOSH.URL, "some-username", "some-password", caCertFor(fakeBOSH))
 				_, err := client.Info()
 				Expect(err).To(MatchError("unexpected http response 404 Not Found"))
 			})
 
 			It("returns an error when the url cannot be parsed", func() {
-				client := bosh.NewClient("%%%", "some-username", "some-password")
+				client := bosh.NewClient("%%%", "some-username", "some-password", "")
 				_, err := client.Info()
 				Expect(err.(*url.Error).Op).To(Equal("parse"))
 			})
 
 			It("returns an error when the request fails", func() {
-				client := bosh.NewClient("fake://some-url", "some-username", "some-password")
+				client := bosh.NewClient("fake://some-url", "some-username", "some-password", "")
 				_, err := client.Info()
 				Expect(err).To(MatchError(ContainSubstring("unsupported protocol scheme")))
 			})
@@ -61,13 +70,34 @@ var _ = Describe("Client", func() {
 					responseWriter.Write([]byte(`%%%`))
 				}))
 
-				client := bosh.NewClient(fakeBOSH.URL, "some-username", "some-password")
+				client := bosh.NewClient(fakeBOSH.URL, "some-username", "some-password", caCertFor(fakeBOSH))
 				_, err := client.Info()
 				Expect(err).To(MatchError(ContainSubstring("invalid character")))
 			})
 
-		})
+			It("returns an error when the ca cert is not valid PEM", func() {
+				fakeBOSH := httptest.NewTLSServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+					responseWriter.Write([]byte(`{}`))
+				}))
+
+				client := bosh.NewClient(fakeBOSH.URL, "some-username", "some-password", "some-invalid-ca")
+				_, err := client.Info()
+				Expect(err).To(MatchError("director ca cert does not contain a valid PEM certificate"))
+
+				_, err = client.Stemcells()
+				Expect(err).To(MatchError("director ca cert does not contain a valid PEM certificate"))
+			})
 
+			It("returns an error when the director certificate is not signed by the ca", func() {
+				fakeBOSH := httptest.NewTLSServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+					responseWriter.Write([]byte(`{}`))
+				}))
+
+				client := bosh.NewClient(fakeBOSH.URL, "some-username", "some-password", "")
+				_, err := client.Info()
+				Expect(err).To(MatchError(ContainSubstring("certificate signed by unknown authority")))
+			})
+		})
 	})
 
 	Describe("UpdateCloudConfig", func() {
@@ -80,6 +110,11 @@ var _ = Describe("Client", func() {
 			)
 
 			fakeBOSH := httptest.NewTLSServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+				if request.URL.Path == "/info" {
+					responseWriter.Write([]byte(`{}`))
+					return
+				}
+
 				var (
 					err error
 				)
@@ -93,7 +128,7 @@ var _ = Describe("Client", func() {
 				responseWriter.WriteHeader(http.StatusCreated)
 			}))
 
-			client := bosh.NewClient(fakeBOSH.URL, "some-username", "some-password")
+			client := bosh.NewClient(fakeBOSH.URL, "some-username", "some-password", caCertFor(fakeBOSH))
 
 			err := client.UpdateCloudConfig([]byte("cloud: config"))
 			Expect(err).NotTo(HaveOccurred())
@@ -104,20 +139,96 @@ var _ = Describe("Client", func() {
 			Expect(password).To(Equal("some-password"))
 		})
 
+		Context("when the director uses uaa", func() {
+			It("authenticates with a client credentials token", func() {
+				var (
+					tokenRequests int
+					grantType     string
+					clientID      string
+					clientSecret  string
+					authorization string
+				)
+
+				fakeUAA := httptest.NewTLSServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+					Expect(request.URL.Path).To(Equal("/oauth/token"))
+					tokenRequests++
+
+					clientID, clientSecret, _ = request.BasicAuth()
+					Expect(request.ParseForm()).To(Succeed())
+					grantType = request.Form.Get("grant_type")
+
+					responseWriter.Write([]byte(`{
+						"access_token": "some-access-token",
+						"token_type": "bearer",
+						"expires_in": 3600
+					}`))
+				}))
+
+				fakeBOSH := httptest.NewTLSServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+					if request.URL.Path == "/info" {
+						responseWriter.Write([]byte(`{
+							"user_authentication": {
+								"type": "uaa",
+								"options": {"url": "` + fakeUAA.URL + `"}
+							}
+						}`))
+						return
+					}
+
+					authorization = request.Header.Get("Authorization")
+					responseWriter.WriteHeader(http.StatusCreated)
+				}))
+
+				client := bosh.NewClient(fakeBOSH.URL, "some-client", "some-client-secret", caCertFor(fakeBOSH))
+
+				err := client.UpdateCloudConfig([]byte("cloud: config"))
+				Expect(err).NotTo(HaveOccurred())
+
+				err = client.UpdateCloudConfig([]byte("cloud: config"))
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(tokenRequests).To(Equal(1))
+				Expect(grantType).To(Equal("client_credentials"))
+				Expect(clientID).To(Equal("some-client"))
+				Expect(clientSecret).To(Equal("some-client-secret"))
+				Expect(authorization).To(Equal("bearer some-access-token"))
+			})
+
+			It("returns an error when the token cannot be fetched", func() {
+				fakeUAA := httptest.NewTLSServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+					responseWriter.WriteHeader(http.StatusUnauthorized)
+				}))
+
+				fakeBOSH := httptest.NewTLSServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+					responseWriter.Write([]byte(`{
+						"user_authentication": {
+							"type": "uaa",
+							"options": {"url": "` + fakeUAA.URL + `"}
+						}
+					}`))
+				}))
+
+				client := bosh.NewClient(fakeBOSH.URL, "some-client", "some-client-secret", caCertFor(fakeBOSH))
+
+				err := client.UpdateCloudConfig([]byte("cloud: config"))
+				Expect(err).To(MatchError("failed to get uaa token: unexpected http response 401 Unauthorized"))
+			})
+		})
+
 		Context("failure cases", func() {
 			It("returns an error when the status code is not StatusCreated", func() {
 				fakeBOSH := httptest.NewTLSServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
 					responseWriter.WriteHeader(http.StatusInternalServerError)
 				}))
 
-				client := bosh.NewClient(fakeBOSH.URL, "", "")
+				client := bosh.NewClient(fakeBOSH.URL, "", "", caCertFor(fakeBOSH))
 
 				err := client.UpdateCloudConfig([]byte("cloud: config"))
 				Expect(err).To(MatchError("unexpected http response 500 Internal Server Error"))
 			})
 
 			It("returns an error when the director address is malformed", func() {
-				client := bosh.NewClient("%%%%%%%%%%%%%%%", "", "")
+				client := bosh.NewClient("%%%%%%%%%%%%%%%", "", "", "")
 
 				err := client.UpdateCloudConfig([]byte("cloud: config"))
 				Expect(err.(*url.Error).Op).To(Equal("parse"))
@@ -128,7 +239,7 @@ var _ = Describe("Client", func() {
 					responseWriter.WriteHeader(http.StatusInternalServerError)
 				}))
 
-				client := bosh.NewClient(fakeBOSH.URL, "", "")
+				client := bosh.NewClient(fakeBOSH.URL, "", "", caCertFor(fakeBOSH))
 
 				fakeBOSH.Close()
 
@@ -137,4 +248,258 @@ var _ = Describe("Client", func() {
 			})
 		})
 	})
+
+	Describe("configs", func() {
+		var (
+			fakeBOSH    *httptest.Server
+			client      bosh.Client
+			requestURLs []string
+			requestBody []byte
+		)
+
+		BeforeEach(func() {
+			requestURLs = []string{}
+
+			fakeBOSH = httptest.NewTLSServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+				if request.URL.Path == "/info" {
+					responseWriter.Write([]byte(`{}`))
+					return
+				}
+
+				requestURLs = append(requestURLs, request.Method+" "+request.URL.RequestURI())
+
+				switch request.Method {
+				case "GET":
+					responseWriter.Write([]byte(`[{"properties": "some-config", "created_at": "2017-05-17 00:00:00 UTC"}]`))
+				case "POST":
+					var err error
+					requestBody, err = ioutil.ReadAll(request.Body)
+					Expect(err).NotTo(HaveOccurred())
+					responseWriter.WriteHeader(http.StatusCreated)
+				}
+			}))
+
+			client = bosh.NewClient(fakeBOSH.URL, "some-username", "some-password", caCertFor(fakeBOSH))
+		})
+
+		It("returns the latest cloud config", func() {
+			cloudConfig, err := client.CloudConfig()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cloudConfig).To(Equal("some-config"))
+			Expect(requestURLs).To(Equal([]string{"GET /cloud_configs?limit=1"}))
+		})
+
+		It("returns the latest named runtime config", func() {
+			runtimeConfig, err := client.RuntimeConfig("some-name")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runtimeConfig).To(Equal("some-config"))
+			Expect(requestURLs).To(Equal([]string{"GET /runtime_configs?limit=1&name=some-name"}))
+		})
+
+		It("uploads a named runtime config", func() {
+			err := client.UpdateRuntimeConfig("some-name", []byte("runtime: config"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requestURLs).To(Equal([]string{"POST /runtime_configs?name=some-name"}))
+			Expect(requestBody).To(Equal([]byte("runtime: config")))
+		})
+
+		It("returns the latest default runtime config", func() {
+			runtimeConfig, err := client.RuntimeConfig("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runtimeConfig).To(Equal("some-config"))
+			Expect(requestURLs).To(Equal([]string{"GET /runtime_configs?limit=1"}))
+		})
+
+		It("uploads the default runtime config", func() {
+			err := client.UpdateRuntimeConfig("", []byte("runtime: config"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requestURLs).To(Equal([]string{"POST /runtime_configs"}))
+			Expect(requestBody).To(Equal([]byte("runtime: config")))
+		})
+
+		It("returns the latest cpi config", func() {
+			cpiConfig, err := client.CPIConfig()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cpiConfig).To(Equal("some-config"))
+			Expect(requestURLs).To(Equal([]string{"GET /cpi_configs?limit=1"}))
+		})
+
+		It("uploads a cpi config", func() {
+			err := client.UpdateCPIConfig([]byte("cpi: config"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requestURLs).To(Equal([]string{"POST /cpi_configs"}))
+			Expect(requestBody).To(Equal([]byte("cpi: config")))
+		})
+
+		Context("when the director has no config of the type", func() {
+			It("returns an empty config", func() {
+				fakeBOSH.Config.Handler = http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+					if request.URL.Path == "/info" {
+						responseWriter.Write([]byte(`{}`))
+						return
+					}
+
+					responseWriter.Write([]byte(`[]`))
+				})
+
+				cloudConfig, err := client.CloudConfig()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cloudConfig).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("Deployments", func() {
+		It("returns the deployments on the director", func() {
+			fakeBOSH := httptest.NewTLSServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+				switch request.URL.Path {
+				case "/info":
+					responseWriter.Write([]byte(`{}`))
+				case "/deployments":
+					responseWriter.Write([]byte(`[{
+						"name": "some-deployment",
+						"cloud_config": "latest",
+						"releases": [{"name": "some-release", "version": "1"}],
+						"stemcells": [{"name": "some-stemcell", "version": "2"}]
+					}]`))
+				}
+			}))
+
+			client := bosh.NewClient(fakeBOSH.URL, "some-username", "some-password", caCertFor(fakeBOSH))
+
+			deployments, err := client.Deployments()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployments).To(Equal([]bosh.Deployment{
+				{
+					Name:        "some-deployment",
+					CloudConfig: "latest",
+					Releases:    []bosh.NameVersion{{Name: "some-release", Version: "1"}},
+					Stemcells:   []bosh.NameVersion{{Name: "some-stemcell", Version: "2"}},
+				},
+			}))
+		})
+	})
+
+	Describe("DeleteDeployment", func() {
+		var (
+			taskStates []string
+			taskPolls  int
+			fakeBOSH   *httptest.Server
+			client     bosh.Client
+		)
+
+		BeforeEach(func() {
+			bosh.SetTaskPollInterval(time.Millisecond)
+
+			taskPolls = 0
+			taskStates = []string{"queued", "processing", "done"}
+
+			fakeBOSH = httptest.NewTLSServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+				switch {
+				case request.URL.Path == "/info":
+					responseWriter.Write([]byte(`{}`))
+				case request.Method == "DELETE" && request.URL.Path == "/deployments/some-deployment":
+					responseWriter.Header().Set("Location", "/tasks/42")
+					responseWriter.WriteHeader(http.StatusFound)
+				case request.URL.Path == "/tasks/42":
+					state := taskStates[taskPolls]
+					taskPolls++
+					responseWriter.Write([]byte(`{"id": 42, "state": "` + state + `", "result": "some-result"}`))
+				default:
+					responseWriter.WriteHeader(http.StatusNotFound)
+				}
+			}))
+
+			client = bosh.NewClient(fakeBOSH.URL, "some-username", "some-password", caCertFor(fakeBOSH))
+		})
+
+		AfterEach(func() {
+			bosh.ResetTaskPollInterval()
+			bosh.ResetTaskTimeout()
+		})
+
+		It("deletes the deployment and waits for the task to finish", func() {
+			err := client.DeleteDeployment("some-deployment")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(taskPolls).To(Equal(3))
+		})
+
+		It("returns an error when the task does not succeed", func() {
+			taskStates = []string{"processing", "error"}
+
+			err := client.DeleteDeployment("some-deployment")
+			Expect(err).To(MatchError("task 42 error: some-result"))
+		})
+
+		It("returns an error when the task does not finish in time", func() {
+			bosh.SetTaskTimeout(0)
+			taskStates = []string{"processing", "processing"}
+
+			err := client.DeleteDeployment("some-deployment")
+			Expect(err).To(MatchError("timed out after 0s waiting for task 42 to finish, it is still processing"))
+			Expect(taskPolls).To(Equal(1))
+		})
+
+		It("returns an error when the director does not return a task", func() {
+			err := client.DeleteDeployment("some-other-deployment")
+			Expect(err).To(MatchError("unexpected http response 404 Not Found"))
+		})
+	})
+
+	Describe("Stemcells", func() {
+		It("returns the uploaded stemcells", func() {
+			fakeBOSH := httptest.NewTLSServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+				switch request.URL.Path {
+				case "/info":
+					responseWriter.Write([]byte(`{}`))
+				case "/stemcells":
+					responseWriter.Write([]byte(`[{
+						"name": "some-stemcell",
+						"operating_system": "ubuntu-trusty",
+						"version": "3363.20",
+						"cid": "some-cid"
+					}]`))
+				}
+			}))
+
+			client := bosh.NewClient(fakeBOSH.URL, "some-username", "some-password", caCertFor(fakeBOSH))
+
+			stemcells, err := client.Stemcells()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stemcells).To(HaveLen(1))
+			Expect(stemcells[0].Name).To(Equal("some-stemcell"))
+			Expect(stemcells[0].OperatingSystem).To(Equal("ubuntu-trusty"))
+			Expect(stemcells[0].Version).To(Equal("3363.20"))
+			Expect(stemcells[0].CID).To(Equal("some-cid"))
+		})
+	})
+
+	Describe("Releases", func() {
+		It("returns the uploaded releases", func() {
+			fakeBOSH := httptest.NewTLSServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+				switch request.URL.Path {
+				case "/info":
+					responseWriter.Write([]byte(`{}`))
+				case "/releases":
+					responseWriter.Write([]byte(`[{
+						"name": "some-release",
+						"release_versions": [{"version": "1", "commit_hash": "abc123", "currently_deployed": true}]
+					}]`))
+				}
+			}))
+
+			client := bosh.NewClient(fakeBOSH.URL, "some-username", "some-password", caCertFor(fakeBOSH))
+
+			releases, err := client.Releases()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(releases).To(Equal([]bosh.Release{
+				{
+					Name: "some-release",
+					ReleaseVersions: []bosh.ReleaseVersion{
+						{Version: "1", CommitHash: "abc123", CurrentlyDeployed: true},
+					},
+				},
+			}))
+		})
+	})
 })
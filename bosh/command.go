@@ -2,6 +2,7 @@ package bosh
 
 import (
 	"io"
+	"os"
 	"os/exec"
 )
 
@@ -16,8 +17,14 @@ func NewCmd(stderr io.Writer) Cmd {
 }
 
 func (c Cmd) Run(stdout io.Writer, workingDirectory string, args []string) error {
+	return c.RunWithEnv(stdout, workingDirectory, args, nil)
+}
+
+// RunWithEnv runs the bosh cli with env added to the environment of bbl.
+func (c Cmd) RunWithEnv(stdout io.Writer, workingDirectory string, args []string, env []string) error {
 	command := exec.Command("bosh", args...)
 	command.Dir = workingDirectory
+	command.Env = append(os.Environ(), env...)
 
 	command.Stdout = stdout
 	command.Stderr = c.stderr
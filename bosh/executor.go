@@ -27,6 +27,7 @@ type InterpolateInput struct {
 	DeploymentVars string
 	BOSHState      map[string]interface{}
 	Variables      string
+	BBLOpsFile     string
 	OpsFile        string
 }
 
@@ -36,9 +37,10 @@ type InterpolateOutput struct {
 }
 
 type CreateEnvInput struct {
-	Manifest  string
-	Variables string
-	State     map[string]interface{}
+	Manifest       string
+	Variables      string
+	State          map[string]interface{}
+	CPICredentials CPICredentials
 }
 
 type CreateEnvOutput struct {
@@ -46,13 +48,24 @@ type CreateEnvOutput struct {
 }
 
 type DeleteEnvInput struct {
-	Manifest  string
-	Variables string
-	State     map[string]interface{}
+	Manifest       string
+	Variables      string
+	State          map[string]interface{}
+	CPICredentials CPICredentials
+}
+
+// CPICredentials are given to the cpi of create-env and delete-env through
+// the environment of the bosh cli, so that they never end up in the manifest.
+type CPICredentials struct {
+	AWSAccessKeyID       string
+	AWSSecretAccessKey   string
+	AWSSessionToken      string
+	GCPServiceAccountKey string
 }
 
 type command interface {
 	Run(stdout io.Writer, workingDirectory string, args []string) error
+	RunWithEnv(stdout io.Writer, workingDirectory string, args []string, env []string) error
 }
 
 func NewExecutor(cmd command, tempDir func(string, string) (string, error), readFile func(string) ([]byte, error),
@@ -80,6 +93,7 @@ func (e Executor) Interpolate(interpolateInput InterpolateInput) (InterpolateOut
 	variablesPath := filepath.Join(tempDir, "variables.yml")
 	boshManifestPath := filepath.Join(tempDir, "bosh.yml")
 	cpiOpsFilePath := filepath.Join(tempDir, "cpi.yml")
+	bblOpsFilePath := filepath.Join(tempDir, "bbl-ops-file.yml")
 	externalIPNotRecommendedOpsFilePath := filepath.Join(tempDir, "external-ip-not-recommended.yml")
 
 	if interpolateInput.Variables != "" {
@@ -145,9 +159,21 @@ func (e Executor) Interpolate(interpolateInput InterpolateInput) (InterpolateOut
 		"--var-errs-unused",
 		"-o", cpiOpsFilePath,
 		"-o", externalIPNotRecommendedOpsFilePath,
+	}
+
+	if interpolateInput.BBLOpsFile != "" {
+		err = e.writeFile(bblOpsFilePath, []byte(interpolateInput.BBLOpsFile), os.ModePerm)
+		if err != nil {
+			return InterpolateOutput{}, err
+		}
+
+		args = append(args, "-o", bblOpsFilePath)
+	}
+
+	args = append(args,
 		"--vars-store", variablesPath,
 		"--vars-file", deploymentVarsPath,
-	}
+	)
 
 	buffer := bytes.NewBuffer([]byte{})
 	err = e.command.Run(buffer, tempDir, args)
@@ -210,7 +236,12 @@ func (e Executor) CreateEnv(createEnvInput CreateEnvInput) (CreateEnvOutput, err
 		"--state", statePath,
 	}
 
-	err = e.command.Run(os.Stdout, tempDir, args)
+	env, err := e.cpiEnv(tempDir, createEnvInput.CPICredentials)
+	if err != nil {
+		return CreateEnvOutput{}, err
+	}
+
+	err = e.command.RunWithEnv(os.Stdout, tempDir, args, env)
 	if err != nil {
 		state, readErr := e.readBOSHState(statePath)
 		if readErr != nil {
@@ -233,6 +264,33 @@ func (e Executor) CreateEnv(createEnvInput CreateEnvInput) (CreateEnvOutput, err
 	}, nil
 }
 
+func (e Executor) cpiEnv(tempDir string, credentials CPICredentials) ([]string, error) {
+	env := []string{}
+
+	if credentials.AWSAccessKeyID != "" {
+		env = append(env,
+			fmt.Sprintf("AWS_ACCESS_KEY_ID=%s", credentials.AWSAccessKeyID),
+			fmt.Sprintf("AWS_SECRET_ACCESS_KEY=%s", credentials.AWSSecretAccessKey),
+		)
+	}
+
+	if credentials.AWSSessionToken != "" {
+		env = append(env, fmt.Sprintf("AWS_SESSION_TOKEN=%s", credentials.AWSSessionToken))
+	}
+
+	if credentials.GCPServiceAccountKey != "" {
+		serviceAccountKeyPath := filepath.Join(tempDir, "gcp-credentials.json")
+		err := e.writeFile(serviceAccountKeyPath, []byte(credentials.GCPServiceAccountKey), os.FileMode(0600))
+		if err != nil {
+			return nil, err
+		}
+
+		env = append(env, fmt.Sprintf("GOOGLE_APPLICATION_CREDENTIALS=%s", serviceAccountKeyPath))
+	}
+
+	return env, nil
+}
+
 func (e Executor) readBOSHState(statePath string) (map[string]interface{}, error) {
 	stateContents, err := e.readFile(statePath)
 	if err != nil {
@@ -264,7 +322,12 @@ func (e Executor) DeleteEnv(deleteEnvInput DeleteEnvInput) error {
 		"--state", statePath,
 	}
 
-	err = e.command.Run(os.Stdout, tempDir, args)
+	env, err := e.cpiEnv(tempDir, deleteEnvInput.CPICredentials)
+	if err != nil {
+		return err
+	}
+
+	err = e.command.RunWithEnv(os.Stdout, tempDir, args, env)
 	if err != nil {
 		state, readErr := e.readBOSHState(statePath)
 		if readErr != nil {
@@ -7,6 +7,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	yaml "gopkg.in/yaml.v2"
 
@@ -148,6 +149,31 @@ gcp_credentials_json: 'some-credential-json'`,
 			}),
 		)
 
+		Context("when a bbl opsfile is provided", func() {
+			It("applies it with the cpi ops before the variables are checked", func() {
+				awsInterpolateInput.BBLOpsFile = "some-bbl-ops-file"
+
+				_, err := executor.Interpolate(awsInterpolateInput)
+				Expect(err).NotTo(HaveOccurred())
+
+				bblOpsFileContents, err := ioutil.ReadFile(fmt.Sprintf("%s/bbl-ops-file.yml", tempDir))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(bblOpsFileContents)).To(Equal("some-bbl-ops-file"))
+
+				_, _, args := cmd.RunArgsForCall(0)
+				Expect(args).To(Equal([]string{
+					"interpolate", fmt.Sprintf("%s/bosh.yml", tempDir),
+					"--var-errs",
+					"--var-errs-unused",
+					"-o", fmt.Sprintf("%s/cpi.yml", tempDir),
+					"-o", fmt.Sprintf("%s/external-ip-not-recommended.yml", tempDir),
+					"-o", fmt.Sprintf("%s/bbl-ops-file.yml", tempDir),
+					"--vars-store", fmt.Sprintf("%s/variables.yml", tempDir),
+					"--vars-file", fmt.Sprintf("%s/deployment-vars.yml", tempDir),
+				}))
+			})
+		})
+
 		Context("when a user opsfile is provided", func() {
 			It("re-interpolates the bosh manifest", func() {
 				interpolateInput := bosh.InterpolateInput{
@@ -462,7 +488,7 @@ networks
 		})
 
 		It("fails when the run command returns an error", func() {
-			cmd.RunReturnsOnCall(0, errors.New("failed to run"))
+			cmd.RunWithEnvReturnsOnCall(0, errors.New("failed to run"))
 			err := callback(executor)
 			Expect(err).To(MatchError("failed to run"))
 		})
@@ -508,7 +534,7 @@ networks
 			variablesPath = fmt.Sprintf("%s/variables.yml", tempDir)
 			statePath = fmt.Sprintf("%s/state.json", tempDir)
 
-			cmd.RunStub = func(stdout io.Writer, workingDirectory string, args []string) error {
+			cmd.RunWithEnvStub = func(stdout io.Writer, workingDirectory string, args []string, env []string) error {
 				return ioutil.WriteFile(statePath, []byte(`{"key": "value"}`), os.ModePerm)
 			}
 		})
@@ -531,9 +557,10 @@ networks
 			Expect(err).NotTo(HaveOccurred())
 			Expect(string(variablesContents)).To(Equal("some-variables"))
 
-			writer, dir, args := cmd.RunArgsForCall(0)
+			writer, dir, args, env := cmd.RunWithEnvArgsForCall(0)
 			Expect(writer).To(Equal(os.Stdout))
 			Expect(dir).To(Equal(tempDir))
+			Expect(env).To(BeEmpty())
 			Expect(args).To(Equal([]string{
 				"create-env", manifestPath,
 				"--vars-store", variablesPath,
@@ -545,6 +572,70 @@ networks
 			}))
 		})
 
+		It("passes the cpi credentials to the bosh cli through its environment", func() {
+			createEnvInput.CPICredentials = bosh.CPICredentials{
+				AWSAccessKeyID:     "some-access-key-id",
+				AWSSecretAccessKey: "some-secret-access-key",
+				AWSSessionToken:    "some-session-token",
+			}
+
+			_, err := executor.CreateEnv(createEnvInput)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, args, env := cmd.RunWithEnvArgsForCall(0)
+			Expect(env).To(Equal([]string{
+				"AWS_ACCESS_KEY_ID=some-access-key-id",
+				"AWS_SECRET_ACCESS_KEY=some-secret-access-key",
+				"AWS_SESSION_TOKEN=some-session-token",
+			}))
+
+			manifestContents, err := ioutil.ReadFile(manifestPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(manifestContents)).NotTo(ContainSubstring("some-secret-access-key"))
+			Expect(args).NotTo(ContainElement(ContainSubstring("some-secret-access-key")))
+		})
+
+		It("passes the gcp service account key to the bosh cli as a credentials file", func() {
+			createEnvInput.CPICredentials = bosh.CPICredentials{
+				GCPServiceAccountKey: "some-service-account-key",
+			}
+
+			_, err := executor.CreateEnv(createEnvInput)
+			Expect(err).NotTo(HaveOccurred())
+
+			serviceAccountKeyPath := filepath.Join(tempDir, "gcp-credentials.json")
+			_, _, _, env := cmd.RunWithEnvArgsForCall(0)
+			Expect(env).To(Equal([]string{
+				fmt.Sprintf("GOOGLE_APPLICATION_CREDENTIALS=%s", serviceAccountKeyPath),
+			}))
+
+			serviceAccountKey, err := ioutil.ReadFile(serviceAccountKeyPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(serviceAccountKey)).To(Equal("some-service-account-key"))
+
+			fileInfo, err := os.Stat(serviceAccountKeyPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fileInfo.Mode()).To(Equal(os.FileMode(0600)))
+		})
+
+		It("fails when the gcp service account key cannot be written", func() {
+			writeFile := func(filename string, contents []byte, mode os.FileMode) error {
+				if filepath.Base(filename) == "gcp-credentials.json" {
+					return errors.New("failed to write file")
+				}
+				return ioutil.WriteFile(filename, contents, mode)
+			}
+			executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, yaml.Unmarshal, json.Unmarshal, json.Marshal, writeFile)
+
+			createEnvInput.CPICredentials = bosh.CPICredentials{
+				GCPServiceAccountKey: "some-service-account-key",
+			}
+
+			_, err := executor.CreateEnv(createEnvInput)
+			Expect(err).To(MatchError("failed to write file"))
+			Expect(cmd.RunWithEnvCallCount()).To(Equal(0))
+		})
+
 		Context("failure cases", func() {
 			createEnvDeleteEnvFailureCases(func(executor bosh.Executor) error {
 				createEnvInput := bosh.CreateEnvInput{
@@ -558,10 +649,10 @@ networks
 
 			Context("when command run fails", func() {
 				BeforeEach(func() {
-					cmd.RunReturns(errors.New("failed to run"))
+					cmd.RunWithEnvReturns(errors.New("failed to run"))
 					executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, yaml.Unmarshal, json.Unmarshal, json.Marshal, ioutil.WriteFile)
 
-					cmd.RunStub = func(stdout io.Writer, workingDirectory string, args []string) error {
+					cmd.RunWithEnvStub = func(stdout io.Writer, workingDirectory string, args []string, env []string) error {
 						ioutil.WriteFile(statePath, []byte(`{"key": "value"}`), os.ModePerm)
 						return errors.New("failed to run")
 					}
@@ -668,7 +759,7 @@ networks
 			variablesPath = fmt.Sprintf("%s/variables.yml", tempDir)
 			statePath = fmt.Sprintf("%s/state.json", tempDir)
 
-			cmd.RunStub = func(stdout io.Writer, workingDirectory string, args []string) error {
+			cmd.RunWithEnvStub = func(stdout io.Writer, workingDirectory string, args []string, env []string) error {
 				return ioutil.WriteFile(statePath, []byte(`{"key": "value"}`), os.ModePerm)
 			}
 		})
@@ -691,9 +782,10 @@ networks
 			Expect(err).NotTo(HaveOccurred())
 			Expect(string(variablesContents)).To(Equal("some-variables"))
 
-			writer, dir, args := cmd.RunArgsForCall(0)
+			writer, dir, args, env := cmd.RunWithEnvArgsForCall(0)
 			Expect(writer).To(Equal(os.Stdout))
 			Expect(dir).To(Equal(tempDir))
+			Expect(env).To(BeEmpty())
 			Expect(args).To(Equal([]string{
 				"delete-env", manifestPath,
 				"--vars-store", variablesPath,
@@ -701,6 +793,36 @@ networks
 			}))
 		})
 
+		It("passes the cpi credentials to the bosh cli through its environment", func() {
+			deleteEnvInput.CPICredentials = bosh.CPICredentials{
+				AWSAccessKeyID:     "some-access-key-id",
+				AWSSecretAccessKey: "some-secret-access-key",
+			}
+
+			err := executor.DeleteEnv(deleteEnvInput)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, _, env := cmd.RunWithEnvArgsForCall(0)
+			Expect(env).To(Equal([]string{
+				"AWS_ACCESS_KEY_ID=some-access-key-id",
+				"AWS_SECRET_ACCESS_KEY=some-secret-access-key",
+			}))
+		})
+
+		It("passes the gcp service account key to the bosh cli as a credentials file", func() {
+			deleteEnvInput.CPICredentials = bosh.CPICredentials{
+				GCPServiceAccountKey: "some-service-account-key",
+			}
+
+			err := executor.DeleteEnv(deleteEnvInput)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, _, env := cmd.RunWithEnvArgsForCall(0)
+			Expect(env).To(Equal([]string{
+				fmt.Sprintf("GOOGLE_APPLICATION_CREDENTIALS=%s", filepath.Join(tempDir, "gcp-credentials.json")),
+			}))
+		})
+
 		Context("failure cases", func() {
 			createEnvDeleteEnvFailureCases(func(executor bosh.Executor) error {
 				deleteEnvInput := bosh.DeleteEnvInput{
@@ -713,10 +835,10 @@ networks
 
 			Context("when command run fails", func() {
 				BeforeEach(func() {
-					cmd.RunReturnsOnCall(0, errors.New("failed to run"))
+					cmd.RunWithEnvReturnsOnCall(0, errors.New("failed to run"))
 					executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, yaml.Unmarshal, json.Unmarshal, json.Marshal, ioutil.WriteFile)
 
-					cmd.RunStub = func(stdout io.Writer, workingDirectory string, args []string) error {
+					cmd.RunWithEnvStub = func(stdout io.Writer, workingDirectory string, args []string, env []string) error {
 						ioutil.WriteFile(statePath, []byte(`{"partial": "state"}`), os.ModePerm)
 						return errors.New("failed to run")
 					}
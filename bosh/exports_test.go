@@ -0,0 +1,19 @@
+package bosh
+
+import "time"
+
+func SetTaskPollInterval(interval time.Duration) {
+	taskPollInterval = interval
+}
+
+func ResetTaskPollInterval() {
+	taskPollInterval = 2 * time.Second
+}
+
+func SetTaskTimeout(timeout time.Duration) {
+	taskTimeout = timeout
+}
+
+func ResetTaskTimeout() {
+	taskTimeout = 30 * time.Minute
+}
@@ -7,6 +7,7 @@ import (
 
 	yaml "gopkg.in/yaml.v2"
 
+	"github.com/cloudfoundry/bosh-bootloader/aws"
 	"github.com/cloudfoundry/bosh-bootloader/aws/cloudformation"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
@@ -16,10 +17,11 @@ const (
 )
 
 type Manager struct {
-	executor         executor
-	terraformManager terraformManager
-	stackManager     stackManager
-	logger           logger
+	executor            executor
+	terraformManager    terraformManager
+	stackManager        stackManager
+	credentialsProvider credentialsProvider
+	logger              logger
 }
 
 type directorOutputs struct {
@@ -48,6 +50,12 @@ type deploymentVariables struct {
 	AccessKeyID           string
 }
 
+type op struct {
+	Type  string      `yaml:"type"`
+	Path  string      `yaml:"path"`
+	Value interface{} `yaml:"value,omitempty"`
+}
+
 type iaasInputs struct {
 	InterpolateInput InterpolateInput
 	DirectorAddress  string
@@ -68,16 +76,21 @@ type stackManager interface {
 	Describe(stackName string) (cloudformation.Stack, error)
 }
 
+type credentialsProvider interface {
+	GetCredentials() (aws.Credentials, error)
+}
+
 type logger interface {
 	Step(string, ...interface{})
 }
 
-func NewManager(executor executor, terraformManager terraformManager, stackManager stackManager, logger logger) Manager {
+func NewManager(executor executor, terraformManager terraformManager, stackManager stackManager, credentialsProvider credentialsProvider, logger logger) Manager {
 	return Manager{
-		executor:         executor,
-		terraformManager: terraformManager,
-		stackManager:     stackManager,
-		logger:           logger,
+		executor:            executor,
+		terraformManager:    terraformManager,
+		stackManager:        stackManager,
+		credentialsProvider: credentialsProvider,
+		logger:              logger,
 	}
 }
 
@@ -98,8 +111,19 @@ func (m Manager) Create(state storage.State) (storage.State, error) {
 		return storage.State{}, err
 	}
 
+	iaasInputs.InterpolateInput.BBLOpsFile, err = bblOpsFile(state)
+	if err != nil {
+		//not tested
+		return storage.State{}, err
+	}
+
 	iaasInputs.InterpolateInput.OpsFile = state.BOSH.UserOpsFile
 
+	cpiCredentials, err := m.cpiCredentials(state)
+	if err != nil {
+		return storage.State{}, err
+	}
+
 	interpolateOutputs, err := m.executor.Interpolate(iaasInputs.InterpolateInput)
 	if err != nil {
 		return storage.State{}, err
@@ -107,9 +131,10 @@ func (m Manager) Create(state storage.State) (storage.State, error) {
 
 	variables, err := yaml.Marshal(interpolateOutputs.Variables)
 	createEnvOutputs, err := m.executor.CreateEnv(CreateEnvInput{
-		Manifest:  interpolateOutputs.Manifest,
-		State:     state.BOSH.State,
-		Variables: string(variables),
+		Manifest:       interpolateOutputs.Manifest,
+		State:          state.BOSH.State,
+		Variables:      string(variables),
+		CPICredentials: cpiCredentials,
 	})
 	switch err.(type) {
 	case CreateEnvError:
@@ -144,10 +169,16 @@ func (m Manager) Create(state storage.State) (storage.State, error) {
 }
 
 func (m Manager) Delete(state storage.State) error {
-	err := m.executor.DeleteEnv(DeleteEnvInput{
-		Manifest:  state.BOSH.Manifest,
-		State:     state.BOSH.State,
-		Variables: state.BOSH.Variables,
+	cpiCredentials, err := m.cpiCredentials(state)
+	if err != nil {
+		return err
+	}
+
+	err = m.executor.DeleteEnv(DeleteEnvInput{
+		Manifest:       state.BOSH.Manifest,
+		State:          state.BOSH.State,
+		Variables:      state.BOSH.Variables,
+		CPICredentials: cpiCredentials,
 	})
 	switch err.(type) {
 	case DeleteEnvError:
@@ -181,21 +212,49 @@ internal_ip: 10.0.0.6`
 			fmt.Sprintf("subnetwork: %s", terraformOutputs["subnetwork_name"]),
 			fmt.Sprintf("tags: [%s, %s]", terraformOutputs["bosh_open_tag_name"], terraformOutputs["internal_tag_name"]),
 			fmt.Sprintf("project_id: %s", state.GCP.ProjectID),
-			fmt.Sprintf("gcp_credentials_json: '%s'", state.GCP.ServiceAccountKey),
 		}, "\n")
+
+		// With a director service account the json key stays out of the
+		// manifest, the cpi that creates the director is given the key
+		// through its environment instead.
+		if state.GCP.DirectorServiceAccount {
+			vars = strings.Join([]string{vars,
+				fmt.Sprintf("director_service_account: %s", terraformOutputs["director_service_account"]),
+			}, "\n")
+		} else {
+			vars = strings.Join([]string{vars,
+				fmt.Sprintf("gcp_credentials_json: '%s'", state.GCP.ServiceAccountKey),
+			}, "\n")
+		}
 	case "aws":
 		if state.TFState != "" {
 			terraformOutputs, err := m.terraformManager.GetOutputs(state)
 			if err != nil {
 				return "", err
 			}
+
 			vars = strings.Join([]string{vars,
 				fmt.Sprintf("director_name: %s", fmt.Sprintf("bosh-%s", state.EnvID)),
 				fmt.Sprintf("external_ip: %s", terraformOutputs["external_ip"]),
 				fmt.Sprintf("az: %s", terraformOutputs["az"]),
 				fmt.Sprintf("subnet_id: %s", terraformOutputs["subnet_id"]),
-				fmt.Sprintf("access_key_id: %s", terraformOutputs["access_key_id"]),
-				fmt.Sprintf("secret_access_key: %s", terraformOutputs["secret_access_key"]),
+			}, "\n")
+
+			// Without a bosh user there are no keys for the manifest, the
+			// cpi that creates the director is given the credentials of bbl
+			// through its environment instead.
+			if state.AWS.IAMInstanceProfile {
+				vars = strings.Join([]string{vars,
+					fmt.Sprintf("iam_instance_profile: %s", terraformOutputs["iam_instance_profile"]),
+				}, "\n")
+			} else {
+				vars = strings.Join([]string{vars,
+					fmt.Sprintf("access_key_id: %s", terraformOutputs["access_key_id"]),
+					fmt.Sprintf("secret_access_key: %s", terraformOutputs["secret_access_key"]),
+				}, "\n")
+			}
+
+			vars = strings.Join([]string{vars,
 				fmt.Sprintf("default_key_name: %s", state.KeyPair.Name),
 				fmt.Sprintf("default_security_groups: [%s]", terraformOutputs["default_security_groups"]),
 				fmt.Sprintf("region: %s", state.AWS.Region),
@@ -224,6 +283,121 @@ internal_ip: 10.0.0.6`
 	return strings.TrimSuffix(vars, "\n"), nil
 }
 
+// bblOpsFile holds the ops that bbl applies on top of bosh-deployment. They
+// are applied before the variables are checked, so that they can remove
+// properties whose variables bbl does not provide, and before the user ops
+// file, so that it can still override them.
+func bblOpsFile(state storage.State) (string, error) {
+	ops := append(tagsOps(state), iamInstanceProfileOps(state)...)
+	ops = append(ops, directorServiceAccountOps(state)...)
+	if len(ops) == 0 {
+		return "", nil
+	}
+
+	contents, err := yaml.Marshal(ops)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(contents)), nil
+}
+
+// tagsOps set the environment tags as director tags, which the cpi applies
+// to every vm and disk it creates. The director vm itself is tagged, or
+// labelled on gcp, as well.
+func tagsOps(state storage.State) []op {
+	if len(state.Tags) == 0 {
+		return nil
+	}
+
+	ops := []op{
+		{
+			Type:  "replace",
+			Path:  "/instance_groups/name=bosh/properties/director/tags?",
+			Value: state.Tags,
+		},
+	}
+
+	switch state.IAAS {
+	case "aws":
+		ops = append(ops, op{
+			Type:  "replace",
+			Path:  "/resource_pools/name=vms/cloud_properties/tags?",
+			Value: state.Tags,
+		})
+	case "gcp":
+		ops = append(ops, op{
+			Type:  "replace",
+			Path:  "/resource_pools/name=vms/cloud_properties/labels?",
+			Value: state.Tags,
+		})
+	}
+
+	return ops
+}
+
+// iamInstanceProfileOps make the director cpi use the instance profile of
+// the director vm instead of access keys. The cpi that creates the director
+// reads the credentials of bbl from its environment, so that no keys end up
+// in the stored manifest.
+func iamInstanceProfileOps(state storage.State) []op {
+	if state.IAAS != "aws" || !state.AWS.IAMInstanceProfile {
+		return nil
+	}
+
+	return []op{
+		{Type: "replace", Path: "/resource_pools/name=vms/cloud_properties/iam_instance_profile?", Value: "((iam_instance_profile))"},
+		{Type: "remove", Path: "/instance_groups/name=bosh/properties/aws/access_key_id"},
+		{Type: "remove", Path: "/instance_groups/name=bosh/properties/aws/secret_access_key"},
+		{Type: "replace", Path: "/instance_groups/name=bosh/properties/aws/credentials_source?", Value: "env_or_profile"},
+		{Type: "remove", Path: "/cloud_provider/properties/aws/access_key_id"},
+		{Type: "remove", Path: "/cloud_provider/properties/aws/secret_access_key"},
+		{Type: "replace", Path: "/cloud_provider/properties/aws/credentials_source?", Value: "env_or_profile"},
+	}
+}
+
+// cpiCredentials are the credentials bbl was run with, for the cpi that
+// creates and deletes a director whose manifest holds none.
+func (m Manager) cpiCredentials(state storage.State) (CPICredentials, error) {
+	if state.IAAS == "gcp" && state.GCP.DirectorServiceAccount {
+		return CPICredentials{
+			GCPServiceAccountKey: state.GCP.ServiceAccountKey,
+		}, nil
+	}
+
+	if state.IAAS != "aws" || !state.AWS.IAMInstanceProfile {
+		return CPICredentials{}, nil
+	}
+
+	credentials, err := m.credentialsProvider.GetCredentials()
+	if err != nil {
+		return CPICredentials{}, err
+	}
+
+	return CPICredentials{
+		AWSAccessKeyID:     credentials.AccessKeyID,
+		AWSSecretAccessKey: credentials.SecretAccessKey,
+		AWSSessionToken:    credentials.SessionToken,
+	}, nil
+}
+
+// directorServiceAccountOps attach the director service account to the
+// director vm and leave the json key out of the manifest. The director cpi
+// uses the credentials of the vm and the cpi that creates the director finds
+// the key bbl was run with through GOOGLE_APPLICATION_CREDENTIALS.
+func directorServiceAccountOps(state storage.State) []op {
+	if state.IAAS != "gcp" || !state.GCP.DirectorServiceAccount {
+		return nil
+	}
+
+	return []op{
+		{Type: "replace", Path: "/resource_pools/name=vms/cloud_properties/service_account?", Value: "((director_service_account))"},
+		{Type: "replace", Path: "/resource_pools/name=vms/cloud_properties/service_scopes?", Value: []string{"https://www.googleapis.com/auth/cloud-platform"}},
+		{Type: "replace", Path: "/instance_groups/name=bosh/properties/google?", Value: map[string]string{"project": "((project_id))"}},
+		{Type: "replace", Path: "/cloud_provider/properties/google?", Value: map[string]string{"project": "((project_id))"}},
+	}
+}
+
 func (m Manager) generateIAASInputs(state storage.State) (iaasInputs, error) {
 	switch state.IAAS {
 	case "gcp":
@@ -3,9 +3,11 @@ package bosh_test
 import (
 	"errors"
 
+	"github.com/cloudfoundry/bosh-bootloader/aws"
 	"github.com/cloudfoundry/bosh-bootloader/aws/cloudformation"
 	"github.com/cloudfoundry/bosh-bootloader/bosh"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/patch"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 
 	. "github.com/onsi/ginkgo"
@@ -25,22 +27,24 @@ director_ssl:
 var _ = Describe("Manager", func() {
 	Describe("Create", func() {
 		var (
-			boshExecutor     *fakes.BOSHExecutor
-			terraformManager *fakes.TerraformManager
-			stackManager     *fakes.StackManager
-			logger           *fakes.Logger
-			boshManager      bosh.Manager
-			incomingGCPState storage.State
-			incomingAWSState storage.State
-			variablesMap     map[interface{}]interface{}
+			boshExecutor      *fakes.BOSHExecutor
+			terraformManager  *fakes.TerraformManager
+			stackManager      *fakes.StackManager
+			awsClientProvider *fakes.AWSClientProvider
+			logger            *fakes.Logger
+			boshManager       bosh.Manager
+			incomingGCPState  storage.State
+			incomingAWSState  storage.State
+			variablesMap      map[interface{}]interface{}
 		)
 
 		BeforeEach(func() {
 			terraformManager = &fakes.TerraformManager{}
 			stackManager = &fakes.StackManager{}
 			boshExecutor = &fakes.BOSHExecutor{}
+			awsClientProvider = &fakes.AWSClientProvider{}
 			logger = &fakes.Logger{}
-			boshManager = bosh.NewManager(boshExecutor, terraformManager, stackManager, logger)
+			boshManager = bosh.NewManager(boshExecutor, terraformManager, stackManager, awsClientProvider, logger)
 
 			terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{
 				"network_name":       "some-network",
@@ -68,8 +72,10 @@ var _ = Describe("Manager", func() {
 					},
 				},
 				TFState: "some-tf-state",
-				LB: storage.LB{
-					Type: "cf",
+				LBs: []storage.LB{
+					{
+						Type: "cf",
+					},
 				},
 			}
 
@@ -89,8 +95,10 @@ var _ = Describe("Manager", func() {
 					},
 				},
 				TFState: "some-tf-state",
-				LB: storage.LB{
-					Type: "cf",
+				LBs: []storage.LB{
+					{
+						Type: "cf",
+					},
 				},
 			}
 			variablesMap = map[interface{}]interface{}{
@@ -154,6 +162,102 @@ gcp_credentials_json: 'some-credential-json'`,
 				}))
 			})
 
+			It("sets the tags as director tags and labels the director vm", func() {
+				incomingGCPState.BOSH.UserOpsFile = "- some-user-op"
+				incomingGCPState.Tags = map[string]string{
+					"owner":       "some-owner",
+					"cost-center": "cc-123",
+				}
+
+				_, err := boshManager.Create(incomingGCPState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(boshExecutor.InterpolateCall.Receives.InterpolateInput.BBLOpsFile).To(Equal(`- type: replace
+  path: /instance_groups/name=bosh/properties/director/tags?
+  value:
+    cost-center: cc-123
+    owner: some-owner
+- type: replace
+  path: /resource_pools/name=vms/cloud_properties/labels?
+  value:
+    cost-center: cc-123
+    owner: some-owner`))
+				Expect(boshExecutor.InterpolateCall.Receives.InterpolateInput.OpsFile).To(Equal("- some-user-op"))
+			})
+
+			Context("when the environment has a director service account", func() {
+				BeforeEach(func() {
+					terraformManager.GetOutputsCall.Returns.Outputs["director_service_account"] = "some-service-account-email"
+					incomingGCPState.GCP.DirectorServiceAccount = true
+				})
+
+				It("deploys the director with the service account instead of the json key", func() {
+					_, err := boshManager.Create(incomingGCPState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(boshExecutor.InterpolateCall.Receives.InterpolateInput.DeploymentVars).To(HaveSuffix(`
+project_id: some-project-id
+director_service_account: some-service-account-email`))
+					Expect(boshExecutor.InterpolateCall.Receives.InterpolateInput.DeploymentVars).NotTo(ContainSubstring("gcp_credentials_json"))
+
+					Expect(boshExecutor.InterpolateCall.Receives.InterpolateInput.BBLOpsFile).To(Equal(`- type: replace
+  path: /resource_pools/name=vms/cloud_properties/service_account?
+  value: ((director_service_account))
+- type: replace
+  path: /resource_pools/name=vms/cloud_properties/service_scopes?
+  value:
+  - https://www.googleapis.com/auth/cloud-platform
+- type: replace
+  path: /instance_groups/name=bosh/properties/google?
+  value:
+    project: ((project_id))
+- type: replace
+  path: /cloud_provider/properties/google?
+  value:
+    project: ((project_id))`))
+				})
+
+				It("creates the director with the json key through the environment", func() {
+					_, err := boshManager.Create(incomingGCPState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(boshExecutor.CreateEnvCall.Receives.Input.CPICredentials).To(Equal(bosh.CPICredentials{
+						GCPServiceAccountKey: "some-credential-json",
+					}))
+				})
+
+				It("stores a manifest without the json key", func() {
+					boshManifest, err := bosh.Asset("vendor/github.com/cloudfoundry/bosh-deployment/bosh.yml")
+					Expect(err).NotTo(HaveOccurred())
+
+					cpiOpsFile, err := bosh.Asset("vendor/github.com/cloudfoundry/bosh-deployment/gcp/cpi.yml")
+					Expect(err).NotTo(HaveOccurred())
+
+					boshExecutor.InterpolateCall.Stub = func(input bosh.InterpolateInput) (bosh.InterpolateOutput, error) {
+						manifest, err := patch.Interpolate(string(boshManifest), string(cpiOpsFile), input.BBLOpsFile)
+						if err != nil {
+							return bosh.InterpolateOutput{}, err
+						}
+
+						return bosh.InterpolateOutput{Manifest: manifest, Variables: variablesMap}, nil
+					}
+
+					state, err := boshManager.Create(incomingGCPState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(state.BOSH.Manifest).NotTo(ContainSubstring("json_key"))
+					Expect(state.BOSH.Manifest).NotTo(ContainSubstring("gcp_credentials_json"))
+					Expect(state.BOSH.Manifest).NotTo(ContainSubstring("some-credential-json"))
+				})
+
+				It("deletes the director with the json key through the environment", func() {
+					err := boshManager.Delete(incomingGCPState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(boshExecutor.DeleteEnvCall.Receives.Input.CPICredentials.GCPServiceAccountKey).To(Equal("some-credential-json"))
+				})
+			})
+
 			It("returns a state with a proper bosh state", func() {
 				state, err := boshManager.Create(incomingGCPState)
 				Expect(err).NotTo(HaveOccurred())
@@ -184,8 +288,10 @@ gcp_credentials_json: 'some-credential-json'`,
 						DirectorSSLPrivateKey:  "some-private-key",
 					},
 					TFState: "some-tf-state",
-					LB: storage.LB{
-						Type: "cf",
+					LBs: []storage.LB{
+						{
+							Type: "cf",
+						},
 					},
 				}))
 			})
@@ -291,6 +397,123 @@ private_key: |-
 					}))
 				})
 
+				It("sets the tags as director tags and tags the director vm", func() {
+					incomingAWSState.Tags = map[string]string{"owner": "some-owner"}
+
+					_, err := boshManager.Create(incomingAWSState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(boshExecutor.InterpolateCall.Receives.InterpolateInput.BBLOpsFile).To(Equal(`- type: replace
+  path: /instance_groups/name=bosh/properties/director/tags?
+  value:
+    owner: some-owner
+- type: replace
+  path: /resource_pools/name=vms/cloud_properties/tags?
+  value:
+    owner: some-owner`))
+				})
+
+				Context("when the director uses an instance profile", func() {
+					BeforeEach(func() {
+						terraformManager.GetOutputsCall.Returns.Outputs["iam_instance_profile"] = "some-instance-profile"
+						incomingAWSState.AWS.IAMInstanceProfile = true
+						incomingAWSState.AWS.Profile = "some-profile"
+						awsClientProvider.GetCredentialsCall.Returns.Credentials = aws.Credentials{
+							AccessKeyID:     "some-access-key-id",
+							SecretAccessKey: "some-secret-access-key",
+							SessionToken:    "some-session-token",
+						}
+					})
+
+					It("deploys the director with the instance profile and no access keys", func() {
+						_, err := boshManager.Create(incomingAWSState)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(boshExecutor.InterpolateCall.Receives.InterpolateInput.DeploymentVars).To(Equal(`internal_cidr: 10.0.0.0/24
+internal_gw: 10.0.0.1
+internal_ip: 10.0.0.6
+director_name: bosh-some-env-id
+external_ip: some-bosh-elastic-ip
+az: some-bosh-subnet-az
+subnet_id: some-bosh-subnet
+iam_instance_profile: some-instance-profile
+default_key_name: some-keypair-name
+default_security_groups: [some-bosh-security-group]
+region: some-region
+private_key: |-
+  some-private-key`))
+
+						Expect(boshExecutor.InterpolateCall.Receives.InterpolateInput.BBLOpsFile).To(Equal(`- type: replace
+  path: /resource_pools/name=vms/cloud_properties/iam_instance_profile?
+  value: ((iam_instance_profile))
+- type: remove
+  path: /instance_groups/name=bosh/properties/aws/access_key_id
+- type: remove
+  path: /instance_groups/name=bosh/properties/aws/secret_access_key
+- type: replace
+  path: /instance_groups/name=bosh/properties/aws/credentials_source?
+  value: env_or_profile
+- type: remove
+  path: /cloud_provider/properties/aws/access_key_id
+- type: remove
+  path: /cloud_provider/properties/aws/secret_access_key
+- type: replace
+  path: /cloud_provider/properties/aws/credentials_source?
+  value: env_or_profile`))
+					})
+
+					It("creates the director with the credentials of bbl through the environment", func() {
+						_, err := boshManager.Create(incomingAWSState)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(awsClientProvider.GetCredentialsCall.CallCount).To(Equal(1))
+						Expect(boshExecutor.CreateEnvCall.Receives.Input.CPICredentials).To(Equal(bosh.CPICredentials{
+							AWSAccessKeyID:     "some-access-key-id",
+							AWSSecretAccessKey: "some-secret-access-key",
+							AWSSessionToken:    "some-session-token",
+						}))
+					})
+
+					It("stores a manifest without any access keys", func() {
+						boshManifest, err := bosh.Asset("vendor/github.com/cloudfoundry/bosh-deployment/bosh.yml")
+						Expect(err).NotTo(HaveOccurred())
+
+						cpiOpsFile, err := bosh.Asset("vendor/github.com/cloudfoundry/bosh-deployment/aws/cpi.yml")
+						Expect(err).NotTo(HaveOccurred())
+
+						boshExecutor.InterpolateCall.Stub = func(input bosh.InterpolateInput) (bosh.InterpolateOutput, error) {
+							manifest, err := patch.Interpolate(string(boshManifest), string(cpiOpsFile), input.BBLOpsFile)
+							if err != nil {
+								return bosh.InterpolateOutput{}, err
+							}
+
+							return bosh.InterpolateOutput{Manifest: manifest, Variables: variablesMap}, nil
+						}
+
+						state, err := boshManager.Create(incomingAWSState)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(state.BOSH.Manifest).To(ContainSubstring("credentials_source: env_or_profile"))
+						Expect(state.BOSH.Manifest).NotTo(ContainSubstring("access_key"))
+						Expect(state.BOSH.Manifest).NotTo(ContainSubstring("some-secret-access-key"))
+						Expect(state.BOSH.Variables).NotTo(ContainSubstring("some-secret-access-key"))
+					})
+
+					It("deletes the director with the credentials of bbl through the environment", func() {
+						err := boshManager.Delete(incomingAWSState)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(boshExecutor.DeleteEnvCall.Receives.Input.CPICredentials.AWSSecretAccessKey).To(Equal("some-secret-access-key"))
+					})
+
+					It("returns an error when the credentials cannot be resolved", func() {
+						awsClientProvider.GetCredentialsCall.Returns.Error = errors.New("failed to assume role")
+
+						_, err := boshManager.Create(incomingAWSState)
+						Expect(err).To(MatchError("failed to assume role"))
+					})
+				})
+
 				It("returns a state with a proper bosh state", func() {
 					state, err := boshManager.Create(incomingAWSState)
 					Expect(err).NotTo(HaveOccurred())
@@ -320,8 +543,10 @@ private_key: |-
 							DirectorSSLPrivateKey:  "some-private-key",
 						},
 						TFState: "some-tf-state",
-						LB: storage.LB{
-							Type: "cf",
+						LBs: []storage.LB{
+							{
+								Type: "cf",
+							},
 						},
 					}))
 				})
@@ -421,19 +646,21 @@ private_key: |-
 
 	Describe("Delete", func() {
 		var (
-			stackManager     *fakes.StackManager
-			boshExecutor     *fakes.BOSHExecutor
-			terraformManager *fakes.TerraformManager
-			logger           *fakes.Logger
-			boshManager      bosh.Manager
+			stackManager      *fakes.StackManager
+			boshExecutor      *fakes.BOSHExecutor
+			terraformManager  *fakes.TerraformManager
+			awsClientProvider *fakes.AWSClientProvider
+			logger            *fakes.Logger
+			boshManager       bosh.Manager
 		)
 
 		BeforeEach(func() {
 			terraformManager = &fakes.TerraformManager{}
 			stackManager = &fakes.StackManager{}
 			boshExecutor = &fakes.BOSHExecutor{}
+			awsClientProvider = &fakes.AWSClientProvider{}
 			logger = &fakes.Logger{}
-			boshManager = bosh.NewManager(boshExecutor, terraformManager, stackManager, logger)
+			boshManager = bosh.NewManager(boshExecutor, terraformManager, stackManager, awsClientProvider, logger)
 		})
 
 		It("calls delete env", func() {
@@ -507,19 +734,21 @@ private_key: |-
 
 	Describe("GetDeploymentVars", func() {
 		var (
-			stackManager     *fakes.StackManager
-			boshExecutor     *fakes.BOSHExecutor
-			terraformManager *fakes.TerraformManager
-			logger           *fakes.Logger
-			boshManager      bosh.Manager
+			stackManager      *fakes.StackManager
+			boshExecutor      *fakes.BOSHExecutor
+			terraformManager  *fakes.TerraformManager
+			awsClientProvider *fakes.AWSClientProvider
+			logger            *fakes.Logger
+			boshManager       bosh.Manager
 		)
 
 		BeforeEach(func() {
 			terraformManager = &fakes.TerraformManager{}
 			stackManager = &fakes.StackManager{}
 			boshExecutor = &fakes.BOSHExecutor{}
+			awsClientProvider = &fakes.AWSClientProvider{}
 			logger = &fakes.Logger{}
-			boshManager = bosh.NewManager(boshExecutor, terraformManager, stackManager, logger)
+			boshManager = bosh.NewManager(boshExecutor, terraformManager, stackManager, awsClientProvider, logger)
 		})
 
 		Context("gcp", func() {
@@ -545,8 +774,10 @@ private_key: |-
 						},
 					},
 					TFState: "some-tf-state",
-					LB: storage.LB{
-						Type: "cf",
+					LBs: []storage.LB{
+						{
+							Type: "cf",
+						},
 					},
 				}
 
@@ -598,8 +829,10 @@ gcp_credentials_json: 'some-credential-json'`))
 							"some-key": "some-value",
 						},
 					},
-					LB: storage.LB{
-						Type: "cf",
+					LBs: []storage.LB{
+						{
+							Type: "cf",
+						},
 					},
 				}
 			})
@@ -701,19 +934,21 @@ private_key: |-
 
 	Describe("Version", func() {
 		var (
-			stackManager     *fakes.StackManager
-			boshExecutor     *fakes.BOSHExecutor
-			terraformManager *fakes.TerraformManager
-			logger           *fakes.Logger
-			boshManager      bosh.Manager
+			stackManager      *fakes.StackManager
+			boshExecutor      *fakes.BOSHExecutor
+			terraformManager  *fakes.TerraformManager
+			awsClientProvider *fakes.AWSClientProvider
+			logger            *fakes.Logger
+			boshManager       bosh.Manager
 		)
 
 		BeforeEach(func() {
 			terraformManager = &fakes.TerraformManager{}
 			stackManager = &fakes.StackManager{}
 			boshExecutor = &fakes.BOSHExecutor{}
+			awsClientProvider = &fakes.AWSClientProvider{}
 			logger = &fakes.Logger{}
-			boshManager = bosh.NewManager(boshExecutor, terraformManager, stackManager, logger)
+			boshManager = bosh.NewManager(boshExecutor, terraformManager, stackManager, awsClientProvider, logger)
 
 			boshExecutor.VersionCall.Returns.Version = "2.0.0"
 		})
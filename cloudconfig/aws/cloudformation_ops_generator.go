@@ -45,12 +45,16 @@ func (a CloudFormationOpsGenerator) Generate(state storage.State) (string, error
 }
 
 func (a CloudFormationOpsGenerator) generateCloudFormationAWSOps(state storage.State) ([]op, error) {
-	azs, err := a.availabilityZoneRetriever.Retrieve(state.AWS.Region)
+	ops, err := generateVMTypeOps(state)
+	if err != nil {
+		return []op{}, err
+	}
+
+	azs, err := state.AWS.AvailabilityZones(a.availabilityZoneRetriever)
 	if err != nil {
 		return []op{}, err
 	}
 
-	ops := []op{}
 	for i, awsAZ := range azs {
 		op := createOp("replace", "/azs/-", az{
 			Name: fmt.Sprintf("z%d", i+1),
@@ -74,8 +74,19 @@ var _ = Describe("CloudFormationOpsGenerator", func() {
 			Expect(opsYAML).To(gomegamatchers.MatchYAML(expectedOpsYAML))
 		})
 
+		It("uses the availability zones from the state when they are present", func() {
+			availabilityZoneRetriever.RetrieveCall.Returns.Error = errors.New("failed to retrieve")
+			incomingState.AWS.AZs = []string{"us-east-1a", "us-east-1b", "us-east-1c"}
+
+			opsYAML, err := opsGenerator.Generate(incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(availabilityZoneRetriever.RetrieveCall.Receives.Region).To(BeEmpty())
+			Expect(opsYAML).To(gomegamatchers.MatchYAML(expectedOpsYAML))
+		})
+
 		DescribeTable("returns an ops file with additional vm extensions to support lb", func(lbType string, lbOutputs map[string]string) {
-			incomingState.LB.Type = lbType
+			incomingState.LBs = []storage.LB{{Type: lbType}}
 
 			expectedLBOpsFile, err := ioutil.ReadFile(filepath.Join("fixtures", fmt.Sprintf("aws-%s-lb-ops.yml", lbType)))
 			Expect(err).NotTo(HaveOccurred())
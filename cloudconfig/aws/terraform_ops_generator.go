@@ -65,7 +65,8 @@ type lb struct {
 }
 
 type lbCloudProperties struct {
-	ELBs           []string
+	ELBs           []string `yaml:",omitempty"`
+	LBTargetGroups []string `yaml:"lb_target_groups,omitempty"`
 	SecurityGroups []string `yaml:"security_groups"`
 }
 
@@ -107,12 +108,16 @@ func createOp(opType, opPath string, value interface{}) op {
 }
 
 func (a TerraformOpsGenerator) generateTerraformAWSOps(state storage.State) ([]op, error) {
-	azs, err := a.availabilityZoneRetriever.Retrieve(state.AWS.Region)
+	ops, err := generateVMTypeOps(state)
+	if err != nil {
+		return []op{}, err
+	}
+
+	azs, err := state.AWS.AvailabilityZones(a.availabilityZoneRetriever)
 	if err != nil {
 		return []op{}, err
 	}
 
-	ops := []op{}
 	for i, awsAZ := range azs {
 		op := createOp("replace", "/azs/-", az{
 			Name: fmt.Sprintf("z%d", i+1),
@@ -170,70 +175,123 @@ func (a TerraformOpsGenerator) generateTerraformAWSOps(state storage.State) ([]o
 		Type:    "manual",
 	}))
 
-	switch state.LB.Type {
-	case "cf":
-		cfRouterLoadBalancer, ok := terraformOutputs["cf_router_load_balancer"].(string)
-		if !ok {
-			return []op{}, errors.New("missing cf_router_load_balancer terraform output")
-		}
-
-		cfRouterInternalSecurityGroup, ok := terraformOutputs["cf_router_internal_security_group"].(string)
-		if !ok {
-			return []op{}, errors.New("missing cf_router_internal_security_group terraform output")
-		}
-
-		cfSSHProxyLoadBalancer, ok := terraformOutputs["cf_ssh_proxy_load_balancer"].(string)
-		if !ok {
-			return []op{}, errors.New("missing cf_ssh_proxy_load_balancer terraform output")
-		}
-
-		cfSSHProxyInternalSecurityGroup, ok := terraformOutputs["cf_ssh_proxy_internal_security_group"].(string)
-		if !ok {
-			return []op{}, errors.New("missing cf_ssh_proxy_internal_security_group terraform output")
-		}
-
-		ops = append(ops, createOp("replace", "/vm_extensions/-", lb{
-			Name: "router-lb",
-			CloudProperties: lbCloudProperties{
-				ELBs: []string{cfRouterLoadBalancer},
-				SecurityGroups: []string{
-					cfRouterInternalSecurityGroup,
-					internalSecurityGroup,
+	for _, loadBalancer := range state.LBs {
+		switch loadBalancer.Type {
+		case "cf":
+			cfRouterCloudProperties := lbCloudProperties{}
+			if loadBalancer.Kind != "" {
+				cfRouterTargetGroup, ok := terraformOutputs["cf_router_target_group"].(string)
+				if !ok {
+					return []op{}, errors.New("missing cf_router_target_group terraform output")
+				}
+
+				cfRouterCloudProperties.LBTargetGroups = []string{cfRouterTargetGroup}
+			} else {
+				cfRouterLoadBalancer, ok := terraformOutputs["cf_router_load_balancer"].(string)
+				if !ok {
+					return []op{}, errors.New("missing cf_router_load_balancer terraform output")
+				}
+
+				cfRouterCloudProperties.ELBs = []string{cfRouterLoadBalancer}
+			}
+
+			cfRouterInternalSecurityGroup, ok := terraformOutputs["cf_router_internal_security_group"].(string)
+			if !ok {
+				return []op{}, errors.New("missing cf_router_internal_security_group terraform output")
+			}
+
+			cfSSHProxyLoadBalancer, ok := terraformOutputs["cf_ssh_proxy_load_balancer"].(string)
+			if !ok {
+				return []op{}, errors.New("missing cf_ssh_proxy_load_balancer terraform output")
+			}
+
+			cfSSHProxyInternalSecurityGroup, ok := terraformOutputs["cf_ssh_proxy_internal_security_group"].(string)
+			if !ok {
+				return []op{}, errors.New("missing cf_ssh_proxy_internal_security_group terraform output")
+			}
+
+			cfRouterCloudProperties.SecurityGroups = []string{
+				cfRouterInternalSecurityGroup,
+				internalSecurityGroup,
+			}
+
+			ops = append(ops, createOp("replace", "/vm_extensions/-", lb{
+				Name:            "router-lb",
+				CloudProperties: cfRouterCloudProperties,
+			}))
+
+			ops = append(ops, createOp("replace", "/vm_extensions/-", lb{
+				Name: "ssh-proxy-lb",
+				CloudProperties: lbCloudProperties{
+					ELBs: []string{cfSSHProxyLoadBalancer},
+					SecurityGroups: []string{
+						cfSSHProxyInternalSecurityGroup,
+						internalSecurityGroup,
+					},
 				},
-			},
-		}))
-
-		ops = append(ops, createOp("replace", "/vm_extensions/-", lb{
-			Name: "ssh-proxy-lb",
-			CloudProperties: lbCloudProperties{
-				ELBs: []string{cfSSHProxyLoadBalancer},
-				SecurityGroups: []string{
-					cfSSHProxyInternalSecurityGroup,
-					internalSecurityGroup,
+			}))
+		case "concourse":
+			concourseCloudProperties := lbCloudProperties{}
+			if loadBalancer.Kind != "" {
+				for _, output := range []string{"concourse_target_group", "concourse_tsa_target_group"} {
+					concourseTargetGroup, ok := terraformOutputs[output].(string)
+					if !ok {
+						return []op{}, fmt.Errorf("missing %s terraform output", output)
+					}
+
+					concourseCloudProperties.LBTargetGroups = append(concourseCloudProperties.LBTargetGroups, concourseTargetGroup)
+				}
+			} else {
+				concourseLoadBalancer, ok := terraformOutputs["concourse_load_balancer"].(string)
+				if !ok {
+					return []op{}, errors.New("missing concourse_load_balancer terraform output")
+				}
+
+				concourseCloudProperties.ELBs = []string{concourseLoadBalancer}
+			}
+
+			concourseInternalSecurityGroup, ok := terraformOutputs["concourse_internal_security_group"].(string)
+			if !ok {
+				return []op{}, errors.New("missing concourse_internal_security_group terraform output")
+			}
+
+			concourseCloudProperties.SecurityGroups = []string{
+				concourseInternalSecurityGroup,
+				internalSecurityGroup,
+			}
+
+			ops = append(ops, createOp("replace", "/vm_extensions/-", lb{
+				Name:            "lb",
+				CloudProperties: concourseCloudProperties,
+			}))
+		default:
+			if !loadBalancer.IsCustom() {
+				continue
+			}
+
+			customLoadBalancerOutput := fmt.Sprintf("%s_load_balancer", loadBalancer.Type)
+			customLoadBalancer, ok := terraformOutputs[customLoadBalancerOutput].(string)
+			if !ok {
+				return []op{}, fmt.Errorf("missing %s terraform output", customLoadBalancerOutput)
+			}
+
+			customInternalSecurityGroupOutput := fmt.Sprintf("%s_internal_security_group", loadBalancer.Type)
+			customInternalSecurityGroup, ok := terraformOutputs[customInternalSecurityGroupOutput].(string)
+			if !ok {
+				return []op{}, fmt.Errorf("missing %s terraform output", customInternalSecurityGroupOutput)
+			}
+
+			ops = append(ops, createOp("replace", "/vm_extensions/-", lb{
+				Name: fmt.Sprintf("%s-lb", loadBalancer.Type),
+				CloudProperties: lbCloudProperties{
+					ELBs: []string{customLoadBalancer},
+					SecurityGroups: []string{
+						customInternalSecurityGroup,
+						internalSecurityGroup,
+					},
 				},
-			},
-		}))
-	case "concourse":
-		concourseLoadBalancer, ok := terraformOutputs["concourse_load_balancer"].(string)
-		if !ok {
-			return []op{}, errors.New("missing concourse_load_balancer terraform output")
+			}))
 		}
-
-		concourseInternalSecurityGroup, ok := terraformOutputs["concourse_internal_security_group"].(string)
-		if !ok {
-			return []op{}, errors.New("missing concourse_internal_security_group terraform output")
-		}
-
-		ops = append(ops, createOp("replace", "/vm_extensions/-", lb{
-			Name: "lb",
-			CloudProperties: lbCloudProperties{
-				ELBs: []string{concourseLoadBalancer},
-				SecurityGroups: []string{
-					concourseInternalSecurityGroup,
-					internalSecurityGroup,
-				},
-			},
-		}))
 	}
 
 	return ops, nil
@@ -59,11 +59,16 @@ var _ = Describe("TerraformOpsGenerator", func() {
 				},
 				"internal_security_group":              "some-internal-security-group",
 				"cf_router_load_balancer":              "some-cf-router-lb",
+				"cf_router_target_group":               "some-cf-router-target-group",
 				"cf_router_internal_security_group":    "some-cf-router-internal-security-group",
 				"cf_ssh_proxy_load_balancer":           "some-cf-ssh-proxy-lb",
 				"cf_ssh_proxy_internal_security_group": "some-cf-ssh-proxy-internal-security-group",
 				"concourse_load_balancer":              "some-concourse-lb",
+				"concourse_target_group":               "some-concourse-target-group",
+				"concourse_tsa_target_group":           "some-concourse-tsa-target-group",
 				"concourse_internal_security_group":    "some-concourse-internal-security-group",
+				"vault_load_balancer":                  "some-vault-lb",
+				"vault_internal_security_group":        "some-vault-internal-security-group",
 			}
 
 			opsGenerator = aws.NewTerraformOpsGenerator(availabilityZoneRetriever, terraformManager)
@@ -98,7 +103,7 @@ var _ = Describe("TerraformOpsGenerator", func() {
 			})
 
 			It("returns an ops file to transform base cloud config into aws specific cloud config", func() {
-				incomingState.LB.Type = "cf"
+				incomingState.LBs = []storage.LB{{Type: "cf"}}
 				opsYAML, err := opsGenerator.Generate(incomingState)
 				Expect(err).NotTo(HaveOccurred())
 
@@ -119,7 +124,7 @@ var _ = Describe("TerraformOpsGenerator", func() {
 			})
 
 			It("returns an ops file to transform base cloud config into aws specific cloud config", func() {
-				incomingState.LB.Type = "concourse"
+				incomingState.LBs = []storage.LB{{Type: "concourse"}}
 				opsYAML, err := opsGenerator.Generate(incomingState)
 				Expect(err).NotTo(HaveOccurred())
 
@@ -130,7 +135,125 @@ var _ = Describe("TerraformOpsGenerator", func() {
 			})
 		})
 
+		Context("when there are cf and concourse lbs", func() {
+			BeforeEach(func() {
+				baseOpsYAMLContents, err := ioutil.ReadFile(filepath.Join("fixtures", "aws-ops.yml"))
+				Expect(err).NotTo(HaveOccurred())
+				cfLBOpsYAMLContents, err := ioutil.ReadFile(filepath.Join("fixtures", "aws-cf-lb-ops.yml"))
+				Expect(err).NotTo(HaveOccurred())
+				concourseLBOpsYAMLContents, err := ioutil.ReadFile(filepath.Join("fixtures", "aws-concourse-lb-ops.yml"))
+				Expect(err).NotTo(HaveOccurred())
+				expectedOpsYAML = strings.Join([]string{string(baseOpsYAMLContents), string(cfLBOpsYAMLContents), string(concourseLBOpsYAMLContents)}, "\n")
+			})
+
+			It("returns an ops file with the vm extensions of each lb", func() {
+				incomingState.LBs = []storage.LB{{Type: "cf"}, {Type: "concourse"}}
+				opsYAML, err := opsGenerator.Generate(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(opsYAML).To(gomegamatchers.MatchYAML(expectedOpsYAML))
+			})
+		})
+
+		Context("when there are application and network lbs", func() {
+			BeforeEach(func() {
+				baseOpsYAMLContents, err := ioutil.ReadFile(filepath.Join("fixtures", "aws-ops.yml"))
+				Expect(err).NotTo(HaveOccurred())
+				cfLBOpsYAMLContents, err := ioutil.ReadFile(filepath.Join("fixtures", "aws-cf-elbv2-lb-ops.yml"))
+				Expect(err).NotTo(HaveOccurred())
+				concourseLBOpsYAMLContents, err := ioutil.ReadFile(filepath.Join("fixtures", "aws-concourse-elbv2-lb-ops.yml"))
+				Expect(err).NotTo(HaveOccurred())
+				expectedOpsYAML = strings.Join([]string{string(baseOpsYAMLContents), string(cfLBOpsYAMLContents), string(concourseLBOpsYAMLContents)}, "\n")
+			})
+
+			It("returns an ops file with vm extensions that use lb target groups", func() {
+				incomingState.LBs = []storage.LB{{Type: "cf", Kind: "alb"}, {Type: "concourse", Kind: "nlb"}}
+				opsYAML, err := opsGenerator.Generate(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(opsYAML).To(gomegamatchers.MatchYAML(expectedOpsYAML))
+			})
+		})
+
+		Context("when there is a custom lb", func() {
+			BeforeEach(func() {
+				baseOpsYAMLContents, err := ioutil.ReadFile(filepath.Join("fixtures", "aws-ops.yml"))
+				Expect(err).NotTo(HaveOccurred())
+				lbsOpsYAMLContents, err := ioutil.ReadFile(filepath.Join("fixtures", "aws-custom-lb-ops.yml"))
+				Expect(err).NotTo(HaveOccurred())
+				expectedOpsYAML = strings.Join([]string{string(baseOpsYAMLContents), string(lbsOpsYAMLContents)}, "\n")
+			})
+
+			It("returns an ops file with a vm extension named after the custom lb", func() {
+				incomingState.LBs = []storage.LB{{Type: "vault", Spec: &storage.CustomLBSpec{Name: "vault"}}}
+				opsYAML, err := opsGenerator.Generate(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(opsYAML).To(gomegamatchers.MatchYAML(expectedOpsYAML))
+			})
+		})
+
+		Context("when the state has availability zones", func() {
+			It("uses them instead of every zone in the region", func() {
+				baseOpsYAMLContents, err := ioutil.ReadFile(filepath.Join("fixtures", "aws-ops.yml"))
+				Expect(err).NotTo(HaveOccurred())
+
+				availabilityZoneRetriever.RetrieveCall.Returns.Error = errors.New("failed to retrieve")
+				incomingState.AWS.AZs = []string{"us-east-1a", "us-east-1b", "us-east-1c"}
+
+				opsYAML, err := opsGenerator.Generate(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(availabilityZoneRetriever.RetrieveCall.Receives.Region).To(BeEmpty())
+				Expect(opsYAML).To(gomegamatchers.MatchYAML(string(baseOpsYAMLContents)))
+			})
+		})
+
+		Context("when the state has a vm type catalog", func() {
+			It("generates vm_types that are available in the region", func() {
+				incomingState.AWS.Region = "eu-west-2"
+				incomingState.VMTypeCatalog = `
+vm_types:
+- name: small
+  machine_type: m5.large
+`
+				opsYAML, err := opsGenerator.Generate(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(opsYAML).To(ContainSubstring(`- type: replace
+  path: /vm_types/name=small/cloud_properties?
+  value:
+    instance_type: m5.large
+    ephemeral_disk:
+      size: 10240
+      type: gp2
+`))
+				Expect(opsYAML).To(ContainSubstring(`- type: replace
+  path: /vm_types/name=default/cloud_properties?
+  value:
+    instance_type: m4.large
+    ephemeral_disk:
+      size: 10240
+      type: gp2
+`))
+			})
+		})
+
 		Context("failure cases", func() {
+			It("returns an error when a vm type in the catalog is not available in the region", func() {
+				_, err := opsGenerator.Generate(storage.State{
+					AWS: storage.AWS{
+						Region: "eu-west-2",
+					},
+					VMTypeCatalog: `
+vm_types:
+- name: default
+  machine_type: m3.medium
+`,
+				})
+				Expect(err).To(MatchError(`vm_type "default" uses machine type "m3.medium" which is not available in eu-west-2`))
+			})
+
 			It("returns an error when az retriever fails to retrieve", func() {
 				availabilityZoneRetriever.RetrieveCall.Returns.Error = errors.New("failed to retrieve")
 				_, err := opsGenerator.Generate(storage.State{})
@@ -163,8 +286,10 @@ var _ = Describe("TerraformOpsGenerator", func() {
 			DescribeTable("when an terraform output is missing", func(outputKey, lbType string) {
 				delete(terraformManager.GetOutputsCall.Returns.Outputs, outputKey)
 				_, err := opsGenerator.Generate(storage.State{
-					LB: storage.LB{
-						Type: lbType,
+					LBs: []storage.LB{
+						{
+							Type: lbType,
+						},
 					},
 				})
 				Expect(err).To(MatchError(fmt.Sprintf("missing %s terraform output", outputKey)))
@@ -181,6 +306,39 @@ var _ = Describe("TerraformOpsGenerator", func() {
 				Entry("when concourse_load_balancer is missing", "concourse_load_balancer", "concourse"),
 				Entry("when concourse_internal_security_group is missing", "concourse_internal_security_group", "concourse"),
 			)
+
+			DescribeTable("when a target group terraform output is missing", func(outputKey, lbType string) {
+				delete(terraformManager.GetOutputsCall.Returns.Outputs, outputKey)
+				_, err := opsGenerator.Generate(storage.State{
+					LBs: []storage.LB{
+						{
+							Type: lbType,
+							Kind: "alb",
+						},
+					},
+				})
+				Expect(err).To(MatchError(fmt.Sprintf("missing %s terraform output", outputKey)))
+			},
+				Entry("when cf_router_target_group is missing", "cf_router_target_group", "cf"),
+				Entry("when concourse_target_group is missing", "concourse_target_group", "concourse"),
+				Entry("when concourse_tsa_target_group is missing", "concourse_tsa_target_group", "concourse"),
+			)
+
+			DescribeTable("when a custom lb terraform output is missing", func(outputKey string) {
+				delete(terraformManager.GetOutputsCall.Returns.Outputs, outputKey)
+				_, err := opsGenerator.Generate(storage.State{
+					LBs: []storage.LB{
+						{
+							Type: "vault",
+							Spec: &storage.CustomLBSpec{Name: "vault"},
+						},
+					},
+				})
+				Expect(err).To(MatchError(fmt.Sprintf("missing %s terraform output", outputKey)))
+			},
+				Entry("when vault_load_balancer is missing", "vault_load_balancer"),
+				Entry("when vault_internal_security_group is missing", "vault_internal_security_group"),
+			)
 		})
 	})
 })
@@ -0,0 +1,53 @@
+package aws
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry/bosh-bootloader/cloudconfig/vmtypes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type vmType struct {
+	Name            string
+	CloudProperties vmTypeCloudProperties `yaml:"cloud_properties"`
+}
+
+type vmTypeCloudProperties struct {
+	InstanceType  string        `yaml:"instance_type"`
+	EphemeralDisk ephemeralDisk `yaml:"ephemeral_disk"`
+}
+
+type ephemeralDisk struct {
+	Size int
+	Type string
+}
+
+func generateVMTypeOps(state storage.State) ([]op, error) {
+	catalog, err := vmtypes.Load("aws", state.AWS.Region, state.VMTypeCatalog)
+	if err != nil {
+		return []op{}, err
+	}
+
+	ops := []op{createOp("replace", "/compilation/vm_type", catalog.Compilation)}
+	for _, t := range catalog.VMTypes {
+		cloudProperties := vmTypeCloudProperties{
+			InstanceType: t.MachineType,
+			EphemeralDisk: ephemeralDisk{
+				Size: 10240,
+				Type: "gp2",
+			},
+		}
+
+		if vmtypes.IsBase(t.Name) {
+			ops = append(ops, createOp("replace", fmt.Sprintf("/vm_types/name=%s/cloud_properties?", t.Name), cloudProperties))
+			continue
+		}
+
+		ops = append(ops, createOp("replace", "/vm_types/-", vmType{
+			Name:            t.Name,
+			CloudProperties: cloudProperties,
+		}))
+	}
+
+	return ops, nil
+}
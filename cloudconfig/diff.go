@@ -0,0 +1,247 @@
+package cloudconfig
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+type change struct {
+	op    string
+	path  string
+	value interface{}
+	from  interface{}
+}
+
+// DiffYAML compares two YAML documents structurally and describes every
+// change needed to turn current into desired. Lists whose items all have a
+// unique name are matched by name, the same way go-patch paths address them.
+// An empty string is returned when the documents are equivalent.
+func DiffYAML(current, desired string) (string, error) {
+	var currentDocument, desiredDocument interface{}
+
+	err := yaml.Unmarshal([]byte(current), &currentDocument)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse current cloud config: %s", err)
+	}
+
+	err = yaml.Unmarshal([]byte(desired), &desiredDocument)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse desired cloud config: %s", err)
+	}
+
+	changes := diffValues("", currentDocument, desiredDocument)
+
+	lines := []string{}
+	for _, c := range changes {
+		lines = append(lines, c.String())
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+func (c change) String() string {
+	path := c.path
+	if path == "" {
+		path = "/"
+	}
+
+	switch c.op {
+	case "~":
+		return fmt.Sprintf("~ %s: %s -> %s", path, formatScalar(c.from), formatScalar(c.value))
+	default:
+		if isScalar(c.value) {
+			return fmt.Sprintf("%s %s: %s", c.op, path, formatScalar(c.value))
+		}
+
+		contents, err := yaml.Marshal(c.value)
+		if err != nil {
+			return fmt.Sprintf("%s %s", c.op, path)
+		}
+
+		lines := strings.Split(strings.TrimRight(string(contents), "\n"), "\n")
+		for i := range lines {
+			lines[i] = "    " + lines[i]
+		}
+
+		return fmt.Sprintf("%s %s:\n%s", c.op, path, strings.Join(lines, "\n"))
+	}
+}
+
+func diffValues(path string, current, desired interface{}) []change {
+	if current == nil && desired == nil {
+		return nil
+	}
+
+	// An empty document is treated as an empty map so that its diff
+	// against a cloud config lists each top level key.
+	if path == "" && current == nil {
+		current = map[interface{}]interface{}{}
+	}
+
+	if path == "" && desired == nil {
+		desired = map[interface{}]interface{}{}
+	}
+
+	if current == nil {
+		return []change{{op: "+", path: path, value: desired}}
+	}
+
+	if desired == nil {
+		return []change{{op: "-", path: path, value: current}}
+	}
+
+	switch currentValue := current.(type) {
+	case map[interface{}]interface{}:
+		if desiredValue, ok := desired.(map[interface{}]interface{}); ok {
+			return diffMaps(path, currentValue, desiredValue)
+		}
+	case []interface{}:
+		if desiredValue, ok := desired.([]interface{}); ok {
+			return diffSlices(path, currentValue, desiredValue)
+		}
+	}
+
+	if reflect.DeepEqual(current, desired) {
+		return nil
+	}
+
+	if isScalar(current) && isScalar(desired) {
+		return []change{{op: "~", path: path, from: current, value: desired}}
+	}
+
+	return []change{
+		{op: "-", path: path, value: current},
+		{op: "+", path: path, value: desired},
+	}
+}
+
+func diffMaps(path string, current, desired map[interface{}]interface{}) []change {
+	keys := map[string]interface{}{}
+	for key := range current {
+		keys[fmt.Sprint(key)] = key
+	}
+	for key := range desired {
+		keys[fmt.Sprint(key)] = key
+	}
+
+	sortedKeys := []string{}
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	changes := []change{}
+	for _, key := range sortedKeys {
+		changes = append(changes, diffValues(fmt.Sprintf("%s/%s", path, key), current[keys[key]], desired[keys[key]])...)
+	}
+
+	return changes
+}
+
+func diffSlices(path string, current, desired []interface{}) []change {
+	currentNames, currentNamed := namesOf(current)
+	desiredNames, desiredNamed := namesOf(desired)
+
+	changes := []change{}
+	if !currentNamed || !desiredNamed {
+		for i := 0; i < len(current) || i < len(desired); i++ {
+			var currentItem, desiredItem interface{}
+			if i < len(current) {
+				currentItem = current[i]
+			}
+			if i < len(desired) {
+				desiredItem = desired[i]
+			}
+
+			if currentItem != nil && desiredItem == nil {
+				changes = append(changes, change{op: "-", path: fmt.Sprintf("%s/%d", path, i), value: currentItem})
+				continue
+			}
+			if currentItem == nil && desiredItem != nil {
+				changes = append(changes, change{op: "+", path: fmt.Sprintf("%s/%d", path, i), value: desiredItem})
+				continue
+			}
+			changes = append(changes, diffValues(fmt.Sprintf("%s/%d", path, i), currentItem, desiredItem)...)
+		}
+
+		return changes
+	}
+
+	for i, name := range currentNames {
+		if _, ok := indexOf(desiredNames, name); !ok {
+			changes = append(changes, change{op: "-", path: fmt.Sprintf("%s/name=%s", path, name), value: current[i]})
+		}
+	}
+
+	for i, name := range desiredNames {
+		itemPath := fmt.Sprintf("%s/name=%s", path, name)
+
+		j, ok := indexOf(currentNames, name)
+		if !ok {
+			changes = append(changes, change{op: "+", path: itemPath, value: desired[i]})
+			continue
+		}
+
+		changes = append(changes, diffValues(itemPath, current[j], desired[i])...)
+	}
+
+	return changes
+}
+
+func namesOf(items []interface{}) ([]string, bool) {
+	names := []string{}
+	seen := map[string]bool{}
+
+	for _, item := range items {
+		itemMap, ok := item.(map[interface{}]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		name, ok := itemMap["name"]
+		if !ok || !isScalar(name) {
+			return nil, false
+		}
+
+		nameString := fmt.Sprint(name)
+		if seen[nameString] {
+			return nil, false
+		}
+
+		seen[nameString] = true
+		names = append(names, nameString)
+	}
+
+	return names, true
+}
+
+func indexOf(names []string, name string) (int, bool) {
+	for i, n := range names {
+		if n == name {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+func isScalar(value interface{}) bool {
+	switch value.(type) {
+	case map[interface{}]interface{}, []interface{}:
+		return false
+	default:
+		return true
+	}
+}
+
+func formatScalar(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+
+	return fmt.Sprint(value)
+}
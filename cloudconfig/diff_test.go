@@ -0,0 +1,122 @@
+package cloudconfig_test
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/cloudconfig"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DiffYAML", func() {
+	It("returns an empty diff when the documents are equivalent", func() {
+		diff, err := cloudconfig.DiffYAML(`
+vm_types:
+- name: default
+  cloud_properties:
+    instance_type: m3.medium
+compilation:
+  workers: 5
+`, `
+compilation:
+  workers: 5
+vm_types:
+- cloud_properties:
+    instance_type: m3.medium
+  name: default
+`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff).To(BeEmpty())
+	})
+
+	It("describes changed values by the path of the named item", func() {
+		diff, err := cloudconfig.DiffYAML(`
+vm_types:
+- name: default
+  cloud_properties:
+    instance_type: m3.medium
+- name: large
+  cloud_properties:
+    instance_type: m3.large
+`, `
+vm_types:
+- name: large
+  cloud_properties:
+    instance_type: m3.large
+- name: default
+  cloud_properties:
+    instance_type: m4.large
+`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff).To(Equal("~ /vm_types/name=default/cloud_properties/instance_type: m3.medium -> m4.large"))
+	})
+
+	It("describes added and removed items", func() {
+		diff, err := cloudconfig.DiffYAML(`
+networks:
+- name: private
+  type: manual
+vm_extensions:
+- name: some-old-extension
+`, `
+networks:
+- name: private
+  type: manual
+vm_extensions:
+- name: lb
+  cloud_properties:
+    elbs: [some-elb]
+`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff).To(Equal(`- /vm_extensions/name=some-old-extension:
+    name: some-old-extension
++ /vm_extensions/name=lb:
+    cloud_properties:
+      elbs:
+      - some-elb
+    name: lb`))
+	})
+
+	It("compares lists without names by index", func() {
+		diff, err := cloudconfig.DiffYAML(`
+networks:
+- name: private
+  subnets:
+  - range: 10.0.16.0/20
+    az: z1
+`, `
+networks:
+- name: private
+  subnets:
+  - range: 10.0.16.0/20
+    az: z1
+  - range: 10.0.32.0/20
+    az: z2
+`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff).To(Equal(`+ /networks/name=private/subnets/1:
+    az: z2
+    range: 10.0.32.0/20`))
+	})
+
+	It("describes every top level key as added when there is no current document", func() {
+		diff, err := cloudconfig.DiffYAML("", `
+compilation:
+  workers: 5
+`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff).To(Equal(`+ /compilation:
+    workers: 5`))
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when the current document is not valid yaml", func() {
+			_, err := cloudconfig.DiffYAML("%%%", "")
+			Expect(err).To(MatchError(ContainSubstring("failed to parse current cloud config")))
+		})
+
+		It("returns an error when the desired document is not valid yaml", func() {
+			_, err := cloudconfig.DiffYAML("", "%%%")
+			Expect(err).To(MatchError(ContainSubstring("failed to parse desired cloud config")))
+		})
+	})
+})
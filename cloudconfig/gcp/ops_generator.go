@@ -20,7 +20,7 @@ type terraformManager interface {
 }
 
 type zones interface {
-	Get(string) []string
+	Get(string) ([]string, error)
 }
 
 type op struct {
@@ -109,9 +109,19 @@ func createOp(opType, opPath string, value interface{}) op {
 }
 
 func (o *OpsGenerator) generateGCPOps(state storage.State) ([]op, error) {
-	var ops []op
+	ops, err := generateVMTypeOps(state)
+	if err != nil {
+		return []op{}, err
+	}
+
+	zones := state.GCP.Zones
+	if len(zones) == 0 {
+		zones, err = o.zones.Get(state.GCP.Region)
+		if err != nil {
+			return []op{}, err
+		}
+	}
 
-	zones := o.zones.Get(state.GCP.Region)
 	for i, zone := range zones {
 		ops = append(ops, createOp("replace", "/azs/-", az{
 			Name: fmt.Sprintf("z%d", i+1),
@@ -156,47 +166,79 @@ func (o *OpsGenerator) generateGCPOps(state storage.State) ([]op, error) {
 		Type:    "manual",
 	}))
 
-	if state.LB.Type == "concourse" {
-		ops = append(ops, createOp("replace", "/vm_extensions/-", lb{
-			Name: "lb",
-			CloudProperties: lbCloudProperties{
-				TargetPool: outputs["concourse_target_pool"].(string),
-			},
-		}))
-	}
-
-	if state.LB.Type == "cf" {
-		ops = append(ops, createOp("replace", "/vm_extensions/-", lb{
-			Name: "cf-router-network-properties",
-			CloudProperties: lbCloudProperties{
-				BackendService: outputs["router_backend_service"].(string),
-				TargetPool:     outputs["ws_target_pool"].(string),
-				Tags: []string{
-					outputs["router_backend_service"].(string),
-					outputs["ws_target_pool"].(string),
+	for _, loadBalancer := range state.LBs {
+		switch loadBalancer.Type {
+		case "concourse":
+			ops = append(ops, createOp("replace", "/vm_extensions/-", lb{
+				Name: "lb",
+				CloudProperties: lbCloudProperties{
+					TargetPool: outputs["concourse_target_pool"].(string),
 				},
-			},
-		}))
-
-		ops = append(ops, createOp("replace", "/vm_extensions/-", lb{
-			Name: "diego-ssh-proxy-network-properties",
-			CloudProperties: lbCloudProperties{
-				TargetPool: outputs["ssh_proxy_target_pool"].(string),
-				Tags: []string{
-					outputs["ssh_proxy_target_pool"].(string),
+			}))
+		case "cf":
+			ops = append(ops, createOp("replace", "/vm_extensions/-", lb{
+				Name: "cf-router-network-properties",
+				CloudProperties: lbCloudProperties{
+					BackendService: outputs["router_backend_service"].(string),
+					TargetPool:     outputs["ws_target_pool"].(string),
+					Tags: []string{
+						outputs["router_backend_service"].(string),
+						outputs["ws_target_pool"].(string),
+					},
 				},
-			},
-		}))
-
-		ops = append(ops, createOp("replace", "/vm_extensions/-", lb{
-			Name: "cf-tcp-router-network-properties",
-			CloudProperties: lbCloudProperties{
-				TargetPool: outputs["tcp_router_target_pool"].(string),
-				Tags: []string{
-					outputs["tcp_router_target_pool"].(string),
+			}))
+
+			ops = append(ops, createOp("replace", "/vm_extensions/-", lb{
+				Name: "diego-ssh-proxy-network-properties",
+				CloudProperties: lbCloudProperties{
+					TargetPool: outputs["ssh_proxy_target_pool"].(string),
+					Tags: []string{
+						outputs["ssh_proxy_target_pool"].(string),
+					},
 				},
-			},
-		}))
+			}))
+
+			ops = append(ops, createOp("replace", "/vm_extensions/-", lb{
+				Name: "cf-tcp-router-network-properties",
+				CloudProperties: lbCloudProperties{
+					TargetPool: outputs["tcp_router_target_pool"].(string),
+					Tags: []string{
+						outputs["tcp_router_target_pool"].(string),
+					},
+				},
+			}))
+		default:
+			if !loadBalancer.IsCustom() {
+				continue
+			}
+
+			if loadBalancer.Spec.TLS {
+				backendService, ok := outputs[fmt.Sprintf("%s_backend_service", loadBalancer.Type)].(string)
+				if !ok {
+					return []op{}, fmt.Errorf("missing %s_backend_service terraform output", loadBalancer.Type)
+				}
+				ops = append(ops, createOp("replace", "/vm_extensions/-", lb{
+					Name: fmt.Sprintf("%s-lb", loadBalancer.Type),
+					CloudProperties: lbCloudProperties{
+						BackendService: backendService,
+						Tags:           []string{backendService},
+					},
+				}))
+				continue
+			}
+
+			targetPool, ok := outputs[fmt.Sprintf("%s_target_pool", loadBalancer.Type)].(string)
+			if !ok {
+				return []op{}, fmt.Errorf("missing %s_target_pool terraform output", loadBalancer.Type)
+			}
+			ops = append(ops, createOp("replace", "/vm_extensions/-", lb{
+				Name: fmt.Sprintf("%s-lb", loadBalancer.Type),
+				CloudProperties: lbCloudProperties{
+					TargetPool: targetPool,
+					Tags:       []string{targetPool},
+				},
+			}))
+		}
 	}
 
 	return ops, nil
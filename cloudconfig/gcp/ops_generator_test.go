@@ -65,9 +65,20 @@ var _ = Describe("GCPOpsGenerator", func() {
 			Expect(opsYAML).To(gomegamatchers.MatchYAML(expectedOpsFile))
 		})
 
+		It("uses the zones stored in the state when there are any", func() {
+			incomingState.GCP.Zones = []string{"us-east1-b", "us-east1-c", "us-east1-d"}
+			zones.GetCall.Returns.Zones = []string{"some-other-zone"}
+
+			opsYAML, err := opsGenerator.Generate(incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(zones.GetCall.CallCount).To(Equal(0))
+			Expect(opsYAML).To(gomegamatchers.MatchYAML(expectedOpsFile))
+		})
+
 		DescribeTable("returns an ops file with additional vm extensions to support lb",
 			func(lbType string, lbOutputs map[string]interface{}) {
-				incomingState.LB.Type = lbType
+				incomingState.LBs = []storage.LB{{Type: lbType}}
 
 				expectedLBOpsFile, err := ioutil.ReadFile(filepath.Join("fixtures", fmt.Sprintf("gcp-%s-lb-ops.yml", lbType)))
 				Expect(err).NotTo(HaveOccurred())
@@ -104,7 +115,147 @@ var _ = Describe("GCPOpsGenerator", func() {
 				}),
 		)
 
+		It("returns an ops file with the vm extensions of each lb", func() {
+			incomingState.LBs = []storage.LB{{Type: "cf"}, {Type: "concourse"}}
+
+			cfLBOpsFile, err := ioutil.ReadFile(filepath.Join("fixtures", "gcp-cf-lb-ops.yml"))
+			Expect(err).NotTo(HaveOccurred())
+			concourseLBOpsFile, err := ioutil.ReadFile(filepath.Join("fixtures", "gcp-concourse-lb-ops.yml"))
+			Expect(err).NotTo(HaveOccurred())
+
+			expectedOps := strings.Join([]string{string(expectedOpsFile), string(cfLBOpsFile), string(concourseLBOpsFile)}, "\n")
+
+			terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{
+				"network_name":           "some-network-name",
+				"subnetwork_name":        "some-subnetwork-name",
+				"bosh_open_tag_name":     "some-bosh-tag",
+				"internal_tag_name":      "some-internal-tag",
+				"router_backend_service": "router-backend-service",
+				"ws_target_pool":         "ws-target-pool",
+				"ssh_proxy_target_pool":  "ssh-proxy-target-pool",
+				"tcp_router_target_pool": "tcp-router-target-pool",
+				"concourse_target_pool":  "concourse-target-pool",
+			}
+
+			opsYAML, err := opsGenerator.Generate(incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(opsYAML).To(gomegamatchers.MatchYAML(expectedOps))
+		})
+
+		It("returns an ops file with a vm extension named after a custom lb", func() {
+			incomingState.LBs = []storage.LB{{Type: "vault", Spec: &storage.CustomLBSpec{Name: "vault"}}}
+
+			customLBOpsFile, err := ioutil.ReadFile(filepath.Join("fixtures", "gcp-custom-lb-ops.yml"))
+			Expect(err).NotTo(HaveOccurred())
+
+			expectedOps := strings.Join([]string{string(expectedOpsFile), string(customLBOpsFile)}, "\n")
+
+			terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{
+				"network_name":       "some-network-name",
+				"subnetwork_name":    "some-subnetwork-name",
+				"bosh_open_tag_name": "some-bosh-tag",
+				"internal_tag_name":  "some-internal-tag",
+				"vault_target_pool":  "vault-target-pool",
+			}
+
+			opsYAML, err := opsGenerator.Generate(incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(opsYAML).To(gomegamatchers.MatchYAML(expectedOps))
+		})
+
+		It("returns an ops file with a vm extension for the backend service of a custom lb with tls", func() {
+			incomingState.LBs = []storage.LB{{Type: "vault", Spec: &storage.CustomLBSpec{Name: "vault", TLS: true}}}
+
+			expectedOps := strings.Join([]string{string(expectedOpsFile), `- type: replace
+  path: /vm_extensions/-
+  value:
+    name: vault-lb
+    cloud_properties:
+      backend_service: vault-backend-service
+      tags:
+      - vault-backend-service
+`}, "\n")
+
+			terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{
+				"network_name":          "some-network-name",
+				"subnetwork_name":       "some-subnetwork-name",
+				"bosh_open_tag_name":    "some-bosh-tag",
+				"internal_tag_name":     "some-internal-tag",
+				"vault_backend_service": "vault-backend-service",
+			}
+
+			opsYAML, err := opsGenerator.Generate(incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(opsYAML).To(gomegamatchers.MatchYAML(expectedOps))
+		})
+
+		Context("when the state has a vm type catalog", func() {
+			It("generates vm_types from the catalog", func() {
+				incomingState.VMTypeCatalog = `
+compilation: builder
+vm_types:
+- name: default
+  machine_type: n1-standard-2
+- name: builder
+  machine_type: n1-highcpu-16
+`
+				opsYAML, err := opsGenerator.Generate(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(opsYAML).To(ContainSubstring(`- type: replace
+  path: /compilation/vm_type
+  value: builder
+`))
+				Expect(opsYAML).To(ContainSubstring(`- type: replace
+  path: /vm_types/name=default/cloud_properties?
+  value:
+    machine_type: n1-standard-2
+    root_disk_size_gb: 10
+    root_disk_type: pd-ssd
+`))
+				Expect(opsYAML).To(ContainSubstring(`- type: replace
+  path: /vm_types/-
+  value:
+    name: builder
+    cloud_properties:
+      machine_type: n1-highcpu-16
+      root_disk_size_gb: 10
+      root_disk_type: pd-ssd
+`))
+			})
+		})
+
 		Context("failure cases", func() {
+			It("returns an error when the backend service of a custom lb with tls is missing", func() {
+				incomingState.LBs = []storage.LB{{Type: "vault", Spec: &storage.CustomLBSpec{Name: "vault", TLS: true}}}
+
+				_, err := opsGenerator.Generate(incomingState)
+				Expect(err).To(MatchError("missing vault_backend_service terraform output"))
+			})
+
+			It("returns an error when the target pool of a custom lb is missing", func() {
+				incomingState.LBs = []storage.LB{{Type: "vault", Spec: &storage.CustomLBSpec{Name: "vault"}}}
+
+				_, err := opsGenerator.Generate(incomingState)
+				Expect(err).To(MatchError("missing vault_target_pool terraform output"))
+			})
+
+			It("returns an error when the vm type catalog is invalid", func() {
+				_, err := opsGenerator.Generate(storage.State{
+					VMTypeCatalog: "compilation: some-missing-vm-type",
+				})
+				Expect(err).To(MatchError(`compilation vm_type "some-missing-vm-type" is not defined in the vm type catalog`))
+			})
+
+			It("returns an error when the zones cannot be retrieved", func() {
+				zones.GetCall.Returns.Error = errors.New("failed to get zones")
+				_, err := opsGenerator.Generate(storage.State{})
+				Expect(err).To(MatchError("failed to get zones"))
+			})
+
 			It("returns an error when terraform output provider fails to retrieve", func() {
 				terraformManager.GetOutputsCall.Returns.Error = errors.New("failed to output")
 				_, err := opsGenerator.Generate(storage.State{})
@@ -0,0 +1,47 @@
+package gcp
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry/bosh-bootloader/cloudconfig/vmtypes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type vmType struct {
+	Name            string
+	CloudProperties vmTypeCloudProperties `yaml:"cloud_properties"`
+}
+
+type vmTypeCloudProperties struct {
+	MachineType    string `yaml:"machine_type"`
+	RootDiskSizeGB int    `yaml:"root_disk_size_gb"`
+	RootDiskType   string `yaml:"root_disk_type"`
+}
+
+func generateVMTypeOps(state storage.State) ([]op, error) {
+	catalog, err := vmtypes.Load("gcp", state.GCP.Region, state.VMTypeCatalog)
+	if err != nil {
+		return []op{}, err
+	}
+
+	ops := []op{createOp("replace", "/compilation/vm_type", catalog.Compilation)}
+	for _, t := range catalog.VMTypes {
+		cloudProperties := vmTypeCloudProperties{
+			MachineType:    t.MachineType,
+			RootDiskSizeGB: 10,
+			RootDiskType:   "pd-ssd",
+		}
+
+		if vmtypes.IsBase(t.Name) {
+			ops = append(ops, createOp("replace", fmt.Sprintf("/vm_types/name=%s/cloud_properties?", t.Name), cloudProperties))
+			continue
+		}
+
+		ops = append(ops, createOp("replace", "/vm_types/-", vmType{
+			Name:            t.Name,
+			CloudProperties: cloudProperties,
+		}))
+	}
+
+	return ops, nil
+}
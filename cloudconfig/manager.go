@@ -1,35 +1,27 @@
 package cloudconfig
 
 import (
-	"bytes"
 	"fmt"
 	"io"
-	"io/ioutil"
-	"os"
-	"path/filepath"
+	"strings"
 
 	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/patch"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
-var (
-	tempDir   func(string, string) (string, error)    = ioutil.TempDir
-	writeFile func(string, []byte, os.FileMode) error = ioutil.WriteFile
-)
-
 type Manager struct {
 	logger             logger
-	command            command
 	opsGenerator       opsGenerator
 	boshClientProvider boshClientProvider
+	stdin              io.Reader
+	confirm            bool
 }
 
 type logger interface {
 	Step(string, ...interface{})
-}
-
-type command interface {
-	Run(stdout io.Writer, workingDirectory string, args []string) error
+	Println(string)
+	Prompt(string)
 }
 
 type opsGenerator interface {
@@ -37,51 +29,43 @@ type opsGenerator interface {
 }
 
 type boshClientProvider interface {
-	Client(directorAddress, directorUsername, directorPassword string) bosh.Client
+	Client(directorAddress, directorUsername, directorPassword, directorCACert string) bosh.Client
 }
 
-func NewManager(logger logger, cmd command, opsGenerator opsGenerator, boshClientProvider boshClientProvider) Manager {
+func NewManager(logger logger, opsGenerator opsGenerator, boshClientProvider boshClientProvider, stdin io.Reader, confirm bool) Manager {
 	return Manager{
 		logger:             logger,
-		command:            cmd,
 		opsGenerator:       opsGenerator,
 		boshClientProvider: boshClientProvider,
+		stdin:              stdin,
+		confirm:            confirm,
 	}
 }
 
 func (m Manager) Generate(state storage.State) (string, error) {
-	buf := bytes.NewBuffer([]byte{})
-	workingDir, err := tempDir("", "")
+	ops, err := m.opsGenerator.Generate(state)
 	if err != nil {
 		return "", err
 	}
 
-	err = writeFile(filepath.Join(workingDir, "cloud-config.yml"), []byte(BaseCloudConfig), os.ModePerm)
-	if err != nil {
-		return "", err
-	}
+	opsFiles := append([]string{ops}, state.CloudConfigOpsFiles...)
 
-	ops, err := m.opsGenerator.Generate(state)
-	if err != nil {
-		return "", err
-	}
+	return patch.Interpolate(BaseCloudConfig, opsFiles...)
+}
 
-	err = writeFile(filepath.Join(workingDir, "ops.yml"), []byte(ops), os.ModePerm)
+func (m Manager) Diff(state storage.State) (string, error) {
+	cloudConfig, err := m.Generate(state)
 	if err != nil {
 		return "", err
 	}
 
-	args := []string{
-		"interpolate", fmt.Sprintf("%s/cloud-config.yml", workingDir),
-		"-o", fmt.Sprintf("%s/ops.yml", workingDir),
-	}
-
-	err = m.command.Run(buf, workingDir, args)
+	boshClient := m.boshClientProvider.Client(state.BOSH.DirectorAddress, state.BOSH.DirectorUsername, state.BOSH.DirectorPassword, state.BOSH.DirectorSSLCA)
+	currentCloudConfig, err := boshClient.CloudConfig()
 	if err != nil {
 		return "", err
 	}
 
-	return buf.String(), nil
+	return DiffYAML(currentCloudConfig, cloudConfig)
 }
 
 func (m Manager) Update(state storage.State) error {
@@ -91,12 +75,58 @@ func (m Manager) Update(state storage.State) error {
 		return err
 	}
 
-	m.logger.Step("applying cloud config")
-	boshClient := m.boshClientProvider.Client(state.BOSH.DirectorAddress, state.BOSH.DirectorUsername, state.BOSH.DirectorPassword)
-	err = boshClient.UpdateCloudConfig([]byte(cloudConfig))
+	boshClient := m.boshClientProvider.Client(state.BOSH.DirectorAddress, state.BOSH.DirectorUsername, state.BOSH.DirectorPassword, state.BOSH.DirectorSSLCA)
+	currentCloudConfig, err := boshClient.CloudConfig()
+	if err != nil {
+		return err
+	}
+
+	diff, err := DiffYAML(currentCloudConfig, cloudConfig)
 	if err != nil {
 		return err
 	}
 
+	if diff == "" {
+		m.logger.Step("cloud config is up to date")
+	} else {
+		m.logger.Println(diff)
+
+		if m.confirm && !m.confirmed() {
+			m.logger.Step("skipping cloud config update")
+		} else {
+			m.logger.Step("applying cloud config")
+			err = boshClient.UpdateCloudConfig([]byte(cloudConfig))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if state.RuntimeConfig != "" {
+		m.logger.Step("applying runtime config")
+		err = boshClient.UpdateRuntimeConfig("", []byte(state.RuntimeConfig))
+		if err != nil {
+			return err
+		}
+	}
+
+	if state.CPIConfig != "" {
+		m.logger.Step("applying cpi config")
+		err = boshClient.UpdateCPIConfig([]byte(state.CPIConfig))
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
+
+func (m Manager) confirmed() bool {
+	m.logger.Prompt("Are you sure you want to apply these changes to the cloud config?")
+
+	var proceed string
+	fmt.Fscanln(m.stdin, &proceed)
+
+	proceed = strings.ToLower(proceed)
+	return proceed == "yes" || proceed == "y"
+}
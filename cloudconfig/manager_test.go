@@ -1,31 +1,29 @@
 package cloudconfig_test
 
 import (
+	"bytes"
 	"errors"
-	"fmt"
-	"io"
 	"io/ioutil"
-	"os"
-	"strings"
+	"path/filepath"
 
 	"github.com/cloudfoundry/bosh-bootloader/cloudconfig"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 
 	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
 var _ = Describe("Manager", func() {
 	var (
 		logger             *fakes.Logger
-		cmd                *fakes.BOSHCommand
 		opsGenerator       *fakes.CloudConfigOpsGenerator
 		boshClientProvider *fakes.BOSHClientProvider
 		boshClient         *fakes.BOSHClient
 		manager            cloudconfig.Manager
+		stdin              *bytes.Buffer
 
-		tempDir       string
 		incomingState storage.State
 
 		baseCloudConfig []byte
@@ -33,113 +31,93 @@ var _ = Describe("Manager", func() {
 
 	BeforeEach(func() {
 		logger = &fakes.Logger{}
-		cmd = &fakes.BOSHCommand{}
 		opsGenerator = &fakes.CloudConfigOpsGenerator{}
 		boshClient = &fakes.BOSHClient{}
 		boshClientProvider = &fakes.BOSHClientProvider{}
 
 		boshClientProvider.ClientCall.Returns.Client = boshClient
 
-		var err error
-		tempDir, err = ioutil.TempDir("", "")
-		Expect(err).NotTo(HaveOccurred())
-
-		cloudconfig.SetTempDir(func(string, string) (string, error) {
-			return tempDir, nil
-		})
-
-		cmd.RunStub = func(stdout io.Writer, workingDirectory string, args []string) error {
-			stdout.Write([]byte("some-cloud-config"))
-			return nil
-		}
-
 		incomingState = storage.State{
 			IAAS: "gcp",
 			BOSH: storage.BOSH{
 				DirectorAddress:  "some-director-address",
 				DirectorUsername: "some-director-username",
 				DirectorPassword: "some-director-password",
+				DirectorSSLCA:    "some-director-ca-cert",
 			},
 		}
 
-		opsGenerator.GenerateCall.Returns.OpsYAML = "some-ops"
+		opsGenerator.GenerateCall.Returns.OpsYAML = `
+- type: replace
+  path: /compilation/workers
+  value: 8
+`
 
+		var err error
 		baseCloudConfig, err = ioutil.ReadFile("fixtures/base-cloud-config.yml")
 		Expect(err).NotTo(HaveOccurred())
 
-		manager = cloudconfig.NewManager(logger, cmd, opsGenerator, boshClientProvider)
-	})
+		stdin = bytes.NewBuffer([]byte{})
 
-	AfterEach(func() {
-		cloudconfig.ResetTempDir()
+		manager = cloudconfig.NewManager(logger, opsGenerator, boshClientProvider, stdin, false)
 	})
 
 	Describe("Generate", func() {
-		It("returns a cloud config yaml provided a valid bbl state", func() {
-			expectedArgs := []string{
-				"interpolate", fmt.Sprintf("%s/cloud-config.yml", tempDir),
-				"-o", fmt.Sprintf("%s/ops.yml", tempDir),
-			}
-
+		It("applies the generated ops to the base cloud config", func() {
 			cloudConfigYAML, err := manager.Generate(incomingState)
 			Expect(err).NotTo(HaveOccurred())
 
-			cloudConfig, err := ioutil.ReadFile(fmt.Sprintf("%s/cloud-config.yml", tempDir))
-			Expect(err).NotTo(HaveOccurred())
-			Expect(cloudConfig).To(Equal(baseCloudConfig))
-
 			Expect(opsGenerator.GenerateCall.Receives.State).To(Equal(incomingState))
 
-			ops, err := ioutil.ReadFile(fmt.Sprintf("%s/ops.yml", tempDir))
+			diff, err := cloudconfig.DiffYAML(string(baseCloudConfig), cloudConfigYAML)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(string(ops)).To(Equal("some-ops"))
-
-			Expect(cmd.RunCallCount()).To(Equal(1))
-			_, workingDirectory, args := cmd.RunArgsForCall(0)
-			Expect(workingDirectory).To(Equal(tempDir))
-			Expect(args).To(Equal(expectedArgs))
-
-			Expect(cloudConfigYAML).To(Equal("some-cloud-config"))
+			Expect(diff).To(Equal("~ /compilation/workers: 6 -> 8"))
 		})
 
-		Context("failure cases", func() {
-			Context("when temp dir fails", func() {
-				BeforeEach(func() {
-					cloudconfig.SetTempDir(func(string, string) (string, error) {
-						return "", errors.New("failed to create temp dir")
-					})
-				})
+		table.DescribeTable("produces the same cloud config as bosh interpolate",
+			func(opsFixture, cloudConfigFixture string) {
+				ops, err := ioutil.ReadFile(opsFixture)
+				Expect(err).NotTo(HaveOccurred())
 
-				AfterEach(func() {
-					cloudconfig.ResetTempDir()
-				})
+				expectedCloudConfig, err := ioutil.ReadFile(cloudConfigFixture)
+				Expect(err).NotTo(HaveOccurred())
 
-				It("returns an error", func() {
-					_, err := manager.Generate(storage.State{})
-					Expect(err).To(MatchError("failed to create temp dir"))
-				})
+				opsGenerator.GenerateCall.Returns.OpsYAML = string(ops)
+
+				cloudConfigYAML, err := manager.Generate(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cloudConfigYAML).To(MatchYAML(string(expectedCloudConfig)))
+			},
+			table.Entry("for aws", filepath.Join("aws", "fixtures", "aws-ops.yml"), filepath.Join("fixtures", "aws-cloud-config.yml")),
+			table.Entry("for gcp", filepath.Join("gcp", "fixtures", "gcp-ops.yml"), filepath.Join("fixtures", "gcp-cloud-config.yml")),
+		)
+
+		Context("when the state contains user cloud config ops files", func() {
+			BeforeEach(func() {
+				incomingState.CloudConfigOpsFiles = []string{
+					"- {type: replace, path: /compilation/workers, value: 10}",
+					"- {type: replace, path: /compilation/workers, value: 12}",
+				}
 			})
 
-			Context("when write file fails to write cloud-config.yml", func() {
-				BeforeEach(func() {
-					cloudconfig.SetWriteFile(func(filename string, body []byte, mode os.FileMode) error {
-						if strings.Contains(filename, "cloud-config.yml") {
-							return errors.New("failed to write file")
-						}
-						return nil
-					})
-				})
+			It("applies them in order after the bbl ops", func() {
+				cloudConfigYAML, err := manager.Generate(incomingState)
+				Expect(err).NotTo(HaveOccurred())
 
-				AfterEach(func() {
-					cloudconfig.ResetWriteFile()
-				})
+				diff, err := cloudconfig.DiffYAML(string(baseCloudConfig), cloudConfigYAML)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(diff).To(Equal("~ /compilation/workers: 6 -> 12"))
+			})
 
-				It("returns an error", func() {
-					_, err := manager.Generate(storage.State{})
-					Expect(err).To(MatchError("failed to write file"))
-				})
+			It("returns an error when a user ops file cannot be applied", func() {
+				incomingState.CloudConfigOpsFiles = []string{"- {type: remove, path: /some-missing-key}"}
+
+				_, err := manager.Generate(incomingState)
+				Expect(err).To(MatchError(`expected to find a map key "some-missing-key" for path "/some-missing-key"`))
 			})
+		})
 
+		Context("failure cases", func() {
 			Context("when ops generator fails to generate", func() {
 				BeforeEach(func() {
 					opsGenerator.GenerateCall.Returns.Error = errors.New("failed to generate")
@@ -151,35 +129,44 @@ var _ = Describe("Manager", func() {
 				})
 			})
 
-			Context("when write file fails to write ops.yml", func() {
+			Context("when the generated ops are not valid", func() {
 				BeforeEach(func() {
-					cloudconfig.SetWriteFile(func(filename string, body []byte, mode os.FileMode) error {
-						if strings.Contains(filename, "ops.yml") {
-							return errors.New("failed to write file")
-						}
-						return nil
-					})
-				})
-
-				AfterEach(func() {
-					cloudconfig.ResetWriteFile()
+					opsGenerator.GenerateCall.Returns.OpsYAML = "%%%"
 				})
 
 				It("returns an error", func() {
 					_, err := manager.Generate(storage.State{})
-					Expect(err).To(MatchError("failed to write file"))
+					Expect(err).To(MatchError(ContainSubstring("failed to parse ops file")))
 				})
 			})
+		})
+	})
 
-			Context("when command fails to run", func() {
-				BeforeEach(func() {
-					cmd.RunReturns(errors.New("failed to run"))
-				})
+	Describe("Diff", func() {
+		It("returns the difference between the director's cloud config and the generated one", func() {
+			boshClient.CloudConfigCall.Returns.CloudConfig = string(baseCloudConfig)
 
-				It("returns an error", func() {
-					_, err := manager.Generate(storage.State{})
-					Expect(err).To(MatchError("failed to run"))
-				})
+			diff, err := manager.Diff(incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(boshClientProvider.ClientCall.Receives.DirectorAddress).To(Equal("some-director-address"))
+			Expect(boshClientProvider.ClientCall.Receives.DirectorCACert).To(Equal("some-director-ca-cert"))
+			Expect(diff).To(Equal("~ /compilation/workers: 6 -> 8"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the cloud config cannot be generated", func() {
+				opsGenerator.GenerateCall.Returns.Error = errors.New("failed to generate")
+
+				_, err := manager.Diff(incomingState)
+				Expect(err).To(MatchError("failed to generate"))
+			})
+
+			It("returns an error when the current cloud config cannot be fetched", func() {
+				boshClient.CloudConfigCall.Returns.Error = errors.New("failed to get cloud config")
+
+				_, err := manager.Diff(incomingState)
+				Expect(err).To(MatchError("failed to get cloud config"))
 			})
 		})
 	})
@@ -195,25 +182,152 @@ var _ = Describe("Manager", func() {
 		})
 
 		It("updates the bosh director with a cloud config provided a valid bbl state", func() {
-			err := manager.Update(incomingState)
+			cloudConfig, err := manager.Generate(incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = manager.Update(incomingState)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(boshClientProvider.ClientCall.Receives.DirectorAddress).To(Equal("some-director-address"))
 			Expect(boshClientProvider.ClientCall.Receives.DirectorUsername).To(Equal("some-director-username"))
 			Expect(boshClientProvider.ClientCall.Receives.DirectorPassword).To(Equal("some-director-password"))
+			Expect(boshClientProvider.ClientCall.Receives.DirectorCACert).To(Equal("some-director-ca-cert"))
 
-			Expect(boshClient.UpdateCloudConfigCall.Receives.Yaml).To(Equal([]byte("some-cloud-config")))
+			Expect(boshClient.UpdateCloudConfigCall.Receives.Yaml).To(Equal([]byte(cloudConfig)))
+		})
+
+		Context("when the state has a runtime config and a cpi config", func() {
+			BeforeEach(func() {
+				incomingState.RuntimeConfig = "some-runtime-config"
+				incomingState.CPIConfig = "some-cpi-config"
+			})
+
+			It("applies them after the cloud config", func() {
+				err := manager.Update(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.StepCall.Messages).To(Equal([]string{
+					"generating cloud config",
+					"applying cloud config",
+					"applying runtime config",
+					"applying cpi config",
+				}))
+				Expect(boshClient.UpdateRuntimeConfigCall.Receives.Name).To(Equal(""))
+				Expect(boshClient.UpdateRuntimeConfigCall.Receives.Yaml).To(Equal([]byte("some-runtime-config")))
+				Expect(boshClient.UpdateCPIConfigCall.Receives.Yaml).To(Equal([]byte("some-cpi-config")))
+			})
+
+			It("returns an error when the runtime config cannot be applied", func() {
+				boshClient.UpdateRuntimeConfigCall.Returns.Error = errors.New("failed to update runtime config")
+
+				err := manager.Update(incomingState)
+				Expect(err).To(MatchError("failed to update runtime config"))
+			})
+
+			It("returns an error when the cpi config cannot be applied", func() {
+				boshClient.UpdateCPIConfigCall.Returns.Error = errors.New("failed to update cpi config")
+
+				err := manager.Update(incomingState)
+				Expect(err).To(MatchError("failed to update cpi config"))
+			})
+		})
+
+		It("prints the difference from the director's current cloud config", func() {
+			boshClient.CloudConfigCall.Returns.CloudConfig = string(baseCloudConfig)
+
+			err := manager.Update(incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(boshClient.CloudConfigCall.CallCount).To(Equal(1))
+			Expect(logger.PrintlnCall.Messages).To(Equal([]string{"~ /compilation/workers: 6 -> 8"}))
+		})
+
+		Context("when the cloud config has not changed", func() {
+			BeforeEach(func() {
+				cloudConfig, err := manager.Generate(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				boshClient.CloudConfigCall.Returns.CloudConfig = cloudConfig
+			})
+
+			It("does not update the cloud config", func() {
+				err := manager.Update(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(boshClient.UpdateCloudConfigCall.CallCount).To(Equal(0))
+				Expect(logger.StepCall.Messages).To(Equal([]string{
+					"generating cloud config",
+					"cloud config is up to date",
+				}))
+			})
+		})
+
+		Context("when confirmation is required", func() {
+			BeforeEach(func() {
+				manager = cloudconfig.NewManager(logger, opsGenerator, boshClientProvider, stdin, true)
+			})
+
+			It("updates the cloud config when the user confirms", func() {
+				stdin.WriteString("yes\n")
+
+				err := manager.Update(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PromptCall.Receives.Message).To(Equal("Are you sure you want to apply these changes to the cloud config?"))
+				Expect(boshClient.UpdateCloudConfigCall.CallCount).To(Equal(1))
+			})
+
+			It("skips the cloud config update when the user declines", func() {
+				stdin.WriteString("no\n")
+
+				err := manager.Update(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(boshClient.UpdateCloudConfigCall.CallCount).To(Equal(0))
+				Expect(logger.StepCall.Messages).To(ContainElement("skipping cloud config update"))
+			})
+
+			It("does not prompt when the cloud config has not changed", func() {
+				cloudConfig, err := manager.Generate(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				boshClient.CloudConfigCall.Returns.CloudConfig = cloudConfig
+
+				err = manager.Update(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PromptCall.CallCount).To(Equal(0))
+			})
+		})
+
+		It("does not apply a runtime config or cpi config when none are registered", func() {
+			err := manager.Update(incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(boshClient.UpdateRuntimeConfigCall.CallCount).To(Equal(0))
+			Expect(boshClient.UpdateCPIConfigCall.CallCount).To(Equal(0))
 		})
 
 		Context("failure cases", func() {
-			Context("when manager generate's command fails to run", func() {
+			Context("when manager fails to generate the cloud config", func() {
+				BeforeEach(func() {
+					opsGenerator.GenerateCall.Returns.Error = errors.New("failed to generate")
+				})
+
+				It("returns an error", func() {
+					err := manager.Update(storage.State{})
+					Expect(err).To(MatchError("failed to generate"))
+				})
+			})
+
+			Context("when bosh client fails to get the current cloud config", func() {
 				BeforeEach(func() {
-					cmd.RunReturns(errors.New("failed to run"))
+					boshClient.CloudConfigCall.Returns.Error = errors.New("failed to get cloud config")
 				})
 
 				It("returns an error", func() {
 					err := manager.Update(storage.State{})
-					Expect(err).To(MatchError("failed to run"))
+					Expect(err).To(MatchError("failed to get cloud config"))
 				})
 			})
 
@@ -0,0 +1,281 @@
+package vmtypes
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+type VMType struct {
+	Name        string `yaml:"name"`
+	MachineType string `yaml:"machine_type"`
+}
+
+// Catalog describes the vm_types offered by the generated cloud config and
+// the machine types they may be backed by.
+type Catalog struct {
+	Compilation  string   `yaml:"compilation"`
+	VMTypes      []VMType `yaml:"vm_types"`
+	MachineTypes []string `yaml:"machine_types"`
+}
+
+// BaseVMTypes are declared by the base cloud config. Every other vm_type in
+// a catalog is appended to it.
+var BaseVMTypes = []string{"default", "minimal", "sharedcpu", "small", "medium", "large", "extra-large"}
+
+// AWS regions opened after the m3, c3 and r3 families were retired. This is
+// the only regional difference the catalogs know about: availability is not
+// looked up from the IaaS, so every other region is assumed to offer the full
+// static list of machine types below, and newly opened regions must be added
+// here by hand.
+var awsCurrentGenerationOnlyRegions = map[string]bool{
+	"us-east-2":    true,
+	"ca-central-1": true,
+	"eu-west-2":    true,
+	"ap-south-1":   true,
+}
+
+func IsBase(name string) bool {
+	for _, base := range BaseVMTypes {
+		if base == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Lookup returns the built-in catalog for the given IaaS and region.
+func Lookup(iaas, region string) (Catalog, error) {
+	switch iaas {
+	case "aws":
+		return awsCatalog(region), nil
+	case "gcp":
+		return gcpCatalog(), nil
+	default:
+		return Catalog{}, fmt.Errorf("no vm type catalog for iaas %q", iaas)
+	}
+}
+
+// Load returns the built-in catalog for the given IaaS and region with the
+// user supplied catalog YAML applied on top of it. Vm_types in the override
+// replace built-in vm_types of the same name and must be backed by a machine
+// type of the built-in catalog, which is a static approximation of what the
+// region offers. The override cannot add machine types.
+func Load(iaas, region, override string) (Catalog, error) {
+	catalog, err := Lookup(iaas, region)
+	if err != nil {
+		return Catalog{}, err
+	}
+
+	if override != "" {
+		var userCatalog Catalog
+		err = yaml.Unmarshal([]byte(override), &userCatalog)
+		if err != nil {
+			return Catalog{}, fmt.Errorf("failed to parse vm type catalog: %s", err)
+		}
+
+		if len(userCatalog.MachineTypes) > 0 {
+			return Catalog{}, errors.New("machine_types cannot be set in the vm type catalog, vm_types must use the machine types of the built-in catalog")
+		}
+
+		err = validateVMTypes(userCatalog.VMTypes, catalog.MachineTypes, region)
+		if err != nil {
+			return Catalog{}, err
+		}
+
+		catalog = catalog.merge(userCatalog)
+	}
+
+	err = catalog.validate(region)
+	if err != nil {
+		return Catalog{}, err
+	}
+
+	return catalog, nil
+}
+
+func (c Catalog) merge(override Catalog) Catalog {
+	if override.Compilation != "" {
+		c.Compilation = override.Compilation
+	}
+
+	vmTypes := append([]VMType{}, c.VMTypes...)
+	for _, vmType := range override.VMTypes {
+		replaced := false
+		for i := range vmTypes {
+			if vmTypes[i].Name == vmType.Name {
+				vmTypes[i] = vmType
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			vmTypes = append(vmTypes, vmType)
+		}
+	}
+	c.VMTypes = vmTypes
+
+	return c
+}
+
+func (c Catalog) validate(region string) error {
+	err := validateVMTypes(c.VMTypes, c.MachineTypes, region)
+	if err != nil {
+		return err
+	}
+
+	for _, vmType := range c.VMTypes {
+		if vmType.Name == c.Compilation {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("compilation vm_type %q is not defined in the vm type catalog", c.Compilation)
+}
+
+func validateVMTypes(vmTypes []VMType, machineTypes []string, region string) error {
+	available := map[string]bool{}
+	for _, machineType := range machineTypes {
+		available[machineType] = true
+	}
+
+	for _, vmType := range vmTypes {
+		if vmType.Name == "" {
+			return fmt.Errorf("vm_type with machine type %q is missing a name", vmType.MachineType)
+		}
+
+		if !available[vmType.MachineType] {
+			return fmt.Errorf("vm_type %q uses machine type %q which is not available in %s", vmType.Name, vmType.MachineType, region)
+		}
+	}
+
+	return nil
+}
+
+func awsCatalog(region string) Catalog {
+	machineType := func(instanceType string) string {
+		if awsCurrentGenerationOnlyRegions[region] {
+			return awsCurrentGeneration(instanceType)
+		}
+		return instanceType
+	}
+
+	catalog := Catalog{
+		Compilation: "c3.large",
+		VMTypes: []VMType{
+			{Name: "default", MachineType: machineType("m3.medium")},
+			{Name: "minimal", MachineType: machineType("m3.medium")},
+			{Name: "sharedcpu", MachineType: machineType("t2.small")},
+			{Name: "small", MachineType: machineType("m3.large")},
+			{Name: "medium", MachineType: machineType("m4.xlarge")},
+			{Name: "large", MachineType: machineType("m4.2xlarge")},
+			{Name: "extra-large", MachineType: machineType("m4.4xlarge")},
+		},
+	}
+
+	for _, instanceType := range []string{
+		"m3.medium", "m3.large", "m3.xlarge", "m3.2xlarge",
+		"m4.large", "m4.xlarge", "m4.2xlarge", "m4.4xlarge", "m4.10xlarge",
+		"c3.large", "c3.xlarge", "c3.2xlarge", "c3.4xlarge", "c3.8xlarge",
+		"c4.large", "c4.xlarge", "c4.2xlarge", "c4.4xlarge", "c4.8xlarge",
+		"r3.large", "r3.xlarge", "r3.2xlarge", "r3.4xlarge", "r3.8xlarge",
+		"t2.nano", "t2.micro", "t2.small", "t2.medium", "t2.large",
+	} {
+		catalog.VMTypes = append(catalog.VMTypes, VMType{Name: instanceType, MachineType: machineType(instanceType)})
+	}
+
+	catalog.VMTypes = append(catalog.VMTypes,
+		VMType{Name: "small-highmem", MachineType: machineType("r3.xlarge")},
+		VMType{Name: "small-highcpu", MachineType: machineType("c3.large")},
+	)
+
+	for _, family := range []struct {
+		name  string
+		sizes []string
+	}{
+		{"t2", []string{"nano", "micro", "small", "medium", "large", "xlarge", "2xlarge"}},
+		{"m3", []string{"medium", "large", "xlarge", "2xlarge"}},
+		{"m4", []string{"large", "xlarge", "2xlarge", "4xlarge", "10xlarge", "16xlarge"}},
+		{"m5", []string{"large", "xlarge", "2xlarge", "4xlarge", "12xlarge", "24xlarge"}},
+		{"c3", []string{"large", "xlarge", "2xlarge", "4xlarge", "8xlarge"}},
+		{"c4", []string{"large", "xlarge", "2xlarge", "4xlarge", "8xlarge"}},
+		{"c5", []string{"large", "xlarge", "2xlarge", "4xlarge", "9xlarge", "18xlarge"}},
+		{"r3", []string{"large", "xlarge", "2xlarge", "4xlarge", "8xlarge"}},
+		{"r4", []string{"large", "xlarge", "2xlarge", "4xlarge", "8xlarge", "16xlarge"}},
+	} {
+		for _, size := range family.sizes {
+			instanceType := fmt.Sprintf("%s.%s", family.name, size)
+			if machineType(instanceType) != instanceType {
+				continue
+			}
+			catalog.MachineTypes = append(catalog.MachineTypes, instanceType)
+		}
+	}
+
+	return catalog
+}
+
+func awsCurrentGeneration(instanceType string) string {
+	switch {
+	case instanceType == "m3.medium":
+		return "m4.large"
+	case strings.HasPrefix(instanceType, "m3."):
+		return "m4." + strings.TrimPrefix(instanceType, "m3.")
+	case strings.HasPrefix(instanceType, "c3."):
+		return "c4." + strings.TrimPrefix(instanceType, "c3.")
+	case strings.HasPrefix(instanceType, "r3."):
+		return "r4." + strings.TrimPrefix(instanceType, "r3.")
+	default:
+		return instanceType
+	}
+}
+
+func gcpCatalog() Catalog {
+	catalog := Catalog{
+		Compilation: "n1-highcpu-8",
+		VMTypes: []VMType{
+			{Name: "default", MachineType: "n1-standard-1"},
+			{Name: "minimal", MachineType: "n1-standard-1"},
+			{Name: "sharedcpu", MachineType: "g1-small"},
+			{Name: "small", MachineType: "n1-standard-2"},
+			{Name: "medium", MachineType: "n1-standard-4"},
+			{Name: "large", MachineType: "n1-standard-8"},
+			{Name: "extra-large", MachineType: "n1-standard-16"},
+		},
+	}
+
+	for _, machineType := range []string{
+		"n1-standard-1", "n1-standard-2", "n1-standard-4", "n1-standard-8", "n1-standard-16", "n1-standard-32",
+		"n1-highmem-2", "n1-highmem-4", "n1-highmem-8", "n1-highmem-16", "n1-highmem-32",
+		"n1-highcpu-2", "n1-highcpu-4", "n1-highcpu-8", "n1-highcpu-16", "n1-highcpu-32",
+		"f1-micro", "g1-small",
+	} {
+		catalog.VMTypes = append(catalog.VMTypes, VMType{Name: machineType, MachineType: machineType})
+	}
+
+	// Aliases for the AWS instance types commonly referenced by manifests.
+	catalog.VMTypes = append(catalog.VMTypes,
+		VMType{Name: "m3.medium", MachineType: "n1-standard-1"},
+		VMType{Name: "m3.large", MachineType: "n1-standard-2"},
+		VMType{Name: "c3.large", MachineType: "n1-highcpu-2"},
+		VMType{Name: "r3.xlarge", MachineType: "n1-highmem-4"},
+		VMType{Name: "t2.small", MachineType: "g1-small"},
+		VMType{Name: "small-highmem", MachineType: "n1-highmem-4"},
+		VMType{Name: "small-highcpu", MachineType: "n1-highcpu-2"},
+	)
+
+	for _, family := range []string{"n1-standard", "n1-highmem", "n1-highcpu"} {
+		for _, cpus := range []int{1, 2, 4, 8, 16, 32, 64} {
+			if cpus == 1 && family != "n1-standard" {
+				continue
+			}
+			catalog.MachineTypes = append(catalog.MachineTypes, fmt.Sprintf("%s-%d", family, cpus))
+		}
+	}
+	catalog.MachineTypes = append(catalog.MachineTypes, "f1-micro", "g1-small")
+
+	return catalog
+}
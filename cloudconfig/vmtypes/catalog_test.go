@@ -0,0 +1,131 @@
+package vmtypes_test
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/cloudconfig/vmtypes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Catalog", func() {
+	Describe("Lookup", func() {
+		It("returns the aws catalog", func() {
+			catalog, err := vmtypes.Lookup("aws", "us-east-1")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(catalog.Compilation).To(Equal("c3.large"))
+			Expect(catalog.VMTypes).To(ContainElement(vmtypes.VMType{Name: "default", MachineType: "m3.medium"}))
+			Expect(catalog.VMTypes).To(ContainElement(vmtypes.VMType{Name: "c3.large", MachineType: "c3.large"}))
+			Expect(catalog.MachineTypes).To(ContainElement("m3.medium"))
+		})
+
+		It("substitutes current generation instance types in regions without the previous generation", func() {
+			catalog, err := vmtypes.Lookup("aws", "eu-west-2")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(catalog.Compilation).To(Equal("c3.large"))
+			Expect(catalog.VMTypes).To(ContainElement(vmtypes.VMType{Name: "default", MachineType: "m4.large"}))
+			Expect(catalog.VMTypes).To(ContainElement(vmtypes.VMType{Name: "c3.large", MachineType: "c4.large"}))
+			Expect(catalog.VMTypes).To(ContainElement(vmtypes.VMType{Name: "small-highmem", MachineType: "r4.xlarge"}))
+			Expect(catalog.MachineTypes).NotTo(ContainElement("m3.medium"))
+		})
+
+		It("returns the gcp catalog", func() {
+			catalog, err := vmtypes.Lookup("gcp", "us-east1")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(catalog.Compilation).To(Equal("n1-highcpu-8"))
+			Expect(catalog.VMTypes).To(ContainElement(vmtypes.VMType{Name: "default", MachineType: "n1-standard-1"}))
+			Expect(catalog.VMTypes).To(ContainElement(vmtypes.VMType{Name: "m3.medium", MachineType: "n1-standard-1"}))
+		})
+
+		It("returns an error for an unknown iaas", func() {
+			_, err := vmtypes.Lookup("some-iaas", "some-region")
+			Expect(err).To(MatchError(`no vm type catalog for iaas "some-iaas"`))
+		})
+	})
+
+	Describe("Load", func() {
+		It("returns the built-in catalog when there is no override", func() {
+			catalog, err := vmtypes.Load("aws", "us-east-1", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			builtIn, err := vmtypes.Lookup("aws", "us-east-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(catalog).To(Equal(builtIn))
+		})
+
+		It("replaces and adds vm_types from the override", func() {
+			catalog, err := vmtypes.Load("aws", "us-east-1", `
+compilation: builder
+vm_types:
+- name: default
+  machine_type: m4.large
+- name: builder
+  machine_type: c4.2xlarge
+- name: huge
+  machine_type: m4.16xlarge
+`)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(catalog.Compilation).To(Equal("builder"))
+			Expect(catalog.VMTypes[0]).To(Equal(vmtypes.VMType{Name: "default", MachineType: "m4.large"}))
+			Expect(catalog.VMTypes).To(ContainElement(vmtypes.VMType{Name: "builder", MachineType: "c4.2xlarge"}))
+			Expect(catalog.VMTypes).To(ContainElement(vmtypes.VMType{Name: "huge", MachineType: "m4.16xlarge"}))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the override is not valid yaml", func() {
+				_, err := vmtypes.Load("aws", "us-east-1", "%%%")
+				Expect(err).To(MatchError(ContainSubstring("failed to parse vm type catalog")))
+			})
+
+			It("returns an error when a vm_type uses a machine type that is not available in the region", func() {
+				_, err := vmtypes.Load("aws", "eu-west-2", `
+vm_types:
+- name: default
+  machine_type: m3.medium
+`)
+				Expect(err).To(MatchError(`vm_type "default" uses machine type "m3.medium" which is not available in eu-west-2`))
+			})
+
+			It("returns an error when the override adds machine types", func() {
+				_, err := vmtypes.Load("aws", "us-east-1", `
+machine_types:
+- x1.16xlarge
+vm_types:
+- name: huge
+  machine_type: x1.16xlarge
+`)
+				Expect(err).To(MatchError("machine_types cannot be set in the vm type catalog, vm_types must use the machine types of the built-in catalog"))
+			})
+
+			It("returns an error when an override vm_type uses a machine type that is not in the built-in catalog", func() {
+				_, err := vmtypes.Load("gcp", "us-east1", `
+vm_types:
+- name: huge
+  machine_type: n1-ultramem-40
+`)
+				Expect(err).To(MatchError(`vm_type "huge" uses machine type "n1-ultramem-40" which is not available in us-east1`))
+			})
+
+			It("returns an error when a vm_type has no name", func() {
+				_, err := vmtypes.Load("gcp", "us-east1", `
+vm_types:
+- machine_type: n1-standard-1
+`)
+				Expect(err).To(MatchError(`vm_type with machine type "n1-standard-1" is missing a name`))
+			})
+
+			It("returns an error when the compilation vm_type is not defined", func() {
+				_, err := vmtypes.Load("gcp", "us-east1", "compilation: some-missing-vm-type")
+				Expect(err).To(MatchError(`compilation vm_type "some-missing-vm-type" is not defined in the vm type catalog`))
+			})
+
+			It("returns an error for an unknown iaas", func() {
+				_, err := vmtypes.Load("some-iaas", "some-region", "")
+				Expect(err).To(MatchError(`no vm type catalog for iaas "some-iaas"`))
+			})
+		})
+	})
+})
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 
@@ -29,7 +30,16 @@ type AWSCreateLBsConfig struct {
 	KeyPath      string
 	ChainPath    string
 	Domain       string
+	SpecPath     string
 	SkipIfExists bool
+
+	// CA is the certificate authority that signed the certificate at
+	// CertPath when create-lbs generated a self-signed certificate.
+	CA string
+
+	// LBKind is "alb" or "nlb" to create application or network load
+	// balancers instead of classic elbs.
+	LBKind string
 }
 
 type certificateManager interface {
@@ -75,12 +85,41 @@ func (c AWSCreateLBs) Execute(config AWSCreateLBsConfig, state storage.State) er
 		return err
 	}
 
-	if config.SkipIfExists && lbExists(state.Stack.LBType) {
-		c.logger.Println(fmt.Sprintf("lb type %q exists, skipping...", state.Stack.LBType))
-		return nil
+	spec, err := customLBSpecFor(config.LBType, config.SpecPath, state)
+	if err != nil {
+		return err
+	}
+
+	lbType := config.LBType
+	if spec != nil {
+		lbType = spec.Name
+	}
+
+	if config.SkipIfExists {
+		existingLBType := state.Stack.LBType
+		if _, ok := state.GetLB(lbType); ok {
+			existingLBType = lbType
+		}
+
+		if lbExists(existingLBType) {
+			c.logger.Println(fmt.Sprintf("lb type %q exists, skipping...", existingLBType))
+			return nil
+		}
+	}
+
+	if spec == nil {
+		if err := c.checkFastFails(config.LBType, state.Stack.LBType); err != nil {
+			return err
+		}
+	} else if state.TFState == "" {
+		return errors.New("custom load balancers are only supported for environments created with terraform")
+	}
+
+	if config.CA != "" && state.TFState == "" {
+		return errors.New("--self-signed is only supported for environments created with terraform")
 	}
 
-	if err := c.checkFastFails(config.LBType, state.Stack.LBType); err != nil {
+	if err := c.checkLBKind(config.LBKind, spec, state); err != nil {
 		return err
 	}
 
@@ -89,7 +128,25 @@ func (c AWSCreateLBs) Execute(config AWSCreateLBsConfig, state storage.State) er
 	}
 
 	if state.TFState != "" {
-		if config.LBType == "cf" || config.LBType == "concourse" {
+		lb, _ := state.GetLB(lbType)
+		lb.Type = lbType
+		lb.Spec = spec
+
+		switch config.LBKind {
+		case "":
+		case "elb":
+			lb.Kind = ""
+		default:
+			lb.Kind = config.LBKind
+		}
+
+		if spec != nil && spec.TLS {
+			if err := validateCertAndKeyFlags(config.CertPath, config.KeyPath); err != nil {
+				return err
+			}
+		}
+
+		if config.LBType == "cf" || config.LBType == "concourse" || (spec != nil && spec.TLS) {
 			certContents, err := ioutil.ReadFile(config.CertPath)
 			if err != nil {
 				return err
@@ -100,8 +157,9 @@ func (c AWSCreateLBs) Execute(config AWSCreateLBsConfig, state storage.State) er
 				return err
 			}
 
-			state.LB.Cert = string(certContents)
-			state.LB.Key = string(keyContents)
+			lb.Cert = string(certContents)
+			lb.Key = string(keyContents)
+			lb.CA = config.CA
 
 			if config.ChainPath != "" {
 				chainContents, err := ioutil.ReadFile(config.ChainPath)
@@ -109,15 +167,15 @@ func (c AWSCreateLBs) Execute(config AWSCreateLBsConfig, state storage.State) er
 					return err
 				}
 
-				state.LB.Chain = string(chainContents)
+				lb.Chain = string(chainContents)
 			}
 		}
 
 		if config.Domain != "" {
-			state.LB.Domain = config.Domain
+			lb.Domain = config.Domain
 		}
 
-		state.LB.Type = config.LBType
+		state = state.SetLB(lb)
 
 		state, err = c.terraformManager.Apply(state)
 		if err != nil {
@@ -144,7 +202,7 @@ func (c AWSCreateLBs) Execute(config AWSCreateLBsConfig, state storage.State) er
 		state.Stack.CertificateName = certificateName
 		state.Stack.LBType = config.LBType
 
-		if err := c.updateStack(state.AWS.Region, certificateName, state.KeyPair.Name, state.Stack.Name, state.Stack.BOSHAZ, config.LBType, state.EnvID); err != nil {
+		if err := c.updateStack(state.AWS, certificateName, state.KeyPair.Name, state.Stack.Name, state.Stack.BOSHAZ, config.LBType, state.EnvID, state.DirectorAllowedCIDRs, state.LBAllowedCIDRs, state.Tags); err != nil {
 			return err
 		}
 	}
@@ -174,7 +232,7 @@ func (c AWSCreateLBs) checkFastFails(newLBType string, currentLBType string) err
 	}
 
 	if !c.isValidLBType(newLBType) {
-		return fmt.Errorf("%q is not a valid lb type, valid lb types are: concourse and cf", newLBType)
+		return fmt.Errorf("%q is not a valid lb type, valid lb types are: concourse, cf and custom", newLBType)
 	}
 
 	if lbExists(currentLBType) {
@@ -184,16 +242,36 @@ func (c AWSCreateLBs) checkFastFails(newLBType string, currentLBType string) err
 	return nil
 }
 
-func (c AWSCreateLBs) updateStack(awsRegion string, certificateName string, keyPairName string, stackName string, boshAZ, lbType string,
-	envID string) error {
-	availabilityZones, err := c.availabilityZoneRetriever.Retrieve(awsRegion)
+func (AWSCreateLBs) checkLBKind(lbKind string, spec *storage.CustomLBSpec, state storage.State) error {
+	if lbKind == "" {
+		return nil
+	}
+
+	if lbKind != "elb" && lbKind != "alb" && lbKind != "nlb" {
+		return fmt.Errorf("%q is not a valid aws lb kind, valid aws lb kinds are: elb, alb and nlb", lbKind)
+	}
+
+	if spec != nil {
+		return errors.New("--aws-lb-kind is not supported for custom load balancers")
+	}
+
+	if state.TFState == "" {
+		return errors.New("--aws-lb-kind is only supported for environments created with terraform")
+	}
+
+	return nil
+}
+
+func (c AWSCreateLBs) updateStack(awsState storage.AWS, certificateName string, keyPairName string, stackName string, boshAZ, lbType string,
+	envID string, directorAllowedCIDRs, lbAllowedCIDRs []string, tags map[string]string) error {
+	availabilityZones, err := awsState.AvailabilityZones(c.availabilityZoneRetriever)
 	if err != nil {
 		return err
 	}
 
 	certificate, err := c.certificateManager.Describe(certificateName)
 
-	_, err = c.infrastructureManager.Update(keyPairName, availabilityZones, stackName, boshAZ, lbType, certificate.ARN, envID)
+	_, err = c.infrastructureManager.Update(keyPairName, availabilityZones, stackName, boshAZ, lbType, certificate.ARN, envID, directorAllowedCIDRs, lbAllowedCIDRs, awsState.NATType, tags)
 	if err != nil {
 		return err
 	}
@@ -10,6 +10,8 @@ import (
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/testhelpers"
+	"github.com/cloudfoundry/multierror"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -141,6 +143,35 @@ var _ = Describe("AWS Create LBs", func() {
 				Expect(infrastructureManager.UpdateCall.Receives.EnvID).To(Equal("some-env-id-timestamp"))
 				Expect(infrastructureManager.UpdateCall.Receives.BOSHAZ).To(Equal("some-bosh-az"))
 			})
+
+			It("creates the load balancer in the availability zones from the state", func() {
+				incomingState.AWS.AZs = []string{"b", "c"}
+
+				err := command.Execute(commands.AWSCreateLBsConfig{
+					LBType:   "concourse",
+					CertPath: "temp/some-cert.crt",
+					KeyPath:  "temp/some-key.key",
+				}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(availabilityZoneRetriever.RetrieveCall.Receives.Region).To(BeEmpty())
+				Expect(infrastructureManager.UpdateCall.Receives.AZs).To(Equal([]string{"b", "c"}))
+			})
+
+			It("restricts the stack ingress to the allowed cidrs from the state", func() {
+				incomingState.DirectorAllowedCIDRs = []string{"10.0.0.0/8"}
+				incomingState.LBAllowedCIDRs = []string{"172.16.0.0/12"}
+
+				err := command.Execute(commands.AWSCreateLBsConfig{
+					LBType:   "concourse",
+					CertPath: "temp/some-cert.crt",
+					KeyPath:  "temp/some-key.key",
+				}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(infrastructureManager.UpdateCall.Receives.DirectorAllowedCIDRs).To(Equal([]string{"10.0.0.0/8"}))
+				Expect(infrastructureManager.UpdateCall.Receives.LBAllowedCIDRs).To(Equal([]string{"172.16.0.0/12"}))
+			})
 		})
 
 		Context("when terraform was used to create infrastructure", func() {
@@ -205,10 +236,12 @@ var _ = Describe("AWS Create LBs", func() {
 			Context("when lb type desired is cf", func() {
 				BeforeEach(func() {
 					statePassedToTerraform = incomingState
-					statePassedToTerraform.LB = storage.LB{
-						Type: "cf",
-						Cert: "some-cert",
-						Key:  "some-key",
+					statePassedToTerraform.LBs = []storage.LB{
+						{
+							Type: "cf",
+							Cert: "some-cert",
+							Key:  "some-key",
+						},
 					}
 
 					stateReturnedFromTerraform = statePassedToTerraform
@@ -233,7 +266,7 @@ var _ = Describe("AWS Create LBs", func() {
 
 				Context("when the optional chain is provided", func() {
 					BeforeEach(func() {
-						statePassedToTerraform.LB.Chain = "some-chain"
+						statePassedToTerraform.LBs[0].Chain = "some-chain"
 
 						stateReturnedFromTerraform = statePassedToTerraform
 						stateReturnedFromTerraform.TFState = "some-updated-tf-state"
@@ -256,11 +289,13 @@ var _ = Describe("AWS Create LBs", func() {
 
 				Context("when a domain is provided", func() {
 					BeforeEach(func() {
-						statePassedToTerraform.LB = storage.LB{
-							Type:   "cf",
-							Cert:   "some-cert",
-							Key:    "some-key",
-							Domain: "some-domain",
+						statePassedToTerraform.LBs = []storage.LB{
+							{
+								Type:   "cf",
+								Cert:   "some-cert",
+								Key:    "some-key",
+								Domain: "some-domain",
+							},
 						}
 
 						stateReturnedFromTerraform = statePassedToTerraform
@@ -284,11 +319,13 @@ var _ = Describe("AWS Create LBs", func() {
 
 				Context("when a domain exists", func() {
 					BeforeEach(func() {
-						incomingState.LB = storage.LB{
-							Type:   "cf",
-							Cert:   "some-cert",
-							Key:    "some-key",
-							Domain: "some-domain",
+						incomingState.LBs = []storage.LB{
+							{
+								Type:   "cf",
+								Cert:   "some-cert",
+								Key:    "some-key",
+								Domain: "some-domain",
+							},
 						}
 						statePassedToTerraform = incomingState
 
@@ -314,10 +351,12 @@ var _ = Describe("AWS Create LBs", func() {
 			Context("when lb type desired is concourse", func() {
 				BeforeEach(func() {
 					statePassedToTerraform = incomingState
-					statePassedToTerraform.LB = storage.LB{
-						Type: "concourse",
-						Cert: "some-cert",
-						Key:  "some-key",
+					statePassedToTerraform.LBs = []storage.LB{
+						{
+							Type: "concourse",
+							Cert: "some-cert",
+							Key:  "some-key",
+						},
 					}
 
 					stateReturnedFromTerraform = statePassedToTerraform
@@ -342,7 +381,7 @@ var _ = Describe("AWS Create LBs", func() {
 
 				Context("when optional chain is provided", func() {
 					BeforeEach(func() {
-						statePassedToTerraform.LB.Chain = "some-chain"
+						statePassedToTerraform.LBs[0].Chain = "some-chain"
 
 						stateReturnedFromTerraform = statePassedToTerraform
 						stateReturnedFromTerraform.TFState = "some-updated-tf-state"
@@ -362,7 +401,269 @@ var _ = Describe("AWS Create LBs", func() {
 						Expect(stateStore.SetCall.Receives[0].State).To(Equal(stateReturnedFromTerraform))
 					})
 				})
+
+				Context("when a cf lb is already attached", func() {
+					BeforeEach(func() {
+						incomingState.LBs = []storage.LB{
+							{
+								Type:   "cf",
+								Cert:   "some-cf-cert",
+								Key:    "some-cf-key",
+								Domain: "some-domain",
+							},
+						}
+					})
+
+					It("attaches the concourse lb alongside the cf lb", func() {
+						err := command.Execute(commands.AWSCreateLBsConfig{
+							LBType:   "concourse",
+							CertPath: certPath,
+							KeyPath:  keyPath,
+						}, incomingState)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(terraformManager.ApplyCall.Receives.BBLState.LBs).To(Equal([]storage.LB{
+							{
+								Type:   "cf",
+								Cert:   "some-cf-cert",
+								Key:    "some-cf-key",
+								Domain: "some-domain",
+							},
+							{
+								Type: "concourse",
+								Cert: "some-cert",
+								Key:  "some-key",
+							},
+						}))
+					})
+				})
+			})
+
+			Context("when lb type desired is custom", func() {
+				var specPath string
+
+				BeforeEach(func() {
+					var err error
+					specPath, err = testhelpers.WriteContentsToTempFile(`
+name: vault
+tls: true
+listeners:
+- protocol: tcp
+  port: 8200
+- protocol: http
+  port: 80
+  target_port: 8080
+health_check:
+  protocol: http
+  port: 8200
+  path: /v1/sys/health
+`)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("creates a load balancer named after the spec using terraform", func() {
+					err := command.Execute(commands.AWSCreateLBsConfig{
+						LBType:   "custom",
+						CertPath: certPath,
+						KeyPath:  keyPath,
+						SpecPath: specPath,
+					}, incomingState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(terraformManager.ApplyCall.Receives.BBLState.LBs).To(Equal([]storage.LB{
+						{
+							Type: "vault",
+							Cert: "some-cert",
+							Key:  "some-key",
+							Spec: &storage.CustomLBSpec{
+								Name: "vault",
+								TLS:  true,
+								Listeners: []storage.CustomLBListener{
+									{Protocol: "tcp", Port: 8200, TargetPort: 8200},
+									{Protocol: "http", Port: 80, TargetPort: 8080},
+								},
+								HealthCheck: storage.CustomLBHealthCheck{
+									Protocol: "http",
+									Port:     8200,
+									Path:     "/v1/sys/health",
+								},
+							},
+						},
+					}))
+				})
+
+				It("returns an error when the spec enables tls and cert and key are not provided", func() {
+					expectedErrors := multierror.NewMultiError("create-lbs")
+					expectedErrors.Add(errors.New("--cert is required"))
+					expectedErrors.Add(errors.New("--key is required"))
+
+					err := command.Execute(commands.AWSCreateLBsConfig{
+						LBType:   "custom",
+						SpecPath: specPath,
+					}, incomingState)
+					Expect(err).To(MatchError(expectedErrors))
+					Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+				})
+
+				It("does not require a cert and key when the spec does not enable tls", func() {
+					plainSpecPath, err := testhelpers.WriteContentsToTempFile("name: kafka\nlisteners:\n- {protocol: tcp, port: 9092}\n")
+					Expect(err).NotTo(HaveOccurred())
+
+					err = command.Execute(commands.AWSCreateLBsConfig{
+						LBType:   "custom",
+						SpecPath: plainSpecPath,
+					}, incomingState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(terraformManager.ApplyCall.Receives.BBLState.LBs).To(HaveLen(1))
+					Expect(terraformManager.ApplyCall.Receives.BBLState.LBs[0].Type).To(Equal("kafka"))
+					Expect(terraformManager.ApplyCall.Receives.BBLState.LBs[0].Cert).To(BeEmpty())
+				})
 			})
+
+			Context("when the certificate is self-signed", func() {
+				It("stores the CA with the lb", func() {
+					err := command.Execute(commands.AWSCreateLBsConfig{
+						LBType:   "concourse",
+						CertPath: certPath,
+						KeyPath:  keyPath,
+						CA:       "some-ca",
+					}, incomingState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(terraformManager.ApplyCall.Receives.BBLState.LBs).To(Equal([]storage.LB{
+						{
+							Type: "concourse",
+							Cert: "some-cert",
+							Key:  "some-key",
+							CA:   "some-ca",
+						},
+					}))
+				})
+			})
+
+			Context("when an aws lb kind is provided", func() {
+				It("stores the kind with the lb", func() {
+					err := command.Execute(commands.AWSCreateLBsConfig{
+						LBType:   "cf",
+						CertPath: certPath,
+						KeyPath:  keyPath,
+						LBKind:   "alb",
+					}, incomingState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(terraformManager.ApplyCall.Receives.BBLState.LBs).To(Equal([]storage.LB{
+						{
+							Type: "cf",
+							Cert: "some-cert",
+							Key:  "some-key",
+							Kind: "alb",
+						},
+					}))
+				})
+
+				It("keeps the kind of an attached lb when no kind is provided", func() {
+					incomingState.LBs = []storage.LB{{Type: "concourse", Kind: "nlb"}}
+
+					err := command.Execute(commands.AWSCreateLBsConfig{
+						LBType:   "concourse",
+						CertPath: certPath,
+						KeyPath:  keyPath,
+					}, incomingState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(terraformManager.ApplyCall.Receives.BBLState.LBs[0].Kind).To(Equal("nlb"))
+				})
+
+				It("switches an attached lb back to a classic elb", func() {
+					incomingState.LBs = []storage.LB{{Type: "concourse", Kind: "nlb"}}
+
+					err := command.Execute(commands.AWSCreateLBsConfig{
+						LBType:   "concourse",
+						CertPath: certPath,
+						KeyPath:  keyPath,
+						LBKind:   "elb",
+					}, incomingState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(terraformManager.ApplyCall.Receives.BBLState.LBs[0].Kind).To(BeEmpty())
+				})
+
+				It("returns an error when the kind is not valid", func() {
+					err := command.Execute(commands.AWSCreateLBsConfig{
+						LBType:   "cf",
+						CertPath: certPath,
+						KeyPath:  keyPath,
+						LBKind:   "gwlb",
+					}, incomingState)
+					Expect(err).To(MatchError(`"gwlb" is not a valid aws lb kind, valid aws lb kinds are: elb, alb and nlb`))
+					Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+				})
+
+				It("returns an error when the lb is custom", func() {
+					specPath, err := testhelpers.WriteContentsToTempFile("name: vault\nlisteners:\n- {protocol: tcp, port: 8200, target_port: 8200}\nhealth_check: {protocol: tcp, port: 8200}\n")
+					Expect(err).NotTo(HaveOccurred())
+
+					err = command.Execute(commands.AWSCreateLBsConfig{
+						LBType:   "custom",
+						SpecPath: specPath,
+						LBKind:   "alb",
+					}, incomingState)
+					Expect(err).To(MatchError("--aws-lb-kind is not supported for custom load balancers"))
+					Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+				})
+			})
+
+			Context("when skip if exists is true and an lb of the same type is attached", func() {
+				BeforeEach(func() {
+					incomingState.LBs = []storage.LB{
+						{
+							Type: "concourse",
+						},
+					}
+				})
+
+				It("no-ops", func() {
+					err := command.Execute(commands.AWSCreateLBsConfig{
+						LBType:       "concourse",
+						SkipIfExists: true,
+					}, incomingState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+					Expect(logger.PrintlnCall.Receives.Message).To(Equal(`lb type "concourse" exists, skipping...`))
+				})
+			})
+		})
+
+		It("returns an error when a self-signed certificate is used for a cloudformation environment", func() {
+			err := command.Execute(commands.AWSCreateLBsConfig{
+				LBType: "concourse",
+				CA:     "some-ca",
+			}, incomingState)
+			Expect(err).To(MatchError("--self-signed is only supported for environments created with terraform"))
+			Expect(certificateManager.CreateCall.CallCount).To(Equal(0))
+		})
+
+		It("returns an error when an aws lb kind is requested for a cloudformation environment", func() {
+			err := command.Execute(commands.AWSCreateLBsConfig{
+				LBType: "concourse",
+				LBKind: "alb",
+			}, incomingState)
+			Expect(err).To(MatchError("--aws-lb-kind is only supported for environments created with terraform"))
+			Expect(certificateManager.CreateCall.CallCount).To(Equal(0))
+		})
+
+		It("returns an error when a custom lb is requested for a cloudformation environment", func() {
+			specPath, err := testhelpers.WriteContentsToTempFile("name: vault\nlisteners:\n- {protocol: tcp, port: 8200}\n")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = command.Execute(commands.AWSCreateLBsConfig{
+				LBType:   "custom",
+				SpecPath: specPath,
+			}, incomingState)
+			Expect(err).To(MatchError("custom load balancers are only supported for environments created with terraform"))
+			Expect(infrastructureManager.UpdateCall.CallCount).To(Equal(0))
 		})
 
 		It("names the loadbalancer without EnvID when EnvID is not set", func() {
@@ -494,7 +795,7 @@ var _ = Describe("AWS Create LBs", func() {
 					CertPath: "temp/some-cert.crt",
 					KeyPath:  "temp/some-key.key",
 				}, incomingState)
-				Expect(err).To(MatchError("\"some-invalid-lb\" is not a valid lb type, valid lb types are: concourse and cf"))
+				Expect(err).To(MatchError("\"some-invalid-lb\" is not a valid lb type, valid lb types are: concourse, cf and custom"))
 			})
 
 			It("returns a helpful error when no lb type is provided", func() {
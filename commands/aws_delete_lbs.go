@@ -19,6 +19,7 @@ type AWSDeleteLBs struct {
 }
 
 type deleteLBsConfig struct {
+	lbType        string
 	skipIfMissing bool
 }
 
@@ -40,7 +41,7 @@ func NewAWSDeleteLBs(credentialValidator credentialValidator, availabilityZoneRe
 	}
 }
 
-func (c AWSDeleteLBs) Execute(state storage.State) error {
+func (c AWSDeleteLBs) Execute(lbType string, state storage.State) error {
 	err := c.credentialValidator.Validate()
 	if err != nil {
 		return err
@@ -52,15 +53,13 @@ func (c AWSDeleteLBs) Execute(state storage.State) error {
 	}
 
 	if state.TFState != "" {
-		if !lbExists(state.LB.Type) {
+		var found bool
+		state, found = removeLBs(state, lbType)
+		if !found {
 			return LBNotFound
 		}
-
-		state.LB.Type = ""
-		state.LB.Cert = ""
-		state.LB.Key = ""
 	} else {
-		if !lbExists(state.Stack.LBType) {
+		if !lbExists(state.Stack.LBType) || (lbType != "" && lbType != state.Stack.LBType) {
 			return LBNotFound
 		}
 
@@ -85,12 +84,12 @@ func (c AWSDeleteLBs) Execute(state storage.State) error {
 			return handleTerraformError(err, c.stateStore)
 		}
 	} else {
-		azs, err := c.availabilityZoneRetriever.Retrieve(state.AWS.Region)
+		azs, err := state.AWS.AvailabilityZones(c.availabilityZoneRetriever)
 		if err != nil {
 			return err
 		}
 
-		_, err = c.infrastructureManager.Update(state.KeyPair.Name, azs, state.Stack.Name, state.Stack.BOSHAZ, "", "", state.EnvID)
+		_, err = c.infrastructureManager.Update(state.KeyPair.Name, azs, state.Stack.Name, state.Stack.BOSHAZ, "", "", state.EnvID, state.DirectorAllowedCIDRs, state.LBAllowedCIDRs, state.AWS.NATType, state.Tags)
 		if err != nil {
 			return err
 		}
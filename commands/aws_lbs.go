@@ -1,8 +1,8 @@
 package commands
 
 import (
-	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/cloudfoundry/bosh-bootloader/storage"
@@ -36,40 +36,38 @@ func (l AWSLBs) Execute(subcommandFlags []string, state storage.State) error {
 			return err
 		}
 
-		switch state.LB.Type {
-		case "cf":
-			if len(subcommandFlags) > 0 && subcommandFlags[0] == "--json" {
-				lbOutput, err := json.Marshal(struct {
-					RouterLBName           string   `json:"cf_router_lb,omitempty"`
-					RouterLBURL            string   `json:"cf_router_lb_url,omitempty"`
-					SSHProxyLBName         string   `json:"cf_ssh_proxy_lb,omitempty"`
-					SSHProxyLBURL          string   `json:"cf_ssh_proxy_lb_url,omitempty"`
-					SystemDomainDNSServers []string `json:"env_dns_zone_name_servers,omitempty"`
-				}{
-					RouterLBName:           terraformOutputs["cf_router_load_balancer"].(string),
-					RouterLBURL:            terraformOutputs["cf_router_load_balancer_url"].(string),
-					SSHProxyLBName:         terraformOutputs["cf_ssh_proxy_load_balancer"].(string),
-					SSHProxyLBURL:          terraformOutputs["cf_ssh_proxy_load_balancer_url"].(string),
-					SystemDomainDNSServers: terraformOutputs["cf_system_domain_dns_servers"].([]string),
-				})
-				if err != nil {
-					// not tested
-					return err
-				}
+		if len(state.LBs) == 0 {
+			return errors.New("no lbs found")
+		}
 
-				l.logger.Println(string(lbOutput))
-			} else {
+		if isJSONFlag(subcommandFlags) {
+			return printLBsJSON(l.logger, l.terraformLBsOutput(state, terraformOutputs))
+		}
+
+		for _, lb := range state.LBs {
+			switch lb.Type {
+			case "cf":
 				l.logger.Printf("CF Router LB: %s [%s]\n", terraformOutputs["cf_router_load_balancer"], terraformOutputs["cf_router_load_balancer_url"])
 				l.logger.Printf("CF SSH Proxy LB: %s [%s]\n", terraformOutputs["cf_ssh_proxy_load_balancer"], terraformOutputs["cf_ssh_proxy_load_balancer_url"])
 
 				if dnsServers, ok := terraformOutputs["cf_system_domain_dns_servers"]; ok {
 					l.logger.Printf("CF System Domain DNS servers: %s\n", strings.Join(dnsServers.([]string), " "))
 				}
+			case "concourse":
+				l.logger.Printf("Concourse LB: %s [%s]\n", terraformOutputs["concourse_load_balancer"], terraformOutputs["concourse_load_balancer_url"])
+
+				if _, ok := terraformOutputs["concourse_tsa_load_balancer"]; ok {
+					l.logger.Printf("Concourse TSA LB: %s [%s]\n", terraformOutputs["concourse_tsa_load_balancer"], terraformOutputs["concourse_tsa_load_balancer_url"])
+				}
+
+				if dnsServers, ok := terraformOutputs["concourse_domain_dns_servers"]; ok {
+					l.logger.Printf("Concourse Domain DNS servers: %s\n", strings.Join(dnsServers.([]string), " "))
+				}
+			default:
+				if lb.IsCustom() {
+					l.logger.Printf("%s LB: %s [%s]\n", lb.Type, terraformOutputs[fmt.Sprintf("%s_load_balancer", lb.Type)], terraformOutputs[fmt.Sprintf("%s_load_balancer_url", lb.Type)])
+				}
 			}
-		case "concourse":
-			l.logger.Printf("Concourse LB: %s [%s]\n", terraformOutputs["concourse_load_balancer"], terraformOutputs["concourse_load_balancer_url"])
-		default:
-			return errors.New("no lbs found")
 		}
 
 	} else {
@@ -78,15 +76,115 @@ func (l AWSLBs) Execute(subcommandFlags []string, state storage.State) error {
 			return err
 		}
 
+		lbsOutput := LBsOutput{IAAS: "aws", AllowedCIDRs: state.LBAllowedCIDRs, LBs: []LBOutput{}}
+		switch state.Stack.LBType {
+		case "cf":
+			lbsOutput.LBs = append(lbsOutput.LBs, LBOutput{
+				Type:            "cf",
+				CertificateName: state.Stack.CertificateName,
+				LoadBalancers: []LoadBalancerOutput{
+					{Component: "router", Name: stack.Outputs["CFRouterLoadBalancer"], DNSName: stack.Outputs["CFRouterLoadBalancerURL"]},
+					{Component: "ssh_proxy", Name: stack.Outputs["CFSSHProxyLoadBalancer"], DNSName: stack.Outputs["CFSSHProxyLoadBalancerURL"]},
+				},
+			})
+		case "concourse":
+			lbsOutput.LBs = append(lbsOutput.LBs, LBOutput{
+				Type:            "concourse",
+				CertificateName: state.Stack.CertificateName,
+				LoadBalancers: []LoadBalancerOutput{
+					{Component: "concourse", Name: stack.Outputs["ConcourseLoadBalancer"], DNSName: stack.Outputs["ConcourseLoadBalancerURL"]},
+				},
+			})
+		default:
+			return errors.New("no lbs found")
+		}
+
+		if isJSONFlag(subcommandFlags) {
+			return printLBsJSON(l.logger, lbsOutput)
+		}
+
 		switch state.Stack.LBType {
 		case "cf":
 			l.logger.Printf("CF Router LB: %s [%s]\n", stack.Outputs["CFRouterLoadBalancer"], stack.Outputs["CFRouterLoadBalancerURL"])
 			l.logger.Printf("CF SSH Proxy LB: %s [%s]\n", stack.Outputs["CFSSHProxyLoadBalancer"], stack.Outputs["CFSSHProxyLoadBalancerURL"])
 		case "concourse":
 			l.logger.Printf("Concourse LB: %s [%s]\n", stack.Outputs["ConcourseLoadBalancer"], stack.Outputs["ConcourseLoadBalancerURL"])
-		default:
-			return errors.New("no lbs found")
 		}
 	}
 	return nil
 }
+
+func (AWSLBs) terraformLBsOutput(state storage.State, terraformOutputs map[string]interface{}) LBsOutput {
+	lbsOutput := LBsOutput{IAAS: "aws", AllowedCIDRs: state.LBAllowedCIDRs, LBs: []LBOutput{}}
+
+	for _, lb := range state.LBs {
+		lbOutput := LBOutput{
+			Type:            lb.Type,
+			Kind:            lb.Kind,
+			Domain:          lb.Domain,
+			CertificateName: stringOutput(terraformOutputs, fmt.Sprintf("%s_certificate_name", lb.Type)),
+		}
+
+		switch lb.Type {
+		case "cf":
+			lbOutput.DNSServers, _ = terraformOutputs["cf_system_domain_dns_servers"].([]string)
+			lbOutput.LoadBalancers = []LoadBalancerOutput{
+				{
+					Component:    "router",
+					Name:         stringOutput(terraformOutputs, "cf_router_load_balancer"),
+					DNSName:      stringOutput(terraformOutputs, "cf_router_load_balancer_url"),
+					TargetGroups: stringsOutput(terraformOutputs, "cf_router_target_group"),
+				},
+				{
+					Component: "ssh_proxy",
+					Name:      stringOutput(terraformOutputs, "cf_ssh_proxy_load_balancer"),
+					DNSName:   stringOutput(terraformOutputs, "cf_ssh_proxy_load_balancer_url"),
+				},
+				{
+					Component: "tcp_router",
+					Name:      stringOutput(terraformOutputs, "cf_tcp_router_load_balancer"),
+					DNSName:   stringOutput(terraformOutputs, "cf_tcp_router_load_balancer_url"),
+				},
+			}
+		case "concourse":
+			lbOutput.DNSServers, _ = terraformOutputs["concourse_domain_dns_servers"].([]string)
+			concourse := LoadBalancerOutput{
+				Component: "concourse",
+				Name:      stringOutput(terraformOutputs, "concourse_load_balancer"),
+				DNSName:   stringOutput(terraformOutputs, "concourse_load_balancer_url"),
+			}
+
+			if _, ok := terraformOutputs["concourse_tsa_load_balancer"]; ok {
+				concourse.TargetGroups = stringsOutput(terraformOutputs, "concourse_target_group")
+				lbOutput.LoadBalancers = []LoadBalancerOutput{
+					concourse,
+					{
+						Component:    "tsa",
+						Name:         stringOutput(terraformOutputs, "concourse_tsa_load_balancer"),
+						DNSName:      stringOutput(terraformOutputs, "concourse_tsa_load_balancer_url"),
+						TargetGroups: stringsOutput(terraformOutputs, "concourse_tsa_target_group"),
+					},
+				}
+			} else {
+				concourse.TargetGroups = stringsOutput(terraformOutputs, "concourse_target_group", "concourse_tsa_target_group")
+				lbOutput.LoadBalancers = []LoadBalancerOutput{concourse}
+			}
+		default:
+			if !lb.IsCustom() {
+				continue
+			}
+
+			lbOutput.LoadBalancers = []LoadBalancerOutput{
+				{
+					Component: lb.Type,
+					Name:      stringOutput(terraformOutputs, fmt.Sprintf("%s_load_balancer", lb.Type)),
+					DNSName:   stringOutput(terraformOutputs, fmt.Sprintf("%s_load_balancer_url", lb.Type)),
+				},
+			}
+		}
+
+		lbsOutput.LBs = append(lbsOutput.LBs, lbOutput)
+	}
+
+	return lbsOutput
+}
@@ -89,6 +89,36 @@ var _ = Describe("AWSLBs", func() {
 				}))
 			})
 
+			It("prints the lbs in json format when the json flag is provided", func() {
+				infrastructureManager.DescribeCall.Returns.Stack = cloudformation.Stack{
+					Name: "some-stack-name",
+					Outputs: map[string]string{
+						"ConcourseLoadBalancer":    "some-lb-name",
+						"ConcourseLoadBalancerURL": "http://some.lb.url",
+					},
+				}
+				incomingState.Stack = storage.Stack{
+					LBType:          "concourse",
+					Name:            "some-stack-name",
+					CertificateName: "some-certificate-name",
+				}
+
+				err := command.Execute([]string{"--json"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintfCall.CallCount).To(Equal(0))
+				Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(`{
+					"iaas": "aws",
+					"lbs": [{
+						"type": "concourse",
+						"certificate_name": "some-certificate-name",
+						"load_balancers": [
+							{"component": "concourse", "name": "some-lb-name", "dns_name": "http://some.lb.url"}
+						]
+					}]
+				}`))
+			})
+
 			It("returns error when lb type is not cf or concourse", func() {
 				incomingState.Stack = storage.Stack{
 					LBType: "",
@@ -127,8 +157,10 @@ var _ = Describe("AWSLBs", func() {
 					incomingState = storage.State{
 						IAAS:    "aws",
 						TFState: "some-tf-state",
-						LB: storage.LB{
-							Type: "cf",
+						LBs: []storage.LB{
+							{
+								Type: "cf",
+							},
 						},
 					}
 					terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{
@@ -153,7 +185,7 @@ var _ = Describe("AWSLBs", func() {
 
 				Context("when the domain is specified", func() {
 					BeforeEach(func() {
-						incomingState.LB.Domain = "some-domain"
+						incomingState.LBs[0].Domain = "some-domain"
 
 						terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{
 							"cf_router_load_balancer":        "some-router-lb-name",
@@ -178,22 +210,27 @@ var _ = Describe("AWSLBs", func() {
 
 					Context("when the json flag is provided", func() {
 						It("prints LB names, URLs, and DNS servers in json format", func() {
-							incomingState.LB = storage.LB{
-								Type:   "cf",
-								Domain: "some-domain",
+							incomingState.LBs = []storage.LB{
+								{
+									Type:   "cf",
+									Domain: "some-domain",
+								},
 							}
 							err := command.Execute([]string{"--json"}, incomingState)
 							Expect(err).NotTo(HaveOccurred())
 
 							Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(`{
-								"cf_router_lb": "some-router-lb-name",
-								"cf_router_lb_url": "some-router-lb-url",
-								"cf_ssh_proxy_lb": "some-ssh-proxy-lb-name",
-								"cf_ssh_proxy_lb_url": "some-ssh-proxy-lb-url",
-								"env_dns_zone_name_servers": [
-									"name-server-1.",
-									"name-server-2."
-								]
+								"iaas": "aws",
+								"lbs": [{
+									"type": "cf",
+									"domain": "some-domain",
+									"dns_servers": ["name-server-1.", "name-server-2."],
+									"load_balancers": [
+										{"component": "router", "name": "some-router-lb-name", "dns_name": "some-router-lb-url"},
+										{"component": "ssh_proxy", "name": "some-ssh-proxy-lb-name", "dns_name": "some-ssh-proxy-lb-url"},
+										{"component": "tcp_router"}
+									]
+								}]
 							}`))
 						})
 					})
@@ -205,8 +242,10 @@ var _ = Describe("AWSLBs", func() {
 					incomingState = storage.State{
 						IAAS:    "aws",
 						TFState: "some-tf-state",
-						LB: storage.LB{
-							Type: "concourse",
+						LBs: []storage.LB{
+							{
+								Type: "concourse",
+							},
 						},
 					}
 					terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{
@@ -225,15 +264,196 @@ var _ = Describe("AWSLBs", func() {
 						"Concourse LB: some-concourse-lb-name [some-concourse-lb-url]\n",
 					}))
 				})
+
+				Context("when the lb is an application lb", func() {
+					BeforeEach(func() {
+						incomingState.LBs[0].Kind = "alb"
+						terraformManager.GetOutputsCall.Returns.Outputs["concourse_tsa_load_balancer"] = "some-concourse-tsa-lb-name"
+						terraformManager.GetOutputsCall.Returns.Outputs["concourse_tsa_load_balancer_url"] = "some-concourse-tsa-lb-url"
+					})
+
+					It("prints the name and URL of the tsa lb", func() {
+						err := command.Execute([]string{}, incomingState)
+
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(logger.PrintfCall.Messages).To(ConsistOf([]string{
+							"Concourse LB: some-concourse-lb-name [some-concourse-lb-url]\n",
+							"Concourse TSA LB: some-concourse-tsa-lb-name [some-concourse-tsa-lb-url]\n",
+						}))
+					})
+				})
+
+				Context("when the domain is specified", func() {
+					BeforeEach(func() {
+						incomingState.LBs[0].Domain = "ci.example.com"
+						terraformManager.GetOutputsCall.Returns.Outputs["concourse_domain_dns_servers"] = []string{"name-server-1.", "name-server-2."}
+					})
+
+					It("prints the DNS servers of the concourse zone", func() {
+						err := command.Execute([]string{}, incomingState)
+
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(logger.PrintfCall.Messages).To(ConsistOf([]string{
+							"Concourse LB: some-concourse-lb-name [some-concourse-lb-url]\n",
+							"Concourse Domain DNS servers: name-server-1. name-server-2.\n",
+						}))
+					})
+
+					It("prints the domain and DNS servers in json format when the json flag is provided", func() {
+						err := command.Execute([]string{"--json"}, incomingState)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(`{
+							"iaas": "aws",
+							"lbs": [{
+								"type": "concourse",
+								"domain": "ci.example.com",
+								"dns_servers": ["name-server-1.", "name-server-2."],
+								"load_balancers": [{
+									"component": "concourse",
+									"name": "some-concourse-lb-name",
+									"dns_name": "some-concourse-lb-url"
+								}]
+							}]
+						}`))
+					})
+				})
+
+				Context("when the json flag is provided", func() {
+					BeforeEach(func() {
+						terraformManager.GetOutputsCall.Returns.Outputs["concourse_certificate_name"] = "some-certificate-name"
+						terraformManager.GetOutputsCall.Returns.Outputs["concourse_target_group"] = "some-concourse-target-group"
+						terraformManager.GetOutputsCall.Returns.Outputs["concourse_tsa_target_group"] = "some-concourse-tsa-target-group"
+					})
+
+					It("prints the network lb with both target groups", func() {
+						incomingState.LBs[0].Kind = "nlb"
+
+						err := command.Execute([]string{"--json"}, incomingState)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(`{
+							"iaas": "aws",
+							"lbs": [{
+								"type": "concourse",
+								"kind": "nlb",
+								"certificate_name": "some-certificate-name",
+								"load_balancers": [{
+									"component": "concourse",
+									"name": "some-concourse-lb-name",
+									"dns_name": "some-concourse-lb-url",
+									"target_groups": ["some-concourse-target-group", "some-concourse-tsa-target-group"]
+								}]
+							}]
+						}`))
+					})
+
+					It("prints the application lb and the tsa lb", func() {
+						incomingState.LBs[0].Kind = "alb"
+						terraformManager.GetOutputsCall.Returns.Outputs["concourse_tsa_load_balancer"] = "some-concourse-tsa-lb-name"
+						terraformManager.GetOutputsCall.Returns.Outputs["concourse_tsa_load_balancer_url"] = "some-concourse-tsa-lb-url"
+
+						err := command.Execute([]string{"--json"}, incomingState)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(`{
+							"iaas": "aws",
+							"lbs": [{
+								"type": "concourse",
+								"kind": "alb",
+								"certificate_name": "some-certificate-name",
+								"load_balancers": [
+									{
+										"component": "concourse",
+										"name": "some-concourse-lb-name",
+										"dns_name": "some-concourse-lb-url",
+										"target_groups": ["some-concourse-target-group"]
+									},
+									{
+										"component": "tsa",
+										"name": "some-concourse-tsa-lb-name",
+										"dns_name": "some-concourse-tsa-lb-url",
+										"target_groups": ["some-concourse-tsa-target-group"]
+									}
+								]
+							}]
+						}`))
+					})
+				})
 			})
 
-			It("returns error when lb type is not cf or concourse", func() {
+			Context("when a custom lb is attached", func() {
+				BeforeEach(func() {
+					incomingState = storage.State{
+						IAAS:    "aws",
+						TFState: "some-tf-state",
+						LBs: []storage.LB{
+							{
+								Type: "vault",
+								Spec: &storage.CustomLBSpec{Name: "vault"},
+							},
+						},
+					}
+					terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{
+						"vault_load_balancer":     "some-vault-lb-name",
+						"vault_load_balancer_url": "some-vault-lb-url",
+					}
+				})
+
+				It("prints LB name and URL", func() {
+					err := command.Execute([]string{}, incomingState)
+
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(logger.PrintfCall.Messages).To(ConsistOf([]string{
+						"vault LB: some-vault-lb-name [some-vault-lb-url]\n",
+					}))
+				})
+			})
+
+			Context("when the cf and concourse lbs are attached", func() {
+				BeforeEach(func() {
+					incomingState = storage.State{
+						IAAS:    "aws",
+						TFState: "some-tf-state",
+						LBs: []storage.LB{
+							{
+								Type: "cf",
+							},
+							{
+								Type: "concourse",
+							},
+						},
+					}
+					terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{
+						"cf_router_load_balancer":        "some-router-lb-name",
+						"cf_router_load_balancer_url":    "some-router-lb-url",
+						"cf_ssh_proxy_load_balancer":     "some-ssh-proxy-lb-name",
+						"cf_ssh_proxy_load_balancer_url": "some-ssh-proxy-lb-url",
+						"concourse_load_balancer":        "some-concourse-lb-name",
+						"concourse_load_balancer_url":    "some-concourse-lb-url",
+					}
+				})
+
+				It("prints the names and URLs of both lbs", func() {
+					err := command.Execute([]string{}, incomingState)
+
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(logger.PrintfCall.Messages).To(ConsistOf([]string{
+						"CF Router LB: some-router-lb-name [some-router-lb-url]\n",
+						"CF SSH Proxy LB: some-ssh-proxy-lb-name [some-ssh-proxy-lb-url]\n",
+						"Concourse LB: some-concourse-lb-name [some-concourse-lb-url]\n",
+					}))
+				})
+			})
+
+			It("returns error when no lb is attached", func() {
 				incomingState = storage.State{
 					IAAS:    "aws",
 					TFState: "some-tf-state",
-					LB: storage.LB{
-						Type: "other",
-					},
 				}
 				err := command.Execute([]string{}, incomingState)
 
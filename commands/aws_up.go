@@ -25,8 +25,8 @@ type keyPairManager interface {
 }
 
 type infrastructureManager interface {
-	Create(keyPairName string, azs []string, stackName, boshAZ, lbType, lbCertificateARN, envID string) (cloudformation.Stack, error)
-	Update(keyPairName string, azs []string, stackName, boshAZ, lbType, lbCertificateARN, envID string) (cloudformation.Stack, error)
+	Create(keyPairName string, azs []string, stackName, boshAZ, lbType, lbCertificateARN, envID string, directorAllowedCIDRs, lbAllowedCIDRs []string, natType string, tags map[string]string) (cloudformation.Stack, error)
+	Update(keyPairName string, azs []string, stackName, boshAZ, lbType, lbCertificateARN, envID string, directorAllowedCIDRs, lbAllowedCIDRs []string, natType string, tags map[string]string) (cloudformation.Stack, error)
 	Exists(stackName string) (bool, error)
 	Delete(stackName string) error
 	Describe(stackName string) (cloudformation.Stack, error)
@@ -62,6 +62,7 @@ type configProvider interface {
 type cloudConfigManager interface {
 	Update(state storage.State) error
 	Generate(state storage.State) (string, error)
+	Diff(state storage.State) (string, error)
 }
 
 type brokenEnvironmentValidator interface {
@@ -81,17 +82,30 @@ type AWSUp struct {
 	envIDManager               envIDManager
 	terraformManager           terraformManager
 	brokenEnvironmentValidator brokenEnvironmentValidator
+	logger                     logger
 }
 
 type AWSUpConfig struct {
-	AccessKeyID     string
-	SecretAccessKey string
-	Region          string
-	OpsFilePath     string
-	BOSHAZ          string
-	Name            string
-	NoDirector      bool
-	Terraform       bool
+	AccessKeyID             string
+	SecretAccessKey         string
+	SessionToken            string
+	Profile                 string
+	AssumeRoleARN           string
+	MFASerial               string
+	Region                  string
+	OpsFilePath             string
+	RuntimeConfigPath       string
+	CPIConfigPath           string
+	CloudConfigOpsFilePaths []string
+	VMTypeCatalogPath       string
+	BOSHAZ                  string
+	AZs                     []string
+	AZCount                 int
+	NATType                 string
+	IAMInstanceProfile      bool
+	Name                    string
+	NoDirector              bool
+	Terraform               bool
 }
 
 func NewAWSUp(
@@ -100,7 +114,8 @@ func NewAWSUp(
 	availabilityZoneRetriever availabilityZoneRetriever,
 	certificateDescriber certificateDescriber, cloudConfigManager cloudConfigManager,
 	stateStore stateStore, configProvider configProvider, envIDManager envIDManager,
-	terraformManager terraformManager, brokenEnvironmentValidator brokenEnvironmentValidator) AWSUp {
+	terraformManager terraformManager, brokenEnvironmentValidator brokenEnvironmentValidator,
+	logger logger) AWSUp {
 
 	return AWSUp{
 		credentialValidator:        credentialValidator,
@@ -115,6 +130,7 @@ func NewAWSUp(
 		envIDManager:               envIDManager,
 		terraformManager:           terraformManager,
 		brokenEnvironmentValidator: brokenEnvironmentValidator,
+		logger:                     logger,
 	}
 }
 
@@ -124,13 +140,31 @@ func (u AWSUp) Execute(config AWSUpConfig, state storage.State) error {
 	if u.awsCredentialsPresent(config) {
 		state.AWS.AccessKeyID = config.AccessKeyID
 		state.AWS.SecretAccessKey = config.SecretAccessKey
+		state.AWS.SessionToken = config.SessionToken
+		state.AWS.Profile = config.Profile
+		state.AWS.AssumeRoleARN = config.AssumeRoleARN
+		state.AWS.MFASerial = config.MFASerial
 		state.AWS.Region = config.Region
+
+		// Keys used alongside a profile or role are only needed for this run
+		// and are kept out of the state. Later commands resolve the base
+		// credentials from the environment or the shared aws files.
+		if config.Profile != "" || config.AssumeRoleARN != "" {
+			state.AWS.AccessKeyID = ""
+			state.AWS.SecretAccessKey = ""
+			state.AWS.SessionToken = ""
+		}
+
 		if err := u.stateStore.Set(state); err != nil {
 			return err
 		}
 		u.configProvider.SetConfig(aws.Config{
 			AccessKeyID:     config.AccessKeyID,
 			SecretAccessKey: config.SecretAccessKey,
+			SessionToken:    config.SessionToken,
+			Profile:         config.Profile,
+			AssumeRoleARN:   config.AssumeRoleARN,
+			MFASerial:       config.MFASerial,
 			Region:          config.Region,
 		})
 	} else if u.awsCredentialsNotPresent(config) {
@@ -142,6 +176,11 @@ func (u AWSUp) Execute(config AWSUpConfig, state storage.State) error {
 		return u.awsMissingCredentials(config)
 	}
 
+	state, err := selectNATType(state, config.NATType)
+	if err != nil {
+		return err
+	}
+
 	if config.NoDirector {
 		if !state.BOSH.IsEmpty() {
 			return errors.New(`Director already exists, you must re-create your environment to use "--no-director"`)
@@ -150,11 +189,24 @@ func (u AWSUp) Execute(config AWSUpConfig, state storage.State) error {
 		state.NoDirector = true
 	}
 
-	err := u.checkForFastFails(state, config)
+	err = u.checkForFastFails(state, config)
+	if err != nil {
+		return err
+	}
+
+	if config.IAMInstanceProfile {
+		state.AWS.IAMInstanceProfile = true
+	}
+
+	state, err = selectAvailabilityZones(u.availabilityZoneRetriever, state, config.AZs, config.AZCount)
 	if err != nil {
 		return err
 	}
 
+	if config.BOSHAZ != "" && len(state.AWS.AZs) > 0 && !containsString(state.AWS.AZs, config.BOSHAZ) {
+		return fmt.Errorf("--aws-bosh-az %s is not one of the selected availability zones: %s", config.BOSHAZ, strings.Join(state.AWS.AZs, ", "))
+	}
+
 	state, err = u.envIDManager.Sync(state, config.Name)
 	if err != nil {
 		return err
@@ -185,7 +237,7 @@ func (u AWSUp) Execute(config AWSUpConfig, state storage.State) error {
 		return err
 	}
 
-	availabilityZones, err := u.availabilityZoneRetriever.Retrieve(state.AWS.Region)
+	availabilityZones, err := state.AWS.AvailabilityZones(u.availabilityZoneRetriever)
 	if err != nil {
 		return err
 	}
@@ -218,7 +270,7 @@ func (u AWSUp) Execute(config AWSUpConfig, state storage.State) error {
 				return err
 			}
 		}
-		_, err = u.infrastructureManager.Create(state.KeyPair.Name, availabilityZones, state.Stack.Name, state.Stack.BOSHAZ, state.Stack.LBType, certificateARN, state.EnvID)
+		_, err = u.infrastructureManager.Create(state.KeyPair.Name, availabilityZones, state.Stack.Name, state.Stack.BOSHAZ, state.Stack.LBType, certificateARN, state.EnvID, state.DirectorAllowedCIDRs, state.LBAllowedCIDRs, state.AWS.NATType, state.Tags)
 		if err != nil {
 			return err
 		}
@@ -234,6 +286,16 @@ func (u AWSUp) Execute(config AWSUpConfig, state storage.State) error {
 		}
 		state.BOSH.UserOpsFile = string(opsFile)
 
+		state, err = readDirectorConfigs(state, directorConfigPaths{
+			runtimeConfig:       config.RuntimeConfigPath,
+			cpiConfig:           config.CPIConfigPath,
+			cloudConfigOpsFiles: config.CloudConfigOpsFilePaths,
+			vmTypeCatalog:       config.VMTypeCatalogPath,
+		})
+		if err != nil {
+			return err
+		}
+
 		state, err = u.boshManager.Create(state)
 		switch err.(type) {
 		case bosh.ManagerCreateError:
@@ -259,6 +321,9 @@ func (u AWSUp) Execute(config AWSUpConfig, state storage.State) error {
 			return err
 		}
 	}
+
+	warnExpiringCertificates(u.logger, state)
+
 	return nil
 }
 
@@ -268,6 +333,18 @@ func (u AWSUp) checkForFastFails(state storage.State, config AWSUpConfig) error
 		return err
 	}
 
+	if len(config.AZs) > 0 && config.AZCount != 0 {
+		return errors.New("--azs and --az-count cannot be used together")
+	}
+
+	if config.AZCount < 0 {
+		return errors.New("--az-count must be a positive number")
+	}
+
+	if config.IAMInstanceProfile && !config.Terraform && state.TFState == "" {
+		return errors.New("--aws-iam-instance-profile is only supported for environments created with terraform")
+	}
+
 	if state.Stack.Name != "" && state.Stack.BOSHAZ != config.BOSHAZ {
 		return errors.New("The --aws-bosh-az cannot be changed for existing environments.")
 	}
@@ -276,18 +353,20 @@ func (u AWSUp) checkForFastFails(state storage.State, config AWSUpConfig) error
 }
 
 func (AWSUp) awsCredentialsPresent(config AWSUpConfig) bool {
-	return config.AccessKeyID != "" && config.SecretAccessKey != "" && config.Region != ""
+	keysPresent := config.AccessKeyID != "" && config.SecretAccessKey != ""
+	return (keysPresent || usesSharedCredentials(config)) && config.Region != ""
 }
 
 func (AWSUp) awsCredentialsNotPresent(config AWSUpConfig) bool {
-	return config.AccessKeyID == "" && config.SecretAccessKey == "" && config.Region == ""
+	return config.AccessKeyID == "" && config.SecretAccessKey == "" && config.SessionToken == "" &&
+		config.Profile == "" && config.AssumeRoleARN == "" && config.MFASerial == "" && config.Region == ""
 }
 
 func (AWSUp) awsMissingCredentials(config AWSUpConfig) error {
 	switch {
-	case config.AccessKeyID == "":
+	case config.AccessKeyID == "" && !usesSharedCredentials(config):
 		return errors.New("AWS access key ID must be provided")
-	case config.SecretAccessKey == "":
+	case config.SecretAccessKey == "" && !usesSharedCredentials(config):
 		return errors.New("AWS secret access key must be provided")
 	case config.Region == "":
 		return errors.New("AWS region must be provided")
@@ -295,3 +374,107 @@ func (AWSUp) awsMissingCredentials(config AWSUpConfig) error {
 
 	return nil
 }
+
+// usesSharedCredentials reports whether credentials come from a profile or
+// an assumed role rather than from access keys alone.
+func usesSharedCredentials(config AWSUpConfig) bool {
+	return config.Profile != "" || config.AssumeRoleARN != ""
+}
+
+// selectNATType stores the nat type chosen with --aws-nat-type in the state.
+// Fresh environments default to nat gateways, while environments created
+// before the choice existed keep their nat instance.
+func selectNATType(state storage.State, natType string) (storage.State, error) {
+	switch natType {
+	case "gateway", "instance":
+		state.AWS.NATType = natType
+	case "":
+		if state.AWS.NATType != "" {
+			break
+		}
+
+		if state.Stack.Name != "" || state.TFState != "" {
+			state.AWS.NATType = "instance"
+		} else {
+			state.AWS.NATType = "gateway"
+		}
+	default:
+		return storage.State{}, fmt.Errorf("%q is an invalid nat type, supported values are: [gateway, instance]", natType)
+	}
+
+	return state, nil
+}
+
+// selectAvailabilityZones stores the zones chosen with --azs or --az-count
+// in the state. Environments without a choice keep using every zone in the
+// region. Existing environments can only add zones, since dropping or
+// reordering them would replace subnets that still have VMs attached.
+func selectAvailabilityZones(retriever availabilityZoneRetriever, state storage.State, azs []string, azCount int) (storage.State, error) {
+	if len(azs) == 0 && azCount == 0 {
+		return state, nil
+	}
+
+	regionAZs, err := retriever.Retrieve(state.AWS.Region)
+	if err != nil {
+		return storage.State{}, err
+	}
+
+	var selected []string
+	if azCount > 0 {
+		if azCount > len(regionAZs) {
+			return storage.State{}, fmt.Errorf("--az-count %d is greater than the %d availability zones in region %s", azCount, len(regionAZs), state.AWS.Region)
+		}
+
+		selected = regionAZs[:azCount]
+	} else {
+		for i, az := range azs {
+			if !containsString(regionAZs, az) {
+				return storage.State{}, fmt.Errorf("availability zone %s is not in region %s", az, state.AWS.Region)
+			}
+
+			if containsString(azs[:i], az) {
+				return storage.State{}, fmt.Errorf("availability zone %s is given more than once", az)
+			}
+		}
+
+		selected = azs
+	}
+
+	if state.Stack.Name != "" || state.TFState != "" {
+		current := state.AWS.AZs
+		if len(current) == 0 {
+			current = regionAZs
+		}
+
+		if !isPrefix(current, selected) {
+			return storage.State{}, fmt.Errorf("availability zones cannot be removed or reordered for existing environments, the current zones are %s", strings.Join(current, ", "))
+		}
+	}
+
+	state.AWS.AZs = selected
+	return state, nil
+}
+
+func isPrefix(prefix, values []string) bool {
+	if len(prefix) > len(values) {
+		return false
+	}
+
+	for i := range prefix {
+		if prefix[i] != values[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"io/ioutil"
 	"os"
+	"time"
 
 	"github.com/cloudfoundry/bosh-bootloader/aws"
 	"github.com/cloudfoundry/bosh-bootloader/aws/cloudformation"
@@ -13,6 +14,7 @@ import (
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/keypair"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/testhelpers"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -34,6 +36,7 @@ var _ = Describe("AWSUp", func() {
 			stateStore                 *fakes.StateStore
 			awsClientProvider          *fakes.AWSClientProvider
 			envIDManager               *fakes.EnvIDManager
+			logger                     *fakes.Logger
 		)
 
 		BeforeEach(func() {
@@ -98,10 +101,13 @@ var _ = Describe("AWSUp", func() {
 
 			brokenEnvironmentValidator = &fakes.BrokenEnvironmentValidator{}
 
+			logger = &fakes.Logger{}
+
 			command = commands.NewAWSUp(
 				credentialValidator, infrastructureManager, keyPairManager, boshManager,
 				availabilityZoneRetriever, certificateDescriber, cloudConfigManager,
 				stateStore, awsClientProvider, envIDManager, terraformManager, brokenEnvironmentValidator,
+				logger,
 			)
 		})
 
@@ -128,6 +134,95 @@ var _ = Describe("AWSUp", func() {
 			Expect(credentialValidator.ValidateCall.CallCount).To(Equal(0))
 		})
 
+		Context("when a profile or role is provided", func() {
+			It("retrieves a client that resolves the profile and assumes the role", func() {
+				err := command.Execute(commands.AWSUpConfig{
+					AccessKeyID:     "new-aws-access-key-id",
+					SecretAccessKey: "new-aws-secret-access-key",
+					SessionToken:    "new-aws-session-token",
+					Profile:         "some-profile",
+					AssumeRoleARN:   "some-role-arn",
+					MFASerial:       "some-mfa-serial",
+					Region:          "new-aws-region",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(awsClientProvider.SetConfigCall.Receives.Config).To(Equal(aws.Config{
+					AccessKeyID:     "new-aws-access-key-id",
+					SecretAccessKey: "new-aws-secret-access-key",
+					SessionToken:    "new-aws-session-token",
+					Profile:         "some-profile",
+					AssumeRoleARN:   "some-role-arn",
+					MFASerial:       "some-mfa-serial",
+					Region:          "new-aws-region",
+				}))
+				Expect(credentialValidator.ValidateCall.CallCount).To(Equal(0))
+			})
+
+			It("does not store the access keys given with a role", func() {
+				err := command.Execute(commands.AWSUpConfig{
+					AccessKeyID:     "new-aws-access-key-id",
+					SecretAccessKey: "new-aws-secret-access-key",
+					SessionToken:    "new-aws-session-token",
+					AssumeRoleARN:   "some-role-arn",
+					MFASerial:       "some-mfa-serial",
+					Region:          "new-aws-region",
+				}, storage.State{
+					AWS: storage.AWS{
+						AccessKeyID:     "old-aws-access-key-id",
+						SecretAccessKey: "old-aws-secret-access-key",
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(awsClientProvider.SetConfigCall.Receives.Config.AccessKeyID).To(Equal("new-aws-access-key-id"))
+				for _, call := range stateStore.SetCall.Receives {
+					Expect(call.State.AWS.AccessKeyID).To(BeEmpty())
+					Expect(call.State.AWS.SecretAccessKey).To(BeEmpty())
+					Expect(call.State.AWS.SessionToken).To(BeEmpty())
+				}
+				Expect(stateStore.SetCall.Receives[0].State.AWS).To(Equal(storage.AWS{
+					AssumeRoleARN: "some-role-arn",
+					MFASerial:     "some-mfa-serial",
+					Region:        "new-aws-region",
+				}))
+			})
+
+			It("does not store the access keys given with a profile", func() {
+				err := command.Execute(commands.AWSUpConfig{
+					AccessKeyID:     "new-aws-access-key-id",
+					SecretAccessKey: "new-aws-secret-access-key",
+					SessionToken:    "new-aws-session-token",
+					Profile:         "some-profile",
+					Region:          "new-aws-region",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(stateStore.SetCall.Receives[0].State.AWS).To(Equal(storage.AWS{
+					Profile: "some-profile",
+					Region:  "new-aws-region",
+				}))
+			})
+
+			It("does not require access keys", func() {
+				err := command.Execute(commands.AWSUpConfig{
+					Profile: "some-profile",
+					Region:  "new-aws-region",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(stateStore.SetCall.Receives[0].State.AWS).To(Equal(storage.AWS{
+					Profile: "some-profile",
+					Region:  "new-aws-region",
+				}))
+			})
+
+			It("returns an error when the region is missing", func() {
+				err := command.Execute(commands.AWSUpConfig{Profile: "some-profile"}, storage.State{})
+				Expect(err).To(MatchError("AWS region must be provided"))
+			})
+		})
+
 		It("calls the env id manager and saves the env id", func() {
 			err := command.Execute(commands.AWSUpConfig{
 				AccessKeyID:     "new-aws-access-key-id",
@@ -176,6 +271,7 @@ var _ = Describe("AWSUp", func() {
 					Region:          "some-aws-region",
 					SecretAccessKey: "some-secret-access-key",
 					AccessKeyID:     "some-access-key-id",
+					NATType:         "gateway",
 				},
 				EnvID: "bbl-lake-time-stamp",
 			}))
@@ -255,6 +351,7 @@ var _ = Describe("AWSUp", func() {
 						Region:          "some-aws-region",
 						SecretAccessKey: "some-secret-access-key",
 						AccessKeyID:     "some-access-key-id",
+						NATType:         "gateway",
 					},
 					EnvID: "bbl-lake-time-stamp",
 					KeyPair: storage.KeyPair{
@@ -474,6 +571,27 @@ var _ = Describe("AWSUp", func() {
 			Expect(boshManager.CreateCall.Receives.State).To(Equal(incomingState))
 		})
 
+		Context("when the director certificate expires soon", func() {
+			BeforeEach(func() {
+				boshManager.CreateCall.Returns.State.BOSH.DirectorSSLCertificate = testhelpers.BBL_CERT
+
+				commands.SetNow(func() time.Time {
+					return time.Date(2018, time.May, 1, 0, 0, 0, 0, time.UTC)
+				})
+			})
+
+			AfterEach(func() {
+				commands.ResetNow()
+			})
+
+			It("prints a warning", func() {
+				err := command.Execute(commands.AWSUpConfig{}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.Messages).To(ContainElement("warning: director certificate expires on 2018-05-26T22:13:41Z, run `bbl certs` for details"))
+			})
+		})
+
 		Context("when ops file are passed in via --ops-file flag", func() {
 			It("passes the ops file contents to the bosh manager", func() {
 				opsFile, err := ioutil.TempFile("", "ops-file")
@@ -498,6 +616,145 @@ var _ = Describe("AWSUp", func() {
 			})
 		})
 
+		Context("when a runtime config and cpi config are passed in", func() {
+			It("saves their contents to the state", func() {
+				runtimeConfig, err := ioutil.TempFile("", "runtime-config")
+				Expect(err).NotTo(HaveOccurred())
+				err = ioutil.WriteFile(runtimeConfig.Name(), []byte("some-runtime-config"), os.ModePerm)
+				Expect(err).NotTo(HaveOccurred())
+
+				cpiConfig, err := ioutil.TempFile("", "cpi-config")
+				Expect(err).NotTo(HaveOccurred())
+				err = ioutil.WriteFile(cpiConfig.Name(), []byte("some-cpi-config"), os.ModePerm)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = command.Execute(commands.AWSUpConfig{
+					AccessKeyID:       "some-aws-access-key-id",
+					SecretAccessKey:   "some-aws-secret-access-key",
+					Region:            "some-aws-region",
+					RuntimeConfigPath: runtimeConfig.Name(),
+					CPIConfigPath:     cpiConfig.Name(),
+				}, storage.State{
+					EnvID: "bbl-lake-time-stamp",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(boshManager.CreateCall.Receives.State.RuntimeConfig).To(Equal("some-runtime-config"))
+				Expect(boshManager.CreateCall.Receives.State.CPIConfig).To(Equal("some-cpi-config"))
+			})
+
+			It("returns an error when the runtime config cannot be read", func() {
+				err := command.Execute(commands.AWSUpConfig{
+					AccessKeyID:       "some-aws-access-key-id",
+					SecretAccessKey:   "some-aws-secret-access-key",
+					Region:            "some-aws-region",
+					RuntimeConfigPath: "/some/non-existent/runtime-config",
+				}, storage.State{
+					EnvID: "bbl-lake-time-stamp",
+				})
+				Expect(err).To(MatchError(ContainSubstring("error reading runtime-config contents")))
+			})
+		})
+
+		Context("when cloud config ops files are passed in", func() {
+			It("saves their contents to the state for the cloud config manager", func() {
+				opsFile, err := ioutil.TempFile("", "cloud-config-ops-file")
+				Expect(err).NotTo(HaveOccurred())
+				err = ioutil.WriteFile(opsFile.Name(), []byte("some-cloud-config-ops"), os.ModePerm)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = command.Execute(commands.AWSUpConfig{
+					AccessKeyID:             "some-aws-access-key-id",
+					SecretAccessKey:         "some-aws-secret-access-key",
+					Region:                  "some-aws-region",
+					CloudConfigOpsFilePaths: []string{opsFile.Name()},
+				}, storage.State{
+					EnvID: "bbl-lake-time-stamp",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(boshManager.CreateCall.Receives.State.CloudConfigOpsFiles).To(Equal([]string{"some-cloud-config-ops"}))
+				Expect(cloudConfigManager.UpdateCall.Receives.State.CloudConfigOpsFiles).To(Equal([]string{"some-cloud-config-ops"}))
+			})
+
+			It("keeps previously stored ops files when none are provided", func() {
+				err := command.Execute(commands.AWSUpConfig{}, storage.State{
+					EnvID: "bbl-lake-time-stamp",
+					AWS: storage.AWS{
+						AccessKeyID:     "some-aws-access-key-id",
+						SecretAccessKey: "some-aws-secret-access-key",
+						Region:          "some-aws-region",
+					},
+					CloudConfigOpsFiles: []string{"some-existing-ops"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(boshManager.CreateCall.Receives.State.CloudConfigOpsFiles).To(Equal([]string{"some-existing-ops"}))
+			})
+
+			It("returns an error when an ops file cannot be read", func() {
+				err := command.Execute(commands.AWSUpConfig{
+					AccessKeyID:             "some-aws-access-key-id",
+					SecretAccessKey:         "some-aws-secret-access-key",
+					Region:                  "some-aws-region",
+					CloudConfigOpsFilePaths: []string{"/some/non-existent/ops-file"},
+				}, storage.State{
+					EnvID: "bbl-lake-time-stamp",
+				})
+				Expect(err).To(MatchError(ContainSubstring("error reading cloud-config-ops-file contents")))
+			})
+		})
+
+		Context("when a vm type catalog is passed in", func() {
+			It("saves its contents to the state for the cloud config manager", func() {
+				catalogFile, err := ioutil.TempFile("", "vm-type-catalog")
+				Expect(err).NotTo(HaveOccurred())
+				err = ioutil.WriteFile(catalogFile.Name(), []byte("vm_types:\n- name: default\n  machine_type: m4.large\n"), os.ModePerm)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = command.Execute(commands.AWSUpConfig{
+					AccessKeyID:       "some-aws-access-key-id",
+					SecretAccessKey:   "some-aws-secret-access-key",
+					Region:            "us-east-1",
+					VMTypeCatalogPath: catalogFile.Name(),
+				}, storage.State{
+					EnvID: "bbl-lake-time-stamp",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(cloudConfigManager.UpdateCall.Receives.State.VMTypeCatalog).To(Equal("vm_types:\n- name: default\n  machine_type: m4.large\n"))
+			})
+
+			It("returns an error when the catalog is not valid for the region", func() {
+				catalogFile, err := ioutil.TempFile("", "vm-type-catalog")
+				Expect(err).NotTo(HaveOccurred())
+				err = ioutil.WriteFile(catalogFile.Name(), []byte("vm_types:\n- name: default\n  machine_type: m3.medium\n"), os.ModePerm)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = command.Execute(commands.AWSUpConfig{
+					AccessKeyID:       "some-aws-access-key-id",
+					SecretAccessKey:   "some-aws-secret-access-key",
+					Region:            "eu-west-2",
+					VMTypeCatalogPath: catalogFile.Name(),
+				}, storage.State{
+					EnvID: "bbl-lake-time-stamp",
+				})
+				Expect(err).To(MatchError(`invalid vm-type-catalog: vm_type "default" uses machine type "m3.medium" which is not available in eu-west-2`))
+			})
+
+			It("returns an error when the catalog cannot be read", func() {
+				err := command.Execute(commands.AWSUpConfig{
+					AccessKeyID:       "some-aws-access-key-id",
+					SecretAccessKey:   "some-aws-secret-access-key",
+					Region:            "us-east-1",
+					VMTypeCatalogPath: "/some/non-existent/vm-type-catalog",
+				}, storage.State{
+					EnvID: "bbl-lake-time-stamp",
+				})
+				Expect(err).To(MatchError(ContainSubstring("error reading vm-type-catalog contents")))
+			})
+		})
+
 		Context("when bosh az is provided via --aws-bosh-az flag", func() {
 			It("passes the bosh az to the infrastructure manager", func() {
 				err := command.Execute(commands.AWSUpConfig{
@@ -529,6 +786,137 @@ var _ = Describe("AWSUp", func() {
 			})
 		})
 
+		Context("when availability zones are chosen", func() {
+			BeforeEach(func() {
+				availabilityZoneRetriever.RetrieveCall.Returns.AZs = []string{"some-az-a", "some-az-b", "some-az-c"}
+			})
+
+			It("saves the zones passed with --azs", func() {
+				err := command.Execute(commands.AWSUpConfig{
+					AZs: []string{"some-az-a", "some-az-c"},
+				}, storage.State{
+					AWS: storage.AWS{Region: "some-aws-region"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(availabilityZoneRetriever.RetrieveCall.Receives.Region).To(Equal("some-aws-region"))
+				Expect(stateStore.SetCall.Receives[0].State.AWS.AZs).To(Equal([]string{"some-az-a", "some-az-c"}))
+			})
+
+			It("saves the first zones of the region with --az-count", func() {
+				err := command.Execute(commands.AWSUpConfig{
+					AZCount: 2,
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(stateStore.SetCall.Receives[0].State.AWS.AZs).To(Equal([]string{"some-az-a", "some-az-b"}))
+			})
+
+			It("creates the infrastructure in the zones from the state", func() {
+				keyPairManager.SyncCall.Returns.State.AWS.AZs = []string{"some-az-b"}
+
+				err := command.Execute(commands.AWSUpConfig{}, storage.State{
+					AWS: storage.AWS{AZs: []string{"some-az-b"}},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(availabilityZoneRetriever.RetrieveCall.Receives.Region).To(BeEmpty())
+				Expect(infrastructureManager.CreateCall.Receives.AZs).To(Equal([]string{"some-az-b"}))
+			})
+
+			Context("failure cases", func() {
+				It("returns an error when --azs and --az-count are both provided", func() {
+					err := command.Execute(commands.AWSUpConfig{
+						AZs:     []string{"some-az-a"},
+						AZCount: 1,
+					}, storage.State{})
+					Expect(err).To(MatchError("--azs and --az-count cannot be used together"))
+				})
+
+				It("returns an error when --az-count is negative", func() {
+					err := command.Execute(commands.AWSUpConfig{
+						AZCount: -1,
+					}, storage.State{})
+					Expect(err).To(MatchError("--az-count must be a positive number"))
+				})
+
+				It("returns an error when --az-count is greater than the number of zones", func() {
+					err := command.Execute(commands.AWSUpConfig{
+						AZCount: 4,
+					}, storage.State{
+						AWS: storage.AWS{Region: "some-aws-region"},
+					})
+					Expect(err).To(MatchError("--az-count 4 is greater than the 3 availability zones in region some-aws-region"))
+				})
+
+				It("returns an error when a zone is not in the region", func() {
+					err := command.Execute(commands.AWSUpConfig{
+						AZs: []string{"some-az-a", "some-az-d"},
+					}, storage.State{
+						AWS: storage.AWS{Region: "some-aws-region"},
+					})
+					Expect(err).To(MatchError("availability zone some-az-d is not in region some-aws-region"))
+					Expect(infrastructureManager.CreateCall.CallCount).To(Equal(0))
+				})
+
+				It("returns an error when a zone is given more than once", func() {
+					err := command.Execute(commands.AWSUpConfig{
+						AZs: []string{"some-az-a", "some-az-b", "some-az-a"},
+					}, storage.State{
+						AWS: storage.AWS{Region: "some-aws-region"},
+					})
+					Expect(err).To(MatchError("availability zone some-az-a is given more than once"))
+					Expect(infrastructureManager.CreateCall.CallCount).To(Equal(0))
+				})
+
+				It("returns an error when the bosh az is not one of the chosen zones", func() {
+					err := command.Execute(commands.AWSUpConfig{
+						AZs:    []string{"some-az-a", "some-az-b"},
+						BOSHAZ: "some-az-c",
+					}, storage.State{
+						AWS: storage.AWS{Region: "some-aws-region"},
+					})
+					Expect(err).To(MatchError("--aws-bosh-az some-az-c is not one of the selected availability zones: some-az-a, some-az-b"))
+					Expect(stateStore.SetCall.CallCount).To(Equal(0))
+				})
+
+				Context("when the environment already exists", func() {
+					It("adds zones after the current ones", func() {
+						err := command.Execute(commands.AWSUpConfig{
+							AZs: []string{"some-az-a", "some-az-b", "some-az-c"},
+						}, storage.State{
+							AWS:     storage.AWS{Region: "some-aws-region", AZs: []string{"some-az-a", "some-az-b"}},
+							TFState: "some-tf-state",
+						})
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(stateStore.SetCall.Receives[0].State.AWS.AZs).To(Equal([]string{"some-az-a", "some-az-b", "some-az-c"}))
+					})
+
+					It("returns an error when a zone is removed", func() {
+						err := command.Execute(commands.AWSUpConfig{
+							AZs: []string{"some-az-a"},
+						}, storage.State{
+							AWS:     storage.AWS{Region: "some-aws-region", AZs: []string{"some-az-a", "some-az-b"}},
+							TFState: "some-tf-state",
+						})
+						Expect(err).To(MatchError("availability zones cannot be removed or reordered for existing environments, the current zones are some-az-a, some-az-b"))
+						Expect(stateStore.SetCall.CallCount).To(Equal(0))
+					})
+
+					It("returns an error when an environment using every zone is shrunk", func() {
+						err := command.Execute(commands.AWSUpConfig{
+							AZCount: 2,
+						}, storage.State{
+							AWS:   storage.AWS{Region: "some-aws-region"},
+							Stack: storage.Stack{Name: "some-stack"},
+						})
+						Expect(err).To(MatchError("availability zones cannot be removed or reordered for existing environments, the current zones are some-az-a, some-az-b, some-az-c"))
+					})
+				})
+			})
+		})
+
 		Context("when there is an lb", func() {
 			It("attaches the lb certificate to the lb type in cloudformation", func() {
 				certificateDescriber.DescribeCall.Returns.Certificate = iam.Certificate{
@@ -558,6 +946,9 @@ var _ = Describe("AWSUp", func() {
 				Expect(cloudConfigManager.UpdateCall.Receives.State).To(Equal(storage.State{
 					EnvID: "bbl-lake-time-stamp",
 					IAAS:  "aws",
+					AWS: storage.AWS{
+						NATType: "gateway",
+					},
 					KeyPair: storage.KeyPair{
 						Name:       "keypair-bbl-lake-time-stamp",
 						PrivateKey: "some-private-key",
@@ -668,6 +1059,87 @@ var _ = Describe("AWSUp", func() {
 				})
 			})
 
+			Context("nat type", func() {
+				It("defaults to nat gateways for new environments", func() {
+					err := command.Execute(commands.AWSUpConfig{}, storage.State{})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(stateStore.SetCall.Receives[3].State.AWS.NATType).To(Equal("gateway"))
+					Expect(infrastructureManager.CreateCall.Receives.NATType).To(Equal("gateway"))
+				})
+
+				It("keeps the nat instance of environments created without a nat type", func() {
+					err := command.Execute(commands.AWSUpConfig{}, storage.State{
+						Stack: storage.Stack{Name: "some-stack-name"},
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(infrastructureManager.CreateCall.Receives.NATType).To(Equal("instance"))
+				})
+
+				It("keeps the nat type stored in the state", func() {
+					err := command.Execute(commands.AWSUpConfig{}, storage.State{
+						AWS: storage.AWS{NATType: "instance"},
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(infrastructureManager.CreateCall.Receives.NATType).To(Equal("instance"))
+				})
+
+				It("uses the nat type that is passed in", func() {
+					err := command.Execute(commands.AWSUpConfig{NATType: "instance"}, storage.State{})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(stateStore.SetCall.Receives[3].State.AWS.NATType).To(Equal("instance"))
+					Expect(infrastructureManager.CreateCall.Receives.NATType).To(Equal("instance"))
+				})
+
+				It("returns an error when the nat type is invalid", func() {
+					err := command.Execute(commands.AWSUpConfig{NATType: "some-nat-type"}, storage.State{})
+					Expect(err).To(MatchError(`"some-nat-type" is an invalid nat type, supported values are: [gateway, instance]`))
+					Expect(infrastructureManager.CreateCall.CallCount).To(Equal(0))
+				})
+			})
+
+			Context("iam instance profile", func() {
+				It("stores that the director uses an instance profile", func() {
+					err := command.Execute(commands.AWSUpConfig{
+						IAMInstanceProfile: true,
+						Terraform:          true,
+					}, storage.State{})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(terraformManager.ApplyCall.Receives.BBLState.AWS.IAMInstanceProfile).To(BeTrue())
+				})
+
+				It("keeps using the instance profile once it is stored", func() {
+					err := command.Execute(commands.AWSUpConfig{}, storage.State{
+						TFState: "some-tf-state",
+						AWS:     storage.AWS{IAMInstanceProfile: true},
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(terraformManager.ApplyCall.Receives.BBLState.AWS.IAMInstanceProfile).To(BeTrue())
+				})
+
+				It("returns an error when the environment uses cloudformation", func() {
+					err := command.Execute(commands.AWSUpConfig{IAMInstanceProfile: true}, storage.State{})
+					Expect(err).To(MatchError("--aws-iam-instance-profile is only supported for environments created with terraform"))
+					Expect(infrastructureManager.CreateCall.CallCount).To(Equal(0))
+				})
+			})
+
+			Context("tags", func() {
+				It("tags the stack with the tags in the state", func() {
+					err := command.Execute(commands.AWSUpConfig{}, storage.State{
+						Tags: map[string]string{"owner": "some-owner"},
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(infrastructureManager.CreateCall.Receives.Tags).To(Equal(map[string]string{"owner": "some-owner"}))
+				})
+			})
+
 			Context("aws credentials", func() {
 				Context("when the credentials do not exist", func() {
 					It("saves the credentials", func() {
@@ -683,6 +1155,7 @@ var _ = Describe("AWSUp", func() {
 							AccessKeyID:     "some-aws-access-key-id",
 							SecretAccessKey: "some-aws-secret-access-key",
 							Region:          "some-aws-region",
+							NATType:         "gateway",
 						}))
 					})
 				})
@@ -706,6 +1179,7 @@ var _ = Describe("AWSUp", func() {
 							AccessKeyID:     "new-aws-access-key-id",
 							SecretAccessKey: "new-aws-secret-access-key",
 							Region:          "new-aws-region",
+							NATType:         "gateway",
 						}))
 					})
 
@@ -724,6 +1198,7 @@ var _ = Describe("AWSUp", func() {
 							AccessKeyID:     "aws-access-key-id",
 							SecretAccessKey: "aws-secret-access-key",
 							Region:          "aws-region",
+							NATType:         "gateway",
 						}))
 					})
 				})
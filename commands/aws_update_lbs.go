@@ -51,10 +51,8 @@ func (c AWSUpdateLBs) Execute(config AWSCreateLBsConfig, state storage.State) er
 
 	if state.TFState != "" {
 		if config.Domain == "" {
-			config.Domain = state.LB.Domain
-		}
-		if config.LBType == "" {
-			config.LBType = state.LB.Type
+			lb, _ := state.GetLB(config.LBType)
+			config.Domain = lb.Domain
 		}
 
 		return c.awsCreateLBs.Execute(config, state)
@@ -86,7 +84,7 @@ func (c AWSUpdateLBs) Execute(config AWSCreateLBsConfig, state storage.State) er
 	// Temporary fix for IAM propagation. Terraform should have retry logic for this, so we should remove it once we start using terraform on AWS.
 	time.Sleep(9 * time.Second)
 
-	if err := c.updateStack(certificateName, state.KeyPair.Name, state.Stack.Name, state.Stack.BOSHAZ, state.Stack.LBType, state.AWS.Region, state.EnvID); err != nil {
+	if err := c.updateStack(certificateName, state.KeyPair.Name, state.Stack.Name, state.Stack.BOSHAZ, state.Stack.LBType, state.AWS, state.EnvID, state.DirectorAllowedCIDRs, state.LBAllowedCIDRs, state.Tags); err != nil {
 		return err
 	}
 
@@ -135,8 +133,8 @@ func (c AWSUpdateLBs) checkCertificateAndChain(certPath string, chainPath string
 	return true, nil
 }
 
-func (c AWSUpdateLBs) updateStack(certificateName string, keyPairName string, stackName string, boshAZ string, lbType string, awsRegion, envID string) error {
-	availabilityZones, err := c.availabilityZoneRetriever.Retrieve(awsRegion)
+func (c AWSUpdateLBs) updateStack(certificateName string, keyPairName string, stackName string, boshAZ string, lbType string, awsState storage.AWS, envID string, directorAllowedCIDRs, lbAllowedCIDRs []string, tags map[string]string) error {
+	availabilityZones, err := awsState.AvailabilityZones(c.availabilityZoneRetriever)
 	if err != nil {
 		return err
 	}
@@ -146,7 +144,7 @@ func (c AWSUpdateLBs) updateStack(certificateName string, keyPairName string, st
 		return err
 	}
 
-	_, err = c.infrastructureManager.Update(keyPairName, availabilityZones, stackName, boshAZ, lbType, certificate.ARN, envID)
+	_, err = c.infrastructureManager.Update(keyPairName, availabilityZones, stackName, boshAZ, lbType, certificate.ARN, envID, directorAllowedCIDRs, lbAllowedCIDRs, awsState.NATType, tags)
 	if err != nil {
 		return err
 	}
@@ -79,11 +79,13 @@ var _ = Describe("AWS Update LBs", func() {
 		incomingTerraformState = storage.State{
 			IAAS:    "aws",
 			TFState: "some-tf-state",
-			LB: storage.LB{
-				Type:   "cf",
-				Cert:   "some-cert",
-				Key:    "some-key",
-				Domain: "some-domain",
+			LBs: []storage.LB{
+				{
+					Type:   "cf",
+					Cert:   "some-cert",
+					Key:    "some-key",
+					Domain: "some-domain",
+				},
 			},
 		}
 
@@ -145,28 +147,6 @@ var _ = Describe("AWS Update LBs", func() {
 					Expect(awsCreateLBs.ExecuteCall.Receives.State).To(Equal(incomingTerraformState))
 				})
 			})
-
-			Context("when config does not contain lb type", func() {
-				It("passes lb type from the state", func() {
-					config := commands.AWSCreateLBsConfig{
-						CertPath: "some-cert-path",
-						KeyPath:  "some-key-path",
-						LBType:   "",
-						Domain:   "some-domain",
-					}
-					err := command.Execute(config, incomingTerraformState)
-
-					Expect(err).NotTo(HaveOccurred())
-					Expect(awsCreateLBs.ExecuteCall.CallCount).To(Equal(1))
-					Expect(awsCreateLBs.ExecuteCall.Receives.Config).To(Equal(commands.AWSCreateLBsConfig{
-						CertPath: "some-cert-path",
-						KeyPath:  "some-key-path",
-						LBType:   "cf",
-						Domain:   "some-domain",
-					}))
-					Expect(awsCreateLBs.ExecuteCall.Receives.State).To(Equal(incomingTerraformState))
-				})
-			})
 		})
 
 		It("creates the new certificate with private key", func() {
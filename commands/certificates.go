@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+// certificateExpiryWarningPeriod is how far ahead up and lbs look for
+// certificates that are about to expire.
+const certificateExpiryWarningPeriod = 30 * 24 * time.Hour
+
+var now func() time.Time = time.Now
+
+type managedCertificate struct {
+	Name     string    `json:"name"`
+	Subject  string    `json:"subject"`
+	SANs     []string  `json:"sans"`
+	Issuer   string    `json:"issuer"`
+	NotAfter time.Time `json:"not_after"`
+}
+
+func (c managedCertificate) expiresWithin(period time.Duration) bool {
+	return c.NotAfter.Before(now().Add(period))
+}
+
+// stateCertificates returns every certificate that bbl stores in the state:
+// the certificate and chain of each lb and the director certificate and CA.
+func stateCertificates(state storage.State) ([]managedCertificate, error) {
+	var certificates []managedCertificate
+
+	for _, lb := range state.LBs {
+		lbCertificates, err := parseCertificates(fmt.Sprintf("%s lb certificate", lb.Type), lb.Cert)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, lbCertificates...)
+
+		lbChain, err := parseCertificates(fmt.Sprintf("%s lb chain", lb.Type), lb.Chain)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, lbChain...)
+	}
+
+	directorCertificates, err := parseCertificates("director certificate", state.BOSH.DirectorSSLCertificate)
+	if err != nil {
+		return nil, err
+	}
+	certificates = append(certificates, directorCertificates...)
+
+	directorCA, err := parseCertificates("director ca", state.BOSH.DirectorSSLCA)
+	if err != nil {
+		return nil, err
+	}
+	certificates = append(certificates, directorCA...)
+
+	return certificates, nil
+}
+
+func parseCertificates(name, pemData string) ([]managedCertificate, error) {
+	var certificates []managedCertificate
+
+	rest := []byte(pemData)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %s", name, err)
+		}
+
+		sans := append([]string{}, certificate.DNSNames...)
+		for _, ip := range certificate.IPAddresses {
+			sans = append(sans, ip.String())
+		}
+
+		certificates = append(certificates, managedCertificate{
+			Name:     name,
+			Subject:  certificate.Subject.String(),
+			SANs:     sans,
+			Issuer:   certificate.Issuer.String(),
+			NotAfter: certificate.NotAfter.UTC(),
+		})
+	}
+
+	return certificates, nil
+}
+
+func describeExpiry(certificate managedCertificate) string {
+	if certificate.NotAfter.Before(now()) {
+		return fmt.Sprintf("expired on %s", certificate.NotAfter.Format(time.RFC3339))
+	}
+
+	return fmt.Sprintf("expires on %s", certificate.NotAfter.Format(time.RFC3339))
+}
+
+// warnExpiringCertificates prints a warning for each certificate in the state
+// that expires within the warning period. Certificates that cannot be parsed
+// are left for the certs command to report.
+func warnExpiringCertificates(logger logger, state storage.State) {
+	certificates, err := stateCertificates(state)
+	if err != nil {
+		return
+	}
+
+	for _, certificate := range certificates {
+		if certificate.expiresWithin(certificateExpiryWarningPeriod) {
+			logger.Println(fmt.Sprintf("warning: %s %s, run `bbl certs` for details", certificate.Name, describeExpiry(certificate)))
+		}
+	}
+}
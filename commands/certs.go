@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+const CertsCommand = "certs"
+
+type Certs struct {
+	logger               logger
+	stateValidator       stateValidator
+	certificateDescriber certificateDescriber
+}
+
+type certsConfig struct {
+	json     bool
+	warnDays int
+}
+
+func NewCerts(logger logger, stateValidator stateValidator, certificateDescriber certificateDescriber) Certs {
+	return Certs{
+		logger:               logger,
+		stateValidator:       stateValidator,
+		certificateDescriber: certificateDescriber,
+	}
+}
+
+func (c Certs) Execute(subcommandFlags []string, state storage.State) error {
+	err := c.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	config, err := c.parseFlags(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	certificates, err := stateCertificates(state)
+	if err != nil {
+		return err
+	}
+
+	if state.IAAS == "aws" && state.Stack.CertificateName != "" {
+		iamCertificates, err := c.iamCertificates(state.Stack.CertificateName)
+		if err != nil {
+			return err
+		}
+		certificates = append(certificates, iamCertificates...)
+	}
+
+	if len(certificates) == 0 {
+		return errors.New("no certificates found")
+	}
+
+	if config.json {
+		output, err := json.Marshal(certificates)
+		if err != nil {
+			// not tested
+			return err
+		}
+
+		c.logger.Println(string(output))
+	} else {
+		for _, certificate := range certificates {
+			c.logger.Printf("%s:\n", certificate.Name)
+			c.logger.Printf("  subject: %s\n", certificate.Subject)
+			c.logger.Printf("  sans:    %s\n", strings.Join(certificate.SANs, ", "))
+			c.logger.Printf("  issuer:  %s\n", certificate.Issuer)
+			c.logger.Printf("  %s\n", describeExpiry(certificate))
+		}
+	}
+
+	if config.warnDays > 0 {
+		var expiring int
+		for _, certificate := range certificates {
+			if certificate.expiresWithin(time.Duration(config.warnDays) * 24 * time.Hour) {
+				expiring++
+			}
+		}
+
+		if expiring > 0 {
+			return fmt.Errorf("%d certificate(s) expire within %d days", expiring, config.warnDays)
+		}
+	}
+
+	return nil
+}
+
+func (c Certs) iamCertificates(certificateName string) ([]managedCertificate, error) {
+	certificate, err := c.certificateDescriber.Describe(certificateName)
+	if err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("iam server certificate %s", certificateName)
+	body, err := parseCertificates(name, certificate.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := parseCertificates(fmt.Sprintf("%s chain", name), certificate.Chain)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(body, chain...), nil
+}
+
+func (Certs) parseFlags(subcommandFlags []string) (certsConfig, error) {
+	certsFlags := flags.New("certs")
+
+	config := certsConfig{}
+	certsFlags.Bool(&config.json, "json", "", false)
+	certsFlags.Int(&config.warnDays, "warn-days", 0)
+
+	if err := certsFlags.Parse(subcommandFlags); err != nil {
+		return config, err
+	}
+
+	if config.warnDays < 0 {
+		return config, errors.New("--warn-days must not be negative")
+	}
+
+	return config, nil
+}
@@ -0,0 +1,194 @@
+package commands_test
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws/iam"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/testhelpers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Certs", func() {
+	var (
+		command              commands.Certs
+		logger               *fakes.Logger
+		stateValidator       *fakes.StateValidator
+		certificateDescriber *fakes.CertificateDescriber
+		state                storage.State
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		stateValidator = &fakes.StateValidator{}
+		certificateDescriber = &fakes.CertificateDescriber{}
+
+		commands.SetNow(func() time.Time {
+			return time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC)
+		})
+
+		state = storage.State{
+			IAAS: "gcp",
+			LBs: []storage.LB{
+				{
+					Type:  "cf",
+					Cert:  testhelpers.BBL_CERT,
+					Chain: testhelpers.BBL_CHAIN,
+				},
+			},
+			BOSH: storage.BOSH{
+				DirectorSSLCertificate: testhelpers.ECDSA_BBL_CERT,
+			},
+		}
+
+		command = commands.NewCerts(logger, stateValidator, certificateDescriber)
+	})
+
+	AfterEach(func() {
+		commands.ResetNow()
+	})
+
+	Describe("Execute", func() {
+		It("prints every certificate in the state", func() {
+			err := command.Execute([]string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+			Expect(logger.PrintfCall.Messages).To(Equal([]string{
+				"cf lb certificate:\n",
+				"  subject: CN=bbl-intermediate\n",
+				"  sans:    \n",
+				"  issuer:  CN=bbl-ca\n",
+				"  expires on 2018-05-26T22:13:41Z\n",
+				"cf lb chain:\n",
+				"  subject: CN=bbl-ca\n",
+				"  sans:    \n",
+				"  issuer:  CN=bbl-ca\n",
+				"  expires on 2026-05-04T23:26:05Z\n",
+				"director certificate:\n",
+				"  subject: CN=bbl-ecdsa.example.com\n",
+				"  sans:    bbl-ecdsa.example.com\n",
+				"  issuer:  CN=bbl-ecdsa-intermediate\n",
+				"  expires on 2126-09-24T22:34:46Z\n",
+			}))
+		})
+
+		It("reports certificates that have already expired", func() {
+			commands.SetNow(func() time.Time {
+				return time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)
+			})
+
+			err := command.Execute([]string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintfCall.Messages).To(ContainElement("  expired on 2018-05-26T22:13:41Z\n"))
+		})
+
+		Context("when --json is provided", func() {
+			It("prints the certificates as json", func() {
+				err := command.Execute([]string{"--json"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.CallCount).To(Equal(1))
+
+				var certificates []map[string]interface{}
+				err = json.Unmarshal([]byte(logger.PrintlnCall.Receives.Message), &certificates)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(certificates).To(HaveLen(3))
+				Expect(certificates[2]).To(Equal(map[string]interface{}{
+					"name":      "director certificate",
+					"subject":   "CN=bbl-ecdsa.example.com",
+					"sans":      []interface{}{"bbl-ecdsa.example.com"},
+					"issuer":    "CN=bbl-ecdsa-intermediate",
+					"not_after": "2126-09-24T22:34:46Z",
+				}))
+			})
+		})
+
+		Context("when the environment was created on aws with an iam server certificate", func() {
+			BeforeEach(func() {
+				state = storage.State{
+					IAAS: "aws",
+					Stack: storage.Stack{
+						CertificateName: "some-certificate-name",
+					},
+				}
+
+				certificateDescriber.DescribeCall.Returns.Certificate = iam.Certificate{
+					Body:  testhelpers.BBL_CERT,
+					Chain: testhelpers.BBL_CHAIN,
+				}
+			})
+
+			It("describes the iam server certificate", func() {
+				err := command.Execute([]string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(certificateDescriber.DescribeCall.Receives.CertificateName).To(Equal("some-certificate-name"))
+				Expect(logger.PrintfCall.Messages).To(ContainElement("iam server certificate some-certificate-name:\n"))
+				Expect(logger.PrintfCall.Messages).To(ContainElement("iam server certificate some-certificate-name chain:\n"))
+			})
+
+			It("returns an error when the certificate describer fails", func() {
+				certificateDescriber.DescribeCall.Returns.Error = errors.New("failed to describe")
+
+				err := command.Execute([]string{}, state)
+				Expect(err).To(MatchError("failed to describe"))
+			})
+		})
+
+		Context("when --warn-days is provided", func() {
+			It("returns an error when certificates expire within the given number of days", func() {
+				commands.SetNow(func() time.Time {
+					return time.Date(2018, time.May, 1, 0, 0, 0, 0, time.UTC)
+				})
+
+				err := command.Execute([]string{"--warn-days", "30"}, state)
+				Expect(err).To(MatchError("1 certificate(s) expire within 30 days"))
+			})
+
+			It("does not return an error when no certificates expire within the given number of days", func() {
+				err := command.Execute([]string{"--warn-days", "30"}, state)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns an error when the value is negative", func() {
+				err := command.Execute([]string{"--warn-days", "-1"}, state)
+				Expect(err).To(MatchError("--warn-days must not be negative"))
+			})
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the state validator fails", func() {
+				stateValidator.ValidateCall.Returns.Error = errors.New("state validator failed")
+
+				err := command.Execute([]string{}, state)
+				Expect(err).To(MatchError("state validator failed"))
+			})
+
+			It("returns an error when there are no certificates", func() {
+				err := command.Execute([]string{}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError("no certificates found"))
+			})
+
+			It("returns an error when a certificate cannot be parsed", func() {
+				state.BOSH.DirectorSSLCA = "-----BEGIN CERTIFICATE-----\naGVsbG8=\n-----END CERTIFICATE-----\n"
+
+				err := command.Execute([]string{}, state)
+				Expect(err).To(MatchError(ContainSubstring("failed to parse director ca:")))
+			})
+
+			It("returns an error when flag parsing fails", func() {
+				err := command.Execute([]string{"--unknown-flag"}, state)
+				Expect(err).To(MatchError("flag provided but not defined: -unknown-flag"))
+			})
+		})
+	})
+})
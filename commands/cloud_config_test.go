@@ -46,7 +46,39 @@ var _ = Describe("CloudConfig", func() {
 		Expect(logger.PrintlnCall.Messages).To(ContainElement("some-cloud-config"))
 	})
 
+	Context("when --diff is provided", func() {
+		It("prints the changes to the director's cloud config", func() {
+			cloudConfigManager.DiffCall.Returns.Diff = "~ /compilation/workers: 5 -> 6"
+
+			err := cloudConfig.Execute([]string{"--diff"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cloudConfigManager.DiffCall.Receives.State).To(Equal(state))
+			Expect(cloudConfigManager.GenerateCall.CallCount).To(Equal(0))
+			Expect(logger.PrintlnCall.Messages).To(Equal([]string{"~ /compilation/workers: 5 -> 6"}))
+		})
+
+		It("reports when the director's cloud config is up to date", func() {
+			err := cloudConfig.Execute([]string{"--diff"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Messages).To(Equal([]string{"cloud config is up to date"}))
+		})
+
+		It("returns an error when the cloud config manager fails to diff", func() {
+			cloudConfigManager.DiffCall.Returns.Error = errors.New("failed to diff cloud configuration")
+
+			err := cloudConfig.Execute([]string{"--diff"}, state)
+			Expect(err).To(MatchError("failed to diff cloud configuration"))
+		})
+	})
+
 	Context("failure cases", func() {
+		It("returns an error when an unknown flag is provided", func() {
+			err := cloudConfig.Execute([]string{"--some-unknown-flag"}, state)
+			Expect(err).To(MatchError("flag provided but not defined: -some-unknown-flag"))
+		})
+
 		It("returns an error when the cloud config manager fails to generate", func() {
 			cloudConfigManager.GenerateCall.Returns.Error = errors.New("failed to generate cloud configuration")
 			err := cloudConfig.Execute([]string{}, state)
@@ -6,17 +6,34 @@ const (
   --iaas                     IAAS to deploy your BOSH Director onto. Valid options: "gcp", "aws" (Defaults to environment variable BBL_IAAS)
   [--name]                   Name to assign to your BOSH Director (optional, will be randomly generated)
   [--ops-file]               Path to BOSH ops file (optional)
+  [--runtime-config]         Path to BOSH runtime config to apply to the director (optional)
+  [--cpi-config]             Path to BOSH CPI config to apply to the director (optional)
+  [--cloud-config-ops-file]  Path to ops file applied to the generated cloud config, may be repeated (optional)
+  [--vm-type-catalog]        Path to YAML file overriding the vm_types in the generated cloud config, machine types must be in a built-in list rather than being checked against the IaaS (optional)
   [--no-director]            Skips creating BOSH environment
+  [--director-allowed-cidrs] Comma separated CIDRs allowed to reach the BOSH director (Defaults to environment variable BBL_DIRECTOR_ALLOWED_CIDRS, 0.0.0.0/0 when unset)
+  [--lb-allowed-cidrs]       Comma separated CIDRs allowed to reach the load balancers (Defaults to environment variable BBL_LB_ALLOWED_CIDRS, 0.0.0.0/0 when unset)
+  [--tag]                    Tag in the form key=value applied to every resource and BOSH VM, may be repeated (optional, replaces the tags of an existing environment, --tag "" removes them all)
 
   --aws-access-key-id        AWS Access Key ID to use (Defaults to environment variable BBL_AWS_ACCESS_KEY_ID)
   --aws-secret-access-key    AWS Secret Access Key to use (Defaults to environment variable BBL_AWS_SECRET_ACCESS_KEY)
+  [--aws-session-token]      AWS session token for temporary access keys (Defaults to environment variable BBL_AWS_SESSION_TOKEN)
+  [--aws-profile]            AWS shared config profile to use instead of access keys (Defaults to environment variable BBL_AWS_PROFILE)
+  [--aws-assume-role-arn]    ARN of an AWS role to assume with the access keys or profile (Defaults to environment variable BBL_AWS_ASSUME_ROLE_ARN)
+  [--aws-mfa-serial]         Serial number of the MFA device required by the assumed role, prompts for a token (Defaults to environment variable BBL_AWS_MFA_SERIAL)
   --aws-region               AWS region to use (Defaults to environment variable BBL_AWS_REGION)
   [--aws-bosh-az]            AWS availability zone to use for BOSH director (Defaults to environment variable BBL_AWS_BOSH_AZ)
+  [--azs]                    Comma separated AWS availability zones to create subnets in (Defaults to environment variable BBL_AWS_AZS, all zones in the region when unset)
+  [--az-count]               Number of AWS availability zones to create subnets in, taken in order from the region (Defaults to all zones in the region)
+  [--aws-nat-type]           NAT used by the internal subnets. Valid options: "gateway", "instance" (Defaults to environment variable BBL_AWS_NAT_TYPE, "gateway" for new environments)
+  [--aws-iam-instance-profile] Deploys the director with an IAM instance profile instead of IAM user access keys, requires --terraform (optional)
 
   --gcp-service-account-key  GCP Service Access Key to use (Defaults to environment variable BBL_GCP_SERVICE_ACCOUNT_KEY)
   --gcp-project-id           GCP Project ID to use (Defaults to environment variable BBL_GCP_PROJECT_ID)
   --gcp-zone                 GCP Zone to use (Defaults to environment variable BBL_GCP_ZONE)
-  --gcp-region               GCP Region to use (Defaults to environment variable BBL_GCP_REGION)`
+  --gcp-region               GCP Region to use (Defaults to environment variable BBL_GCP_REGION)
+  [--gcp-zones]              Comma separated GCP Zones for the cloud config AZs (Defaults to environment variable BBL_GCP_ZONES, discovered from the region when unset)
+  [--gcp-no-director-service-account] Deploys a new director with the service account key instead of a dedicated service account, which requires the roles/resourcemanager.projectIamAdmin role (optional)`
 
 	DestroyCommandUsage = `Tears down BOSH director infrastructure
 
@@ -25,26 +42,43 @@ const (
 
 	CreateLBsCommandUsage = `Attaches load balancer(s) with a certificate, key, and optional chain
 
-  --type              Load balancer(s) type. Valid options: "concourse" or "cf"
-  [--cert]            Path to SSL certificate (required when type="cf")
-  [--key]             Path to SSL certificate key (required when type="cf")
+  --type              Load balancer(s) type. Valid options: "concourse", "cf" or "custom"
+  [--cert]            Path to SSL certificate (required when type="cf" or the custom spec enables tls, unless --self-signed)
+  [--key]             Path to SSL certificate key (required when type="cf" or the custom spec enables tls, unless --self-signed)
   [--chain]           Path to SSL certificate chain (optional)
-  [--domain]          Creates a nameserver with a zone for given domain (supported when type="cf")
+  [--domain]          Creates a nameserver with a zone for given domain (supported when type="cf" or "concourse")
+  [--spec]            Path to a YAML file describing the load balancer (required when type="custom")
+  [--self-signed]     Generates a CA and a wildcard certificate for --domain instead of using --cert and --key (optional)
+  [--aws-lb-kind]     Creates classic, application or network load balancers. Valid options: "elb", "alb" or "nlb". An attached lb keeps its kind unless this is given (optional, aws only)
+  [--lb-allowed-cidrs] Comma separated CIDRs allowed to reach the load balancers (optional, defaults to 0.0.0.0/0, not supported with the gcp cf lb)
   [--skip-if-exists]  Skips creating load balancer(s) if it is already attached (optional)`
 
 	UpdateLBsCommandUsage = `Updates load balancer(s) with the supplied certificate, key, and optional chain
 
-  --cert               Path to SSL certificate
-  --key                Path to SSL certificate key
+  [--type]             Load balancer(s) type to update, required when more than one is attached
+  --cert               Path to SSL certificate (not needed to only change the domain of an lb without a certificate)
+  --key                Path to SSL certificate key (not needed to only change the domain of an lb without a certificate)
   [--chain]            Path to SSL certificate chain (optional)
-  [--domain]           Updates domain in the nameserver zone (supported when type="cf", optional)
+  [--domain]           Updates domain in the nameserver zone (supported when type="cf" or "concourse", optional)
   [--skip-if-missing]  Skips updating load balancer(s) if it is not attached (optional)`
 
 	DeleteLBsCommandUsage = `Deletes load balancer(s)
 
+  [--type]             Load balancer(s) type to delete (Defaults to all attached load balancers)
   [--skip-if-missing]  Skips deleting load balancer(s) if it is not attached (optional)`
 
-	LBsCommandUsage = "Prints attached load balancer(s)"
+	LBsCommandUsage = `Prints attached load balancer(s)
+
+  [--json]  Prints the load balancer(s) as JSON (optional)
+
+  The JSON lists every attached lb under "lbs", each with its "load_balancers".
+  It replaces the flat cf lb JSON of earlier versions (cf_router_lb, cf_ssh_proxy_lb,
+  env_dns_zone_name_servers, ...), which is no longer printed.`
+
+	CertsCommandUsage = `Prints certificates managed by bbl and their expiry dates
+
+  [--json]            Prints the certificates as JSON (optional)
+  [--warn-days]       Exits with an error if a certificate expires within the given number of days (optional)`
 
 	VersionCommandUsage = "Prints version"
 
@@ -56,6 +90,12 @@ const (
 
 	RotateCommandUsage = "Rotates the keypair for BOSH"
 
+	MigrateToTerraformCommandUsage = `Moves an AWS environment from CloudFormation to terraform and redeploys the director with a new IAM user access key
+
+  [--cert]   Path to the SSL certificate of the attached load balancer (required when a load balancer is attached)
+  [--key]    Path to the SSL certificate key of the attached load balancer (required when a load balancer is attached)
+  [--chain]  Path to the SSL certificate chain of the attached load balancer (optional)`
+
 	DirectorUsernameCommandUsage = "Prints BOSH director username"
 
 	DirectorPasswordCommandUsage = "Prints BOSH director password"
@@ -64,13 +104,21 @@ const (
 
 	DirectorCACertCommandUsage = "Prints BOSH director CA certificate"
 
+	LBCACommandUsage = "Prints the CA of self-signed load balancer certificates"
+
 	PrintEnvCommandUsage = "Prints required BOSH environment variables"
 
 	LatestErrorCommandUsage = "Prints the output from the latest call to terraform"
 
 	BOSHDeploymentVarsCommandUsage = "Prints required variables for BOSH deployment"
 
-	CloudConfigUsage = "Prints suggested cloud configuration for BOSH environment"
+	CloudConfigUsage = `Prints suggested cloud configuration for BOSH environment
+
+  [--diff]  Prints the changes between the director's current cloud config and the suggested one (optional)`
+
+	RuntimeConfigUsage = "Prints the runtime config applied to the BOSH director"
+
+	CPIConfigUsage = "Prints the CPI config applied to the BOSH director"
 )
 
 func (Up) Usage() string { return UpCommandUsage }
@@ -85,6 +133,8 @@ func (DeleteLBs) Usage() string { return DeleteLBsCommandUsage }
 
 func (LBs) Usage() string { return LBsCommandUsage }
 
+func (Certs) Usage() string { return CertsCommandUsage }
+
 func (Version) Usage() string { return VersionCommandUsage }
 
 func (Usage) Usage() string { return UsageCommandUsage }
@@ -95,10 +145,16 @@ func (LatestError) Usage() string { return LatestErrorCommandUsage }
 
 func (CloudConfig) Usage() string { return CloudConfigUsage }
 
+func (RuntimeConfig) Usage() string { return RuntimeConfigUsage }
+
+func (CPIConfig) Usage() string { return CPIConfigUsage }
+
 func (BOSHDeploymentVars) Usage() string { return BOSHDeploymentVarsCommandUsage }
 
 func (Rotate) Usage() string { return RotateCommandUsage }
 
+func (MigrateToTerraform) Usage() string { return MigrateToTerraformCommandUsage }
+
 func (s StateQuery) Usage() string {
 	switch s.propertyName {
 	case EnvIDPropertyName:
@@ -113,6 +169,8 @@ func (s StateQuery) Usage() string {
 		return DirectorAddressCommandUsage
 	case DirectorCACertPropertyName:
 		return DirectorCACertCommandUsage
+	case LBCAPropertyName:
+		return LBCACommandUsage
 	}
 	return ""
 }
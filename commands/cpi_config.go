@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+const (
+	CPIConfigCommand = "cpi-config"
+)
+
+type CPIConfig struct {
+	logger         logger
+	stateValidator stateValidator
+}
+
+func NewCPIConfig(logger logger, stateValidator stateValidator) CPIConfig {
+	return CPIConfig{
+		logger:         logger,
+		stateValidator: stateValidator,
+	}
+}
+
+func (c CPIConfig) Execute(args []string, state storage.State) error {
+	err := c.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	if state.CPIConfig == "" {
+		return errors.New("no cpi config has been registered for this bbl environment, use bbl up --cpi-config to register one")
+	}
+
+	c.logger.Println(state.CPIConfig)
+	return nil
+}
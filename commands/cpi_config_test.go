@@ -0,0 +1,48 @@
+package commands_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CPIConfig", func() {
+	var (
+		logger         *fakes.Logger
+		stateValidator *fakes.StateValidator
+		cpiConfig      commands.CPIConfig
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		stateValidator = &fakes.StateValidator{}
+
+		cpiConfig = commands.NewCPIConfig(logger, stateValidator)
+	})
+
+	It("prints the registered cpi config", func() {
+		err := cpiConfig.Execute([]string{}, storage.State{
+			CPIConfig: "some-cpi-config",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+		Expect(logger.PrintlnCall.Messages).To(ContainElement("some-cpi-config"))
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when no cpi config has been registered", func() {
+			err := cpiConfig.Execute([]string{}, storage.State{})
+			Expect(err).To(MatchError("no cpi config has been registered for this bbl environment, use bbl up --cpi-config to register one"))
+		})
+
+		It("returns an error when the state validator fails", func() {
+			stateValidator.ValidateCall.Returns.Error = errors.New("failed to validate state")
+			err := cpiConfig.Execute([]string{}, storage.State{})
+			Expect(err).To(MatchError("failed to validate state"))
+		})
+	})
+})
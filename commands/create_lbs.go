@@ -1,17 +1,28 @@
 package commands
 
 import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
 	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/ssl"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
-const CreateLBsCommand = "create-lbs"
+const (
+	CreateLBsCommand = "create-lbs"
+
+	lbCACommonName = "BOSH Bootloader LB CA"
+)
 
 type CreateLBs struct {
-	awsCreateLBs   awsCreateLBs
-	gcpCreateLBs   gcpCreateLBs
-	stateValidator stateValidator
-	boshManager    boshManager
+	awsCreateLBs     awsCreateLBs
+	gcpCreateLBs     gcpCreateLBs
+	stateValidator   stateValidator
+	boshManager      boshManager
+	keyPairGenerator keyPairGenerator
 }
 
 type lbConfig struct {
@@ -20,7 +31,11 @@ type lbConfig struct {
 	keyPath      string
 	chainPath    string
 	domain       string
+	specPath     string
 	skipIfExists bool
+	selfSigned   bool
+	awsLBKind    string
+	allowedCIDRs string
 }
 
 type gcpCreateLBs interface {
@@ -31,12 +46,17 @@ type awsCreateLBs interface {
 	Execute(AWSCreateLBsConfig, storage.State) error
 }
 
-func NewCreateLBs(awsCreateLBs awsCreateLBs, gcpCreateLBs gcpCreateLBs, stateValidator stateValidator, boshManager boshManager) CreateLBs {
+type keyPairGenerator interface {
+	GenerateWildcard(caCommonName, domain string) (ssl.KeyPair, error)
+}
+
+func NewCreateLBs(awsCreateLBs awsCreateLBs, gcpCreateLBs gcpCreateLBs, stateValidator stateValidator, boshManager boshManager, keyPairGenerator keyPairGenerator) CreateLBs {
 	return CreateLBs{
-		awsCreateLBs:   awsCreateLBs,
-		gcpCreateLBs:   gcpCreateLBs,
-		stateValidator: stateValidator,
-		boshManager:    boshManager,
+		awsCreateLBs:     awsCreateLBs,
+		gcpCreateLBs:     gcpCreateLBs,
+		stateValidator:   stateValidator,
+		boshManager:      boshManager,
+		keyPairGenerator: keyPairGenerator,
 	}
 }
 
@@ -57,14 +77,38 @@ func (c CreateLBs) Execute(args []string, state storage.State) error {
 		return err
 	}
 
+	state, err = setAllowedCIDRs(state, "", config.allowedCIDRs)
+	if err != nil {
+		return err
+	}
+
+	var ca string
+	if config.selfSigned {
+		keyPair, certDir, err := c.generateSelfSignedKeyPair(config)
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(certDir)
+
+		config.certPath = filepath.Join(certDir, "cert.pem")
+		config.keyPath = filepath.Join(certDir, "key.pem")
+		ca = string(keyPair.CA)
+	}
+
 	switch state.IAAS {
 	case "gcp":
+		if config.awsLBKind != "" {
+			return errors.New("--aws-lb-kind is only supported on aws")
+		}
+
 		if err := c.gcpCreateLBs.Execute(GCPCreateLBsConfig{
 			LBType:       config.lbType,
 			CertPath:     config.certPath,
 			KeyPath:      config.keyPath,
 			Domain:       config.domain,
+			SpecPath:     config.specPath,
 			SkipIfExists: config.skipIfExists,
+			CA:           ca,
 		}, state); err != nil {
 			return err
 		}
@@ -75,7 +119,10 @@ func (c CreateLBs) Execute(args []string, state storage.State) error {
 			KeyPath:      config.keyPath,
 			ChainPath:    config.chainPath,
 			Domain:       config.domain,
+			SpecPath:     config.specPath,
 			SkipIfExists: config.skipIfExists,
+			CA:           ca,
+			LBKind:       config.awsLBKind,
 		}, state); err != nil {
 			return err
 		}
@@ -93,7 +140,11 @@ func (CreateLBs) parseFlags(subcommandFlags []string) (lbConfig, error) {
 	lbFlags.String(&config.keyPath, "key", "")
 	lbFlags.String(&config.chainPath, "chain", "")
 	lbFlags.String(&config.domain, "domain", "")
+	lbFlags.String(&config.specPath, "spec", "")
 	lbFlags.Bool(&config.skipIfExists, "skip-if-exists", "", false)
+	lbFlags.Bool(&config.selfSigned, "self-signed", "", false)
+	lbFlags.String(&config.awsLBKind, "aws-lb-kind", "")
+	lbFlags.String(&config.allowedCIDRs, "lb-allowed-cidrs", "")
 
 	if err := lbFlags.Parse(subcommandFlags); err != nil {
 		return config, err
@@ -101,3 +152,38 @@ func (CreateLBs) parseFlags(subcommandFlags []string) (lbConfig, error) {
 
 	return config, nil
 }
+
+// generateSelfSignedKeyPair creates a CA and a wildcard certificate for the
+// lb domain and writes the certificate and key to a temporary directory so
+// they can be read like user provided --cert and --key files.
+func (c CreateLBs) generateSelfSignedKeyPair(config lbConfig) (ssl.KeyPair, string, error) {
+	if config.certPath != "" || config.keyPath != "" || config.chainPath != "" {
+		return ssl.KeyPair{}, "", errors.New("--cert, --key and --chain cannot be used with --self-signed")
+	}
+
+	if config.domain == "" {
+		return ssl.KeyPair{}, "", errors.New("--domain is required when --self-signed is provided")
+	}
+
+	keyPair, err := c.keyPairGenerator.GenerateWildcard(lbCACommonName, config.domain)
+	if err != nil {
+		return ssl.KeyPair{}, "", err
+	}
+
+	certDir, err := ioutil.TempDir("", "bbl-lb-cert")
+	if err != nil {
+		return ssl.KeyPair{}, "", err
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(certDir, "cert.pem"), keyPair.Certificate, 0600); err != nil {
+		os.RemoveAll(certDir)
+		return ssl.KeyPair{}, "", err
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(certDir, "key.pem"), keyPair.PrivateKey, 0600); err != nil {
+		os.RemoveAll(certDir)
+		return ssl.KeyPair{}, "", err
+	}
+
+	return keyPair, certDir, nil
+}
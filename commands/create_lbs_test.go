@@ -2,9 +2,12 @@ package commands_test
 
 import (
 	"errors"
+	"io/ioutil"
+	"os"
 
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/ssl"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 
 	. "github.com/onsi/ginkgo"
@@ -13,11 +16,12 @@ import (
 
 var _ = Describe("create-lbs", func() {
 	var (
-		command        commands.CreateLBs
-		awsCreateLBs   *fakes.AWSCreateLBs
-		gcpCreateLBs   *fakes.GCPCreateLBs
-		stateValidator *fakes.StateValidator
-		boshManager    *fakes.BOSHManager
+		command          commands.CreateLBs
+		awsCreateLBs     *fakes.AWSCreateLBs
+		gcpCreateLBs     *fakes.GCPCreateLBs
+		stateValidator   *fakes.StateValidator
+		boshManager      *fakes.BOSHManager
+		keyPairGenerator *fakes.KeyPairGenerator
 	)
 
 	BeforeEach(func() {
@@ -26,8 +30,9 @@ var _ = Describe("create-lbs", func() {
 		stateValidator = &fakes.StateValidator{}
 		boshManager = &fakes.BOSHManager{}
 		boshManager.VersionCall.Returns.Version = "2.0.0"
+		keyPairGenerator = &fakes.KeyPairGenerator{}
 
-		command = commands.NewCreateLBs(awsCreateLBs, gcpCreateLBs, stateValidator, boshManager)
+		command = commands.NewCreateLBs(awsCreateLBs, gcpCreateLBs, stateValidator, boshManager, keyPairGenerator)
 	})
 
 	Describe("Execute", func() {
@@ -114,6 +119,173 @@ var _ = Describe("create-lbs", func() {
 			}))
 		})
 
+		It("passes the custom lb spec to the iaas specific command", func() {
+			err := command.Execute([]string{
+				"--type", "custom",
+				"--spec", "my-spec.yml",
+			}, storage.State{
+				IAAS: "gcp",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gcpCreateLBs.ExecuteCall.Receives.Config).Should(Equal(commands.GCPCreateLBsConfig{
+				LBType:   "custom",
+				SpecPath: "my-spec.yml",
+			}))
+		})
+
+		Context("when --aws-lb-kind is provided", func() {
+			It("passes the lb kind to the aws create lbs command", func() {
+				err := command.Execute([]string{
+					"--type", "cf",
+					"--cert", "my-cert",
+					"--key", "my-key",
+					"--aws-lb-kind", "alb",
+				}, storage.State{
+					IAAS: "aws",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(awsCreateLBs.ExecuteCall.Receives.Config).Should(Equal(commands.AWSCreateLBsConfig{
+					LBType:   "cf",
+					CertPath: "my-cert",
+					KeyPath:  "my-key",
+					LBKind:   "alb",
+				}))
+			})
+
+			It("returns an error on gcp", func() {
+				err := command.Execute([]string{
+					"--type", "cf",
+					"--cert", "my-cert",
+					"--key", "my-key",
+					"--aws-lb-kind", "alb",
+				}, storage.State{
+					IAAS: "gcp",
+				})
+				Expect(err).To(MatchError("--aws-lb-kind is only supported on aws"))
+				Expect(gcpCreateLBs.ExecuteCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when --lb-allowed-cidrs is provided", func() {
+			It("stores the allowed cidrs in the state passed to the iaas specific command", func() {
+				err := command.Execute([]string{
+					"--type", "concourse",
+					"--lb-allowed-cidrs", "10.0.0.0/8,172.16.0.0/12",
+				}, storage.State{
+					IAAS: "gcp",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(gcpCreateLBs.ExecuteCall.Receives.State.LBAllowedCIDRs).To(Equal([]string{"10.0.0.0/8", "172.16.0.0/12"}))
+			})
+
+			It("returns an error when a cidr is invalid", func() {
+				err := command.Execute([]string{
+					"--type", "concourse",
+					"--lb-allowed-cidrs", "10.0.0.0",
+				}, storage.State{
+					IAAS: "aws",
+				})
+				Expect(err).To(MatchError(`--lb-allowed-cidrs contains an invalid cidr "10.0.0.0"`))
+				Expect(awsCreateLBs.ExecuteCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when --self-signed is provided", func() {
+			BeforeEach(func() {
+				keyPairGenerator.GenerateWildcardCall.Returns.KeyPair = ssl.KeyPair{
+					CA:          []byte("some-ca"),
+					Certificate: []byte("some-certificate"),
+					PrivateKey:  []byte("some-private-key"),
+				}
+			})
+
+			It("generates a wildcard certificate for the domain and passes it on with its CA", func() {
+				var certificate, privateKey []byte
+				gcpCreateLBs.ExecuteCall.Stub = func(config commands.GCPCreateLBsConfig, state storage.State) error {
+					var err error
+					certificate, err = ioutil.ReadFile(config.CertPath)
+					Expect(err).NotTo(HaveOccurred())
+					privateKey, err = ioutil.ReadFile(config.KeyPath)
+					Expect(err).NotTo(HaveOccurred())
+					return nil
+				}
+
+				err := command.Execute([]string{
+					"--type", "cf",
+					"--domain", "some-domain.com",
+					"--self-signed",
+				}, storage.State{
+					IAAS: "gcp",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(keyPairGenerator.GenerateWildcardCall.Receives.CACommonName).To(Equal("BOSH Bootloader LB CA"))
+				Expect(keyPairGenerator.GenerateWildcardCall.Receives.Domain).To(Equal("some-domain.com"))
+
+				Expect(certificate).To(Equal([]byte("some-certificate")))
+				Expect(privateKey).To(Equal([]byte("some-private-key")))
+				Expect(gcpCreateLBs.ExecuteCall.Receives.Config.Domain).To(Equal("some-domain.com"))
+				Expect(gcpCreateLBs.ExecuteCall.Receives.Config.CA).To(Equal("some-ca"))
+			})
+
+			It("removes the generated certificate and key files afterwards", func() {
+				err := command.Execute([]string{
+					"--type", "concourse",
+					"--domain", "some-domain.com",
+					"--self-signed",
+				}, storage.State{
+					IAAS: "aws",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(awsCreateLBs.ExecuteCall.Receives.Config.CA).To(Equal("some-ca"))
+
+				_, err = os.Stat(awsCreateLBs.ExecuteCall.Receives.Config.CertPath)
+				Expect(os.IsNotExist(err)).To(BeTrue())
+				_, err = os.Stat(awsCreateLBs.ExecuteCall.Receives.Config.KeyPath)
+				Expect(os.IsNotExist(err)).To(BeTrue())
+			})
+
+			It("returns an error when a domain is not provided", func() {
+				err := command.Execute([]string{
+					"--type", "cf",
+					"--self-signed",
+				}, storage.State{
+					IAAS: "gcp",
+				})
+				Expect(err).To(MatchError("--domain is required when --self-signed is provided"))
+				Expect(gcpCreateLBs.ExecuteCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when a cert or key is also provided", func() {
+				err := command.Execute([]string{
+					"--type", "cf",
+					"--cert", "my-cert",
+					"--domain", "some-domain.com",
+					"--self-signed",
+				}, storage.State{
+					IAAS: "gcp",
+				})
+				Expect(err).To(MatchError("--cert, --key and --chain cannot be used with --self-signed"))
+				Expect(keyPairGenerator.GenerateWildcardCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the certificate cannot be generated", func() {
+				keyPairGenerator.GenerateWildcardCall.Returns.Error = errors.New("failed to generate certificate")
+
+				err := command.Execute([]string{
+					"--type", "cf",
+					"--domain", "some-domain.com",
+					"--self-signed",
+				}, storage.State{
+					IAAS: "gcp",
+				})
+				Expect(err).To(MatchError("failed to generate certificate"))
+				Expect(gcpCreateLBs.ExecuteCall.CallCount).To(Equal(0))
+			})
+		})
+
 		Context("failure cases", func() {
 			It("returns an error when state validator fails", func() {
 				stateValidator.ValidateCall.Returns.Error = errors.New("state validator failed")
@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+const customLBType = "custom"
+
+// Custom lb names end up in ELB names, which are limited to 32 characters
+// including the environment prefix.
+var customLBNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9]{0,9}$`)
+
+func loadCustomLBSpec(specPath string) (storage.CustomLBSpec, error) {
+	if specPath == "" {
+		return storage.CustomLBSpec{}, errors.New("--spec is required when type=\"custom\"")
+	}
+
+	contents, err := ioutil.ReadFile(specPath)
+	if err != nil {
+		return storage.CustomLBSpec{}, err
+	}
+
+	var spec storage.CustomLBSpec
+	if err := yaml.Unmarshal(contents, &spec); err != nil {
+		return storage.CustomLBSpec{}, fmt.Errorf("failed to parse custom lb spec: %s", err)
+	}
+
+	if err := validateCustomLBSpec(spec); err != nil {
+		return storage.CustomLBSpec{}, err
+	}
+
+	return withCustomLBSpecDefaults(spec), nil
+}
+
+func validateCustomLBSpec(spec storage.CustomLBSpec) error {
+	if !customLBNameRegexp.MatchString(spec.Name) {
+		return fmt.Errorf("custom lb name %q must be 1 to 10 lowercase letters or digits, starting with a letter", spec.Name)
+	}
+
+	switch spec.Name {
+	case "cf", "concourse", customLBType:
+		return fmt.Errorf("custom lb name %q is reserved", spec.Name)
+	}
+
+	if len(spec.Listeners) == 0 {
+		return fmt.Errorf("custom lb %q must have at least one listener", spec.Name)
+	}
+
+	ports := map[int]bool{}
+	for _, listener := range spec.Listeners {
+		if listener.Protocol != "tcp" && listener.Protocol != "http" {
+			return fmt.Errorf("custom lb listener protocol %q is not supported, valid protocols are: tcp and http", listener.Protocol)
+		}
+
+		if !validPort(listener.Port) {
+			return fmt.Errorf("custom lb listener port %d is out of range", listener.Port)
+		}
+
+		if listener.TargetPort != 0 && !validPort(listener.TargetPort) {
+			return fmt.Errorf("custom lb listener target port %d is out of range", listener.TargetPort)
+		}
+
+		if ports[listener.Port] {
+			return fmt.Errorf("custom lb listener port %d is used more than once", listener.Port)
+		}
+		ports[listener.Port] = true
+	}
+
+	switch spec.HealthCheck.Protocol {
+	case "", "tcp", "http", "https":
+	default:
+		return fmt.Errorf("custom lb health check protocol %q is not supported, valid protocols are: tcp, http and https", spec.HealthCheck.Protocol)
+	}
+
+	if spec.HealthCheck.Port != 0 && !validPort(spec.HealthCheck.Port) {
+		return fmt.Errorf("custom lb health check port %d is out of range", spec.HealthCheck.Port)
+	}
+
+	return nil
+}
+
+func withCustomLBSpecDefaults(spec storage.CustomLBSpec) storage.CustomLBSpec {
+	listeners := []storage.CustomLBListener{}
+	for _, listener := range spec.Listeners {
+		if listener.TargetPort == 0 {
+			listener.TargetPort = listener.Port
+		}
+		listeners = append(listeners, listener)
+	}
+	spec.Listeners = listeners
+
+	if spec.HealthCheck.Protocol == "" {
+		spec.HealthCheck.Protocol = "tcp"
+	}
+
+	if spec.HealthCheck.Port == 0 {
+		spec.HealthCheck.Port = spec.Listeners[0].TargetPort
+	}
+
+	if spec.HealthCheck.Protocol != "tcp" && spec.HealthCheck.Path == "" {
+		spec.HealthCheck.Path = "/"
+	}
+
+	return spec
+}
+
+func validPort(port int) bool {
+	return port > 0 && port <= 65535
+}
+
+// customLBSpecFor returns the spec of the custom lb that a create or update
+// of lbType targets, or nil for the built-in lb types. Creating a "custom"
+// lb loads the spec from specPath, while updating an attached custom lb
+// reuses the spec stored in the state.
+func customLBSpecFor(lbType, specPath string, state storage.State) (*storage.CustomLBSpec, error) {
+	if lbType == customLBType {
+		spec, err := loadCustomLBSpec(specPath)
+		if err != nil {
+			return nil, err
+		}
+		return &spec, nil
+	}
+
+	if lb, ok := state.GetLB(lbType); ok && lb.IsCustom() {
+		return lb.Spec, nil
+	}
+
+	return nil, nil
+}
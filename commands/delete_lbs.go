@@ -16,11 +16,11 @@ type DeleteLBs struct {
 }
 
 type gcpDeleteLBs interface {
-	Execute(state storage.State) error
+	Execute(lbType string, state storage.State) error
 }
 
 type awsDeleteLBs interface {
-	Execute(state storage.State) error
+	Execute(lbType string, state storage.State) error
 }
 
 func NewDeleteLBs(gcpDeleteLBs gcpDeleteLBs, awsDeleteLBs awsDeleteLBs,
@@ -52,16 +52,20 @@ func (d DeleteLBs) Execute(subcommandFlags []string, state storage.State) error
 		}
 	}
 
-	if config.skipIfMissing && !lbExists(state.Stack.LBType) && !lbExists(state.LB.Type) {
-		d.logger.Println("no lb type exists, skipping...")
+	if config.skipIfMissing && !d.lbAttached(config.lbType, state) {
+		if config.lbType != "" {
+			d.logger.Println(fmt.Sprintf("lb type %q does not exist, skipping...", config.lbType))
+		} else {
+			d.logger.Println("no lb type exists, skipping...")
+		}
 		return nil
 	}
 
 	switch state.IAAS {
 	case "gcp":
-		return d.gcpDeleteLBs.Execute(state)
+		return d.gcpDeleteLBs.Execute(config.lbType, state)
 	case "aws":
-		return d.awsDeleteLBs.Execute(state)
+		return d.awsDeleteLBs.Execute(config.lbType, state)
 	default:
 		return fmt.Errorf("%q is an invalid iaas type in state, supported iaas types are: [gcp, aws]", state.IAAS)
 	}
@@ -73,6 +77,7 @@ func (DeleteLBs) parseFlags(subcommandFlags []string) (deleteLBsConfig, error) {
 	lbFlags := flags.New("delete-lbs")
 
 	config := deleteLBsConfig{}
+	lbFlags.String(&config.lbType, "type", "")
 	lbFlags.Bool(&config.skipIfMissing, "skip-if-missing", "", false)
 
 	err := lbFlags.Parse(subcommandFlags)
@@ -82,3 +87,31 @@ func (DeleteLBs) parseFlags(subcommandFlags []string) (deleteLBsConfig, error) {
 
 	return config, nil
 }
+
+func (DeleteLBs) lbAttached(lbType string, state storage.State) bool {
+	if lbType == "" {
+		return lbExists(state.Stack.LBType) || len(state.LBs) > 0
+	}
+
+	if _, ok := state.GetLB(lbType); ok {
+		return true
+	}
+
+	return state.Stack.LBType == lbType
+}
+
+// removeLBs removes the lb of the given type from the state, or every lb
+// when no type is given. It reports whether anything was removed.
+func removeLBs(state storage.State, lbType string) (storage.State, bool) {
+	if lbType == "" {
+		found := len(state.LBs) > 0
+		state.LBs = nil
+		return state, found
+	}
+
+	if _, ok := state.GetLB(lbType); !ok {
+		return state, false
+	}
+
+	return state.RemoveLB(lbType), true
+}
@@ -40,8 +40,10 @@ var _ = Describe("DeleteLBs", func() {
 				boshManager.VersionCall.Returns.Version = "1.9.0"
 				err := command.Execute([]string{}, storage.State{
 					IAAS: "aws",
-					LB: storage.LB{
-						Type: "concourse",
+					LBs: []storage.LB{
+						{
+							Type: "concourse",
+						},
 					},
 				})
 				Expect(err).To(MatchError("BOSH version must be at least v2.0.0"))
@@ -54,8 +56,10 @@ var _ = Describe("DeleteLBs", func() {
 				err := command.Execute([]string{}, storage.State{
 					IAAS:       "gcp",
 					NoDirector: true,
-					LB: storage.LB{
-						Type: "concourse",
+					LBs: []storage.LB{
+						{
+							Type: "concourse",
+						},
 					},
 				})
 				Expect(err).NotTo(HaveOccurred())
@@ -66,16 +70,20 @@ var _ = Describe("DeleteLBs", func() {
 			It("calls gcp delete lbs", func() {
 				err := command.Execute([]string{}, storage.State{
 					IAAS: "gcp",
-					LB: storage.LB{
-						Type: "concourse",
+					LBs: []storage.LB{
+						{
+							Type: "concourse",
+						},
 					},
 				})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(gcpDeleteLBs.ExecuteCall.CallCount).To(Equal(1))
 				Expect(gcpDeleteLBs.ExecuteCall.Receives.State).To(Equal(storage.State{
 					IAAS: "gcp",
-					LB: storage.LB{
-						Type: "concourse",
+					LBs: []storage.LB{
+						{
+							Type: "concourse",
+						},
 					},
 				}))
 				Expect(awsDeleteLBs.ExecuteCall.CallCount).To(Equal(0))
@@ -102,6 +110,40 @@ var _ = Describe("DeleteLBs", func() {
 			})
 		})
 
+		Context("when --type is provided", func() {
+			It("passes the type to the iaas delete lbs", func() {
+				err := command.Execute([]string{"--type", "cf"}, storage.State{
+					IAAS: "gcp",
+					LBs: []storage.LB{
+						{
+							Type: "concourse",
+						},
+						{
+							Type: "cf",
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(gcpDeleteLBs.ExecuteCall.Receives.LBType).To(Equal("cf"))
+			})
+
+			It("no-ops with --skip-if-missing when no lb of that type exists", func() {
+				err := command.Execute([]string{"--type", "cf", "--skip-if-missing"}, storage.State{
+					IAAS: "gcp",
+					LBs: []storage.LB{
+						{
+							Type: "concourse",
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(gcpDeleteLBs.ExecuteCall.CallCount).To(Equal(0))
+				Expect(logger.PrintlnCall.Receives.Message).To(Equal(`lb type "cf" does not exist, skipping...`))
+			})
+		})
+
 		Context("when --skip-if-missing is provided", func() {
 			DescribeTable("no-ops", func(state storage.State) {
 				err := command.Execute([]string{
@@ -119,11 +161,7 @@ var _ = Describe("DeleteLBs", func() {
 						LBType: "",
 					},
 				}),
-				Entry("no-ops when LB type does not exist in state LB", storage.State{
-					LB: storage.LB{
-						Type: "",
-					},
-				}),
+				Entry("no-ops when no LBs exist in state", storage.State{}),
 			)
 
 			DescribeTable("deletes the LB", func(state storage.State) {
@@ -146,8 +184,10 @@ var _ = Describe("DeleteLBs", func() {
 				}),
 				Entry("deletes the LB when LB type exists in state LB", storage.State{
 					IAAS: "gcp",
-					LB: storage.LB{
-						Type: "concourse",
+					LBs: []storage.LB{
+						{
+							Type: "concourse",
+						},
 					},
 				}),
 			)
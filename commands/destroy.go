@@ -50,7 +50,7 @@ type gcpKeyPairDeleter interface {
 }
 
 type vpcStatusChecker interface {
-	ValidateSafeToDelete(vpcID string, envID string) error
+	ValidateSafeToDelete(vpcID string, envID string, natType string) error
 }
 
 type stackManager interface {
@@ -166,7 +166,7 @@ func (d Destroy) Execute(subcommandFlags []string, state storage.State) error {
 			if err == nil {
 				var vpcID = outputs["vpc_id"]
 				if vpcID != nil {
-					if err := d.vpcStatusChecker.ValidateSafeToDelete(vpcID.(string), state.EnvID); err != nil {
+					if err := d.vpcStatusChecker.ValidateSafeToDelete(vpcID.(string), state.EnvID, state.AWS.NATType); err != nil {
 						return err
 					}
 				}
@@ -186,7 +186,7 @@ func (d Destroy) Execute(subcommandFlags []string, state storage.State) error {
 
 			if stackExists {
 				var vpcID = stack.Outputs["VPCID"]
-				if err := d.vpcStatusChecker.ValidateSafeToDelete(vpcID, ""); err != nil {
+				if err := d.vpcStatusChecker.ValidateSafeToDelete(vpcID, "", state.AWS.NATType); err != nil {
 					return err
 				}
 			}
@@ -375,12 +375,14 @@ var _ = Describe("Destroy", func() {
 
 						vpcStatusChecker.ValidateSafeToDeleteCall.Returns.Error = errors.New("vpc some-vpc-id is not safe to delete")
 						Expect(state.Stack.Name).To(BeEmpty())
+						state.AWS.NATType = "gateway"
 
 						err := destroy.Execute([]string{}, state)
 						Expect(err).To(MatchError("vpc some-vpc-id is not safe to delete"))
 
 						Expect(vpcStatusChecker.ValidateSafeToDeleteCall.Receives.VPCID).To(Equal("some-vpc-id"))
 						Expect(vpcStatusChecker.ValidateSafeToDeleteCall.Receives.EnvID).To(Equal("some-env-id"))
+						Expect(vpcStatusChecker.ValidateSafeToDeleteCall.Receives.NATType).To(Equal("gateway"))
 					})
 
 					Context("when terraform destroy fails", func() {
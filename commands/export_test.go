@@ -1,6 +1,10 @@
 package commands
 
-import yaml "gopkg.in/yaml.v2"
+import (
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+)
 
 func SetMarshal(f func(interface{}) ([]byte, error)) {
 	marshal = f
@@ -9,3 +13,11 @@ func SetMarshal(f func(interface{}) ([]byte, error)) {
 func ResetMarshal() {
 	marshal = yaml.Marshal
 }
+
+func SetNow(f func() time.Time) {
+	now = f
+}
+
+func ResetNow() {
+	now = time.Now
+}
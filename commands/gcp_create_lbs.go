@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 
@@ -16,6 +17,8 @@ type GCPCreateLBs struct {
 	stateStore           stateStore
 	logger               logger
 	environmentValidator environmentValidator
+	zones                gcpZones
+	guidGenerator        guidGenerator
 }
 
 type GCPCreateLBsConfig struct {
@@ -23,18 +26,31 @@ type GCPCreateLBsConfig struct {
 	CertPath     string
 	KeyPath      string
 	Domain       string
+	SpecPath     string
 	SkipIfExists bool
+
+	// CA is the certificate authority that signed the certificate at
+	// CertPath when create-lbs generated a self-signed certificate.
+	CA string
+
+	// CertificateName names the gcp ssl certificate. A new name is
+	// generated when it is empty so that terraform creates the certificate
+	// before deleting the one the https proxy currently uses.
+	CertificateName string
 }
 
 func NewGCPCreateLBs(terraformManager terraformManager,
 	cloudConfigManager cloudConfigManager,
-	stateStore stateStore, logger logger, environmentValidator environmentValidator) GCPCreateLBs {
+	stateStore stateStore, logger logger, environmentValidator environmentValidator, zones gcpZones,
+	guidGenerator guidGenerator) GCPCreateLBs {
 	return GCPCreateLBs{
 		terraformManager:     terraformManager,
 		cloudConfigManager:   cloudConfigManager,
 		stateStore:           stateStore,
 		logger:               logger,
 		environmentValidator: environmentValidator,
+		zones:                zones,
+		guidGenerator:        guidGenerator,
 	}
 }
 
@@ -44,42 +60,75 @@ func (c GCPCreateLBs) Execute(config GCPCreateLBsConfig, state storage.State) er
 		return err
 	}
 
-	err = c.checkFastFails(config, state)
+	spec, err := customLBSpecFor(config.LBType, config.SpecPath, state)
+	if err != nil {
+		return err
+	}
+
+	err = c.checkFastFails(config, spec, state)
 	if err != nil {
 		return err
 	}
 
+	lbType := config.LBType
+	if spec != nil {
+		lbType = spec.Name
+	}
+
 	err = c.environmentValidator.Validate(state)
 	if err != nil {
 		return err
 	}
 
-	if config.SkipIfExists && config.LBType == state.LB.Type {
-		c.logger.Step(fmt.Sprintf("lb type %q exists, skipping...", config.LBType))
+	if _, ok := state.GetLB(lbType); ok && config.SkipIfExists {
+		c.logger.Step(fmt.Sprintf("lb type %q exists, skipping...", lbType))
 		return nil
 	}
 
-	state.LB.Type = config.LBType
+	state, err = syncGCPZones(c.zones, state, nil)
+	if err != nil {
+		return err
+	}
 
-	var cert, key []byte
-	if config.LBType == "cf" {
-		state.LB.Domain = config.Domain
+	lb := storage.LB{
+		Type: lbType,
+		Spec: spec,
+	}
+
+	if config.LBType == "cf" || config.LBType == "concourse" {
+		lb.Domain = config.Domain
+	}
 
+	var cert, key []byte
+	if config.LBType == "cf" || (spec != nil && spec.TLS) {
 		cert, err = ioutil.ReadFile(config.CertPath)
 		if err != nil {
 			return err
 		}
 
-		state.LB.Cert = string(cert)
+		lb.Cert = string(cert)
 
 		key, err = ioutil.ReadFile(config.KeyPath)
 		if err != nil {
 			return err
 		}
 
-		state.LB.Key = string(key)
+		lb.Key = string(key)
+		lb.CA = config.CA
+
+		lb.CertificateName = config.CertificateName
+		if lb.CertificateName == "" {
+			guid, err := c.guidGenerator.Generate()
+			if err != nil {
+				return err
+			}
+
+			lb.CertificateName = fmt.Sprintf("%s-cert-%s", lbType, guid)
+		}
 	}
 
+	state = state.SetLB(lb)
+
 	state, err = c.terraformManager.Apply(state)
 	switch err.(type) {
 	case terraform.ManagerError:
@@ -117,29 +166,46 @@ func (c GCPCreateLBs) Execute(config GCPCreateLBsConfig, state storage.State) er
 	return nil
 }
 
-func (GCPCreateLBs) checkFastFails(config GCPCreateLBsConfig, state storage.State) error {
-	if config.LBType == "" {
-		return fmt.Errorf("--type is a required flag")
-	}
+// errGCPCFLBAllowedCIDRs is returned when lb cidrs would apply to the gcp cf
+// lb. Its routers sit behind google's https proxy and only receive traffic
+// from the google front end ranges, so the cidrs cannot be enforced there.
+var errGCPCFLBAllowedCIDRs = errors.New("--lb-allowed-cidrs is not supported with the cf load balancer on gcp, its routers only receive traffic from google front ends")
 
-	if config.LBType != "concourse" && config.LBType != "cf" {
-		return fmt.Errorf("%q is not a valid lb type, valid lb types are: concourse, cf", config.LBType)
-	}
+func (c GCPCreateLBs) checkFastFails(config GCPCreateLBsConfig, spec *storage.CustomLBSpec, state storage.State) error {
+	if spec != nil {
+		if err := c.checkCustomLBSpec(*spec); err != nil {
+			return err
+		}
 
-	if config.LBType == "cf" {
-		errs := multierror.NewMultiError("create-lbs")
-		if err := validateCertOrKeyFlag("cert", config.CertPath); err != nil {
-			errs.Add(err)
+		if spec.TLS {
+			if err := validateCertAndKeyFlags(config.CertPath, config.KeyPath); err != nil {
+				return err
+			}
 		}
-		if err := validateCertOrKeyFlag("key", config.KeyPath); err != nil {
-			errs.Add(err)
+	} else {
+		if config.LBType == "" {
+			return fmt.Errorf("--type is a required flag")
+		}
+
+		if config.LBType != "concourse" && config.LBType != "cf" {
+			return fmt.Errorf("%q is not a valid lb type, valid lb types are: concourse, cf, custom", config.LBType)
 		}
 
-		if errs.Length() > 0 {
-			return errs
+		if config.LBType == "cf" {
+			if err := validateCertAndKeyFlags(config.CertPath, config.KeyPath); err != nil {
+				return err
+			}
+
+			if len(state.LBAllowedCIDRs) > 0 {
+				return errGCPCFLBAllowedCIDRs
+			}
 		}
 	}
 
+	if config.CA != "" && config.LBType != "cf" && (spec == nil || !spec.TLS) {
+		return fmt.Errorf("--self-signed is only supported for cf load balancers and custom load balancers with tls on gcp")
+	}
+
 	if state.IAAS != "gcp" {
 		return fmt.Errorf("iaas type must be gcp")
 	}
@@ -147,6 +213,45 @@ func (GCPCreateLBs) checkFastFails(config GCPCreateLBsConfig, state storage.Stat
 	return nil
 }
 
+// checkCustomLBSpec rejects the parts of a custom lb spec that a gcp network
+// load balancer cannot provide. Specs with tls get an https load balancer,
+// which serves a single https listener on port 443.
+func (GCPCreateLBs) checkCustomLBSpec(spec storage.CustomLBSpec) error {
+	if spec.TLS {
+		if len(spec.Listeners) != 1 || spec.Listeners[0].Port != 443 || spec.Listeners[0].Protocol != "http" {
+			return fmt.Errorf("custom lb %q with tls must have a single http listener on port 443 on gcp", spec.Name)
+		}
+	} else {
+		for _, listener := range spec.Listeners {
+			if listener.Port != listener.TargetPort {
+				return fmt.Errorf("custom lb %q must use the same port and target port on gcp", spec.Name)
+			}
+		}
+	}
+
+	if spec.HealthCheck.Protocol == "https" {
+		return fmt.Errorf("custom lb %q cannot use an https health check on gcp", spec.Name)
+	}
+
+	return nil
+}
+
+func validateCertAndKeyFlags(certPath, keyPath string) error {
+	errs := multierror.NewMultiError("create-lbs")
+	if err := validateCertOrKeyFlag("cert", certPath); err != nil {
+		errs.Add(err)
+	}
+	if err := validateCertOrKeyFlag("key", keyPath); err != nil {
+		errs.Add(err)
+	}
+
+	if errs.Length() > 0 {
+		return errs
+	}
+
+	return nil
+}
+
 func validateCertOrKeyFlag(flagName, path string) error {
 	if path == "" {
 		return fmt.Errorf("--%s is required", flagName)
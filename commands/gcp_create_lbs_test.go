@@ -9,9 +9,11 @@ import (
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 	"github.com/cloudfoundry/bosh-bootloader/terraform"
+	"github.com/cloudfoundry/bosh-bootloader/testhelpers"
 	"github.com/cloudfoundry/multierror"
 
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
@@ -23,6 +25,8 @@ var _ = Describe("GCPCreateLBs", func() {
 		logger                 *fakes.Logger
 		terraformExecutorError *fakes.TerraformExecutorError
 		environmentValidator   *fakes.EnvironmentValidator
+		zones                  *fakes.Zones
+		guidGenerator          *fakes.GuidGenerator
 
 		command     commands.GCPCreateLBs
 		certPath    string
@@ -38,8 +42,11 @@ var _ = Describe("GCPCreateLBs", func() {
 		logger = &fakes.Logger{}
 		terraformExecutorError = &fakes.TerraformExecutorError{}
 		environmentValidator = &fakes.EnvironmentValidator{}
+		zones = &fakes.Zones{}
+		guidGenerator = &fakes.GuidGenerator{}
+		guidGenerator.GenerateCall.Returns.Output = "some-guid"
 
-		command = commands.NewGCPCreateLBs(terraformManager, cloudConfigManager, stateStore, logger, environmentValidator)
+		command = commands.NewGCPCreateLBs(terraformManager, cloudConfigManager, stateStore, logger, environmentValidator, zones, guidGenerator)
 
 		tempCertFile, err := ioutil.TempFile("", "cert")
 		Expect(err).NotTo(HaveOccurred())
@@ -77,14 +84,68 @@ var _ = Describe("GCPCreateLBs", func() {
 
 				Expect(terraformManager.ApplyCall.Receives.BBLState).To(Equal(storage.State{
 					IAAS: "gcp",
-					LB: storage.LB{
-						Type:   "cf",
-						Cert:   certificate,
-						Key:    key,
-						Domain: "some-domain",
+					LBs: []storage.LB{
+						{
+							Type:            "cf",
+							Cert:            certificate,
+							Key:             key,
+							Domain:          "some-domain",
+							CertificateName: "cf-cert-some-guid",
+						},
+					},
+				}))
+			})
+
+			It("keeps the certificate name when one is provided", func() {
+				err := command.Execute(commands.GCPCreateLBsConfig{
+					LBType:          "cf",
+					CertPath:        certPath,
+					KeyPath:         keyPath,
+					CertificateName: "cf-cert-some-other-guid",
+				}, storage.State{
+					IAAS: "gcp",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(guidGenerator.GenerateCall.Receives.CallCount).To(Equal(0))
+				Expect(terraformManager.ApplyCall.Receives.BBLState.LBs[0].CertificateName).To(Equal("cf-cert-some-other-guid"))
+			})
+		})
+
+		Context("when the cf certificate is self-signed", func() {
+			It("stores the CA with the lb", func() {
+				err := command.Execute(commands.GCPCreateLBsConfig{
+					LBType:   "cf",
+					CertPath: certPath,
+					KeyPath:  keyPath,
+					Domain:   "some-domain",
+					CA:       "some-ca",
+				}, storage.State{
+					IAAS: "gcp",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(terraformManager.ApplyCall.Receives.BBLState.LBs).To(Equal([]storage.LB{
+					{
+						Type:            "cf",
+						Cert:            certificate,
+						Key:             key,
+						CA:              "some-ca",
+						Domain:          "some-domain",
+						CertificateName: "cf-cert-some-guid",
 					},
 				}))
 			})
+
+			It("returns an error for lb types without a certificate", func() {
+				err := command.Execute(commands.GCPCreateLBsConfig{
+					LBType: "concourse",
+					CA:     "some-ca",
+				}, storage.State{
+					IAAS: "gcp",
+				})
+				Expect(err).To(MatchError("--self-signed is only supported for cf load balancers and custom load balancers with tls on gcp"))
+			})
 		})
 
 		Context("when lb type is concourse", func() {
@@ -98,13 +159,247 @@ var _ = Describe("GCPCreateLBs", func() {
 
 				Expect(terraformManager.ApplyCall.Receives.BBLState).To(Equal(storage.State{
 					IAAS: "gcp",
-					LB: storage.LB{
+					LBs: []storage.LB{
+						{
+							Type: "concourse",
+						},
+					},
+				}))
+			})
+
+			It("stores the domain with the lb", func() {
+				err := command.Execute(commands.GCPCreateLBsConfig{
+					LBType: "concourse",
+					Domain: "ci.example.com",
+				}, storage.State{
+					IAAS: "gcp",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(terraformManager.ApplyCall.Receives.BBLState.LBs).To(Equal([]storage.LB{
+					{
+						Type:   "concourse",
+						Domain: "ci.example.com",
+					},
+				}))
+			})
+		})
+
+		Context("when another lb type is already attached", func() {
+			It("keeps the existing lb alongside the new one", func() {
+				err := command.Execute(commands.GCPCreateLBsConfig{
+					LBType: "concourse",
+				}, storage.State{
+					IAAS: "gcp",
+					LBs: []storage.LB{
+						{
+							Type:   "cf",
+							Domain: "some-domain",
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(terraformManager.ApplyCall.Receives.BBLState.LBs).To(Equal([]storage.LB{
+					{
+						Type:   "cf",
+						Domain: "some-domain",
+					},
+					{
 						Type: "concourse",
 					},
 				}))
 			})
 		})
 
+		Context("when lb type is custom", func() {
+			It("attaches the lb under the name from its spec", func() {
+				specPath, err := testhelpers.WriteContentsToTempFile(`
+name: vault
+listeners:
+- protocol: tcp
+  port: 8200
+health_check:
+  protocol: http
+  path: /v1/sys/health
+`)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = command.Execute(commands.GCPCreateLBsConfig{
+					LBType:   "custom",
+					SpecPath: specPath,
+				}, storage.State{
+					IAAS: "gcp",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(terraformManager.ApplyCall.Receives.BBLState.LBs).To(Equal([]storage.LB{
+					{
+						Type: "vault",
+						Spec: &storage.CustomLBSpec{
+							Name: "vault",
+							Listeners: []storage.CustomLBListener{
+								{Protocol: "tcp", Port: 8200, TargetPort: 8200},
+							},
+							HealthCheck: storage.CustomLBHealthCheck{
+								Protocol: "http",
+								Port:     8200,
+								Path:     "/v1/sys/health",
+							},
+						},
+					},
+				}))
+			})
+
+			It("stores the cert and key of a spec with tls", func() {
+				specPath, err := testhelpers.WriteContentsToTempFile("name: vault\ntls: true\nlisteners:\n- {protocol: http, port: 443, target_port: 8200}\n")
+				Expect(err).NotTo(HaveOccurred())
+
+				err = command.Execute(commands.GCPCreateLBsConfig{
+					LBType:   "custom",
+					SpecPath: specPath,
+					CertPath: certPath,
+					KeyPath:  keyPath,
+				}, storage.State{
+					IAAS: "gcp",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				lb := terraformManager.ApplyCall.Receives.BBLState.LBs[0]
+				Expect(lb.Type).To(Equal("vault"))
+				Expect(lb.Cert).To(Equal(certificate))
+				Expect(lb.Key).To(Equal(key))
+				Expect(lb.CertificateName).To(Equal("vault-cert-some-guid"))
+			})
+
+			It("returns an error when a spec with tls is given no cert and key", func() {
+				specPath, err := testhelpers.WriteContentsToTempFile("name: vault\ntls: true\nlisteners:\n- {protocol: http, port: 443, target_port: 8200}\n")
+				Expect(err).NotTo(HaveOccurred())
+
+				expectedErrors := multierror.NewMultiError("create-lbs")
+				expectedErrors.Add(errors.New("--cert is required"))
+				expectedErrors.Add(errors.New("--key is required"))
+
+				err = command.Execute(commands.GCPCreateLBsConfig{
+					LBType:   "custom",
+					SpecPath: specPath,
+				}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError(expectedErrors))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+
+			It("no-ops if SkipIfExists is supplied and the custom lb is attached", func() {
+				specPath, err := testhelpers.WriteContentsToTempFile("name: vault\nlisteners:\n- {protocol: tcp, port: 8200}\n")
+				Expect(err).NotTo(HaveOccurred())
+
+				err = command.Execute(commands.GCPCreateLBsConfig{
+					LBType:       "custom",
+					SpecPath:     specPath,
+					SkipIfExists: true,
+				}, storage.State{
+					IAAS: "gcp",
+					LBs:  []storage.LB{{Type: "vault", Spec: &storage.CustomLBSpec{Name: "vault"}}},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+				Expect(logger.PrintlnCall.Receives.Message).To(Equal(`lb type "vault" exists, skipping...`))
+			})
+
+			DescribeTable("returns an error when the spec is invalid", func(spec, expectedError string) {
+				specPath, err := testhelpers.WriteContentsToTempFile(spec)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = command.Execute(commands.GCPCreateLBsConfig{
+					LBType:   "custom",
+					SpecPath: specPath,
+				}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError(expectedError))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			},
+				Entry("when the spec cannot be parsed", "%%%",
+					"failed to parse custom lb spec: yaml: could not find expected directive name"),
+				Entry("when the name is invalid", "name: Vault-LB\nlisteners:\n- {protocol: tcp, port: 8200}\n",
+					`custom lb name "Vault-LB" must be 1 to 10 lowercase letters or digits, starting with a letter`),
+				Entry("when the name is reserved", "name: cf\nlisteners:\n- {protocol: tcp, port: 8200}\n",
+					`custom lb name "cf" is reserved`),
+				Entry("when there are no listeners", "name: vault\n",
+					`custom lb "vault" must have at least one listener`),
+				Entry("when a listener protocol is not supported", "name: vault\nlisteners:\n- {protocol: udp, port: 8200}\n",
+					`custom lb listener protocol "udp" is not supported, valid protocols are: tcp and http`),
+				Entry("when a listener port is out of range", "name: vault\nlisteners:\n- {protocol: tcp, port: 70000}\n",
+					"custom lb listener port 70000 is out of range"),
+				Entry("when a listener target port is out of range", "name: vault\nlisteners:\n- {protocol: tcp, port: 8200, target_port: -1}\n",
+					"custom lb listener target port -1 is out of range"),
+				Entry("when a listener port is used twice", "name: vault\nlisteners:\n- {protocol: tcp, port: 8200}\n- {protocol: http, port: 8200}\n",
+					"custom lb listener port 8200 is used more than once"),
+				Entry("when the health check protocol is not supported", "name: vault\nlisteners:\n- {protocol: tcp, port: 8200}\nhealth_check: {protocol: udp}\n",
+					`custom lb health check protocol "udp" is not supported, valid protocols are: tcp, http and https`),
+				Entry("when the health check port is out of range", "name: vault\nlisteners:\n- {protocol: tcp, port: 8200}\nhealth_check: {port: 70000}\n",
+					"custom lb health check port 70000 is out of range"),
+				Entry("when a spec with tls does not listen for http on port 443", "name: vault\ntls: true\nlisteners:\n- {protocol: tcp, port: 8200}\n",
+					`custom lb "vault" with tls must have a single http listener on port 443 on gcp`),
+				Entry("when a listener forwards to another port", "name: vault\nlisteners:\n- {protocol: tcp, port: 80, target_port: 8200}\n",
+					`custom lb "vault" must use the same port and target port on gcp`),
+				Entry("when the health check uses https", "name: vault\nlisteners:\n- {protocol: tcp, port: 8200}\nhealth_check: {protocol: https}\n",
+					`custom lb "vault" cannot use an https health check on gcp`),
+			)
+
+			It("returns an error when no spec is provided", func() {
+				err := command.Execute(commands.GCPCreateLBsConfig{
+					LBType: "custom",
+				}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError(`--spec is required when type="custom"`))
+			})
+		})
+
+		Context("when the state has no zones", func() {
+			It("discovers the zones of the region before applying terraform", func() {
+				zones.GetCall.Returns.Zones = []string{"us-east1-b", "us-east1-c", "us-east1-d"}
+
+				err := command.Execute(commands.GCPCreateLBsConfig{
+					LBType: "concourse",
+				}, storage.State{
+					IAAS: "gcp",
+					GCP: storage.GCP{
+						Region: "us-east1",
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(zones.GetCall.Receives.Region).To(Equal("us-east1"))
+				Expect(terraformManager.ApplyCall.Receives.BBLState.GCP.Zones).To(Equal([]string{"us-east1-b", "us-east1-c", "us-east1-d"}))
+			})
+
+			It("returns an error when the zones cannot be discovered", func() {
+				zones.GetCall.Returns.Error = errors.New("failed to get zones")
+
+				err := command.Execute(commands.GCPCreateLBsConfig{
+					LBType: "concourse",
+				}, storage.State{
+					IAAS: "gcp",
+				})
+				Expect(err).To(MatchError("failed to get zones"))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+		})
+
+		It("keeps the zones stored in the state", func() {
+			err := command.Execute(commands.GCPCreateLBsConfig{
+				LBType: "concourse",
+			}, storage.State{
+				IAAS: "gcp",
+				GCP: storage.GCP{
+					Region: "us-east1",
+					Zones:  []string{"us-east1-b"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(zones.GetCall.CallCount).To(Equal(0))
+			Expect(terraformManager.ApplyCall.Receives.BBLState.GCP.Zones).To(Equal([]string{"us-east1-b"}))
+		})
+
 		It("saves the updated tfstate", func() {
 			terraformManager.ApplyCall.Returns.BBLState = storage.State{
 				IAAS: "gcp",
@@ -113,8 +408,10 @@ var _ = Describe("GCPCreateLBs", func() {
 					DirectorPassword: "some-director-password",
 					DirectorAddress:  "some-director-address",
 				},
-				LB: storage.LB{
-					Type: "concourse",
+				LBs: []storage.LB{
+					{
+						Type: "concourse",
+					},
 				},
 				TFState: "some-new-tfstate",
 			}
@@ -140,8 +437,10 @@ var _ = Describe("GCPCreateLBs", func() {
 					DirectorPassword: "some-director-password",
 					DirectorAddress:  "some-director-address",
 				},
-				LB: storage.LB{
-					Type: "concourse",
+				LBs: []storage.LB{
+					{
+						Type: "concourse",
+					},
 				},
 				TFState: "some-new-tfstate",
 			}))
@@ -155,8 +454,10 @@ var _ = Describe("GCPCreateLBs", func() {
 					DirectorPassword: "some-director-password",
 					DirectorAddress:  "some-director-address",
 				},
-				LB: storage.LB{
-					Type: "concourse",
+				LBs: []storage.LB{
+					{
+						Type: "concourse",
+					},
 				},
 			}
 
@@ -180,8 +481,10 @@ var _ = Describe("GCPCreateLBs", func() {
 					DirectorPassword: "some-director-password",
 					DirectorAddress:  "some-director-address",
 				},
-				LB: storage.LB{
-					Type: "concourse",
+				LBs: []storage.LB{
+					{
+						Type: "concourse",
+					},
 				},
 			}))
 		})
@@ -192,8 +495,10 @@ var _ = Describe("GCPCreateLBs", func() {
 				SkipIfExists: true,
 			}, storage.State{
 				IAAS: "gcp",
-				LB: storage.LB{
-					Type: "concourse",
+				LBs: []storage.LB{
+					{
+						Type: "concourse",
+					},
 				},
 			})
 			Expect(err).NotTo(HaveOccurred())
@@ -244,6 +549,20 @@ var _ = Describe("GCPCreateLBs", func() {
 		})
 
 		Context("failure cases", func() {
+			It("returns an error when the certificate name cannot be generated", func() {
+				guidGenerator.GenerateCall.Returns.Error = errors.New("failed to generate guid")
+
+				err := command.Execute(commands.GCPCreateLBsConfig{
+					LBType:   "cf",
+					CertPath: certPath,
+					KeyPath:  keyPath,
+				}, storage.State{
+					IAAS: "gcp",
+				})
+				Expect(err).To(MatchError("failed to generate guid"))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+
 			Context("when creating a cf lb and provided cert and key files are empty", func() {
 				BeforeEach(func() {
 					err := ioutil.WriteFile(certPath, []byte{}, os.ModePerm)
@@ -293,7 +612,7 @@ var _ = Describe("GCPCreateLBs", func() {
 				err := command.Execute(commands.GCPCreateLBsConfig{
 					LBType: "some-fake-lb",
 				}, storage.State{IAAS: "gcp"})
-				Expect(err).To(MatchError(`"some-fake-lb" is not a valid lb type, valid lb types are: concourse, cf`))
+				Expect(err).To(MatchError(`"some-fake-lb" is not a valid lb type, valid lb types are: concourse, cf, custom`))
 			})
 
 			Context("when lb type is cf", func() {
@@ -307,6 +626,19 @@ var _ = Describe("GCPCreateLBs", func() {
 					}, storage.State{IAAS: "gcp"})
 					Expect(err).To(MatchError(expectedErrors))
 				})
+
+				It("returns an error when lb allowed cidrs are set", func() {
+					err := command.Execute(commands.GCPCreateLBsConfig{
+						LBType:   "cf",
+						CertPath: "some-cert-path",
+						KeyPath:  "some-key-path",
+					}, storage.State{
+						IAAS:           "gcp",
+						LBAllowedCIDRs: []string{"10.0.0.0/8"},
+					})
+					Expect(err).To(MatchError("--lb-allowed-cidrs is not supported with the cf load balancer on gcp, its routers only receive traffic from google front ends"))
+					Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+				})
 			})
 
 			It("returns an error when environment validator fails", func() {
@@ -395,8 +727,10 @@ var _ = Describe("GCPCreateLBs", func() {
 				terraformExecutorError.ErrorCall.Returns = "failed to apply"
 				expectedError := terraform.NewManagerError(storage.State{
 					IAAS: "gcp",
-					LB: storage.LB{
-						Type: "concourse",
+					LBs: []storage.LB{
+						{
+							Type: "concourse",
+						},
 					},
 					TFState: "some-tf-state",
 				}, terraformExecutorError)
@@ -415,8 +749,10 @@ var _ = Describe("GCPCreateLBs", func() {
 				terraformExecutorError.ErrorCall.Returns = "failed to apply"
 				expectedError := terraform.NewManagerError(storage.State{
 					IAAS: "gcp",
-					LB: storage.LB{
-						Type: "concourse",
+					LBs: []storage.LB{
+						{
+							Type: "concourse",
+						},
 					},
 					TFState: "some-tf-state",
 				}, terraformExecutorError)
@@ -431,8 +767,10 @@ var _ = Describe("GCPCreateLBs", func() {
 				Expect(stateStore.SetCall.CallCount).To(Equal(1))
 				Expect(stateStore.SetCall.Receives[0].State).To(Equal(storage.State{
 					IAAS: "gcp",
-					LB: storage.LB{
-						Type: "concourse",
+					LBs: []storage.LB{
+						{
+							Type: "concourse",
+						},
 					},
 					TFState: "some-updated-tf-state",
 				}))
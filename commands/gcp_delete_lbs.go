@@ -21,12 +21,17 @@ func NewGCPDeleteLBs(stateStore stateStore,
 	}
 }
 
-func (g GCPDeleteLBs) Execute(state storage.State) error {
+func (g GCPDeleteLBs) Execute(lbType string, state storage.State) error {
 	err := g.terraformManager.ValidateVersion()
 	if err != nil {
 		return err
 	}
-	state.LB.Type = ""
+
+	var found bool
+	state, found = removeLBs(state, lbType)
+	if !found && lbType != "" {
+		return LBNotFound
+	}
 
 	if !state.NoDirector {
 		err = g.cloudConfigManager.Update(state)
@@ -42,7 +42,7 @@ var _ = Describe("GCPDeleteLBs", func() {
 
 		Context("when bbl has a bosh director", func() {
 			It("updates the cloud config", func() {
-				err := command.Execute(storage.State{
+				err := command.Execute("", storage.State{
 					IAAS: "gcp",
 					BOSH: storage.BOSH{
 						DirectorUsername: "some-director-username",
@@ -52,8 +52,10 @@ var _ = Describe("GCPDeleteLBs", func() {
 					GCP: storage.GCP{
 						Region: "some-region",
 					},
-					LB: storage.LB{
-						Type: "cf",
+					LBs: []storage.LB{
+						{
+							Type: "cf",
+						},
 					},
 				})
 				Expect(err).NotTo(HaveOccurred())
@@ -74,14 +76,16 @@ var _ = Describe("GCPDeleteLBs", func() {
 
 		Context("when bbl does not have a bosh director", func() {
 			It("does not update the cloud config", func() {
-				err := command.Execute(storage.State{
+				err := command.Execute("", storage.State{
 					IAAS:       "gcp",
 					NoDirector: true,
 					GCP: storage.GCP{
 						Region: "some-region",
 					},
-					LB: storage.LB{
-						Type: "cf",
+					LBs: []storage.LB{
+						{
+							Type: "cf",
+						},
 					},
 				})
 				Expect(err).NotTo(HaveOccurred())
@@ -98,7 +102,7 @@ var _ = Describe("GCPDeleteLBs", func() {
 			region := "some-region"
 			tfState := "some-tf-state"
 
-			err := command.Execute(storage.State{
+			err := command.Execute("", storage.State{
 				EnvID: envID,
 				GCP: storage.GCP{
 					ServiceAccountKey: credentials,
@@ -106,8 +110,10 @@ var _ = Describe("GCPDeleteLBs", func() {
 					Region:            region,
 					ProjectID:         projectID,
 				},
-				LB: storage.LB{
-					Type: "concourse",
+				LBs: []storage.LB{
+					{
+						Type: "concourse",
+					},
 				},
 				TFState: tfState,
 			})
@@ -128,10 +134,12 @@ var _ = Describe("GCPDeleteLBs", func() {
 
 		Context("state manipulation", func() {
 			It("removes the lb from the state", func() {
-				err := command.Execute(storage.State{
+				err := command.Execute("", storage.State{
 					IAAS: "gcp",
-					LB: storage.LB{
-						Type: "concourse",
+					LBs: []storage.LB{
+						{
+							Type: "concourse",
+						},
 					},
 				})
 				Expect(err).NotTo(HaveOccurred())
@@ -140,15 +148,55 @@ var _ = Describe("GCPDeleteLBs", func() {
 				Expect(stateStore.SetCall.Receives[0].State.Stack.LBType).To(Equal(""))
 			})
 
+			Context("when a type is provided", func() {
+				var incomingState storage.State
+
+				BeforeEach(func() {
+					incomingState = storage.State{
+						IAAS: "gcp",
+						LBs: []storage.LB{
+							{
+								Type: "concourse",
+							},
+							{
+								Type:   "cf",
+								Domain: "some-domain",
+							},
+						},
+					}
+				})
+
+				It("removes only the lb of that type", func() {
+					err := command.Execute("concourse", incomingState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(terraformManager.ApplyCall.Receives.BBLState.LBs).To(Equal([]storage.LB{
+						{
+							Type:   "cf",
+							Domain: "some-domain",
+						},
+					}))
+				})
+
+				It("returns an error when no lb of that type is attached", func() {
+					err := command.Execute("other", incomingState)
+					Expect(err).To(MatchError(commands.LBNotFound))
+
+					Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+				})
+			})
+
 			It("saves the tf state", func() {
 				terraformManager.ApplyCall.Returns.BBLState = storage.State{
 					IAAS: "gcp",
 				}
 
-				err := command.Execute(storage.State{
+				err := command.Execute("", storage.State{
 					IAAS: "gcp",
-					LB: storage.LB{
-						Type: "concourse",
+					LBs: []storage.LB{
+						{
+							Type: "concourse",
+						},
 					},
 				})
 				Expect(err).NotTo(HaveOccurred())
@@ -167,7 +215,7 @@ var _ = Describe("GCPDeleteLBs", func() {
 				}, terraformExecutorError)
 				terraformManager.ApplyCall.Returns.Error = expectedError
 
-				err := command.Execute(storage.State{
+				err := command.Execute("", storage.State{
 					IAAS: "gcp",
 					Stack: storage.Stack{
 						LBType: "concourse",
@@ -186,7 +234,7 @@ var _ = Describe("GCPDeleteLBs", func() {
 			It("fast fails if the terraform version is invalid", func() {
 				terraformManager.ValidateVersionCall.Returns.Error = errors.New("invalid")
 
-				err := command.Execute(storage.State{
+				err := command.Execute("", storage.State{
 					IAAS: "gcp",
 					Stack: storage.Stack{
 						LBType: "concourse",
@@ -198,7 +246,7 @@ var _ = Describe("GCPDeleteLBs", func() {
 			It("returns an error if applier fails with non terraform apply error", func() {
 				terraformManager.ApplyCall.Returns.Error = errors.New("failed to apply")
 
-				err := command.Execute(storage.State{
+				err := command.Execute("", storage.State{
 					IAAS: "gcp",
 					Stack: storage.Stack{
 						LBType: "concourse",
@@ -225,7 +273,7 @@ var _ = Describe("GCPDeleteLBs", func() {
 						{errors.New("failed to set state")},
 					}
 
-					err := command.Execute(storage.State{
+					err := command.Execute("", storage.State{
 						IAAS: "gcp",
 						Stack: storage.Stack{
 							LBType: "concourse",
@@ -251,7 +299,7 @@ var _ = Describe("GCPDeleteLBs", func() {
 						{errors.New("failed to set state")},
 					}
 
-					err := command.Execute(storage.State{
+					err := command.Execute("", storage.State{
 						IAAS: "gcp",
 						Stack: storage.Stack{
 							LBType: "concourse",
@@ -264,7 +312,7 @@ var _ = Describe("GCPDeleteLBs", func() {
 			It("returns an error when updating cloud config fails", func() {
 				cloudConfigManager.UpdateCall.Returns.Error = errors.New("updating cloud config failed")
 
-				err := command.Execute(storage.State{
+				err := command.Execute("", storage.State{
 					IAAS: "gcp",
 					Stack: storage.Stack{
 						LBType: "concourse",
@@ -278,7 +326,7 @@ var _ = Describe("GCPDeleteLBs", func() {
 					{errors.New("failed to set state")},
 				}
 
-				err := command.Execute(storage.State{
+				err := command.Execute("", storage.State{
 					IAAS: "gcp",
 					Stack: storage.Stack{
 						LBType: "concourse",
@@ -1,8 +1,8 @@
 package commands
 
 import (
-	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/cloudfoundry/bosh-bootloader/storage"
@@ -26,29 +26,17 @@ func (l GCPLBs) Execute(subcommandFlags []string, state storage.State) error {
 		return err
 	}
 
-	switch state.LB.Type {
-	case "cf":
-		if len(subcommandFlags) > 0 && subcommandFlags[0] == "--json" {
-			lbOutput, err := json.Marshal(struct {
-				RouterLBIP             string   `json:"cf_router_lb,omitempty"`
-				SSHProxyLBIP           string   `json:"cf_ssh_proxy_lb,omitempty"`
-				TCPRouterLBIP          string   `json:"cf_tcp_router_lb,omitempty"`
-				WebSocketLBIP          string   `json:"cf_websocket_lb,omitempty"`
-				SystemDomainDNSServers []string `json:"cf_system_domain_dns_servers,omitempty"`
-			}{
-				RouterLBIP:             terraformOutputs["router_lb_ip"].(string),
-				SSHProxyLBIP:           terraformOutputs["ssh_proxy_lb_ip"].(string),
-				TCPRouterLBIP:          terraformOutputs["tcp_router_lb_ip"].(string),
-				WebSocketLBIP:          terraformOutputs["ws_lb_ip"].(string),
-				SystemDomainDNSServers: terraformOutputs["system_domain_dns_servers"].([]string),
-			})
-			if err != nil {
-				// not tested
-				return err
-			}
+	if len(state.LBs) == 0 {
+		return errors.New("no lbs found")
+	}
 
-			l.logger.Println(string(lbOutput))
-		} else {
+	if isJSONFlag(subcommandFlags) {
+		return printLBsJSON(l.logger, l.lbsOutput(state, terraformOutputs))
+	}
+
+	for _, lb := range state.LBs {
+		switch lb.Type {
+		case "cf":
 			l.logger.Printf("CF Router LB: %s\n", terraformOutputs["router_lb_ip"])
 			l.logger.Printf("CF SSH Proxy LB: %s\n", terraformOutputs["ssh_proxy_lb_ip"])
 			l.logger.Printf("CF TCP Router LB: %s\n", terraformOutputs["tcp_router_lb_ip"])
@@ -57,12 +45,92 @@ func (l GCPLBs) Execute(subcommandFlags []string, state storage.State) error {
 			if dnsServers, ok := terraformOutputs["system_domain_dns_servers"]; ok {
 				l.logger.Printf("CF System Domain DNS servers: %s\n", strings.Join(dnsServers.([]string), " "))
 			}
+		case "concourse":
+			l.logger.Printf("Concourse LB: %s\n", terraformOutputs["concourse_lb_ip"])
+
+			if dnsServers, ok := terraformOutputs["concourse_domain_dns_servers"]; ok {
+				l.logger.Printf("Concourse Domain DNS servers: %s\n", strings.Join(dnsServers.([]string), " "))
+			}
+		default:
+			if lb.IsCustom() {
+				l.logger.Printf("%s LB: %s\n", lb.Type, terraformOutputs[fmt.Sprintf("%s_lb_ip", lb.Type)])
+			}
 		}
-	case "concourse":
-		l.logger.Printf("Concourse LB: %s\n", terraformOutputs["concourse_lb_ip"])
-	default:
-		return errors.New("no lbs found")
 	}
 
 	return nil
 }
+
+func (GCPLBs) lbsOutput(state storage.State, terraformOutputs map[string]interface{}) LBsOutput {
+	lbsOutput := LBsOutput{IAAS: "gcp", AllowedCIDRs: state.LBAllowedCIDRs, LBs: []LBOutput{}}
+
+	for _, lb := range state.LBs {
+		lbOutput := LBOutput{
+			Type:   lb.Type,
+			Domain: lb.Domain,
+		}
+
+		switch lb.Type {
+		case "cf":
+			// Certificates attached before they were rotated under unique
+			// names are still named after the environment.
+			if lb.Cert != "" {
+				lbOutput.CertificateName = lb.CertificateName
+				if lbOutput.CertificateName == "" {
+					lbOutput.CertificateName = fmt.Sprintf("%s-cf-cert", state.EnvID)
+				}
+			}
+
+			lbOutput.DNSServers, _ = terraformOutputs["system_domain_dns_servers"].([]string)
+			lbOutput.LoadBalancers = []LoadBalancerOutput{
+				{
+					Component:      "router",
+					IP:             stringOutput(terraformOutputs, "router_lb_ip"),
+					BackendService: stringOutput(terraformOutputs, "router_backend_service"),
+				},
+				{
+					Component:  "ssh_proxy",
+					IP:         stringOutput(terraformOutputs, "ssh_proxy_lb_ip"),
+					TargetPool: stringOutput(terraformOutputs, "ssh_proxy_target_pool"),
+				},
+				{
+					Component:  "tcp_router",
+					IP:         stringOutput(terraformOutputs, "tcp_router_lb_ip"),
+					TargetPool: stringOutput(terraformOutputs, "tcp_router_target_pool"),
+				},
+				{
+					Component:  "websocket",
+					IP:         stringOutput(terraformOutputs, "ws_lb_ip"),
+					TargetPool: stringOutput(terraformOutputs, "ws_target_pool"),
+				},
+			}
+		case "concourse":
+			lbOutput.DNSServers, _ = terraformOutputs["concourse_domain_dns_servers"].([]string)
+			lbOutput.LoadBalancers = []LoadBalancerOutput{
+				{
+					Component:  "concourse",
+					IP:         stringOutput(terraformOutputs, "concourse_lb_ip"),
+					TargetPool: stringOutput(terraformOutputs, "concourse_target_pool"),
+				},
+			}
+		default:
+			if !lb.IsCustom() {
+				continue
+			}
+
+			lbOutput.CertificateName = lb.CertificateName
+			lbOutput.LoadBalancers = []LoadBalancerOutput{
+				{
+					Component:      lb.Type,
+					IP:             stringOutput(terraformOutputs, fmt.Sprintf("%s_lb_ip", lb.Type)),
+					TargetPool:     stringOutput(terraformOutputs, fmt.Sprintf("%s_target_pool", lb.Type)),
+					BackendService: stringOutput(terraformOutputs, fmt.Sprintf("%s_backend_service", lb.Type)),
+				},
+			}
+		}
+
+		lbsOutput.LBs = append(lbsOutput.LBs, lbOutput)
+	}
+
+	return lbsOutput
+}
@@ -30,6 +30,7 @@ var _ = Describe("GCPLBs", func() {
 			"tcp_router_lb_ip": "some-tcp-router-lb-ip",
 			"ws_lb_ip":         "some-ws-lb-ip",
 			"concourse_lb_ip":  "some-concourse-lb-ip",
+			"vault_lb_ip":      "some-vault-lb-ip",
 		}
 		logger = &fakes.Logger{}
 
@@ -38,8 +39,10 @@ var _ = Describe("GCPLBs", func() {
 
 	Describe("Execute", func() {
 		It("prints LB ips for lb type cf", func() {
-			incomingState.LB = storage.LB{
-				Type: "cf",
+			incomingState.LBs = []storage.LB{
+				{
+					Type: "cf",
+				},
 			}
 			err := command.Execute([]string{}, incomingState)
 
@@ -66,9 +69,11 @@ var _ = Describe("GCPLBs", func() {
 			})
 
 			It("prints LB ips for lb type cf in human readable format", func() {
-				incomingState.LB = storage.LB{
-					Type:   "cf",
-					Domain: "some-domain",
+				incomingState.LBs = []storage.LB{
+					{
+						Type:   "cf",
+						Domain: "some-domain",
+					},
 				}
 				err := command.Execute([]string{}, incomingState)
 
@@ -85,40 +90,207 @@ var _ = Describe("GCPLBs", func() {
 
 			Context("when the json flag is provided", func() {
 				It("prints LB ips for lb type cf in json format", func() {
-					incomingState.LB = storage.LB{
-						Type:   "cf",
-						Domain: "some-domain",
+					incomingState.LBs = []storage.LB{
+						{
+							Type:   "cf",
+							Domain: "some-domain",
+						},
 					}
+					incomingState.LBAllowedCIDRs = []string{"10.0.0.0/8"}
 					err := command.Execute([]string{"--json"}, incomingState)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(`{
-							"cf_router_lb": "some-router-lb-ip",
-							"cf_ssh_proxy_lb": "some-ssh-proxy-lb-ip",
-							"cf_tcp_router_lb": "some-tcp-router-lb-ip",
-							"cf_websocket_lb": "some-ws-lb-ip",
-							"cf_system_domain_dns_servers": [
-								"name-server-1.",
-								"name-server-2."
+						"iaas": "gcp",
+						"allowed_cidrs": ["10.0.0.0/8"],
+						"lbs": [{
+							"type": "cf",
+							"domain": "some-domain",
+							"dns_servers": ["name-server-1.", "name-server-2."],
+							"load_balancers": [
+								{"component": "router", "ip": "some-router-lb-ip"},
+								{"component": "ssh_proxy", "ip": "some-ssh-proxy-lb-ip"},
+								{"component": "tcp_router", "ip": "some-tcp-router-lb-ip"},
+								{"component": "websocket", "ip": "some-ws-lb-ip"}
 							]
-						}`))
+						}]
+					}`))
 				})
 			})
 		})
 
 		It("prints LB ips for lb type concourse", func() {
-			incomingState.LB = storage.LB{
-				Type: "concourse",
+			incomingState.LBs = []storage.LB{
+				{
+					Type: "concourse",
+				},
+			}
+			err := command.Execute([]string{}, incomingState)
+
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintfCall.Messages).To(ConsistOf([]string{
+				"Concourse LB: some-concourse-lb-ip\n",
+			}))
+		})
+
+		It("prints the DNS servers when the concourse lb has a domain", func() {
+			incomingState.LBs = []storage.LB{
+				{
+					Type:   "concourse",
+					Domain: "ci.example.com",
+				},
 			}
+			terraformManager.GetOutputsCall.Returns.Outputs["concourse_domain_dns_servers"] = []string{"name-server-1.", "name-server-2."}
+
 			err := command.Execute([]string{}, incomingState)
 
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(logger.PrintfCall.Messages).To(ConsistOf([]string{
 				"Concourse LB: some-concourse-lb-ip\n",
+				"Concourse Domain DNS servers: name-server-1. name-server-2.\n",
 			}))
 		})
 
+		It("prints LB ips for a custom lb", func() {
+			incomingState.LBs = []storage.LB{
+				{
+					Type: "vault",
+					Spec: &storage.CustomLBSpec{Name: "vault"},
+				},
+			}
+			err := command.Execute([]string{}, incomingState)
+
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintfCall.Messages).To(ConsistOf([]string{
+				"vault LB: some-vault-lb-ip\n",
+			}))
+		})
+
+		It("prints LB ips for every attached lb", func() {
+			incomingState.LBs = []storage.LB{
+				{
+					Type: "cf",
+				},
+				{
+					Type: "concourse",
+				},
+			}
+			err := command.Execute([]string{}, incomingState)
+
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintfCall.Messages).To(ConsistOf([]string{
+				"CF Router LB: some-router-lb-ip\n",
+				"CF SSH Proxy LB: some-ssh-proxy-lb-ip\n",
+				"CF TCP Router LB: some-tcp-router-lb-ip\n",
+				"CF WebSocket LB: some-ws-lb-ip\n",
+				"Concourse LB: some-concourse-lb-ip\n",
+			}))
+		})
+
+		It("prints every attached lb in json format when the json flag is provided", func() {
+			terraformManager.GetOutputsCall.Returns.Outputs["router_backend_service"] = "some-router-backend-service"
+			terraformManager.GetOutputsCall.Returns.Outputs["ssh_proxy_target_pool"] = "some-ssh-proxy-target-pool"
+			terraformManager.GetOutputsCall.Returns.Outputs["tcp_router_target_pool"] = "some-tcp-router-target-pool"
+			terraformManager.GetOutputsCall.Returns.Outputs["ws_target_pool"] = "some-ws-target-pool"
+			terraformManager.GetOutputsCall.Returns.Outputs["concourse_target_pool"] = "some-concourse-target-pool"
+			terraformManager.GetOutputsCall.Returns.Outputs["vault_target_pool"] = "some-vault-target-pool"
+
+			incomingState.LBs = []storage.LB{
+				{
+					Type: "cf",
+				},
+				{
+					Type: "concourse",
+				},
+				{
+					Type: "vault",
+					Spec: &storage.CustomLBSpec{Name: "vault"},
+				},
+			}
+			err := command.Execute([]string{"--json"}, incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintfCall.CallCount).To(Equal(0))
+			Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(`{
+				"iaas": "gcp",
+				"lbs": [
+					{
+						"type": "cf",
+						"load_balancers": [
+							{"component": "router", "ip": "some-router-lb-ip", "backend_service": "some-router-backend-service"},
+							{"component": "ssh_proxy", "ip": "some-ssh-proxy-lb-ip", "target_pool": "some-ssh-proxy-target-pool"},
+							{"component": "tcp_router", "ip": "some-tcp-router-lb-ip", "target_pool": "some-tcp-router-target-pool"},
+							{"component": "websocket", "ip": "some-ws-lb-ip", "target_pool": "some-ws-target-pool"}
+						]
+					},
+					{
+						"type": "concourse",
+						"load_balancers": [
+							{"component": "concourse", "ip": "some-concourse-lb-ip", "target_pool": "some-concourse-target-pool"}
+						]
+					},
+					{
+						"type": "vault",
+						"load_balancers": [
+							{"component": "vault", "ip": "some-vault-lb-ip", "target_pool": "some-vault-target-pool"}
+						]
+					}
+				]
+			}`))
+		})
+
+		It("prints the backend service and certificate name of a custom lb with tls in json format", func() {
+			terraformManager.GetOutputsCall.Returns.Outputs["vault_backend_service"] = "some-vault-backend-service"
+
+			incomingState.LBs = []storage.LB{
+				{
+					Type:            "vault",
+					CertificateName: "vault-cert-some-guid",
+					Spec:            &storage.CustomLBSpec{Name: "vault", TLS: true},
+				},
+			}
+			err := command.Execute([]string{"--json"}, incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(`{
+				"iaas": "gcp",
+				"lbs": [
+					{
+						"type": "vault",
+						"certificate_name": "vault-cert-some-guid",
+						"load_balancers": [
+							{"component": "vault", "ip": "some-vault-lb-ip", "backend_service": "some-vault-backend-service"}
+						]
+					}
+				]
+			}`))
+		})
+
+		It("prints the name of the ssl certificate of the cf lb in json format", func() {
+			incomingState.EnvID = "some-env-id"
+			incomingState.LBs = []storage.LB{
+				{
+					Type:            "cf",
+					Cert:            "some-cert",
+					CertificateName: "cf-cert-some-guid",
+				},
+			}
+			err := command.Execute([]string{"--json"}, incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Receives.Message).To(ContainSubstring(`"certificate_name":"cf-cert-some-guid"`))
+
+			incomingState.LBs[0].CertificateName = ""
+			err = command.Execute([]string{"--json"}, incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Receives.Message).To(ContainSubstring(`"certificate_name":"some-env-id-cf-cert"`))
+		})
+
 		Context("failure cases", func() {
 			It("returns an error when terraform output provider fails", func() {
 				terraformManager.GetOutputsCall.Returns.Error = errors.New("failed to return terraform output")
@@ -127,9 +299,7 @@ var _ = Describe("GCPLBs", func() {
 			})
 
 			It("returns an nice error message when no lb type is found", func() {
-				incomingState.LB = storage.LB{
-					Type: "",
-				}
+				incomingState.LBs = nil
 				err := command.Execute([]string{}, incomingState)
 				Expect(err).To(MatchError("no lbs found"))
 			})
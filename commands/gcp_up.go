@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"strings"
 
 	"github.com/cloudfoundry/bosh-bootloader/bosh"
 	yaml "gopkg.in/yaml.v2"
@@ -17,6 +18,8 @@ var (
 	marshal = yaml.Marshal
 )
 
+const directorServiceAccountRoleHint = `creating the director service account requires the roles/resourcemanager.projectIamAdmin role, grant it to the service account of --gcp-service-account-key or rerun with "--gcp-no-director-service-account"`
+
 const (
 	DIRECTOR_USERNAME = "admin"
 )
@@ -30,16 +33,23 @@ type GCPUp struct {
 	logger             logger
 	terraformManager   terraformManager
 	envIDManager       envIDManager
+	zones              gcpZones
 }
 
 type GCPUpConfig struct {
-	ServiceAccountKey string
-	ProjectID         string
-	Zone              string
-	Region            string
-	OpsFilePath       string
-	Name              string
-	NoDirector        bool
+	ServiceAccountKey        string
+	ProjectID                string
+	Zone                     string
+	Region                   string
+	Zones                    []string
+	OpsFilePath              string
+	RuntimeConfigPath        string
+	CPIConfigPath            string
+	CloudConfigOpsFilePaths  []string
+	VMTypeCatalogPath        string
+	Name                     string
+	NoDirector               bool
+	NoDirectorServiceAccount bool
 }
 
 type gcpKeyPairCreator interface {
@@ -54,6 +64,10 @@ type gcpProvider interface {
 	SetConfig(string, string, string) error
 }
 
+type gcpZones interface {
+	Get(region string) ([]string, error)
+}
+
 type terraformManager interface {
 	Destroy(storage.State) (storage.State, error)
 	Apply(storage.State) (storage.State, error)
@@ -87,6 +101,7 @@ type NewGCPUpArgs struct {
 	Logger             logger
 	EnvIDManager       envIDManager
 	CloudConfigManager cloudConfigManager
+	Zones              gcpZones
 }
 
 func NewGCPUp(args NewGCPUpArgs) GCPUp {
@@ -99,6 +114,7 @@ func NewGCPUp(args NewGCPUpArgs) GCPUp {
 		cloudConfigManager: args.CloudConfigManager,
 		logger:             args.Logger,
 		envIDManager:       args.EnvIDManager,
+		zones:              args.Zones,
 	}
 }
 
@@ -131,6 +147,8 @@ func (u GCPUp) Execute(upConfig GCPUpConfig, state storage.State) error {
 			state.NoDirector = true
 		}
 
+		gcpDetails.Zones = state.GCP.Zones
+		gcpDetails.DirectorServiceAccount = state.GCP.DirectorServiceAccount
 		state.GCP = gcpDetails
 	}
 
@@ -142,6 +160,24 @@ func (u GCPUp) Execute(upConfig GCPUpConfig, state storage.State) error {
 		return err
 	}
 
+	state, err = syncGCPZones(u.zones, state, upConfig.Zones)
+	if err != nil {
+		return err
+	}
+
+	// New environments deploy the director with a dedicated service account
+	// so that the service account key stays out of the director manifest.
+	// Existing environments keep how their director was deployed.
+	if upConfig.NoDirectorServiceAccount {
+		if state.GCP.DirectorServiceAccount && !state.BOSH.IsEmpty() {
+			return errors.New(`Director service account already exists, you must re-create your environment to use "--gcp-no-director-service-account"`)
+		}
+
+		state.GCP.DirectorServiceAccount = false
+	} else if state.TFState == "" {
+		state.GCP.DirectorServiceAccount = true
+	}
+
 	state, err = u.envIDManager.Sync(state, upConfig.Name)
 	if err != nil {
 		return err
@@ -162,6 +198,10 @@ func (u GCPUp) Execute(upConfig GCPUpConfig, state storage.State) error {
 
 	state, err = u.terraformManager.Apply(state)
 	if err != nil {
+		if directorServiceAccountFailed(err) {
+			return fmt.Errorf("%s\n%s", handleTerraformError(err, u.stateStore), directorServiceAccountRoleHint)
+		}
+
 		return handleTerraformError(err, u.stateStore)
 	}
 
@@ -172,6 +212,17 @@ func (u GCPUp) Execute(upConfig GCPUpConfig, state storage.State) error {
 
 	if !state.NoDirector {
 		state.BOSH.UserOpsFile = string(opsFileContents)
+
+		state, err = readDirectorConfigs(state, directorConfigPaths{
+			runtimeConfig:       upConfig.RuntimeConfigPath,
+			cpiConfig:           upConfig.CPIConfigPath,
+			cloudConfigOpsFiles: upConfig.CloudConfigOpsFilePaths,
+			vmTypeCatalog:       upConfig.VMTypeCatalogPath,
+		})
+		if err != nil {
+			return err
+		}
+
 		state, err = u.boshManager.Create(state)
 		switch err.(type) {
 		case bosh.ManagerCreateError:
@@ -198,6 +249,8 @@ func (u GCPUp) Execute(upConfig GCPUpConfig, state storage.State) error {
 		}
 	}
 
+	warnExpiringCertificates(u.logger, state)
+
 	return nil
 }
 
@@ -213,9 +266,41 @@ func (u GCPUp) validateState(state storage.State) error {
 		return errors.New("GCP zone must be provided")
 	}
 
+	if _, ok := state.GetLB("cf"); ok && len(state.LBAllowedCIDRs) > 0 {
+		return errGCPCFLBAllowedCIDRs
+	}
+
 	return nil
 }
 
+// syncGCPZones sets the zones the environment is spread across. Zones passed
+// on the command line win, zones already in the state are kept so that the
+// AZs stay stable, and otherwise they are discovered from the region.
+func syncGCPZones(zones gcpZones, state storage.State, configZones []string) (storage.State, error) {
+	if len(configZones) > 0 {
+		for _, zone := range configZones {
+			if !strings.HasPrefix(zone, fmt.Sprintf("%s-", state.GCP.Region)) {
+				return storage.State{}, fmt.Errorf("zone %s is not in region %s", zone, state.GCP.Region)
+			}
+		}
+
+		state.GCP.Zones = configZones
+		return state, nil
+	}
+
+	if len(state.GCP.Zones) > 0 {
+		return state, nil
+	}
+
+	regionZones, err := zones.Get(state.GCP.Region)
+	if err != nil {
+		return storage.State{}, err
+	}
+
+	state.GCP.Zones = regionZones
+	return state, nil
+}
+
 func parseUpConfig(upConfig GCPUpConfig) (storage.GCP, []byte, error) {
 	if upConfig.ServiceAccountKey == "" {
 		return storage.GCP{}, []byte{}, errors.New("GCP service account key must be provided")
@@ -242,6 +327,23 @@ func parseUpConfig(upConfig GCPUpConfig) (storage.GCP, []byte, error) {
 	}, opsFileContents, nil
 }
 
+// directorServiceAccountFailed reports whether terraform failed to create the
+// director service account or to grant it its roles.
+func directorServiceAccountFailed(err error) bool {
+	terraformManagerError, ok := err.(terraformManagerError)
+	if !ok {
+		return false
+	}
+
+	state, bblStateErr := terraformManagerError.BBLState()
+	if bblStateErr != nil || !state.GCP.DirectorServiceAccount {
+		return false
+	}
+
+	return strings.Contains(state.LatestTFOutput, "google_service_account.bosh") ||
+		strings.Contains(state.LatestTFOutput, "google_project_iam_member.bosh-")
+}
+
 func (c GCPUpConfig) empty() bool {
 	return c.ServiceAccountKey == "" && c.ProjectID == "" && c.Region == "" && c.Zone == ""
 }
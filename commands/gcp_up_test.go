@@ -4,6 +4,7 @@ import (
 	"errors"
 	"io/ioutil"
 	"os"
+	"time"
 
 	compute "google.golang.org/api/compute/v1"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/testhelpers"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -37,6 +39,7 @@ var _ = Describe("GCPUp", func() {
 		boshManager           *fakes.BOSHManager
 		cloudConfigManager    *fakes.CloudConfigManager
 		envIDManager          *fakes.EnvIDManager
+		zones                 *fakes.Zones
 		logger                *fakes.Logger
 		terraformManagerError *fakes.TerraformManagerError
 
@@ -63,6 +66,7 @@ var _ = Describe("GCPUp", func() {
 		boshManager = &fakes.BOSHManager{}
 		terraformManager = &fakes.TerraformManager{}
 		envIDManager = &fakes.EnvIDManager{}
+		zones = &fakes.Zones{}
 		cloudConfigManager = &fakes.CloudConfigManager{}
 		terraformManagerError = &fakes.TerraformManagerError{}
 
@@ -81,6 +85,7 @@ var _ = Describe("GCPUp", func() {
 				ProjectID:         "some-project-id",
 				Zone:              "some-zone",
 				Region:            "us-west1",
+				Zones:             []string{"us-west1-a", "us-west1-b", "us-west1-c"},
 			},
 		}
 
@@ -119,6 +124,7 @@ var _ = Describe("GCPUp", func() {
 		keyPairManager.SyncCall.Returns.State = expectedKeyPairState
 		terraformManager.ApplyCall.Returns.BBLState = expectedTerraformState
 		boshManager.CreateCall.Returns.State = expectedBOSHState
+		zones.GetCall.Returns.Zones = []string{"us-west1-a", "us-west1-b", "us-west1-c"}
 
 		gcpUp = commands.NewGCPUp(commands.NewGCPUpArgs{
 			StateStore:         stateStore,
@@ -129,6 +135,7 @@ var _ = Describe("GCPUp", func() {
 			Logger:             logger,
 			EnvIDManager:       envIDManager,
 			CloudConfigManager: cloudConfigManager,
+			Zones:              zones,
 		})
 
 		body, err := ioutil.ReadFile("fixtures/terraform_template_no_lb.tf")
@@ -241,6 +248,7 @@ var _ = Describe("GCPUp", func() {
 					ProjectID:         "some-project-id",
 					Zone:              "some-zone",
 					Region:            "us-west1",
+					Zones:             []string{"us-west1-a", "us-west1-b", "us-west1-c"},
 				},
 			}))
 		})
@@ -323,6 +331,79 @@ var _ = Describe("GCPUp", func() {
 			Expect(cloudConfigManager.UpdateCall.Receives.State).To(Equal(expectedBOSHState))
 		})
 
+		Context("zones", func() {
+			It("discovers the zones of the region", func() {
+				err := gcpUp.Execute(commands.GCPUpConfig{
+					ServiceAccountKey: serviceAccountKeyPath,
+					ProjectID:         "some-project-id",
+					Zone:              "some-zone",
+					Region:            "us-west1",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(zones.GetCall.Receives.Region).To(Equal("us-west1"))
+				Expect(stateStore.SetCall.Receives[0].State.GCP.Zones).To(Equal([]string{"us-west1-a", "us-west1-b", "us-west1-c"}))
+			})
+
+			It("keeps the zones already stored in the state", func() {
+				err := gcpUp.Execute(commands.GCPUpConfig{
+					ServiceAccountKey: serviceAccountKeyPath,
+					ProjectID:         "some-project-id",
+					Zone:              "some-zone",
+					Region:            "us-west1",
+				}, storage.State{
+					GCP: storage.GCP{
+						Zones: []string{"us-west1-a", "us-west1-b"},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(zones.GetCall.CallCount).To(Equal(0))
+				Expect(stateStore.SetCall.Receives[0].State.GCP.Zones).To(Equal([]string{"us-west1-a", "us-west1-b"}))
+			})
+
+			It("uses the zones passed in", func() {
+				err := gcpUp.Execute(commands.GCPUpConfig{
+					ServiceAccountKey: serviceAccountKeyPath,
+					ProjectID:         "some-project-id",
+					Zone:              "some-zone",
+					Region:            "us-west1",
+					Zones:             []string{"us-west1-b", "us-west1-c"},
+				}, storage.State{
+					GCP: storage.GCP{
+						Zones: []string{"us-west1-a"},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(zones.GetCall.CallCount).To(Equal(0))
+				Expect(stateStore.SetCall.Receives[0].State.GCP.Zones).To(Equal([]string{"us-west1-b", "us-west1-c"}))
+			})
+
+			It("returns an error when a zone passed in is not in the region", func() {
+				err := gcpUp.Execute(commands.GCPUpConfig{
+					ServiceAccountKey: serviceAccountKeyPath,
+					ProjectID:         "some-project-id",
+					Zone:              "some-zone",
+					Region:            "us-west1",
+					Zones:             []string{"us-west1-a", "us-east1-b"},
+				}, storage.State{})
+				Expect(err).To(MatchError("zone us-east1-b is not in region us-west1"))
+			})
+
+			It("returns an error when the zones cannot be discovered", func() {
+				zones.GetCall.Returns.Error = errors.New("failed to get zones")
+
+				err := gcpUp.Execute(commands.GCPUpConfig{
+					ServiceAccountKey: serviceAccountKeyPath,
+					ProjectID:         "some-project-id",
+					Zone:              "some-zone",
+					Region:            "us-west1",
+				}, storage.State{})
+				Expect(err).To(MatchError("failed to get zones"))
+			})
+		})
+
 		Context("when a name is passed in for env-id", func() {
 			It("passes that name in for the env id manager to use", func() {
 				err := gcpUp.Execute(commands.GCPUpConfig{
@@ -362,6 +443,33 @@ var _ = Describe("GCPUp", func() {
 			})
 		})
 
+		Context("when a runtime config and cpi config are passed in", func() {
+			It("saves their contents to the state", func() {
+				runtimeConfig, err := ioutil.TempFile("", "runtime-config")
+				Expect(err).NotTo(HaveOccurred())
+				err = ioutil.WriteFile(runtimeConfig.Name(), []byte("some-runtime-config"), os.ModePerm)
+				Expect(err).NotTo(HaveOccurred())
+
+				cpiConfig, err := ioutil.TempFile("", "cpi-config")
+				Expect(err).NotTo(HaveOccurred())
+				err = ioutil.WriteFile(cpiConfig.Name(), []byte("some-cpi-config"), os.ModePerm)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = gcpUp.Execute(commands.GCPUpConfig{
+					ServiceAccountKey: serviceAccountKeyPath,
+					ProjectID:         "some-project-id",
+					Zone:              "some-zone",
+					Region:            "us-west1",
+					RuntimeConfigPath: runtimeConfig.Name(),
+					CPIConfigPath:     cpiConfig.Name(),
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(boshManager.CreateCall.Receives.State.RuntimeConfig).To(Equal("some-runtime-config"))
+				Expect(boshManager.CreateCall.Receives.State.CPIConfig).To(Equal("some-cpi-config"))
+			})
+		})
+
 		Context("when the no-director flag is provided", func() {
 			BeforeEach(func() {
 				terraformManager.ApplyCall.Returns.BBLState.NoDirector = true
@@ -405,6 +513,32 @@ var _ = Describe("GCPUp", func() {
 			})
 		})
 
+		Context("when the director certificate expires soon", func() {
+			BeforeEach(func() {
+				boshManager.CreateCall.Returns.State.BOSH.DirectorSSLCertificate = testhelpers.BBL_CERT
+
+				commands.SetNow(func() time.Time {
+					return time.Date(2018, time.May, 1, 0, 0, 0, 0, time.UTC)
+				})
+			})
+
+			AfterEach(func() {
+				commands.ResetNow()
+			})
+
+			It("prints a warning", func() {
+				err := gcpUp.Execute(commands.GCPUpConfig{
+					ServiceAccountKey: serviceAccountKeyPath,
+					ProjectID:         "some-project-id",
+					Zone:              "some-zone",
+					Region:            "us-west1",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.Messages).To(ContainElement("warning: director certificate expires on 2018-05-26T22:13:41Z, run `bbl certs` for details"))
+			})
+		})
+
 		Context("reentrance", func() {
 			var (
 				updatedServiceAccountKey     string
@@ -434,6 +568,89 @@ var _ = Describe("GCPUp", func() {
 				Expect(err).NotTo(HaveOccurred())
 			})
 
+			It("keeps the director service account of the environment", func() {
+				err := gcpUp.Execute(commands.GCPUpConfig{
+					ServiceAccountKey: serviceAccountKeyPath,
+					ProjectID:         "some-project-id",
+					Zone:              "some-zone",
+					Region:            "us-west1",
+				}, storage.State{
+					IAAS: "gcp",
+					GCP: storage.GCP{
+						ServiceAccountKey:      serviceAccountKey,
+						ProjectID:              "some-project-id",
+						Zone:                   "some-zone",
+						Region:                 "us-west1",
+						DirectorServiceAccount: true,
+					},
+					TFState: "existing-tf-state",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(terraformManager.ApplyCall.Receives.BBLState.GCP.DirectorServiceAccount).To(BeTrue())
+			})
+
+			It("creates a director service account for a new environment", func() {
+				err := gcpUp.Execute(commands.GCPUpConfig{
+					ServiceAccountKey: serviceAccountKeyPath,
+					ProjectID:         "some-project-id",
+					Zone:              "some-zone",
+					Region:            "us-west1",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(terraformManager.ApplyCall.Receives.BBLState.GCP.DirectorServiceAccount).To(BeTrue())
+			})
+
+			It("does not create a director service account when opted out", func() {
+				err := gcpUp.Execute(commands.GCPUpConfig{
+					ServiceAccountKey:        serviceAccountKeyPath,
+					ProjectID:                "some-project-id",
+					Zone:                     "some-zone",
+					Region:                   "us-west1",
+					NoDirectorServiceAccount: true,
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(terraformManager.ApplyCall.Receives.BBLState.GCP.DirectorServiceAccount).To(BeFalse())
+			})
+
+			It("returns an error when opting out after the director was deployed with a service account", func() {
+				err := gcpUp.Execute(commands.GCPUpConfig{NoDirectorServiceAccount: true}, storage.State{
+					IAAS: "gcp",
+					GCP: storage.GCP{
+						ServiceAccountKey:      serviceAccountKey,
+						ProjectID:              "some-project-id",
+						Zone:                   "some-zone",
+						Region:                 "us-west1",
+						DirectorServiceAccount: true,
+					},
+					BOSH: storage.BOSH{
+						DirectorName: "some-director",
+					},
+					TFState: "existing-tf-state",
+				})
+				Expect(err).To(MatchError(`Director service account already exists, you must re-create your environment to use "--gcp-no-director-service-account"`))
+
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+
+			It("does not add a director service account to an existing environment", func() {
+				err := gcpUp.Execute(commands.GCPUpConfig{}, storage.State{
+					IAAS: "gcp",
+					GCP: storage.GCP{
+						ServiceAccountKey: serviceAccountKey,
+						ProjectID:         "some-project-id",
+						Zone:              "some-zone",
+						Region:            "us-west1",
+					},
+					TFState: "existing-tf-state",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(terraformManager.ApplyCall.Receives.BBLState.GCP.DirectorServiceAccount).To(BeFalse())
+			})
+
 			It("should not store the state if the provided flags are not valid", func() {
 				err := gcpUp.Execute(
 					commands.GCPUpConfig{
@@ -639,6 +856,20 @@ var _ = Describe("GCPUp", func() {
 				}, "GCP region must be provided"),
 			)
 
+			It("returns an error when lb allowed cidrs are set with a cf lb attached", func() {
+				err := gcpUp.Execute(commands.GCPUpConfig{
+					ServiceAccountKey: serviceAccountKeyPath,
+					ProjectID:         "some-project-id",
+					Zone:              "some-zone",
+					Region:            "us-west1",
+				}, storage.State{
+					LBs:            []storage.LB{{Type: "cf"}},
+					LBAllowedCIDRs: []string{"10.0.0.0/8"},
+				})
+				Expect(err).To(MatchError("--lb-allowed-cidrs is not supported with the cf load balancer on gcp, its routers only receive traffic from google front ends"))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+
 			It("returns an error when setting config fails", func() {
 				gcpClientProvider.SetConfigCall.Returns.Error = errors.New("setting config failed")
 
@@ -744,6 +975,27 @@ var _ = Describe("GCPUp", func() {
 					Expect(stateStore.SetCall.CallCount).To(Equal(2))
 				})
 
+				It("explains the missing role when the director service account fails to be created", func() {
+					terraformManagerError.BBLStateCall.Returns.BBLState = storage.State{
+						GCP: storage.GCP{
+							DirectorServiceAccount: true,
+						},
+						TFState:        "some-updated-tf-state",
+						LatestTFOutput: "* google_project_iam_member.bosh-instance-admin: Error applying IAM policy for project",
+					}
+					terraformManager.ApplyCall.Returns.Error = terraformManagerError
+
+					err := gcpUp.Execute(commands.GCPUpConfig{
+						ServiceAccountKey: serviceAccountKeyPath,
+						ProjectID:         "some-project-id",
+						Zone:              "some-zone",
+						Region:            "us-west1",
+					}, storage.State{})
+
+					Expect(err).To(MatchError("failed to apply\ncreating the director service account requires the roles/resourcemanager.projectIamAdmin role, grant it to the service account of --gcp-service-account-key or rerun with \"--gcp-no-director-service-account\""))
+					Expect(stateStore.SetCall.Receives[2].State.TFState).To(Equal("some-updated-tf-state"))
+				})
+
 				It("returns an error if applier fails with non terraform manager apply error", func() {
 					terraformManager.ApplyCall.Returns.Error = errors.New("failed to apply")
 					err := gcpUp.Execute(commands.GCPUpConfig{
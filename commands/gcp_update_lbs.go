@@ -1,6 +1,11 @@
 package commands
 
-import "github.com/cloudfoundry/bosh-bootloader/storage"
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
 
 type GCPUpdateLBs struct {
 	gcpCreateLBs gcpCreateLBs
@@ -13,8 +18,22 @@ func NewGCPUpdateLBs(gcpCreateLBs gcpCreateLBs) GCPUpdateLBs {
 }
 
 func (g GCPUpdateLBs) Execute(config GCPCreateLBsConfig, state storage.State) error {
+	lb, _ := state.GetLB(config.LBType)
 	if config.Domain == "" {
-		config.Domain = state.LB.Domain
+		config.Domain = lb.Domain
+	}
+
+	// Keep the certificate name when the certificate is unchanged. Otherwise
+	// leave it empty so that a uniquely named certificate replaces the old one.
+	if config.CertPath != "" && lb.CertificateName != "" {
+		certificate, err := ioutil.ReadFile(config.CertPath)
+		if err != nil {
+			return err
+		}
+
+		if strings.TrimSpace(string(certificate)) == strings.TrimSpace(lb.Cert) {
+			config.CertificateName = lb.CertificateName
+		}
 	}
 
 	return g.gcpCreateLBs.Execute(config, state)
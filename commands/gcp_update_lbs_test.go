@@ -4,6 +4,7 @@ import (
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/testhelpers"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -23,11 +24,13 @@ var _ = Describe("GCP Update LBs", func() {
 
 		state = storage.State{
 			IAAS: "gcp",
-			LB: storage.LB{
-				Type:   "cf",
-				Cert:   "some-cert",
-				Key:    "some-key",
-				Domain: "some-domain",
+			LBs: []storage.LB{
+				{
+					Type:   "cf",
+					Cert:   "some-cert",
+					Key:    "some-key",
+					Domain: "some-domain",
+				},
 			},
 		}
 	})
@@ -74,5 +77,49 @@ var _ = Describe("GCP Update LBs", func() {
 				Expect(gcpCreateLBs.ExecuteCall.Receives.State).To(Equal(state))
 			})
 		})
+
+		Context("when the lb has a certificate name", func() {
+			BeforeEach(func() {
+				state.LBs[0].CertificateName = "cf-cert-some-guid"
+			})
+
+			It("keeps the certificate name when the certificate is unchanged", func() {
+				certPath, err := testhelpers.WriteContentsToTempFile("some-cert\n")
+				Expect(err).NotTo(HaveOccurred())
+
+				err = command.Execute(commands.GCPCreateLBsConfig{
+					CertPath: certPath,
+					KeyPath:  "some-key-path",
+					LBType:   "cf",
+				}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(gcpCreateLBs.ExecuteCall.Receives.Config.CertificateName).To(Equal("cf-cert-some-guid"))
+			})
+
+			It("leaves the certificate name empty when the certificate changes", func() {
+				certPath, err := testhelpers.WriteContentsToTempFile("some-new-cert")
+				Expect(err).NotTo(HaveOccurred())
+
+				err = command.Execute(commands.GCPCreateLBsConfig{
+					CertPath: certPath,
+					KeyPath:  "some-key-path",
+					LBType:   "cf",
+				}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(gcpCreateLBs.ExecuteCall.Receives.Config.CertificateName).To(BeEmpty())
+			})
+
+			It("returns an error when the certificate cannot be read", func() {
+				err := command.Execute(commands.GCPCreateLBsConfig{
+					CertPath: "/some/missing/cert",
+					KeyPath:  "some-key-path",
+					LBType:   "cf",
+				}, state)
+				Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+				Expect(gcpCreateLBs.ExecuteCall.CallCount).To(Equal(0))
+			})
+		})
 	})
 })
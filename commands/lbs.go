@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"strings"
+
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
@@ -49,5 +51,13 @@ func (c LBs) Execute(subcommandFlags []string, state storage.State) error {
 		}
 	}
 
+	if !isJSONFlag(subcommandFlags) {
+		if len(state.LBAllowedCIDRs) > 0 {
+			c.logger.Printf("LB allowed CIDRs: %s\n", strings.Join(state.LBAllowedCIDRs, ", "))
+		}
+
+		warnExpiringCertificates(c.logger, state)
+	}
+
 	return nil
 }
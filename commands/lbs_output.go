@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"encoding/json"
+)
+
+// LBsOutput is the schema printed by `bbl lbs --json`. It is the same for
+// every iaas and lb type; fields that do not apply are omitted.
+type LBsOutput struct {
+	IAAS         string     `json:"iaas"`
+	AllowedCIDRs []string   `json:"allowed_cidrs,omitempty"`
+	LBs          []LBOutput `json:"lbs"`
+}
+
+// LBOutput describes an attached lb type. A cf lb is made up of several
+// load balancers, one for each component it routes to.
+type LBOutput struct {
+	Type            string               `json:"type"`
+	Kind            string               `json:"kind,omitempty"`
+	Domain          string               `json:"domain,omitempty"`
+	DNSServers      []string             `json:"dns_servers,omitempty"`
+	CertificateName string               `json:"certificate_name,omitempty"`
+	LoadBalancers   []LoadBalancerOutput `json:"load_balancers"`
+}
+
+// LoadBalancerOutput describes a single load balancer. On aws it has a
+// name, a dns name and the target groups of application and network load
+// balancers. On gcp it has an ip and a target pool or backend service.
+type LoadBalancerOutput struct {
+	Component      string   `json:"component"`
+	Name           string   `json:"name,omitempty"`
+	DNSName        string   `json:"dns_name,omitempty"`
+	TargetGroups   []string `json:"target_groups,omitempty"`
+	IP             string   `json:"ip,omitempty"`
+	TargetPool     string   `json:"target_pool,omitempty"`
+	BackendService string   `json:"backend_service,omitempty"`
+}
+
+func printLBsJSON(logger logger, output LBsOutput) error {
+	lbsJSON, err := json.Marshal(output)
+	if err != nil {
+		// not tested
+		return err
+	}
+
+	logger.Println(string(lbsJSON))
+	return nil
+}
+
+func isJSONFlag(subcommandFlags []string) bool {
+	return len(subcommandFlags) > 0 && subcommandFlags[0] == "--json"
+}
+
+func stringOutput(outputs map[string]interface{}, name string) string {
+	value, _ := outputs[name].(string)
+	return value
+}
+
+func stringsOutput(outputs map[string]interface{}, names ...string) []string {
+	var values []string
+	for _, name := range names {
+		if value := stringOutput(outputs, name); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
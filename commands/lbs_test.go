@@ -2,10 +2,12 @@ package commands_test
 
 import (
 	"errors"
+	"time"
 
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/testhelpers"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -58,6 +60,68 @@ var _ = Describe("LBs", func() {
 			})
 		})
 
+		Context("when the lbs have allowed cidrs", func() {
+			It("prints the allowed cidrs", func() {
+				err := lbsCommand.Execute([]string{}, storage.State{
+					IAAS:           "aws",
+					LBAllowedCIDRs: []string{"10.0.0.0/8", "172.16.0.0/12"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintfCall.Messages).To(Equal([]string{
+					"LB allowed CIDRs: 10.0.0.0/8, 172.16.0.0/12\n",
+				}))
+			})
+
+			It("does not print the allowed cidrs when the json flag is provided", func() {
+				err := lbsCommand.Execute([]string{"--json"}, storage.State{
+					IAAS:           "aws",
+					LBAllowedCIDRs: []string{"10.0.0.0/8"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintfCall.Messages).To(BeEmpty())
+			})
+		})
+
+		Context("when an lb certificate expires soon", func() {
+			BeforeEach(func() {
+				commands.SetNow(func() time.Time {
+					return time.Date(2018, time.May, 1, 0, 0, 0, 0, time.UTC)
+				})
+			})
+
+			AfterEach(func() {
+				commands.ResetNow()
+			})
+
+			It("prints a warning", func() {
+				err := lbsCommand.Execute([]string{}, storage.State{
+					IAAS: "gcp",
+					LBs: []storage.LB{
+						{Type: "cf", Cert: testhelpers.BBL_CERT},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{
+					"warning: cf lb certificate expires on 2018-05-26T22:13:41Z, run `bbl certs` for details",
+				}))
+			})
+
+			It("does not print a warning when the json flag is provided", func() {
+				err := lbsCommand.Execute([]string{"--json"}, storage.State{
+					IAAS: "gcp",
+					LBs: []storage.LB{
+						{Type: "cf", Cert: testhelpers.BBL_CERT},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.CallCount).To(Equal(0))
+			})
+		})
+
 		Context("failure cases", func() {
 			It("returns an error when state validator fails", func() {
 				stateValidator.ValidateCall.Returns.Error = errors.New("state validator failed")
@@ -0,0 +1,405 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/helpers"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+const (
+	MigrateToTerraformCommand = "migrate-to-terraform"
+
+	// cloudFormationIAMUserPolicyName is the name of the inline policy the
+	// cloudformation stacks of bbl gave to the bosh user.
+	cloudFormationIAMUserPolicyName = "aws-cpi"
+)
+
+type stackMigrator interface {
+	GetPhysicalIDForResource(stackName, logicalResourceID string) (string, error)
+	DeleteRetainingResources(keyPairName string, azs []string, stackName, boshAZ, lbType, lbCertificateARN, envID string, directorAllowedCIDRs, lbAllowedCIDRs []string, natType string, tags map[string]string) error
+}
+
+type terraformImporter interface {
+	ValidateVersion() error
+	Import(bblState storage.State, resources map[string]string, createdResources []string) (storage.State, error)
+}
+
+type MigrateToTerraform struct {
+	credentialValidator       credentialValidator
+	stackMigrator             stackMigrator
+	terraformImporter         terraformImporter
+	boshManager               boshManager
+	certificateDescriber      certificateDescriber
+	certificateValidator      certificateValidator
+	availabilityZoneRetriever availabilityZoneRetriever
+	stateStore                stateStore
+	logger                    logger
+}
+
+type migrateToTerraformConfig struct {
+	certPath  string
+	keyPath   string
+	chainPath string
+}
+
+func NewMigrateToTerraform(credentialValidator credentialValidator, stackMigrator stackMigrator,
+	terraformImporter terraformImporter, boshManager boshManager, certificateDescriber certificateDescriber,
+	certificateValidator certificateValidator, availabilityZoneRetriever availabilityZoneRetriever,
+	stateStore stateStore, logger logger) MigrateToTerraform {
+
+	return MigrateToTerraform{
+		credentialValidator:       credentialValidator,
+		stackMigrator:             stackMigrator,
+		terraformImporter:         terraformImporter,
+		boshManager:               boshManager,
+		certificateDescriber:      certificateDescriber,
+		certificateValidator:      certificateValidator,
+		availabilityZoneRetriever: availabilityZoneRetriever,
+		stateStore:                stateStore,
+		logger:                    logger,
+	}
+}
+
+func (m MigrateToTerraform) Execute(subcommandFlags []string, state storage.State) error {
+	if state.IAAS != "aws" {
+		return errors.New("migrate-to-terraform is only supported on aws")
+	}
+
+	if state.Stack.Name == "" {
+		if state.TFState != "" {
+			return errors.New("environment is already managed by terraform")
+		}
+
+		return errors.New("environment has no cloudformation stack to migrate")
+	}
+
+	config, err := m.parseFlags(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	err = m.credentialValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = m.terraformImporter.ValidateVersion()
+	if err != nil {
+		return err
+	}
+
+	azs, err := state.AWS.AvailabilityZones(m.availabilityZoneRetriever)
+	if err != nil {
+		return err
+	}
+
+	var certificateARN string
+	if lbExists(state.Stack.LBType) {
+		certificate, err := m.certificateDescriber.Describe(state.Stack.CertificateName)
+		if err != nil {
+			return err
+		}
+		certificateARN = certificate.ARN
+	}
+
+	// A previous migration that failed after creating resources kept the lb
+	// and the tf state, the import finishes from them.
+	if lbExists(state.Stack.LBType) {
+		if _, ok := state.GetLB(state.Stack.LBType); !ok {
+			state, err = m.setLB(state, config)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	m.logger.Step(fmt.Sprintf("reading resources of cloudformation stack %q", state.Stack.Name))
+
+	physicalIDs := map[string]string{}
+	for _, logicalResourceID := range cloudFormationLogicalIDs(len(azs), state.AWS.NATType, state.Stack.LBType) {
+		physicalResourceID, err := m.stackMigrator.GetPhysicalIDForResource(state.Stack.Name, logicalResourceID)
+		if err != nil {
+			return err
+		}
+
+		physicalIDs[logicalResourceID] = physicalResourceID
+	}
+
+	state.AWS.MigratedFromCloudFormation = true
+	state.AWS.IAMUserName = physicalIDs["BOSHUser"]
+
+	state, err = m.terraformImporter.Import(state, cloudFormationResources(physicalIDs, state, len(azs)),
+		createdResources(state.Stack.LBType))
+	if err != nil {
+		return handleTerraformError(err, m.stateStore)
+	}
+
+	err = m.stateStore.Set(state)
+	if err != nil {
+		return err
+	}
+
+	// The director is moved onto the access key created by terraform while
+	// the one of the stack, which is deleted with it, still works.
+	if !state.NoDirector {
+		state, err = m.boshManager.Create(state)
+		switch err.(type) {
+		case bosh.ManagerCreateError:
+			bcErr := err.(bosh.ManagerCreateError)
+			if setErr := m.stateStore.Set(bcErr.State()); setErr != nil {
+				errorList := helpers.Errors{}
+				errorList.Add(err)
+				errorList.Add(setErr)
+				return errorList
+			}
+			return err
+		case error:
+			return err
+		}
+
+		err = m.stateStore.Set(state)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = m.stackMigrator.DeleteRetainingResources(state.KeyPair.Name, azs, state.Stack.Name, state.Stack.BOSHAZ,
+		state.Stack.LBType, certificateARN, state.EnvID, state.DirectorAllowedCIDRs, state.LBAllowedCIDRs, state.AWS.NATType, state.Tags)
+	if err != nil {
+		return err
+	}
+
+	state.Stack = storage.Stack{}
+
+	err = m.stateStore.Set(state)
+	if err != nil {
+		return err
+	}
+
+	m.logger.Step("migrated environment to terraform")
+
+	return nil
+}
+
+func (MigrateToTerraform) parseFlags(subcommandFlags []string) (migrateToTerraformConfig, error) {
+	migrateFlags := flags.New(MigrateToTerraformCommand)
+
+	config := migrateToTerraformConfig{}
+	migrateFlags.String(&config.certPath, "cert", "")
+	migrateFlags.String(&config.keyPath, "key", "")
+	migrateFlags.String(&config.chainPath, "chain", "")
+
+	if err := migrateFlags.Parse(subcommandFlags); err != nil {
+		return config, err
+	}
+
+	return config, nil
+}
+
+// setLB stores the certificate of the lb of the stack, which terraform has to
+// be given again because the private key cannot be read back from iam.
+func (m MigrateToTerraform) setLB(state storage.State, config migrateToTerraformConfig) (storage.State, error) {
+	err := m.certificateValidator.Validate(MigrateToTerraformCommand, config.certPath, config.keyPath, config.chainPath)
+	if err != nil {
+		return state, err
+	}
+
+	certContents, err := ioutil.ReadFile(config.certPath)
+	if err != nil {
+		return state, err
+	}
+
+	keyContents, err := ioutil.ReadFile(config.keyPath)
+	if err != nil {
+		return state, err
+	}
+
+	lb := storage.LB{
+		Type: state.Stack.LBType,
+		Cert: string(certContents),
+		Key:  string(keyContents),
+	}
+
+	if config.chainPath != "" {
+		chainContents, err := ioutil.ReadFile(config.chainPath)
+		if err != nil {
+			return state, err
+		}
+
+		lb.Chain = string(chainContents)
+	}
+
+	return state.SetLB(lb), nil
+}
+
+// cloudFormationLogicalIDs lists the logical ids of the resources in a bbl
+// cloudformation stack that have a counterpart in the terraform template.
+func cloudFormationLogicalIDs(azCount int, natType, lbType string) []string {
+	logicalIDs := []string{
+		"VPC", "VPCGatewayInternetGateway", "BOSHEIP", "BOSHUser", "BOSHSecurityGroup",
+		"BOSHSubnet", "BOSHRouteTable", "InternalSecurityGroup",
+	}
+
+	if natType != "gateway" {
+		logicalIDs = append(logicalIDs, "InternalRouteTable", "NATSecurityGroup", "NATInstance", "NATEIP")
+	}
+
+	if lbExists(lbType) {
+		logicalIDs = append(logicalIDs, "LoadBalancerRouteTable")
+	}
+
+	for i := 1; i <= azCount; i++ {
+		logicalIDs = append(logicalIDs, fmt.Sprintf("InternalSubnet%d", i))
+
+		if natType == "gateway" {
+			logicalIDs = append(logicalIDs,
+				fmt.Sprintf("InternalRouteTable%d", i),
+				fmt.Sprintf("NATSubnet%d", i),
+				fmt.Sprintf("NATEIP%d", i),
+				fmt.Sprintf("NATGateway%d", i),
+			)
+		}
+
+		if lbExists(lbType) {
+			logicalIDs = append(logicalIDs, fmt.Sprintf("LoadBalancerSubnet%d", i))
+		}
+	}
+
+	switch lbType {
+	case "cf":
+		logicalIDs = append(logicalIDs,
+			"CFRouterLoadBalancer", "CFRouterSecurityGroup", "CFRouterInternalSecurityGroup",
+			"CFSSHProxyLoadBalancer", "CFSSHProxySecurityGroup", "CFSSHProxyInternalSecurityGroup",
+		)
+	case "concourse":
+		logicalIDs = append(logicalIDs, "ConcourseLoadBalancer", "ConcourseSecurityGroup", "ConcourseInternalSecurityGroup")
+	}
+
+	return logicalIDs
+}
+
+// cloudFormationResources maps the terraform resource addresses of the
+// template to the import ids of their counterparts in the stack, given the
+// physical ids of the stack resources by logical id.
+func cloudFormationResources(physicalIDs map[string]string, state storage.State, azCount int) map[string]string {
+	internalSecurityGroup := physicalIDs["InternalSecurityGroup"]
+	boshSecurityGroup := physicalIDs["BOSHSecurityGroup"]
+
+	directorAllowedCIDRs := state.DirectorAllowedCIDRs
+	if len(directorAllowedCIDRs) == 0 {
+		directorAllowedCIDRs = []string{"0.0.0.0/0"}
+	}
+
+	resources := map[string]string{
+		"aws_vpc.vpc":                                physicalIDs["VPC"],
+		"aws_internet_gateway.ig":                    physicalIDs["VPCGatewayInternetGateway"],
+		"aws_eip.bosh_eip":                           physicalIDs["BOSHEIP"],
+		"aws_iam_user.bosh":                          physicalIDs["BOSHUser"],
+		"aws_security_group.bosh_security_group":     boshSecurityGroup,
+		"aws_subnet.bosh_subnet":                     physicalIDs["BOSHSubnet"],
+		"aws_route_table.bosh_route_table":           physicalIDs["BOSHRouteTable"],
+		"aws_security_group.internal_security_group": internalSecurityGroup,
+	}
+
+	resources["aws_iam_user_policy.bosh"] = fmt.Sprintf("%s:%s", physicalIDs["BOSHUser"], cloudFormationIAMUserPolicyName)
+	resources["aws_route_table_association.route_bosh_subnets"] = routeTableAssociationID(physicalIDs["BOSHSubnet"], physicalIDs["BOSHRouteTable"])
+
+	resources["aws_security_group_rule.internal_security_group_rule_tcp"] = securityGroupRuleID(internalSecurityGroup, "ingress", "tcp", 0, 65535, "self")
+	resources["aws_security_group_rule.internal_security_group_rule_udp"] = securityGroupRuleID(internalSecurityGroup, "ingress", "udp", 0, 65535, "self")
+	resources["aws_security_group_rule.internal_security_group_rule_icmp"] = securityGroupRuleID(internalSecurityGroup, "ingress", "icmp", -1, -1, "0.0.0.0/0")
+	resources["aws_security_group_rule.internal_security_group_rule_allow_internet"] = securityGroupRuleID(internalSecurityGroup, "egress", "all", 0, 0, "0.0.0.0/0")
+	resources["aws_security_group_rule.bosh_internal_security_rule_tcp"] = securityGroupRuleID(internalSecurityGroup, "ingress", "tcp", 0, 65535, boshSecurityGroup)
+	resources["aws_security_group_rule.bosh_internal_security_rule_udp"] = securityGroupRuleID(internalSecurityGroup, "ingress", "udp", 0, 65535, boshSecurityGroup)
+	resources["aws_security_group_rule.bosh_security_group_rule_tcp_ssh"] = securityGroupRuleID(boshSecurityGroup, "ingress", "tcp", 22, 22, directorAllowedCIDRs...)
+	resources["aws_security_group_rule.bosh_security_group_rule_tcp_bosh_agent"] = securityGroupRuleID(boshSecurityGroup, "ingress", "tcp", 6868, 6868, directorAllowedCIDRs...)
+	resources["aws_security_group_rule.bosh_security_group_rule_tcp_director_api"] = securityGroupRuleID(boshSecurityGroup, "ingress", "tcp", 25555, 25555, directorAllowedCIDRs...)
+	resources["aws_security_group_rule.bosh_security_group_rule_tcp"] = securityGroupRuleID(boshSecurityGroup, "ingress", "tcp", 0, 65535, internalSecurityGroup)
+	resources["aws_security_group_rule.bosh_security_group_rule_udp"] = securityGroupRuleID(boshSecurityGroup, "ingress", "udp", 0, 65535, internalSecurityGroup)
+	resources["aws_security_group_rule.bosh_security_group_rule_allow_internet"] = securityGroupRuleID(boshSecurityGroup, "egress", "all", 0, 0, "0.0.0.0/0")
+
+	if state.AWS.NATType != "gateway" {
+		resources["aws_route_table.internal_route_table"] = physicalIDs["InternalRouteTable"]
+		resources["aws_security_group.nat_security_group"] = physicalIDs["NATSecurityGroup"]
+		resources["aws_instance.nat"] = physicalIDs["NATInstance"]
+		resources["aws_eip.nat_eip"] = physicalIDs["NATEIP"]
+	}
+
+	if lbExists(state.Stack.LBType) {
+		resources["aws_route_table.lb_route_table"] = physicalIDs["LoadBalancerRouteTable"]
+	}
+
+	for i := 0; i < azCount; i++ {
+		internalSubnet := physicalIDs[fmt.Sprintf("InternalSubnet%d", i+1)]
+		resources[fmt.Sprintf("aws_subnet.internal_subnets[%d]", i)] = internalSubnet
+
+		if state.AWS.NATType == "gateway" {
+			internalRouteTable := physicalIDs[fmt.Sprintf("InternalRouteTable%d", i+1)]
+			natSubnet := physicalIDs[fmt.Sprintf("NATSubnet%d", i+1)]
+
+			resources[fmt.Sprintf("aws_route_table.internal_route_tables[%d]", i)] = internalRouteTable
+			resources[fmt.Sprintf("aws_route_table_association.route_internal_subnets[%d]", i)] = routeTableAssociationID(internalSubnet, internalRouteTable)
+			resources[fmt.Sprintf("aws_subnet.nat_subnets[%d]", i)] = natSubnet
+			resources[fmt.Sprintf("aws_route_table_association.route_nat_subnets[%d]", i)] = routeTableAssociationID(natSubnet, physicalIDs["BOSHRouteTable"])
+			resources[fmt.Sprintf("aws_eip.nat_eips[%d]", i)] = physicalIDs[fmt.Sprintf("NATEIP%d", i+1)]
+			resources[fmt.Sprintf("aws_nat_gateway.nat[%d]", i)] = physicalIDs[fmt.Sprintf("NATGateway%d", i+1)]
+		} else {
+			resources[fmt.Sprintf("aws_route_table_association.route_internal_subnets[%d]", i)] = routeTableAssociationID(internalSubnet, physicalIDs["InternalRouteTable"])
+		}
+
+		if lbExists(state.Stack.LBType) {
+			lbSubnet := physicalIDs[fmt.Sprintf("LoadBalancerSubnet%d", i+1)]
+
+			resources[fmt.Sprintf("aws_subnet.lb_subnets[%d]", i)] = lbSubnet
+			resources[fmt.Sprintf("aws_route_table_association.route_lb_subnets[%d]", i)] = routeTableAssociationID(lbSubnet, physicalIDs["LoadBalancerRouteTable"])
+		}
+	}
+
+	switch state.Stack.LBType {
+	case "cf":
+		resources["aws_iam_server_certificate.cf_lb_cert"] = state.Stack.CertificateName
+		resources["aws_elb.cf_router_lb"] = physicalIDs["CFRouterLoadBalancer"]
+		resources["aws_security_group.cf_router_lb_security_group"] = physicalIDs["CFRouterSecurityGroup"]
+		resources["aws_security_group.cf_router_lb_internal_security_group"] = physicalIDs["CFRouterInternalSecurityGroup"]
+		resources["aws_elb.cf_ssh_lb"] = physicalIDs["CFSSHProxyLoadBalancer"]
+		resources["aws_security_group.cf_ssh_lb_security_group"] = physicalIDs["CFSSHProxySecurityGroup"]
+		resources["aws_security_group.cf_ssh_lb_internal_security_group"] = physicalIDs["CFSSHProxyInternalSecurityGroup"]
+	case "concourse":
+		resources["aws_iam_server_certificate.concourse_lb_cert"] = state.Stack.CertificateName
+		resources["aws_elb.concourse_lb"] = physicalIDs["ConcourseLoadBalancer"]
+		resources["aws_security_group.concourse_lb_security_group"] = physicalIDs["ConcourseSecurityGroup"]
+		resources["aws_security_group.concourse_lb_internal_security_group"] = physicalIDs["ConcourseInternalSecurityGroup"]
+	}
+
+	return resources
+}
+
+// createdResources lists the resources of the terraform template that the
+// stack has no counterpart for. The access key of the bosh user cannot be
+// imported since its secret cannot be read back.
+func createdResources(lbType string) []string {
+	resources := []string{"aws_iam_access_key.bosh"}
+
+	if lbType == "cf" {
+		resources = append(resources,
+			"aws_security_group.cf_tcp_lb_security_group",
+			"aws_security_group.cf_tcp_lb_internal_security_group",
+			"aws_elb.cf_tcp_lb",
+		)
+	}
+
+	return resources
+}
+
+func routeTableAssociationID(subnetID, routeTableID string) string {
+	return fmt.Sprintf("%s/%s", subnetID, routeTableID)
+}
+
+func securityGroupRuleID(securityGroupID, ruleType, protocol string, fromPort, toPort int, sources ...string) string {
+	return fmt.Sprintf("%s_%s_%s_%d_%d_%s", securityGroupID, ruleType, protocol, fromPort, toPort, strings.Join(sources, "_"))
+}
@@ -0,0 +1,452 @@
+package commands_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws/iam"
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	awsterraform "github.com/cloudfoundry/bosh-bootloader/terraform/aws"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MigrateToTerraform", func() {
+	var (
+		credentialValidator       *fakes.CredentialValidator
+		infrastructureManager     *fakes.InfrastructureManager
+		terraformManager          *fakes.TerraformManager
+		boshManager               *fakes.BOSHManager
+		certificateDescriber      *fakes.CertificateDescriber
+		certificateValidator      *fakes.CertificateValidator
+		availabilityZoneRetriever *fakes.AvailabilityZoneRetriever
+		stateStore                *fakes.StateStore
+		logger                    *fakes.Logger
+
+		command       commands.MigrateToTerraform
+		incomingState storage.State
+	)
+
+	BeforeEach(func() {
+		credentialValidator = &fakes.CredentialValidator{}
+		infrastructureManager = &fakes.InfrastructureManager{}
+		terraformManager = &fakes.TerraformManager{}
+		boshManager = &fakes.BOSHManager{}
+		certificateDescriber = &fakes.CertificateDescriber{}
+		certificateValidator = &fakes.CertificateValidator{}
+		availabilityZoneRetriever = &fakes.AvailabilityZoneRetriever{}
+		stateStore = &fakes.StateStore{}
+		logger = &fakes.Logger{}
+
+		availabilityZoneRetriever.RetrieveCall.Returns.AZs = []string{"some-zone-1", "some-zone-2"}
+		infrastructureManager.GetPhysicalIDForResourceCall.Stub = func(stackName, logicalResourceID string) (string, error) {
+			return "physical-" + logicalResourceID, nil
+		}
+
+		incomingState = storage.State{
+			IAAS:    "aws",
+			EnvID:   "some-env-id",
+			KeyPair: storage.KeyPair{Name: "some-keypair-name"},
+			AWS:     storage.AWS{Region: "some-region"},
+			Stack: storage.Stack{
+				Name:   "some-stack-name",
+				BOSHAZ: "some-bosh-az",
+			},
+			DirectorAllowedCIDRs: []string{"10.1.0.0/16", "10.2.0.0/16"},
+		}
+
+		terraformManager.ImportCall.Returns.BBLState = incomingState
+		terraformManager.ImportCall.Returns.BBLState.TFState = "some-tf-state"
+
+		command = commands.NewMigrateToTerraform(credentialValidator, infrastructureManager, terraformManager, boshManager,
+			certificateDescriber, certificateValidator, availabilityZoneRetriever, stateStore, logger)
+	})
+
+	Describe("Execute", func() {
+		It("imports the resources of the stack into terraform", func() {
+			err := command.Execute([]string{}, incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(credentialValidator.ValidateCall.CallCount).To(Equal(1))
+			Expect(terraformManager.ValidateVersionCall.CallCount).To(Equal(1))
+
+			Expect(infrastructureManager.GetPhysicalIDForResourceCall.Receives.StackName).To(Equal("some-stack-name"))
+
+			resources := terraformManager.ImportCall.Receives.Resources
+			Expect(resources).To(HaveKeyWithValue("aws_vpc.vpc", "physical-VPC"))
+			Expect(resources).To(HaveKeyWithValue("aws_iam_user.bosh", "physical-BOSHUser"))
+			Expect(resources).To(HaveKeyWithValue("aws_subnet.internal_subnets[0]", "physical-InternalSubnet1"))
+			Expect(resources).To(HaveKeyWithValue("aws_subnet.internal_subnets[1]", "physical-InternalSubnet2"))
+			Expect(resources).NotTo(HaveKey("aws_subnet.internal_subnets[2]"))
+			Expect(resources).To(HaveKeyWithValue("aws_instance.nat", "physical-NATInstance"))
+		})
+
+		It("imports the dependent resources with the import ids terraform expects", func() {
+			err := command.Execute([]string{}, incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			resources := terraformManager.ImportCall.Receives.Resources
+			Expect(resources).To(HaveKeyWithValue("aws_iam_user_policy.bosh", "physical-BOSHUser:aws-cpi"))
+			Expect(resources).To(HaveKeyWithValue("aws_route_table_association.route_bosh_subnets", "physical-BOSHSubnet/physical-BOSHRouteTable"))
+			Expect(resources).To(HaveKeyWithValue("aws_route_table_association.route_internal_subnets[1]", "physical-InternalSubnet2/physical-InternalRouteTable"))
+			Expect(resources).To(HaveKeyWithValue("aws_security_group_rule.internal_security_group_rule_tcp",
+				"physical-InternalSecurityGroup_ingress_tcp_0_65535_self"))
+			Expect(resources).To(HaveKeyWithValue("aws_security_group_rule.internal_security_group_rule_icmp",
+				"physical-InternalSecurityGroup_ingress_icmp_-1_-1_0.0.0.0/0"))
+			Expect(resources).To(HaveKeyWithValue("aws_security_group_rule.bosh_internal_security_rule_udp",
+				"physical-InternalSecurityGroup_ingress_udp_0_65535_physical-BOSHSecurityGroup"))
+			Expect(resources).To(HaveKeyWithValue("aws_security_group_rule.bosh_security_group_rule_tcp_director_api",
+				"physical-BOSHSecurityGroup_ingress_tcp_25555_25555_10.1.0.0/16_10.2.0.0/16"))
+			Expect(resources).To(HaveKeyWithValue("aws_security_group_rule.bosh_security_group_rule_allow_internet",
+				"physical-BOSHSecurityGroup_egress_all_0_0_0.0.0.0/0"))
+		})
+
+		It("creates the access key of the bosh user instead of importing it", func() {
+			err := command.Execute([]string{}, incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(terraformManager.ImportCall.Receives.Resources).NotTo(HaveKey("aws_iam_access_key.bosh"))
+			Expect(terraformManager.ImportCall.Receives.CreatedResources).To(Equal([]string{"aws_iam_access_key.bosh"}))
+		})
+
+		It("keeps the name of the iam user and the tags the stack gave to resources", func() {
+			err := command.Execute([]string{}, incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(terraformManager.ImportCall.Receives.BBLState.AWS.MigratedFromCloudFormation).To(BeTrue())
+			Expect(terraformManager.ImportCall.Receives.BBLState.AWS.IAMUserName).To(Equal("physical-BOSHUser"))
+		})
+
+		It("redeploys the director with the new access key before removing the stack", func() {
+			err := command.Execute([]string{}, incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(boshManager.CreateCall.CallCount).To(Equal(1))
+			Expect(boshManager.CreateCall.Receives.State.TFState).To(Equal("some-tf-state"))
+			Expect(boshManager.CreateCall.Receives.State.Stack.Name).To(Equal("some-stack-name"))
+		})
+
+		It("removes the stack while retaining its resources", func() {
+			err := command.Execute([]string{}, incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(infrastructureManager.DeleteRetainingResourcesCall.CallCount).To(Equal(1))
+			Expect(infrastructureManager.DeleteRetainingResourcesCall.Receives.KeyPairName).To(Equal("some-keypair-name"))
+			Expect(infrastructureManager.DeleteRetainingResourcesCall.Receives.AZs).To(Equal([]string{"some-zone-1", "some-zone-2"}))
+			Expect(infrastructureManager.DeleteRetainingResourcesCall.Receives.StackName).To(Equal("some-stack-name"))
+			Expect(infrastructureManager.DeleteRetainingResourcesCall.Receives.BOSHAZ).To(Equal("some-bosh-az"))
+			Expect(infrastructureManager.DeleteRetainingResourcesCall.Receives.EnvID).To(Equal("some-env-id"))
+			Expect(infrastructureManager.DeleteRetainingResourcesCall.Receives.DirectorAllowedCIDRs).To(Equal([]string{"10.1.0.0/16", "10.2.0.0/16"}))
+		})
+
+		It("saves the tf state before removing the stack and forgets the stack afterwards", func() {
+			err := command.Execute([]string{}, incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stateStore.SetCall.CallCount).To(Equal(3))
+
+			Expect(stateStore.SetCall.Receives[0].State.TFState).To(Equal("some-tf-state"))
+			Expect(stateStore.SetCall.Receives[0].State.Stack.Name).To(Equal("some-stack-name"))
+
+			Expect(stateStore.SetCall.Receives[2].State.TFState).To(Equal("some-tf-state"))
+			Expect(stateStore.SetCall.Receives[2].State.Stack).To(Equal(storage.Stack{}))
+
+			Expect(logger.StepCall.Messages).To(ContainElement("migrated environment to terraform"))
+		})
+
+		Context("when the stack uses nat gateways", func() {
+			BeforeEach(func() {
+				incomingState.AWS.NATType = "gateway"
+			})
+
+			It("imports the nat gateway of every zone", func() {
+				err := command.Execute([]string{}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				resources := terraformManager.ImportCall.Receives.Resources
+				Expect(resources).To(HaveKeyWithValue("aws_nat_gateway.nat[1]", "physical-NATGateway2"))
+				Expect(resources).To(HaveKeyWithValue("aws_eip.nat_eips[1]", "physical-NATEIP2"))
+				Expect(resources).To(HaveKeyWithValue("aws_subnet.nat_subnets[1]", "physical-NATSubnet2"))
+				Expect(resources).To(HaveKeyWithValue("aws_route_table.internal_route_tables[1]", "physical-InternalRouteTable2"))
+				Expect(resources).To(HaveKeyWithValue("aws_route_table_association.route_nat_subnets[1]", "physical-NATSubnet2/physical-BOSHRouteTable"))
+				Expect(resources).To(HaveKeyWithValue("aws_route_table_association.route_internal_subnets[1]", "physical-InternalSubnet2/physical-InternalRouteTable2"))
+				Expect(resources).NotTo(HaveKey("aws_instance.nat"))
+
+				Expect(infrastructureManager.DeleteRetainingResourcesCall.Receives.NATType).To(Equal("gateway"))
+			})
+		})
+
+		Context("when an lb is attached", func() {
+			var certPath, keyPath, chainPath string
+
+			BeforeEach(func() {
+				tempDir, err := ioutil.TempDir("", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				certPath = filepath.Join(tempDir, "cert.pem")
+				keyPath = filepath.Join(tempDir, "key.pem")
+				chainPath = filepath.Join(tempDir, "chain.pem")
+				Expect(ioutil.WriteFile(certPath, []byte("some-cert"), os.ModePerm)).To(Succeed())
+				Expect(ioutil.WriteFile(keyPath, []byte("some-key"), os.ModePerm)).To(Succeed())
+				Expect(ioutil.WriteFile(chainPath, []byte("some-chain"), os.ModePerm)).To(Succeed())
+
+				incomingState.Stack.LBType = "cf"
+				incomingState.Stack.CertificateName = "some-certificate-name"
+				certificateDescriber.DescribeCall.Returns.Certificate = iam.Certificate{ARN: "some-certificate-arn"}
+			})
+
+			It("imports the lb and its certificate", func() {
+				err := command.Execute([]string{"--cert", certPath, "--key", keyPath, "--chain", chainPath}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(certificateValidator.ValidateCall.Receives.Command).To(Equal("migrate-to-terraform"))
+				Expect(certificateValidator.ValidateCall.Receives.CertificatePath).To(Equal(certPath))
+				Expect(terraformManager.ImportCall.Receives.BBLState.LBs).To(Equal([]storage.LB{{
+					Type:  "cf",
+					Cert:  "some-cert",
+					Key:   "some-key",
+					Chain: "some-chain",
+				}}))
+
+				resources := terraformManager.ImportCall.Receives.Resources
+				Expect(resources).To(HaveKeyWithValue("aws_iam_server_certificate.cf_lb_cert", "some-certificate-name"))
+				Expect(resources).To(HaveKeyWithValue("aws_elb.cf_router_lb", "physical-CFRouterLoadBalancer"))
+				Expect(resources).To(HaveKeyWithValue("aws_security_group.cf_ssh_lb_internal_security_group", "physical-CFSSHProxyInternalSecurityGroup"))
+				Expect(resources).To(HaveKeyWithValue("aws_route_table_association.route_lb_subnets[1]", "physical-LoadBalancerSubnet2/physical-LoadBalancerRouteTable"))
+
+				Expect(terraformManager.ImportCall.Receives.CreatedResources).To(ContainElement("aws_elb.cf_tcp_lb"))
+			})
+
+			It("keeps the lb in the stack until it is removed", func() {
+				err := command.Execute([]string{"--cert", certPath, "--key", keyPath}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(certificateDescriber.DescribeCall.Receives.CertificateName).To(Equal("some-certificate-name"))
+				Expect(infrastructureManager.DeleteRetainingResourcesCall.Receives.LBType).To(Equal("cf"))
+				Expect(infrastructureManager.DeleteRetainingResourcesCall.Receives.LBCertificateARN).To(Equal("some-certificate-arn"))
+			})
+
+			It("returns an error when the certificate is not valid", func() {
+				certificateValidator.ValidateCall.Returns.Error = errors.New("failed to validate certificate")
+
+				err := command.Execute([]string{}, incomingState)
+				Expect(err).To(MatchError("failed to validate certificate"))
+				Expect(terraformManager.ImportCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the certificate cannot be described", func() {
+				certificateDescriber.DescribeCall.Returns.Error = errors.New("failed to describe certificate")
+
+				err := command.Execute([]string{"--cert", certPath, "--key", keyPath}, incomingState)
+				Expect(err).To(MatchError("failed to describe certificate"))
+			})
+		})
+
+		DescribeTable("covers every resource of the rendered terraform template",
+			func(natType, lbType string) {
+				incomingState.AWS.NATType = natType
+				incomingState.Stack.LBType = lbType
+
+				args := []string{}
+				if lbType != "" {
+					tempDir, err := ioutil.TempDir("", "")
+					Expect(err).NotTo(HaveOccurred())
+
+					certPath := filepath.Join(tempDir, "cert.pem")
+					keyPath := filepath.Join(tempDir, "key.pem")
+					Expect(ioutil.WriteFile(certPath, []byte("some-cert"), os.ModePerm)).To(Succeed())
+					Expect(ioutil.WriteFile(keyPath, []byte("some-key"), os.ModePerm)).To(Succeed())
+
+					args = []string{"--cert", certPath, "--key", keyPath}
+				}
+
+				err := command.Execute(args, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				template := awsterraform.NewTemplateGenerator().Generate(terraformManager.ImportCall.Receives.BBLState)
+
+				addresses := map[string]bool{}
+				for address := range terraformManager.ImportCall.Receives.Resources {
+					addresses[address] = true
+				}
+				for _, address := range terraformManager.ImportCall.Receives.CreatedResources {
+					addresses[address] = true
+				}
+
+				templateAddresses := templateResourceAddresses(template, 2)
+				Expect(templateAddresses).NotTo(BeEmpty())
+				for _, address := range templateAddresses {
+					Expect(addresses).To(HaveKey(address))
+				}
+				Expect(addresses).To(HaveLen(len(templateAddresses)))
+			},
+			Entry("with a nat instance", "", ""),
+			Entry("with nat gateways", "gateway", ""),
+			Entry("with a cf lb", "", "cf"),
+			Entry("with a concourse lb", "gateway", "concourse"),
+		)
+
+		Context("when the environment has no director", func() {
+			BeforeEach(func() {
+				incomingState.NoDirector = true
+				terraformManager.ImportCall.Returns.BBLState.NoDirector = true
+			})
+
+			It("does not deploy a director", func() {
+				err := command.Execute([]string{}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(boshManager.CreateCall.CallCount).To(Equal(0))
+				Expect(infrastructureManager.DeleteRetainingResourcesCall.CallCount).To(Equal(1))
+			})
+		})
+
+		Context("when a previous migration kept the tf state", func() {
+			BeforeEach(func() {
+				incomingState.TFState = "some-kept-tf-state"
+				incomingState.Stack.LBType = "concourse"
+				incomingState.LBs = []storage.LB{{Type: "concourse", Cert: "some-cert", Key: "some-key"}}
+			})
+
+			It("finishes the import from it without asking for the lb certificate again", func() {
+				err := command.Execute([]string{}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(certificateValidator.ValidateCall.CallCount).To(Equal(0))
+				Expect(terraformManager.ImportCall.Receives.BBLState.TFState).To(Equal("some-kept-tf-state"))
+				Expect(terraformManager.ImportCall.Receives.BBLState.LBs).To(Equal(incomingState.LBs))
+				Expect(boshManager.CreateCall.CallCount).To(Equal(1))
+				Expect(infrastructureManager.DeleteRetainingResourcesCall.CallCount).To(Equal(1))
+			})
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the iaas is not aws", func() {
+				err := command.Execute([]string{}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError("migrate-to-terraform is only supported on aws"))
+			})
+
+			It("returns an error when the environment is already managed by terraform", func() {
+				err := command.Execute([]string{}, storage.State{IAAS: "aws", TFState: "some-tf-state"})
+				Expect(err).To(MatchError("environment is already managed by terraform"))
+			})
+
+			It("returns an error when there is no stack", func() {
+				err := command.Execute([]string{}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError("environment has no cloudformation stack to migrate"))
+			})
+
+			It("returns an error when the flags cannot be parsed", func() {
+				err := command.Execute([]string{"--unknown-flag"}, incomingState)
+				Expect(err).To(MatchError("flag provided but not defined: -unknown-flag"))
+			})
+
+			It("returns an error when the credential validator fails", func() {
+				credentialValidator.ValidateCall.Returns.Error = errors.New("failed to validate credentials")
+
+				err := command.Execute([]string{}, incomingState)
+				Expect(err).To(MatchError("failed to validate credentials"))
+			})
+
+			It("returns an error when the terraform version is not supported", func() {
+				terraformManager.ValidateVersionCall.Returns.Error = errors.New("failed to validate version")
+
+				err := command.Execute([]string{}, incomingState)
+				Expect(err).To(MatchError("failed to validate version"))
+			})
+
+			It("returns an error when the physical id of a resource cannot be found", func() {
+				infrastructureManager.GetPhysicalIDForResourceCall.Stub = nil
+				infrastructureManager.GetPhysicalIDForResourceCall.Returns.Error = errors.New("failed to describe resource")
+
+				err := command.Execute([]string{}, incomingState)
+				Expect(err).To(MatchError("failed to describe resource"))
+			})
+
+			It("leaves the stack in place when the import fails", func() {
+				terraformManager.ImportCall.Returns.Error = errors.New("imported infrastructure does not match the terraform template")
+
+				err := command.Execute([]string{}, incomingState)
+				Expect(err).To(MatchError("imported infrastructure does not match the terraform template"))
+
+				Expect(stateStore.SetCall.CallCount).To(Equal(0))
+				Expect(boshManager.CreateCall.CallCount).To(Equal(0))
+				Expect(infrastructureManager.DeleteRetainingResourcesCall.CallCount).To(Equal(0))
+			})
+
+			It("saves the tf state and leaves the stack in place when the import fails after creating resources", func() {
+				managerError := &fakes.TerraformManagerError{}
+				managerError.ErrorCall.Returns = "imported infrastructure does not match the terraform template"
+				managerError.BBLStateCall.Returns.BBLState = storage.State{TFState: "some-partial-tf-state"}
+				terraformManager.ImportCall.Returns.Error = managerError
+
+				err := command.Execute([]string{}, incomingState)
+				Expect(err).To(MatchError("imported infrastructure does not match the terraform template"))
+
+				Expect(stateStore.SetCall.CallCount).To(Equal(1))
+				Expect(stateStore.SetCall.Receives[0].State.TFState).To(Equal("some-partial-tf-state"))
+				Expect(boshManager.CreateCall.CallCount).To(Equal(0))
+				Expect(infrastructureManager.DeleteRetainingResourcesCall.CallCount).To(Equal(0))
+			})
+
+			It("saves the bosh state and leaves the stack in place when the director cannot be redeployed", func() {
+				expectedState := incomingState
+				expectedState.TFState = "some-tf-state"
+				boshManager.CreateCall.Returns.Error = bosh.NewManagerCreateError(expectedState, errors.New("failed to create"))
+
+				err := command.Execute([]string{}, incomingState)
+				Expect(err).To(MatchError("failed to create"))
+
+				Expect(stateStore.SetCall.CallCount).To(Equal(2))
+				Expect(stateStore.SetCall.Receives[1].State).To(Equal(expectedState))
+				Expect(infrastructureManager.DeleteRetainingResourcesCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the director cannot be redeployed", func() {
+				boshManager.CreateCall.Returns.Error = errors.New("failed to create")
+
+				err := command.Execute([]string{}, incomingState)
+				Expect(err).To(MatchError("failed to create"))
+				Expect(infrastructureManager.DeleteRetainingResourcesCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the stack cannot be removed", func() {
+				infrastructureManager.DeleteRetainingResourcesCall.Returns.Error = errors.New("failed to delete stack")
+
+				err := command.Execute([]string{}, incomingState)
+				Expect(err).To(MatchError("failed to delete stack"))
+				Expect(stateStore.SetCall.CallCount).To(Equal(2))
+			})
+		})
+	})
+})
+
+var templateResource = regexp.MustCompile(`(?m)^resource "(\w+)" "(\w+)" \{\n(  count\s+=)?`)
+
+// templateResourceAddresses lists the addresses of the resources in a
+// terraform template, with an index per availability zone for counted ones.
+func templateResourceAddresses(template string, azCount int) []string {
+	addresses := []string{}
+	for _, match := range templateResource.FindAllStringSubmatch(template, -1) {
+		address := fmt.Sprintf("%s.%s", match[1], match[2])
+		if match[3] == "" {
+			addresses = append(addresses, address)
+			continue
+		}
+
+		for i := 0; i < azCount; i++ {
+			addresses = append(addresses, fmt.Sprintf("%s[%d]", address, i))
+		}
+	}
+
+	return addresses
+}
@@ -3,6 +3,7 @@ package commands
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
@@ -50,6 +51,16 @@ func (p PrintEnv) Execute(args []string, state storage.State) error {
 		p.logger.Println(fmt.Sprintf("export BOSH_ENVIRONMENT=https://%s:25555", directorAddress))
 	}
 
+	// The allowed cidrs are shell comments, exporting them would make them
+	// the defaults of the next bbl up, even for another environment.
+	if len(state.DirectorAllowedCIDRs) > 0 {
+		p.logger.Println(fmt.Sprintf("# director allowed cidrs: %s", strings.Join(state.DirectorAllowedCIDRs, ",")))
+	}
+
+	if len(state.LBAllowedCIDRs) > 0 {
+		p.logger.Println(fmt.Sprintf("# lb allowed cidrs: %s", strings.Join(state.LBAllowedCIDRs, ",")))
+	}
+
 	return nil
 }
 
@@ -48,6 +48,20 @@ var _ = Describe("PrintEnv", func() {
 		Expect(logger.PrintlnCall.Messages).To(ContainElement("export BOSH_ENVIRONMENT=some-director-address"))
 	})
 
+	It("prints the allowed cidrs as comments when they are in the state", func() {
+		state.DirectorAllowedCIDRs = []string{"10.0.0.0/8", "192.168.0.1/32"}
+		state.LBAllowedCIDRs = []string{"172.16.0.0/12"}
+
+		err := printEnv.Execute([]string{}, state)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(logger.PrintlnCall.Messages).To(ContainElement("# director allowed cidrs: 10.0.0.0/8,192.168.0.1/32"))
+		Expect(logger.PrintlnCall.Messages).To(ContainElement("# lb allowed cidrs: 172.16.0.0/12"))
+
+		for _, message := range logger.PrintlnCall.Messages {
+			Expect(message).NotTo(ContainSubstring("BBL_"))
+		}
+	})
+
 	Context("when print-env is called on a bbl env with no director", func() {
 		Context("aws", func() {
 			It("prints only the BOSH_ENVIRONMENT", func() {
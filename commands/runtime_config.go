@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+const (
+	RuntimeConfigCommand = "runtime-config"
+)
+
+type RuntimeConfig struct {
+	logger         logger
+	stateValidator stateValidator
+}
+
+func NewRuntimeConfig(logger logger, stateValidator stateValidator) RuntimeConfig {
+	return RuntimeConfig{
+		logger:         logger,
+		stateValidator: stateValidator,
+	}
+}
+
+func (r RuntimeConfig) Execute(args []string, state storage.State) error {
+	err := r.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	if state.RuntimeConfig == "" {
+		return errors.New("no runtime config has been registered for this bbl environment, use bbl up --runtime-config to register one")
+	}
+
+	r.logger.Println(state.RuntimeConfig)
+	return nil
+}
@@ -0,0 +1,48 @@
+package commands_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RuntimeConfig", func() {
+	var (
+		logger         *fakes.Logger
+		stateValidator *fakes.StateValidator
+		runtimeConfig  commands.RuntimeConfig
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		stateValidator = &fakes.StateValidator{}
+
+		runtimeConfig = commands.NewRuntimeConfig(logger, stateValidator)
+	})
+
+	It("prints the registered runtime config", func() {
+		err := runtimeConfig.Execute([]string{}, storage.State{
+			RuntimeConfig: "some-runtime-config",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+		Expect(logger.PrintlnCall.Messages).To(ContainElement("some-runtime-config"))
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when no runtime config has been registered", func() {
+			err := runtimeConfig.Execute([]string{}, storage.State{})
+			Expect(err).To(MatchError("no runtime config has been registered for this bbl environment, use bbl up --runtime-config to register one"))
+		})
+
+		It("returns an error when the state validator fails", func() {
+			stateValidator.ValidateCall.Returns.Error = errors.New("failed to validate state")
+			err := runtimeConfig.Execute([]string{}, storage.State{})
+			Expect(err).To(MatchError("failed to validate state"))
+		})
+	})
+})
@@ -3,6 +3,7 @@ package commands
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
@@ -14,6 +15,7 @@ const (
 	DirectorPasswordCommand = "director-password"
 	DirectorAddressCommand  = "director-address"
 	DirectorCACertCommand   = "director-ca-cert"
+	LBCACommand             = "lb-ca"
 
 	EnvIDPropertyName            = "environment id"
 	SSHKeyPropertyName           = "ssh key"
@@ -21,6 +23,7 @@ const (
 	DirectorPasswordPropertyName = "director password"
 	DirectorAddressPropertyName  = "director address"
 	DirectorCACertPropertyName   = "director ca cert"
+	LBCAPropertyName             = "lb ca"
 )
 
 type StateQuery struct {
@@ -49,7 +52,7 @@ func (s StateQuery) Execute(subcommandFlags []string, state storage.State) error
 		return err
 	}
 
-	if state.NoDirector && s.propertyName != DirectorAddressPropertyName && s.propertyName != EnvIDPropertyName {
+	if state.NoDirector && s.propertyName != DirectorAddressPropertyName && s.propertyName != EnvIDPropertyName && s.propertyName != LBCAPropertyName {
 		return errors.New("Error BBL does not manage this director.")
 	}
 
@@ -71,6 +74,8 @@ func (s StateQuery) Execute(subcommandFlags []string, state storage.State) error
 		propertyValue = state.BOSH.DirectorPassword
 	case DirectorCACertPropertyName:
 		propertyValue = state.BOSH.DirectorSSLCA
+	case LBCAPropertyName:
+		propertyValue = lbCAs(state)
 	case SSHKeyPropertyName:
 		propertyValue = state.KeyPair.PrivateKey
 	case EnvIDPropertyName:
@@ -85,6 +90,19 @@ func (s StateQuery) Execute(subcommandFlags []string, state storage.State) error
 	return nil
 }
 
+// lbCAs returns the certificate authorities of every lb attached with a
+// self-signed certificate as a single PEM bundle.
+func lbCAs(state storage.State) string {
+	var cas []string
+	for _, lb := range state.LBs {
+		if lb.CA != "" {
+			cas = append(cas, strings.TrimSpace(lb.CA))
+		}
+	}
+
+	return strings.Join(cas, "\n")
+}
+
 func (s StateQuery) getEIP(state storage.State) (string, error) {
 	switch state.IAAS {
 	case "aws":
@@ -61,6 +61,33 @@ var _ = Describe("StateQuery", func() {
 			)
 		})
 
+		Context("lb-ca", func() {
+			It("prints the CAs of every lb with a self-signed certificate", func() {
+				command := commands.NewStateQuery(fakeLogger, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, "lb ca")
+
+				err := command.Execute([]string{}, storage.State{
+					NoDirector: true,
+					LBs: []storage.LB{
+						{Type: "cf", CA: "some-cf-ca\n"},
+						{Type: "concourse"},
+						{Type: "vault", CA: "some-vault-ca\n"},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeLogger.PrintlnCall.Receives.Message).To(Equal("some-cf-ca\nsome-vault-ca"))
+			})
+
+			It("returns an error when no lb has a self-signed certificate", func() {
+				command := commands.NewStateQuery(fakeLogger, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, "lb ca")
+
+				err := command.Execute([]string{}, storage.State{
+					LBs: []storage.LB{{Type: "cf", Cert: "some-cert"}},
+				})
+				Expect(err).To(MatchError("Could not retrieve lb ca, please make sure you are targeting the proper state dir."))
+			})
+		})
+
 		Context("bbl does not manage the bosh director", func() {
 			var state storage.State
 
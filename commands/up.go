@@ -3,7 +3,12 @@ package commands
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"net"
+	"regexp"
+	"strings"
 
+	"github.com/cloudfoundry/bosh-bootloader/cloudconfig/vmtypes"
 	"github.com/cloudfoundry/bosh-bootloader/flags"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
@@ -30,19 +35,38 @@ type envGetter interface {
 type upConfig struct {
 	awsAccessKeyID       string
 	awsSecretAccessKey   string
+	awsSessionToken      string
+	awsProfile           string
+	awsAssumeRoleARN     string
+	awsMFASerial         string
 	awsRegion            string
 	awsBOSHAZ            string
+	awsAZs               string
+	awsAZCount           int
+	awsNATType           string
+	awsInstanceProfile   bool
 	gcpServiceAccountKey string
 	gcpProjectID         string
 	gcpZone              string
 	gcpRegion            string
+	gcpZones             string
+	gcpNoServiceAccount  bool
 	iaas                 string
 	name                 string
 	opsFile              string
+	runtimeConfig        string
+	cpiConfig            string
+	cloudConfigOpsFiles  []string
+	vmTypeCatalog        string
 	noDirector           bool
 	terraform            bool
+	directorAllowedCIDRs string
+	lbAllowedCIDRs       string
+	tags                 []string
 }
 
+var gcpLabelPattern = regexp.MustCompile(`^[a-z0-9_-]{0,63}$`)
+
 func NewUp(awsUp awsUp, gcpUp gcpUp, envGetter envGetter, boshManager boshManager) Up {
 	return Up{
 		awsUp:       awsUp,
@@ -86,27 +110,55 @@ func (u Up) Execute(args []string, state storage.State) error {
 		return fmt.Errorf("The director name cannot be changed for an existing environment. Current name is %s.", state.EnvID)
 	}
 
+	state, err = setAllowedCIDRs(state, config.directorAllowedCIDRs, config.lbAllowedCIDRs)
+	if err != nil {
+		return err
+	}
+
+	state, err = setTags(state, desiredIAAS, config.tags)
+	if err != nil {
+		return err
+	}
+
 	switch desiredIAAS {
 	case "aws":
 		err = u.awsUp.Execute(AWSUpConfig{
-			AccessKeyID:     config.awsAccessKeyID,
-			SecretAccessKey: config.awsSecretAccessKey,
-			Region:          config.awsRegion,
-			BOSHAZ:          config.awsBOSHAZ,
-			OpsFilePath:     config.opsFile,
-			Name:            config.name,
-			NoDirector:      config.noDirector,
-			Terraform:       config.terraform,
+			AccessKeyID:             config.awsAccessKeyID,
+			SecretAccessKey:         config.awsSecretAccessKey,
+			SessionToken:            config.awsSessionToken,
+			Profile:                 config.awsProfile,
+			AssumeRoleARN:           config.awsAssumeRoleARN,
+			MFASerial:               config.awsMFASerial,
+			Region:                  config.awsRegion,
+			BOSHAZ:                  config.awsBOSHAZ,
+			AZs:                     splitZones(config.awsAZs),
+			AZCount:                 config.awsAZCount,
+			NATType:                 config.awsNATType,
+			IAMInstanceProfile:      config.awsInstanceProfile,
+			OpsFilePath:             config.opsFile,
+			RuntimeConfigPath:       config.runtimeConfig,
+			CPIConfigPath:           config.cpiConfig,
+			CloudConfigOpsFilePaths: config.cloudConfigOpsFiles,
+			VMTypeCatalogPath:       config.vmTypeCatalog,
+			Name:                    config.name,
+			NoDirector:              config.noDirector,
+			Terraform:               config.terraform,
 		}, state)
 	case "gcp":
 		err = u.gcpUp.Execute(GCPUpConfig{
-			ServiceAccountKey: config.gcpServiceAccountKey,
-			ProjectID:         config.gcpProjectID,
-			Zone:              config.gcpZone,
-			Region:            config.gcpRegion,
-			OpsFilePath:       config.opsFile,
-			Name:              config.name,
-			NoDirector:        config.noDirector,
+			ServiceAccountKey:        config.gcpServiceAccountKey,
+			ProjectID:                config.gcpProjectID,
+			Zone:                     config.gcpZone,
+			Region:                   config.gcpRegion,
+			Zones:                    splitZones(config.gcpZones),
+			OpsFilePath:              config.opsFile,
+			RuntimeConfigPath:        config.runtimeConfig,
+			CPIConfigPath:            config.cpiConfig,
+			CloudConfigOpsFilePaths:  config.cloudConfigOpsFiles,
+			VMTypeCatalogPath:        config.vmTypeCatalog,
+			Name:                     config.name,
+			NoDirector:               config.noDirector,
+			NoDirectorServiceAccount: config.gcpNoServiceAccount,
 		}, state)
 	default:
 		return fmt.Errorf("%q is an invalid iaas type, supported values are: [gcp, aws]", desiredIAAS)
@@ -128,18 +180,35 @@ func (u Up) parseArgs(args []string) (upConfig, error) {
 
 	upFlags.String(&config.awsAccessKeyID, "aws-access-key-id", u.envGetter.Get("BBL_AWS_ACCESS_KEY_ID"))
 	upFlags.String(&config.awsSecretAccessKey, "aws-secret-access-key", u.envGetter.Get("BBL_AWS_SECRET_ACCESS_KEY"))
+	upFlags.String(&config.awsSessionToken, "aws-session-token", u.envGetter.Get("BBL_AWS_SESSION_TOKEN"))
+	upFlags.String(&config.awsProfile, "aws-profile", u.envGetter.Get("BBL_AWS_PROFILE"))
+	upFlags.String(&config.awsAssumeRoleARN, "aws-assume-role-arn", u.envGetter.Get("BBL_AWS_ASSUME_ROLE_ARN"))
+	upFlags.String(&config.awsMFASerial, "aws-mfa-serial", u.envGetter.Get("BBL_AWS_MFA_SERIAL"))
 	upFlags.String(&config.awsRegion, "aws-region", u.envGetter.Get("BBL_AWS_REGION"))
 	upFlags.String(&config.awsBOSHAZ, "aws-bosh-az", u.envGetter.Get("BBL_AWS_BOSH_AZ"))
+	upFlags.String(&config.awsAZs, "azs", u.envGetter.Get("BBL_AWS_AZS"))
+	upFlags.Int(&config.awsAZCount, "az-count", 0)
+	upFlags.String(&config.awsNATType, "aws-nat-type", u.envGetter.Get("BBL_AWS_NAT_TYPE"))
+	upFlags.Bool(&config.awsInstanceProfile, "", "aws-iam-instance-profile", false)
 
 	upFlags.String(&config.gcpServiceAccountKey, "gcp-service-account-key", u.envGetter.Get("BBL_GCP_SERVICE_ACCOUNT_KEY"))
 	upFlags.String(&config.gcpProjectID, "gcp-project-id", u.envGetter.Get("BBL_GCP_PROJECT_ID"))
 	upFlags.String(&config.gcpZone, "gcp-zone", u.envGetter.Get("BBL_GCP_ZONE"))
 	upFlags.String(&config.gcpRegion, "gcp-region", u.envGetter.Get("BBL_GCP_REGION"))
+	upFlags.String(&config.gcpZones, "gcp-zones", u.envGetter.Get("BBL_GCP_ZONES"))
+	upFlags.Bool(&config.gcpNoServiceAccount, "", "gcp-no-director-service-account", false)
 
 	upFlags.String(&config.name, "name", "")
 	upFlags.String(&config.opsFile, "ops-file", "")
+	upFlags.String(&config.runtimeConfig, "runtime-config", "")
+	upFlags.String(&config.cpiConfig, "cpi-config", "")
+	upFlags.StringSlice(&config.cloudConfigOpsFiles, "cloud-config-ops-file", nil)
+	upFlags.String(&config.vmTypeCatalog, "vm-type-catalog", "")
 	upFlags.Bool(&config.noDirector, "", "no-director", false)
 	upFlags.Bool(&config.terraform, "", "terraform", false)
+	upFlags.String(&config.directorAllowedCIDRs, "director-allowed-cidrs", u.envGetter.Get("BBL_DIRECTOR_ALLOWED_CIDRS"))
+	upFlags.String(&config.lbAllowedCIDRs, "lb-allowed-cidrs", u.envGetter.Get("BBL_LB_ALLOWED_CIDRS"))
+	upFlags.StringSlice(&config.tags, "tag", nil)
 
 	err := upFlags.Parse(args)
 	if err != nil {
@@ -148,3 +217,151 @@ func (u Up) parseArgs(args []string) (upConfig, error) {
 
 	return config, nil
 }
+
+func splitZones(zones string) []string {
+	if zones == "" {
+		return nil
+	}
+
+	var splitZones []string
+	for _, zone := range strings.Split(zones, ",") {
+		if zone = strings.TrimSpace(zone); zone != "" {
+			splitZones = append(splitZones, zone)
+		}
+	}
+
+	return splitZones
+}
+
+// setAllowedCIDRs stores the comma separated source ranges that may reach
+// the director and the load balancers. Empty values keep the ranges that
+// are already in the state.
+func setAllowedCIDRs(state storage.State, directorAllowedCIDRs, lbAllowedCIDRs string) (storage.State, error) {
+	if directorAllowedCIDRs != "" {
+		cidrs, err := parseCIDRs("director-allowed-cidrs", directorAllowedCIDRs)
+		if err != nil {
+			return storage.State{}, err
+		}
+		state.DirectorAllowedCIDRs = cidrs
+	}
+
+	if lbAllowedCIDRs != "" {
+		cidrs, err := parseCIDRs("lb-allowed-cidrs", lbAllowedCIDRs)
+		if err != nil {
+			return storage.State{}, err
+		}
+		state.LBAllowedCIDRs = cidrs
+	}
+
+	return state, nil
+}
+
+func parseCIDRs(flagName, cidrs string) ([]string, error) {
+	parsedCIDRs := splitZones(cidrs)
+	if len(parsedCIDRs) == 0 {
+		return nil, fmt.Errorf("--%s must contain at least one cidr", flagName)
+	}
+
+	for _, cidr := range parsedCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return nil, fmt.Errorf("--%s contains an invalid cidr %q", flagName, cidr)
+		}
+	}
+
+	return parsedCIDRs, nil
+}
+
+// setTags stores the key=value tags that are applied to every resource of the
+// environment. Passing any tag replaces all of the tags in the state, so that
+// tags can also be removed. An empty tag removes every tag.
+func setTags(state storage.State, iaas string, tags []string) (storage.State, error) {
+	if len(tags) == 0 {
+		return state, nil
+	}
+
+	parsedTags := map[string]string{}
+	for _, tag := range tags {
+		if tag == "" {
+			continue
+		}
+
+		parts := strings.SplitN(tag, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return storage.State{}, fmt.Errorf("--tag %q must be in the form key=value", tag)
+		}
+
+		if iaas == "gcp" && !isGCPLabel(parts[0], parts[1]) {
+			return storage.State{}, fmt.Errorf("--tag %q is not a valid gcp label, keys and values may only contain lowercase letters, numbers, dashes and underscores", tag)
+		}
+
+		parsedTags[parts[0]] = parts[1]
+	}
+
+	state.Tags = parsedTags
+	if len(parsedTags) == 0 {
+		state.Tags = nil
+	}
+
+	return state, nil
+}
+
+func isGCPLabel(key, value string) bool {
+	return key[0] >= 'a' && key[0] <= 'z' && gcpLabelPattern.MatchString(key) && gcpLabelPattern.MatchString(value)
+}
+
+type directorConfigPaths struct {
+	runtimeConfig       string
+	cpiConfig           string
+	cloudConfigOpsFiles []string
+	vmTypeCatalog       string
+}
+
+func readDirectorConfigs(state storage.State, paths directorConfigPaths) (storage.State, error) {
+	if paths.runtimeConfig != "" {
+		runtimeConfig, err := ioutil.ReadFile(paths.runtimeConfig)
+		if err != nil {
+			return storage.State{}, fmt.Errorf("error reading runtime-config contents: %v", err)
+		}
+		state.RuntimeConfig = string(runtimeConfig)
+	}
+
+	if paths.cpiConfig != "" {
+		cpiConfig, err := ioutil.ReadFile(paths.cpiConfig)
+		if err != nil {
+			return storage.State{}, fmt.Errorf("error reading cpi-config contents: %v", err)
+		}
+		state.CPIConfig = string(cpiConfig)
+	}
+
+	if len(paths.cloudConfigOpsFiles) > 0 {
+		cloudConfigOpsFiles := []string{}
+		for _, path := range paths.cloudConfigOpsFiles {
+			opsFile, err := ioutil.ReadFile(path)
+			if err != nil {
+				return storage.State{}, fmt.Errorf("error reading cloud-config-ops-file contents: %v", err)
+			}
+			cloudConfigOpsFiles = append(cloudConfigOpsFiles, string(opsFile))
+		}
+		state.CloudConfigOpsFiles = cloudConfigOpsFiles
+	}
+
+	if paths.vmTypeCatalog != "" {
+		vmTypeCatalog, err := ioutil.ReadFile(paths.vmTypeCatalog)
+		if err != nil {
+			return storage.State{}, fmt.Errorf("error reading vm-type-catalog contents: %v", err)
+		}
+
+		region := state.AWS.Region
+		if state.IAAS == "gcp" {
+			region = state.GCP.Region
+		}
+
+		_, err = vmtypes.Load(state.IAAS, region, string(vmTypeCatalog))
+		if err != nil {
+			return storage.State{}, fmt.Errorf("invalid vm-type-catalog: %s", err)
+		}
+		state.VMTypeCatalog = string(vmTypeCatalog)
+	}
+
+	return state, nil
+}
@@ -141,6 +141,82 @@ var _ = Describe("Up", func() {
 			)
 		})
 
+		Context("when an aws nat type is provided", func() {
+			It("passes it through from the environment variable", func() {
+				fakeEnvGetter.Values = map[string]string{
+					"BBL_AWS_NAT_TYPE": "instance",
+				}
+
+				err := command.Execute([]string{"--iaas", "aws"}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeAWSUp.ExecuteCall.Receives.AWSUpConfig.NATType).To(Equal("instance"))
+			})
+
+			It("gives precedence to the command line arg", func() {
+				fakeEnvGetter.Values = map[string]string{
+					"BBL_AWS_NAT_TYPE": "instance",
+				}
+
+				err := command.Execute([]string{"--iaas", "aws", "--aws-nat-type", "gateway"}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeAWSUp.ExecuteCall.Receives.AWSUpConfig.NATType).To(Equal("gateway"))
+			})
+		})
+
+		Context("when --aws-iam-instance-profile is provided", func() {
+			It("passes it through to aws up", func() {
+				err := command.Execute([]string{"--iaas", "aws", "--terraform", "--aws-iam-instance-profile"}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeAWSUp.ExecuteCall.Receives.AWSUpConfig.IAMInstanceProfile).To(BeTrue())
+			})
+		})
+
+		Context("when aws profile, session token and role args are provided", func() {
+			It("passes them through from environment variables", func() {
+				fakeEnvGetter.Values = map[string]string{
+					"BBL_AWS_SESSION_TOKEN":   "session-token-from-env",
+					"BBL_AWS_PROFILE":         "profile-from-env",
+					"BBL_AWS_ASSUME_ROLE_ARN": "role-arn-from-env",
+					"BBL_AWS_MFA_SERIAL":      "mfa-serial-from-env",
+					"BBL_AWS_REGION":          "region-from-env",
+				}
+
+				err := command.Execute([]string{"--iaas", "aws"}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeAWSUp.ExecuteCall.Receives.AWSUpConfig).To(Equal(commands.AWSUpConfig{
+					SessionToken:  "session-token-from-env",
+					Profile:       "profile-from-env",
+					AssumeRoleARN: "role-arn-from-env",
+					MFASerial:     "mfa-serial-from-env",
+					Region:        "region-from-env",
+				}))
+			})
+
+			It("passes them through from command line args", func() {
+				err := command.Execute([]string{
+					"--iaas", "aws",
+					"--aws-session-token", "some-session-token",
+					"--aws-profile", "some-profile",
+					"--aws-assume-role-arn", "some-role-arn",
+					"--aws-mfa-serial", "some-mfa-serial",
+					"--aws-region", "some-region",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeAWSUp.ExecuteCall.Receives.AWSUpConfig).To(Equal(commands.AWSUpConfig{
+					SessionToken:  "some-session-token",
+					Profile:       "some-profile",
+					AssumeRoleARN: "some-role-arn",
+					MFASerial:     "some-mfa-serial",
+					Region:        "some-region",
+				}))
+			})
+		})
+
 		Context("when an ops-file is provided via command line flag", func() {
 			It("populates the aws config with the correct ops-file path", func() {
 				fakeEnvGetter.Values = map[string]string{
@@ -187,6 +263,268 @@ var _ = Describe("Up", func() {
 			})
 		})
 
+		Context("when a runtime-config and cpi-config are provided via command line flags", func() {
+			It("populates the aws config with the runtime-config and cpi-config paths", func() {
+				err := command.Execute([]string{
+					"--iaas", "aws",
+					"--runtime-config", "some-runtime-config-path",
+					"--cpi-config", "some-cpi-config-path",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeAWSUp.ExecuteCall.Receives.AWSUpConfig.RuntimeConfigPath).To(Equal("some-runtime-config-path"))
+				Expect(fakeAWSUp.ExecuteCall.Receives.AWSUpConfig.CPIConfigPath).To(Equal("some-cpi-config-path"))
+			})
+
+			It("populates the gcp config with the runtime-config and cpi-config paths", func() {
+				err := command.Execute([]string{
+					"--iaas", "gcp",
+					"--runtime-config", "some-runtime-config-path",
+					"--cpi-config", "some-cpi-config-path",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeGCPUp.ExecuteCall.Receives.GCPUpConfig.RuntimeConfigPath).To(Equal("some-runtime-config-path"))
+				Expect(fakeGCPUp.ExecuteCall.Receives.GCPUpConfig.CPIConfigPath).To(Equal("some-cpi-config-path"))
+			})
+		})
+
+		Context("when cloud-config ops files are provided via command line flags", func() {
+			It("passes every ops file path to aws up in order", func() {
+				err := command.Execute([]string{
+					"--iaas", "aws",
+					"--cloud-config-ops-file", "some-ops-file-path",
+					"--cloud-config-ops-file", "some-other-ops-file-path",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeAWSUp.ExecuteCall.Receives.AWSUpConfig.CloudConfigOpsFilePaths).To(Equal([]string{
+					"some-ops-file-path",
+					"some-other-ops-file-path",
+				}))
+			})
+
+			It("passes every ops file path to gcp up in order", func() {
+				err := command.Execute([]string{
+					"--iaas", "gcp",
+					"--cloud-config-ops-file", "some-ops-file-path",
+					"--cloud-config-ops-file", "some-other-ops-file-path",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeGCPUp.ExecuteCall.Receives.GCPUpConfig.CloudConfigOpsFilePaths).To(Equal([]string{
+					"some-ops-file-path",
+					"some-other-ops-file-path",
+				}))
+			})
+		})
+
+		Context("when a vm type catalog is provided via command line flag", func() {
+			It("passes the vm type catalog path to aws up", func() {
+				err := command.Execute([]string{
+					"--iaas", "aws",
+					"--vm-type-catalog", "some-vm-type-catalog-path",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeAWSUp.ExecuteCall.Receives.AWSUpConfig.VMTypeCatalogPath).To(Equal("some-vm-type-catalog-path"))
+			})
+
+			It("passes the vm type catalog path to gcp up", func() {
+				err := command.Execute([]string{
+					"--iaas", "gcp",
+					"--vm-type-catalog", "some-vm-type-catalog-path",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeGCPUp.ExecuteCall.Receives.GCPUpConfig.VMTypeCatalogPath).To(Equal("some-vm-type-catalog-path"))
+			})
+		})
+
+		Context("when aws availability zones are provided", func() {
+			It("passes the zones from --azs to aws up", func() {
+				err := command.Execute([]string{
+					"--iaas", "aws",
+					"--azs", "us-east-1a, us-east-1c",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeAWSUp.ExecuteCall.Receives.AWSUpConfig.AZs).To(Equal([]string{"us-east-1a", "us-east-1c"}))
+			})
+
+			It("uses the zones from the environment variable", func() {
+				fakeEnvGetter.Values = map[string]string{
+					"BBL_AWS_AZS": "us-east-1b",
+				}
+
+				err := command.Execute([]string{
+					"--iaas", "aws",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeAWSUp.ExecuteCall.Receives.AWSUpConfig.AZs).To(Equal([]string{"us-east-1b"}))
+			})
+
+			It("passes the count from --az-count to aws up", func() {
+				err := command.Execute([]string{
+					"--iaas", "aws",
+					"--az-count", "2",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeAWSUp.ExecuteCall.Receives.AWSUpConfig.AZCount).To(Equal(2))
+			})
+		})
+
+		Context("when gcp zones are provided", func() {
+			It("splits the comma separated zones passed to gcp up", func() {
+				err := command.Execute([]string{
+					"--iaas", "gcp",
+					"--gcp-zones", "us-east1-b, us-east1-c,",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeGCPUp.ExecuteCall.Receives.GCPUpConfig.Zones).To(Equal([]string{"us-east1-b", "us-east1-c"}))
+			})
+
+			It("uses the zones from the environment variable", func() {
+				fakeEnvGetter.Values = map[string]string{
+					"BBL_GCP_ZONES": "us-east1-b,us-east1-d",
+				}
+
+				err := command.Execute([]string{
+					"--iaas", "gcp",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeGCPUp.ExecuteCall.Receives.GCPUpConfig.Zones).To(Equal([]string{"us-east1-b", "us-east1-d"}))
+			})
+		})
+
+		Context("when allowed cidrs are provided", func() {
+			It("stores the allowed cidrs in the state passed to aws up", func() {
+				err := command.Execute([]string{
+					"--iaas", "aws",
+					"--director-allowed-cidrs", "10.0.0.0/8, 192.168.0.1/32",
+					"--lb-allowed-cidrs", "172.16.0.0/12",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeAWSUp.ExecuteCall.Receives.State.DirectorAllowedCIDRs).To(Equal([]string{"10.0.0.0/8", "192.168.0.1/32"}))
+				Expect(fakeAWSUp.ExecuteCall.Receives.State.LBAllowedCIDRs).To(Equal([]string{"172.16.0.0/12"}))
+			})
+
+			It("uses the allowed cidrs from the environment variables", func() {
+				fakeEnvGetter.Values = map[string]string{
+					"BBL_DIRECTOR_ALLOWED_CIDRS": "10.0.0.0/8",
+					"BBL_LB_ALLOWED_CIDRS":       "172.16.0.0/12",
+				}
+
+				err := command.Execute([]string{
+					"--iaas", "gcp",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeGCPUp.ExecuteCall.Receives.State.DirectorAllowedCIDRs).To(Equal([]string{"10.0.0.0/8"}))
+				Expect(fakeGCPUp.ExecuteCall.Receives.State.LBAllowedCIDRs).To(Equal([]string{"172.16.0.0/12"}))
+			})
+
+			It("keeps the allowed cidrs in the state when they are not provided", func() {
+				err := command.Execute([]string{}, storage.State{
+					IAAS:                 "gcp",
+					DirectorAllowedCIDRs: []string{"10.0.0.0/8"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeGCPUp.ExecuteCall.Receives.State.DirectorAllowedCIDRs).To(Equal([]string{"10.0.0.0/8"}))
+			})
+
+			It("returns an error when a cidr is invalid", func() {
+				err := command.Execute([]string{
+					"--iaas", "aws",
+					"--lb-allowed-cidrs", "10.0.0.0/8,some-cidr",
+				}, storage.State{})
+				Expect(err).To(MatchError(`--lb-allowed-cidrs contains an invalid cidr "some-cidr"`))
+				Expect(fakeAWSUp.ExecuteCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when no cidr is provided", func() {
+				err := command.Execute([]string{
+					"--iaas", "aws",
+					"--director-allowed-cidrs", ",",
+				}, storage.State{})
+				Expect(err).To(MatchError("--director-allowed-cidrs must contain at least one cidr"))
+			})
+		})
+
+		Context("when tags are provided", func() {
+			It("stores the tags in the state passed to aws up", func() {
+				err := command.Execute([]string{
+					"--iaas", "aws",
+					"--tag", "owner=some-owner",
+					"--tag", "cost-center=CC-123",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeAWSUp.ExecuteCall.Receives.State.Tags).To(Equal(map[string]string{
+					"owner":       "some-owner",
+					"cost-center": "CC-123",
+				}))
+			})
+
+			It("replaces the tags in the state", func() {
+				err := command.Execute([]string{
+					"--tag", "environment=staging",
+				}, storage.State{
+					IAAS: "gcp",
+					Tags: map[string]string{"owner": "some-owner"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeGCPUp.ExecuteCall.Receives.State.Tags).To(Equal(map[string]string{"environment": "staging"}))
+			})
+
+			It("keeps the tags in the state when they are not provided", func() {
+				err := command.Execute([]string{}, storage.State{
+					IAAS: "aws",
+					Tags: map[string]string{"owner": "some-owner"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeAWSUp.ExecuteCall.Receives.State.Tags).To(Equal(map[string]string{"owner": "some-owner"}))
+			})
+
+			It("removes every tag when an empty tag is provided", func() {
+				err := command.Execute([]string{
+					"--tag", "",
+				}, storage.State{
+					IAAS: "aws",
+					Tags: map[string]string{"owner": "some-owner"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeAWSUp.ExecuteCall.Receives.State.Tags).To(BeNil())
+			})
+
+			It("returns an error when a tag is not a key value pair", func() {
+				err := command.Execute([]string{
+					"--iaas", "aws",
+					"--tag", "some-tag",
+				}, storage.State{})
+				Expect(err).To(MatchError(`--tag "some-tag" must be in the form key=value`))
+				Expect(fakeAWSUp.ExecuteCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when a tag is not a valid gcp label", func() {
+				err := command.Execute([]string{
+					"--iaas", "gcp",
+					"--tag", "cost-center=CC-123",
+				}, storage.State{})
+				Expect(err).To(MatchError(`--tag "cost-center=CC-123" is not a valid gcp label, keys and values may only contain lowercase letters, numbers, dashes and underscores`))
+				Expect(fakeGCPUp.ExecuteCall.CallCount).To(Equal(0))
+			})
+		})
+
 		Context("when gcp args are provided through environment variables", func() {
 			BeforeEach(func() {
 				fakeEnvGetter.Values = map[string]string{
@@ -528,5 +866,14 @@ var _ = Describe("Up", func() {
 				Expect(fakeGCPUp.ExecuteCall.Receives.GCPUpConfig.NoDirector).To(Equal(true))
 			})
 		})
+
+		Context("when --gcp-no-director-service-account is provided", func() {
+			It("passes it through to gcp up", func() {
+				err := command.Execute([]string{"--iaas", "gcp", "--gcp-no-director-service-account"}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeGCPUp.ExecuteCall.Receives.GCPUpConfig.NoDirectorServiceAccount).To(BeTrue())
+			})
+		})
 	})
 })
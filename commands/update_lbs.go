@@ -1,6 +1,9 @@
 package commands
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/cloudfoundry/bosh-bootloader/flags"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
@@ -8,6 +11,7 @@ import (
 const UpdateLBsCommand = "update-lbs"
 
 type updateLBConfig struct {
+	lbType        string
 	certPath      string
 	keyPath       string
 	chainPath     string
@@ -63,7 +67,11 @@ func (u UpdateLBs) Execute(subcommandFlags []string, state storage.State) error
 		}
 	}
 
-	lbExists := lbExists(state.Stack.LBType) || lbExists(state.LB.Type)
+	lbType, lbExists, err := u.attachedLBType(config.lbType, state)
+	if err != nil {
+		return err
+	}
+
 	if config.skipIfMissing && !lbExists {
 		u.logger.Println("no lb type exists, skipping...")
 		return nil
@@ -73,15 +81,26 @@ func (u UpdateLBs) Execute(subcommandFlags []string, state storage.State) error
 		return LBNotFound
 	}
 
-	err = u.certificateValidator.Validate(UpdateLBsCommand, config.certPath, config.keyPath, config.chainPath)
-	if err != nil {
-		return err
+	lb, _ := state.GetLB(lbType)
+
+	// An lb without a certificate, like the gcp concourse lb, can only have
+	// its domain updated.
+	domainOnly := config.domain != "" && config.certPath == "" && config.keyPath == "" && config.chainPath == ""
+	if !domainOnly || lb.Cert != "" {
+		err = u.certificateValidator.Validate(UpdateLBsCommand, config.certPath, config.keyPath, config.chainPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	if config.domain != "" && lb.Domain != "" && lb.Domain != config.domain {
+		u.logger.Step(fmt.Sprintf("moving %s lb from %q to %q, update your nameserver delegation with the servers from `bbl lbs`", lbType, lb.Domain, config.domain))
 	}
 
 	switch state.IAAS {
 	case "gcp":
 		if err := u.gcpUpdateLBs.Execute(GCPCreateLBsConfig{
-			LBType:   state.LB.Type,
+			LBType:   lbType,
 			CertPath: config.certPath,
 			KeyPath:  config.keyPath,
 			Domain:   config.domain,
@@ -90,10 +109,11 @@ func (u UpdateLBs) Execute(subcommandFlags []string, state storage.State) error
 		}
 	case "aws":
 		if err := u.awsUpdateLBs.Execute(AWSCreateLBsConfig{
-			LBType:    state.Stack.LBType,
+			LBType:    lbType,
 			CertPath:  config.certPath,
 			KeyPath:   config.keyPath,
 			ChainPath: config.chainPath,
+			Domain:    config.domain,
 		}, state); err != nil {
 			return err
 		}
@@ -105,6 +125,7 @@ func (UpdateLBs) parseFlags(subcommandFlags []string) (updateLBConfig, error) {
 	lbFlags := flags.New("update-lbs")
 
 	config := updateLBConfig{}
+	lbFlags.String(&config.lbType, "type", "")
 	lbFlags.String(&config.certPath, "cert", "")
 	lbFlags.String(&config.keyPath, "key", "")
 	lbFlags.String(&config.chainPath, "chain", "")
@@ -118,3 +139,25 @@ func (UpdateLBs) parseFlags(subcommandFlags []string) (updateLBConfig, error) {
 
 	return config, nil
 }
+
+// attachedLBType resolves which attached lb an update targets. The type may
+// be omitted when the environment has exactly one lb.
+func (UpdateLBs) attachedLBType(lbType string, state storage.State) (string, bool, error) {
+	if lbExists(state.Stack.LBType) {
+		return state.Stack.LBType, lbType == "" || lbType == state.Stack.LBType, nil
+	}
+
+	if lbType != "" {
+		_, ok := state.GetLB(lbType)
+		return lbType, ok, nil
+	}
+
+	switch len(state.LBs) {
+	case 0:
+		return "", false, nil
+	case 1:
+		return state.LBs[0].Type, true, nil
+	default:
+		return "", false, errors.New("--type is required when more than one lb is attached")
+	}
+}
@@ -75,8 +75,10 @@ var _ = Describe("Update LBs", func() {
 					"--domain", "some-domain",
 				}, storage.State{
 					IAAS: "gcp",
-					LB: storage.LB{
-						Type: "cf",
+					LBs: []storage.LB{
+						{
+							Type: "cf",
+						},
 					},
 				})
 				Expect(err).To(MatchError("BOSH version must be at least v2.0.0"))
@@ -93,8 +95,10 @@ var _ = Describe("Update LBs", func() {
 				}, storage.State{
 					IAAS:       "gcp",
 					NoDirector: true,
-					LB: storage.LB{
-						Type: "cf",
+					LBs: []storage.LB{
+						{
+							Type: "cf",
+						},
 					},
 				})
 				Expect(err).NotTo(HaveOccurred())
@@ -108,8 +112,10 @@ var _ = Describe("Update LBs", func() {
 				"--domain", "some-domain",
 			}, storage.State{
 				IAAS: "gcp",
-				LB: storage.LB{
-					Type: "cf",
+				LBs: []storage.LB{
+					{
+						Type: "cf",
+					},
 				},
 			})
 			Expect(err).NotTo(HaveOccurred())
@@ -142,6 +148,141 @@ var _ = Describe("Update LBs", func() {
 			}))
 		})
 
+		It("passes the domain to the AWS lb update", func() {
+			err := command.Execute([]string{
+				"--cert", "my-cert",
+				"--key", "my-key",
+				"--domain", "ci.example.com",
+			}, storage.State{
+				IAAS: "aws",
+				LBs: []storage.LB{
+					{
+						Type: "concourse",
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(awsUpdateLBs.ExecuteCall.Receives.Config.Domain).To(Equal("ci.example.com"))
+		})
+
+		Context("when the domain of the lb changes", func() {
+			It("tells the user to update their nameserver delegation", func() {
+				err := command.Execute([]string{
+					"--cert", "my-cert",
+					"--key", "my-key",
+					"--domain", "new.example.com",
+				}, storage.State{
+					IAAS: "gcp",
+					LBs: []storage.LB{
+						{
+							Type:   "concourse",
+							Domain: "old.example.com",
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.StepCall.Messages).To(ContainElement("moving concourse lb from \"old.example.com\" to \"new.example.com\", update your nameserver delegation with the servers from `bbl lbs`"))
+				Expect(gcpUpdateLBs.ExecuteCall.Receives.Config.Domain).To(Equal("new.example.com"))
+			})
+
+			It("does not require a certificate when the lb has none", func() {
+				err := command.Execute([]string{
+					"--type", "concourse",
+					"--domain", "new.example.com",
+				}, storage.State{
+					IAAS: "gcp",
+					LBs: []storage.LB{
+						{
+							Type:   "concourse",
+							Domain: "old.example.com",
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(certificateValidator.ValidateCall.CallCount).To(Equal(0))
+				Expect(gcpUpdateLBs.ExecuteCall.Receives.Config).To(Equal(commands.GCPCreateLBsConfig{
+					LBType: "concourse",
+					Domain: "new.example.com",
+				}))
+			})
+
+			It("still validates the certificate when the lb has one", func() {
+				certificateValidator.ValidateCall.Returns.Error = errors.New("failed to validate")
+				err := command.Execute([]string{
+					"--domain", "new.example.com",
+				}, storage.State{
+					IAAS: "gcp",
+					LBs: []storage.LB{
+						{
+							Type:   "cf",
+							Cert:   "some-cert",
+							Key:    "some-key",
+							Domain: "old.example.com",
+						},
+					},
+				})
+				Expect(err).To(MatchError("failed to validate"))
+
+				Expect(gcpUpdateLBs.ExecuteCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when more than one lb is attached", func() {
+			var multipleLBsState storage.State
+
+			BeforeEach(func() {
+				multipleLBsState = storage.State{
+					IAAS:    "aws",
+					TFState: "some-tf-state",
+					LBs: []storage.LB{
+						{
+							Type: "cf",
+						},
+						{
+							Type: "concourse",
+						},
+					},
+				}
+			})
+
+			It("updates the lb of the provided type", func() {
+				err := command.Execute([]string{
+					"--type", "concourse",
+					"--cert", "my-cert",
+					"--key", "my-key",
+				}, multipleLBsState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(awsUpdateLBs.ExecuteCall.Receives.Config).To(Equal(commands.AWSCreateLBsConfig{
+					LBType:   "concourse",
+					CertPath: "my-cert",
+					KeyPath:  "my-key",
+				}))
+			})
+
+			It("returns an error when the type is not provided", func() {
+				err := command.Execute([]string{
+					"--cert", "my-cert",
+					"--key", "my-key",
+				}, multipleLBsState)
+				Expect(err).To(MatchError("--type is required when more than one lb is attached"))
+
+				Expect(awsUpdateLBs.ExecuteCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when no lb of the provided type is attached", func() {
+				err := command.Execute([]string{
+					"--type", "other",
+					"--cert", "my-cert",
+					"--key", "my-key",
+				}, multipleLBsState)
+				Expect(err).To(MatchError(commands.LBNotFound))
+			})
+		})
+
 		It("returns an error when state validator fails", func() {
 			stateValidator.ValidateCall.Returns.Error = errors.New("state validator failed")
 			err := command.Execute([]string{}, storage.State{})
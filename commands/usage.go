@@ -18,6 +18,7 @@ Global Options:
   --help      [-h]       Prints usage
   --state-dir            Directory containing bbl-state.json
   --debug                Prints debugging output
+  --confirm-cloud-config Prompts before applying cloud config changes
   --version              Prints version
 %s
 `
@@ -29,7 +30,9 @@ Global Options:
 const GlobalUsage = `
 Commands:
   bosh-deployment-vars   Prints required variables for BOSH deployment
+  certs                  Prints certificates managed by bbl and their expiry dates
   cloud-config           Prints suggested cloud configuration for BOSH environment
+  cpi-config             Prints the CPI config applied to the BOSH director
   create-lbs             Attaches load balancer(s)
   delete-lbs             Deletes attached load balancer(s)
   destroy                Tears down BOSH director infrastructure
@@ -39,8 +42,11 @@ Commands:
   director-ca-cert       Prints BOSH director CA certificate
   env-id                 Prints environment ID
   latest-error           Prints the output from the latest call to terraform
+  lb-ca                  Prints the CA of self-signed load balancer certificates
+  migrate-to-terraform   Moves an AWS environment from CloudFormation to terraform
   print-env              Prints BOSH friendly environment variables
   rotate                 Rotates the keypair for BOSH
+  runtime-config         Prints the runtime config applied to the BOSH director
   help                   Prints usage
   lbs                    Prints attached load balancer(s)
   ssh-key                Prints SSH private key
@@ -32,6 +32,13 @@ type AWSClientProvider struct {
 			IAMClient iam.Client
 		}
 	}
+	GetCredentialsCall struct {
+		CallCount int
+		Returns   struct {
+			Credentials aws.Credentials
+			Error       error
+		}
+	}
 }
 
 func (c *AWSClientProvider) SetConfig(config aws.Config) {
@@ -53,3 +60,8 @@ func (c *AWSClientProvider) GetIAMClient() iam.Client {
 	c.GetIAMClientCall.CallCount++
 	return c.GetIAMClientCall.Returns.IAMClient
 }
+
+func (c *AWSClientProvider) GetCredentials() (aws.Credentials, error) {
+	c.GetCredentialsCall.CallCount++
+	return c.GetCredentialsCall.Returns.Credentials, c.GetCredentialsCall.Returns.Error
+}
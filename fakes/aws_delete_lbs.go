@@ -6,7 +6,8 @@ type AWSDeleteLBs struct {
 	ExecuteCall struct {
 		CallCount int
 		Receives  struct {
-			State storage.State
+			LBType string
+			State  storage.State
 		}
 
 		Returns struct {
@@ -15,8 +16,9 @@ type AWSDeleteLBs struct {
 	}
 }
 
-func (a *AWSDeleteLBs) Execute(state storage.State) error {
+func (a *AWSDeleteLBs) Execute(lbType string, state storage.State) error {
 	a.ExecuteCall.CallCount++
+	a.ExecuteCall.Receives.LBType = lbType
 	a.ExecuteCall.Receives.State = state
 	return a.ExecuteCall.Returns.Error
 }
@@ -13,6 +13,54 @@ type BOSHClient struct {
 		}
 	}
 
+	CloudConfigCall struct {
+		CallCount int
+		Returns   struct {
+			CloudConfig string
+			Error       error
+		}
+	}
+
+	UpdateRuntimeConfigCall struct {
+		CallCount int
+		Receives  struct {
+			Name string
+			Yaml []byte
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	RuntimeConfigCall struct {
+		CallCount int
+		Receives  struct {
+			Name string
+		}
+		Returns struct {
+			RuntimeConfig string
+			Error         error
+		}
+	}
+
+	UpdateCPIConfigCall struct {
+		CallCount int
+		Receives  struct {
+			Yaml []byte
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	CPIConfigCall struct {
+		CallCount int
+		Returns   struct {
+			CPIConfig string
+			Error     error
+		}
+	}
+
 	InfoCall struct {
 		CallCount int
 		Returns   struct {
@@ -20,6 +68,62 @@ type BOSHClient struct {
 			Error error
 		}
 	}
+
+	DeploymentsCall struct {
+		CallCount int
+		Returns   struct {
+			Deployments []bosh.Deployment
+			Error       error
+		}
+	}
+
+	DeleteDeploymentCall struct {
+		CallCount int
+		Receives  struct {
+			Name string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	TaskCall struct {
+		CallCount int
+		Receives  struct {
+			ID int
+		}
+		Returns struct {
+			Task  bosh.Task
+			Error error
+		}
+	}
+
+	WaitForTaskCall struct {
+		CallCount int
+		Receives  struct {
+			ID int
+		}
+		Returns struct {
+			Task  bosh.Task
+			Error error
+		}
+	}
+
+	StemcellsCall struct {
+		CallCount int
+		Returns   struct {
+			Stemcells []bosh.Stemcell
+			Error     error
+		}
+	}
+
+	ReleasesCall struct {
+		CallCount int
+		Returns   struct {
+			Releases []bosh.Release
+			Error    error
+		}
+	}
 }
 
 func (c *BOSHClient) UpdateCloudConfig(yaml []byte) error {
@@ -28,7 +132,69 @@ func (c *BOSHClient) UpdateCloudConfig(yaml []byte) error {
 	return c.UpdateCloudConfigCall.Returns.Error
 }
 
+func (c *BOSHClient) CloudConfig() (string, error) {
+	c.CloudConfigCall.CallCount++
+	return c.CloudConfigCall.Returns.CloudConfig, c.CloudConfigCall.Returns.Error
+}
+
+func (c *BOSHClient) UpdateRuntimeConfig(name string, yaml []byte) error {
+	c.UpdateRuntimeConfigCall.CallCount++
+	c.UpdateRuntimeConfigCall.Receives.Name = name
+	c.UpdateRuntimeConfigCall.Receives.Yaml = yaml
+	return c.UpdateRuntimeConfigCall.Returns.Error
+}
+
+func (c *BOSHClient) RuntimeConfig(name string) (string, error) {
+	c.RuntimeConfigCall.CallCount++
+	c.RuntimeConfigCall.Receives.Name = name
+	return c.RuntimeConfigCall.Returns.RuntimeConfig, c.RuntimeConfigCall.Returns.Error
+}
+
+func (c *BOSHClient) UpdateCPIConfig(yaml []byte) error {
+	c.UpdateCPIConfigCall.CallCount++
+	c.UpdateCPIConfigCall.Receives.Yaml = yaml
+	return c.UpdateCPIConfigCall.Returns.Error
+}
+
+func (c *BOSHClient) CPIConfig() (string, error) {
+	c.CPIConfigCall.CallCount++
+	return c.CPIConfigCall.Returns.CPIConfig, c.CPIConfigCall.Returns.Error
+}
+
 func (c *BOSHClient) Info() (bosh.Info, error) {
 	c.InfoCall.CallCount++
 	return c.InfoCall.Returns.Info, c.InfoCall.Returns.Error
 }
+
+func (c *BOSHClient) Deployments() ([]bosh.Deployment, error) {
+	c.DeploymentsCall.CallCount++
+	return c.DeploymentsCall.Returns.Deployments, c.DeploymentsCall.Returns.Error
+}
+
+func (c *BOSHClient) DeleteDeployment(name string) error {
+	c.DeleteDeploymentCall.CallCount++
+	c.DeleteDeploymentCall.Receives.Name = name
+	return c.DeleteDeploymentCall.Returns.Error
+}
+
+func (c *BOSHClient) Task(id int) (bosh.Task, error) {
+	c.TaskCall.CallCount++
+	c.TaskCall.Receives.ID = id
+	return c.TaskCall.Returns.Task, c.TaskCall.Returns.Error
+}
+
+func (c *BOSHClient) WaitForTask(id int) (bosh.Task, error) {
+	c.WaitForTaskCall.CallCount++
+	c.WaitForTaskCall.Receives.ID = id
+	return c.WaitForTaskCall.Returns.Task, c.WaitForTaskCall.Returns.Error
+}
+
+func (c *BOSHClient) Stemcells() ([]bosh.Stemcell, error) {
+	c.StemcellsCall.CallCount++
+	return c.StemcellsCall.Returns.Stemcells, c.StemcellsCall.Returns.Error
+}
+
+func (c *BOSHClient) Releases() ([]bosh.Release, error) {
+	c.ReleasesCall.CallCount++
+	return c.ReleasesCall.Returns.Releases, c.ReleasesCall.Returns.Error
+}
@@ -12,6 +12,7 @@ type BOSHClientProvider struct {
 			DirectorAddress  string
 			DirectorUsername string
 			DirectorPassword string
+			DirectorCACert   string
 		}
 		Returns struct {
 			Client bosh.Client
@@ -19,10 +20,11 @@ type BOSHClientProvider struct {
 	}
 }
 
-func (b *BOSHClientProvider) Client(directorAddress, directorUsername, directorPassword string) bosh.Client {
+func (b *BOSHClientProvider) Client(directorAddress, directorUsername, directorPassword, directorCACert string) bosh.Client {
 	b.ClientCall.CallCount++
 	b.ClientCall.Receives.DirectorAddress = directorAddress
 	b.ClientCall.Receives.DirectorUsername = directorUsername
 	b.ClientCall.Receives.DirectorPassword = directorPassword
+	b.ClientCall.Receives.DirectorCACert = directorCACert
 	return b.ClientCall.Returns.Client
 }
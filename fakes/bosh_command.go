@@ -19,6 +19,20 @@ type BOSHCommand struct {
 	runReturnsOnCall map[int]struct {
 		result1 error
 	}
+	RunWithEnvStub        func(stdout io.Writer, workingDirectory string, args []string, env []string) error
+	runWithEnvMutex       sync.RWMutex
+	runWithEnvArgsForCall []struct {
+		stdout           io.Writer
+		workingDirectory string
+		args             []string
+		env              []string
+	}
+	runWithEnvReturns struct {
+		result1 error
+	}
+	runWithEnvReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -79,11 +93,75 @@ func (fake *BOSHCommand) RunReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *BOSHCommand) RunWithEnv(stdout io.Writer, workingDirectory string, args []string, env []string) error {
+	var argsCopy []string
+	if args != nil {
+		argsCopy = make([]string, len(args))
+		copy(argsCopy, args)
+	}
+	var envCopy []string
+	if env != nil {
+		envCopy = make([]string, len(env))
+		copy(envCopy, env)
+	}
+	fake.runWithEnvMutex.Lock()
+	ret, specificReturn := fake.runWithEnvReturnsOnCall[len(fake.runWithEnvArgsForCall)]
+	fake.runWithEnvArgsForCall = append(fake.runWithEnvArgsForCall, struct {
+		stdout           io.Writer
+		workingDirectory string
+		args             []string
+		env              []string
+	}{stdout, workingDirectory, argsCopy, envCopy})
+	fake.recordInvocation("RunWithEnv", []interface{}{stdout, workingDirectory, argsCopy, envCopy})
+	fake.runWithEnvMutex.Unlock()
+
+	if fake.RunWithEnvStub != nil {
+		return fake.RunWithEnvStub(stdout, workingDirectory, args, env)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.runWithEnvReturns.result1
+}
+
+func (fake *BOSHCommand) RunWithEnvCallCount() int {
+	fake.runWithEnvMutex.RLock()
+	defer fake.runWithEnvMutex.RUnlock()
+	return len(fake.runWithEnvArgsForCall)
+}
+
+func (fake *BOSHCommand) RunWithEnvArgsForCall(i int) (io.Writer, string, []string, []string) {
+	fake.runWithEnvMutex.RLock()
+	defer fake.runWithEnvMutex.RUnlock()
+	return fake.runWithEnvArgsForCall[i].stdout, fake.runWithEnvArgsForCall[i].workingDirectory, fake.runWithEnvArgsForCall[i].args, fake.runWithEnvArgsForCall[i].env
+}
+
+func (fake *BOSHCommand) RunWithEnvReturns(result1 error) {
+	fake.RunWithEnvStub = nil
+	fake.runWithEnvReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *BOSHCommand) RunWithEnvReturnsOnCall(i int, result1 error) {
+	fake.RunWithEnvStub = nil
+	if fake.runWithEnvReturnsOnCall == nil {
+		fake.runWithEnvReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.runWithEnvReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *BOSHCommand) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.runMutex.RLock()
 	defer fake.runMutex.RUnlock()
+	fake.runWithEnvMutex.RLock()
+	defer fake.runWithEnvMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -26,6 +26,7 @@ type BOSHExecutor struct {
 
 	InterpolateCall struct {
 		CallCount int
+		Stub      func(bosh.InterpolateInput) (bosh.InterpolateOutput, error)
 		Receives  struct {
 			InterpolateInput bosh.InterpolateInput
 		}
@@ -62,6 +63,10 @@ func (e *BOSHExecutor) Interpolate(input bosh.InterpolateInput) (bosh.Interpolat
 	e.InterpolateCall.CallCount++
 	e.InterpolateCall.Receives.InterpolateInput = input
 
+	if e.InterpolateCall.Stub != nil {
+		return e.InterpolateCall.Stub(input)
+	}
+
 	return e.InterpolateCall.Returns.Output, e.InterpolateCall.Returns.Error
 }
 
@@ -54,6 +54,18 @@ type CloudFormationClient struct {
 			Error  error
 		}
 	}
+
+	DescribeStackEventsCall struct {
+		CallCount int
+		Stub      func(*cloudformation.DescribeStackEventsInput) (*cloudformation.DescribeStackEventsOutput, error)
+		Receives  struct {
+			Input *cloudformation.DescribeStackEventsInput
+		}
+		Returns struct {
+			Output *cloudformation.DescribeStackEventsOutput
+			Error  error
+		}
+	}
 }
 
 func (c *CloudFormationClient) CreateStack(input *cloudformation.CreateStackInput) (*cloudformation.CreateStackOutput, error) {
@@ -88,3 +100,14 @@ func (c *CloudFormationClient) DescribeStackResource(input *cloudformation.Descr
 	return c.DescribeStackResourceCall.Returns.Output, c.DescribeStackResourceCall.Returns.Error
 
 }
+
+func (c *CloudFormationClient) DescribeStackEvents(input *cloudformation.DescribeStackEventsInput) (*cloudformation.DescribeStackEventsOutput, error) {
+	c.DescribeStackEventsCall.CallCount++
+	c.DescribeStackEventsCall.Receives.Input = input
+
+	if c.DescribeStackEventsCall.Stub != nil {
+		return c.DescribeStackEventsCall.Stub(input)
+	}
+
+	return c.DescribeStackEventsCall.Returns.Output, c.DescribeStackEventsCall.Returns.Error
+}
@@ -43,6 +43,16 @@ type GCPClient struct {
 			Error       error
 		}
 	}
+	ListZonesCall struct {
+		CallCount int
+		Receives  struct {
+			Region string
+		}
+		Returns struct {
+			ZoneList *compute.ZoneList
+			Error    error
+		}
+	}
 }
 
 func (g *GCPClient) ProjectID() string {
@@ -71,3 +81,9 @@ func (g *GCPClient) GetNetworks(name string) (*compute.NetworkList, error) {
 	g.GetNetworksCall.Receives.Name = name
 	return g.GetNetworksCall.Returns.NetworkList, g.GetNetworksCall.Returns.Error
 }
+
+func (g *GCPClient) ListZones(region string) (*compute.ZoneList, error) {
+	g.ListZonesCall.CallCount++
+	g.ListZonesCall.Receives.Region = region
+	return g.ListZonesCall.Returns.ZoneList, g.ListZonesCall.Returns.Error
+}
@@ -9,6 +9,7 @@ type GCPCreateLBs struct {
 	Name        string
 	ExecuteCall struct {
 		CallCount int
+		Stub      func(commands.GCPCreateLBsConfig, storage.State) error
 		Receives  struct {
 			Config commands.GCPCreateLBsConfig
 			State  storage.State
@@ -23,5 +24,10 @@ func (u *GCPCreateLBs) Execute(config commands.GCPCreateLBsConfig, state storage
 	u.ExecuteCall.CallCount++
 	u.ExecuteCall.Receives.Config = config
 	u.ExecuteCall.Receives.State = state
+
+	if u.ExecuteCall.Stub != nil {
+		return u.ExecuteCall.Stub(config, state)
+	}
+
 	return u.ExecuteCall.Returns.Error
 }
@@ -6,7 +6,8 @@ type GCPDeleteLBs struct {
 	ExecuteCall struct {
 		CallCount int
 		Receives  struct {
-			State storage.State
+			LBType string
+			State  storage.State
 		}
 
 		Returns struct {
@@ -15,8 +16,9 @@ type GCPDeleteLBs struct {
 	}
 }
 
-func (g *GCPDeleteLBs) Execute(state storage.State) error {
+func (g *GCPDeleteLBs) Execute(lbType string, state storage.State) error {
 	g.ExecuteCall.CallCount++
+	g.ExecuteCall.Receives.LBType = lbType
 	g.ExecuteCall.Receives.State = state
 	return g.ExecuteCall.Returns.Error
 }
@@ -14,6 +14,11 @@ type InfrastructureManager struct {
 			AZs              []string
 			BOSHAZ           string
 			EnvID            string
+
+			DirectorAllowedCIDRs []string
+			LBAllowedCIDRs       []string
+			NATType              string
+			Tags                 map[string]string
 		}
 		Returns struct {
 			Stack cloudformation.Stack
@@ -31,6 +36,11 @@ type InfrastructureManager struct {
 			LBCertificateARN string
 			BOSHAZ           string
 			EnvID            string
+
+			DirectorAllowedCIDRs []string
+			LBAllowedCIDRs       []string
+			NATType              string
+			Tags                 map[string]string
 		}
 		Returns struct {
 			Stack cloudformation.Stack
@@ -68,9 +78,43 @@ type InfrastructureManager struct {
 			Error error
 		}
 	}
+
+	DeleteRetainingResourcesCall struct {
+		CallCount int
+		Receives  struct {
+			KeyPairName      string
+			AZs              []string
+			StackName        string
+			LBType           string
+			LBCertificateARN string
+			BOSHAZ           string
+			EnvID            string
+
+			DirectorAllowedCIDRs []string
+			LBAllowedCIDRs       []string
+			NATType              string
+			Tags                 map[string]string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	GetPhysicalIDForResourceCall struct {
+		CallCount int
+		Stub      func(stackName, logicalResourceID string) (string, error)
+		Receives  struct {
+			StackName         string
+			LogicalResourceID string
+		}
+		Returns struct {
+			PhysicalResourceID string
+			Error              error
+		}
+	}
 }
 
-func (m *InfrastructureManager) Create(keyPairName string, azs []string, stackName, boshAZ, lbType, lbCertificateARN, envID string) (cloudformation.Stack, error) {
+func (m *InfrastructureManager) Create(keyPairName string, azs []string, stackName, boshAZ, lbType, lbCertificateARN, envID string, directorAllowedCIDRs, lbAllowedCIDRs []string, natType string, tags map[string]string) (cloudformation.Stack, error) {
 	m.CreateCall.CallCount++
 	m.CreateCall.Receives.StackName = stackName
 	m.CreateCall.Receives.LBType = lbType
@@ -79,6 +123,10 @@ func (m *InfrastructureManager) Create(keyPairName string, azs []string, stackNa
 	m.CreateCall.Receives.AZs = azs
 	m.CreateCall.Receives.BOSHAZ = boshAZ
 	m.CreateCall.Receives.EnvID = envID
+	m.CreateCall.Receives.DirectorAllowedCIDRs = directorAllowedCIDRs
+	m.CreateCall.Receives.LBAllowedCIDRs = lbAllowedCIDRs
+	m.CreateCall.Receives.NATType = natType
+	m.CreateCall.Receives.Tags = tags
 
 	if m.CreateCall.Stub != nil {
 		return m.CreateCall.Stub(keyPairName, azs, stackName, lbType, envID)
@@ -87,7 +135,7 @@ func (m *InfrastructureManager) Create(keyPairName string, azs []string, stackNa
 	return m.CreateCall.Returns.Stack, m.CreateCall.Returns.Error
 }
 
-func (m *InfrastructureManager) Update(keyPairName string, azs []string, stackName, boshAZ, lbType, lbCertificateARN, envID string) (cloudformation.Stack, error) {
+func (m *InfrastructureManager) Update(keyPairName string, azs []string, stackName, boshAZ, lbType, lbCertificateARN, envID string, directorAllowedCIDRs, lbAllowedCIDRs []string, natType string, tags map[string]string) (cloudformation.Stack, error) {
 	m.UpdateCall.CallCount++
 	m.UpdateCall.Receives.KeyPairName = keyPairName
 	m.UpdateCall.Receives.AZs = azs
@@ -96,6 +144,10 @@ func (m *InfrastructureManager) Update(keyPairName string, azs []string, stackNa
 	m.UpdateCall.Receives.LBCertificateARN = lbCertificateARN
 	m.UpdateCall.Receives.BOSHAZ = boshAZ
 	m.UpdateCall.Receives.EnvID = envID
+	m.UpdateCall.Receives.DirectorAllowedCIDRs = directorAllowedCIDRs
+	m.UpdateCall.Receives.LBAllowedCIDRs = lbAllowedCIDRs
+	m.UpdateCall.Receives.NATType = natType
+	m.UpdateCall.Receives.Tags = tags
 	return m.UpdateCall.Returns.Stack, m.UpdateCall.Returns.Error
 }
 
@@ -118,3 +170,32 @@ func (m *InfrastructureManager) Describe(stackName string) (cloudformation.Stack
 
 	return m.DescribeCall.Returns.Stack, m.DescribeCall.Returns.Error
 }
+
+func (m *InfrastructureManager) DeleteRetainingResources(keyPairName string, azs []string, stackName, boshAZ, lbType, lbCertificateARN, envID string, directorAllowedCIDRs, lbAllowedCIDRs []string, natType string, tags map[string]string) error {
+	m.DeleteRetainingResourcesCall.CallCount++
+	m.DeleteRetainingResourcesCall.Receives.KeyPairName = keyPairName
+	m.DeleteRetainingResourcesCall.Receives.AZs = azs
+	m.DeleteRetainingResourcesCall.Receives.StackName = stackName
+	m.DeleteRetainingResourcesCall.Receives.LBType = lbType
+	m.DeleteRetainingResourcesCall.Receives.LBCertificateARN = lbCertificateARN
+	m.DeleteRetainingResourcesCall.Receives.BOSHAZ = boshAZ
+	m.DeleteRetainingResourcesCall.Receives.EnvID = envID
+	m.DeleteRetainingResourcesCall.Receives.DirectorAllowedCIDRs = directorAllowedCIDRs
+	m.DeleteRetainingResourcesCall.Receives.LBAllowedCIDRs = lbAllowedCIDRs
+	m.DeleteRetainingResourcesCall.Receives.NATType = natType
+	m.DeleteRetainingResourcesCall.Receives.Tags = tags
+
+	return m.DeleteRetainingResourcesCall.Returns.Error
+}
+
+func (m *InfrastructureManager) GetPhysicalIDForResource(stackName, logicalResourceID string) (string, error) {
+	m.GetPhysicalIDForResourceCall.CallCount++
+	m.GetPhysicalIDForResourceCall.Receives.StackName = stackName
+	m.GetPhysicalIDForResourceCall.Receives.LogicalResourceID = logicalResourceID
+
+	if m.GetPhysicalIDForResourceCall.Stub != nil {
+		return m.GetPhysicalIDForResourceCall.Stub(stackName, logicalResourceID)
+	}
+
+	return m.GetPhysicalIDForResourceCall.Returns.PhysicalResourceID, m.GetPhysicalIDForResourceCall.Returns.Error
+}
@@ -0,0 +1,25 @@
+package fakes
+
+import "github.com/cloudfoundry/bosh-bootloader/ssl"
+
+type KeyPairGenerator struct {
+	GenerateWildcardCall struct {
+		CallCount int
+		Receives  struct {
+			CACommonName string
+			Domain       string
+		}
+		Returns struct {
+			KeyPair ssl.KeyPair
+			Error   error
+		}
+	}
+}
+
+func (k *KeyPairGenerator) GenerateWildcard(caCommonName, domain string) (ssl.KeyPair, error) {
+	k.GenerateWildcardCall.CallCount++
+	k.GenerateWildcardCall.Receives.CACommonName = caCommonName
+	k.GenerateWildcardCall.Receives.Domain = domain
+
+	return k.GenerateWildcardCall.Returns.KeyPair, k.GenerateWildcardCall.Returns.Error
+}
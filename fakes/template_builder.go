@@ -12,6 +12,10 @@ type TemplateBuilder struct {
 			IAMUserName      string
 			EnvID            string
 			BOSHAZ           string
+
+			DirectorAllowedCIDRs []string
+			LBAllowedCIDRs       []string
+			NATType              string
 		}
 		Returns struct {
 			Template templates.Template
@@ -19,7 +23,7 @@ type TemplateBuilder struct {
 	}
 }
 
-func (b *TemplateBuilder) Build(keyPairName string, azs []string, lbType string, lbCertificateARN string, iamUserName string, envID string, boshAZ string) templates.Template {
+func (b *TemplateBuilder) Build(keyPairName string, azs []string, lbType string, lbCertificateARN string, iamUserName string, envID string, boshAZ string, directorAllowedCIDRs, lbAllowedCIDRs []string, natType string) templates.Template {
 	b.BuildCall.Receives.KeyPairName = keyPairName
 	b.BuildCall.Receives.AZs = azs
 	b.BuildCall.Receives.LBType = lbType
@@ -27,6 +31,9 @@ func (b *TemplateBuilder) Build(keyPairName string, azs []string, lbType string,
 	b.BuildCall.Receives.IAMUserName = iamUserName
 	b.BuildCall.Receives.EnvID = envID
 	b.BuildCall.Receives.BOSHAZ = boshAZ
+	b.BuildCall.Receives.DirectorAllowedCIDRs = directorAllowedCIDRs
+	b.BuildCall.Receives.LBAllowedCIDRs = lbAllowedCIDRs
+	b.BuildCall.Receives.NATType = natType
 
 	return b.BuildCall.Returns.Template
 }
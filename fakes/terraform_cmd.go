@@ -15,6 +15,10 @@ type TerraformCmd struct {
 			Args             []string
 			Debug            bool
 		}
+		ArgsForCall [][]string
+		// ErrorsForCall overrides Returns.Error for the calls, counted
+		// from zero, that it has an entry for.
+		ErrorsForCall map[int]error
 	}
 }
 
@@ -24,10 +28,15 @@ func (t *TerraformCmd) Run(stdout io.Writer, workingDirectory string, args []str
 	t.RunCall.Receives.WorkingDirectory = workingDirectory
 	t.RunCall.Receives.Args = args
 	t.RunCall.Receives.Debug = debug
+	t.RunCall.ArgsForCall = append(t.RunCall.ArgsForCall, args)
 
 	if t.RunCall.Stub != nil {
 		t.RunCall.Stub(stdout)
 	}
 
+	if err, ok := t.RunCall.ErrorsForCall[t.RunCall.CallCount-1]; ok {
+		return err
+	}
+
 	return t.RunCall.Returns.Error
 }
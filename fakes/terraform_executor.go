@@ -25,6 +25,58 @@ type TerraformExecutor struct {
 			Error   error
 		}
 	}
+	ImportCall struct {
+		CallCount int
+		Receives  struct {
+			Inputs    map[string]string
+			Template  string
+			TFState   string
+			Resources map[string]string
+		}
+		Returns struct {
+			TFState string
+			Error   error
+		}
+	}
+	ApplyTargetsCall struct {
+		CallCount int
+		Receives  struct {
+			Inputs   map[string]string
+			Template string
+			TFState  string
+			Targets  []string
+		}
+		Returns struct {
+			TFState string
+			Error   error
+		}
+	}
+	PlanCall struct {
+		CallCount int
+		Stub      func(tfState string, targets []string) (bool, error)
+		Receives  struct {
+			Inputs   map[string]string
+			Template string
+			TFState  string
+			Targets  []string
+		}
+		Returns struct {
+			HasChanges bool
+			Error      error
+		}
+	}
+	MoveStateCall struct {
+		CallCount int
+		Receives  struct {
+			TFState string
+			From    string
+			To      string
+		}
+		Returns struct {
+			TFState string
+			Error   error
+		}
+	}
 	VersionCall struct {
 		CallCount int
 		Returns   struct {
@@ -73,6 +125,46 @@ func (t *TerraformExecutor) Destroy(inputs map[string]string, template, tfState
 	return t.DestroyCall.Returns.TFState, t.DestroyCall.Returns.Error
 }
 
+func (t *TerraformExecutor) Import(inputs map[string]string, template, tfState string, resources map[string]string) (string, error) {
+	t.ImportCall.CallCount++
+	t.ImportCall.Receives.Inputs = inputs
+	t.ImportCall.Receives.Template = template
+	t.ImportCall.Receives.TFState = tfState
+	t.ImportCall.Receives.Resources = resources
+	return t.ImportCall.Returns.TFState, t.ImportCall.Returns.Error
+}
+
+func (t *TerraformExecutor) ApplyTargets(inputs map[string]string, template, tfState string, targets []string) (string, error) {
+	t.ApplyTargetsCall.CallCount++
+	t.ApplyTargetsCall.Receives.Inputs = inputs
+	t.ApplyTargetsCall.Receives.Template = template
+	t.ApplyTargetsCall.Receives.TFState = tfState
+	t.ApplyTargetsCall.Receives.Targets = targets
+	return t.ApplyTargetsCall.Returns.TFState, t.ApplyTargetsCall.Returns.Error
+}
+
+func (t *TerraformExecutor) Plan(inputs map[string]string, template, tfState string, targets []string) (bool, error) {
+	t.PlanCall.CallCount++
+	t.PlanCall.Receives.Inputs = inputs
+	t.PlanCall.Receives.Template = template
+	t.PlanCall.Receives.TFState = tfState
+	t.PlanCall.Receives.Targets = targets
+
+	if t.PlanCall.Stub != nil {
+		return t.PlanCall.Stub(tfState, targets)
+	}
+
+	return t.PlanCall.Returns.HasChanges, t.PlanCall.Returns.Error
+}
+
+func (t *TerraformExecutor) MoveState(tfState, from, to string) (string, error) {
+	t.MoveStateCall.CallCount++
+	t.MoveStateCall.Receives.TFState = tfState
+	t.MoveStateCall.Receives.From = from
+	t.MoveStateCall.Receives.To = to
+	return t.MoveStateCall.Returns.TFState, t.MoveStateCall.Returns.Error
+}
+
 func (t *TerraformExecutor) Version() (string, error) {
 	t.VersionCall.CallCount++
 	return t.VersionCall.Returns.Version, t.VersionCall.Returns.Error
@@ -42,6 +42,18 @@ type TerraformManager struct {
 			Error    error
 		}
 	}
+	ImportCall struct {
+		CallCount int
+		Receives  struct {
+			BBLState         storage.State
+			Resources        map[string]string
+			CreatedResources []string
+		}
+		Returns struct {
+			BBLState storage.State
+			Error    error
+		}
+	}
 	ValidateVersionCall struct {
 		CallCount int
 		Returns   struct {
@@ -57,6 +69,15 @@ func (t *TerraformManager) Apply(bblState storage.State) (storage.State, error)
 	return t.ApplyCall.Returns.BBLState, t.ApplyCall.Returns.Error
 }
 
+func (t *TerraformManager) Import(bblState storage.State, resources map[string]string, createdResources []string) (storage.State, error) {
+	t.ImportCall.CallCount++
+	t.ImportCall.Receives.BBLState = bblState
+	t.ImportCall.Receives.Resources = resources
+	t.ImportCall.Receives.CreatedResources = createdResources
+
+	return t.ImportCall.Returns.BBLState, t.ImportCall.Returns.Error
+}
+
 func (t *TerraformManager) Destroy(bblState storage.State) (storage.State, error) {
 	t.DestroyCall.CallCount++
 	t.DestroyCall.Receives.BBLState = bblState
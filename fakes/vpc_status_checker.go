@@ -4,8 +4,9 @@ type VPCStatusChecker struct {
 	ValidateSafeToDeleteCall struct {
 		CallCount int
 		Receives  struct {
-			VPCID string
-			EnvID string
+			VPCID   string
+			EnvID   string
+			NATType string
 		}
 		Returns struct {
 			Error error
@@ -13,9 +14,10 @@ type VPCStatusChecker struct {
 	}
 }
 
-func (v *VPCStatusChecker) ValidateSafeToDelete(vpcID, envID string) error {
+func (v *VPCStatusChecker) ValidateSafeToDelete(vpcID, envID, natType string) error {
 	v.ValidateSafeToDeleteCall.CallCount++
 	v.ValidateSafeToDeleteCall.Receives.VPCID = vpcID
 	v.ValidateSafeToDeleteCall.Receives.EnvID = envID
+	v.ValidateSafeToDeleteCall.Receives.NATType = natType
 	return v.ValidateSafeToDeleteCall.Returns.Error
 }
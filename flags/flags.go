@@ -3,6 +3,7 @@ package flags
 import (
 	"flag"
 	"io/ioutil"
+	"strings"
 )
 
 type Flags struct {
@@ -30,6 +31,15 @@ func (f Flags) String(v *string, name string, value string) {
 	f.set.StringVar(v, name, value, "")
 }
 
+func (f Flags) Int(v *int, name string, value int) {
+	f.set.IntVar(v, name, value, "")
+}
+
+func (f Flags) StringSlice(v *[]string, name string, value []string) {
+	*v = value
+	f.set.Var(&stringSlice{values: v}, name, "")
+}
+
 func (f Flags) Parse(args []string) error {
 	return f.set.Parse(args)
 }
@@ -37,3 +47,26 @@ func (f Flags) Parse(args []string) error {
 func (f Flags) Args() []string {
 	return f.set.Args()
 }
+
+type stringSlice struct {
+	values *[]string
+	set    bool
+}
+
+func (s *stringSlice) String() string {
+	if s.values == nil {
+		return ""
+	}
+	return strings.Join(*s.values, ",")
+}
+
+func (s *stringSlice) Set(value string) error {
+	// The first occurrence replaces the default so that flags given on the
+	// command line are not appended to values from the environment.
+	if !s.set {
+		*s.values = []string{}
+		s.set = true
+	}
+	*s.values = append(*s.values, value)
+	return nil
+}
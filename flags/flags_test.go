@@ -9,15 +9,19 @@ import (
 
 var _ = Describe("Flags", func() {
 	var (
-		f         flags.Flags
-		boolVal   bool
-		stringVal string
+		f              flags.Flags
+		boolVal        bool
+		stringVal      string
+		intVal         int
+		stringSliceVal []string
 	)
 
 	BeforeEach(func() {
 		f = flags.New("test")
 		f.Bool(&boolVal, "b", "bool", false)
 		f.String(&stringVal, "string", "")
+		f.Int(&intVal, "int", 0)
+		f.StringSlice(&stringSliceVal, "string-slice", []string{"default-value"})
 	})
 
 	Describe("Parse", func() {
@@ -49,6 +53,33 @@ var _ = Describe("Flags", func() {
 				Expect(stringVal).To(Equal("string_value"))
 			})
 		})
+
+		Context("Int flags", func() {
+			It("can parse int fields from flags", func() {
+				err := f.Parse([]string{"--int", "3"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(intVal).To(Equal(3))
+			})
+
+			It("returns an error when the value is not an int", func() {
+				err := f.Parse([]string{"--int", "three"})
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("StringSlice flags", func() {
+			It("collects every occurrence of the flag", func() {
+				err := f.Parse([]string{"--string-slice", "first_value", "--string-slice", "second_value"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stringSliceVal).To(Equal([]string{"first_value", "second_value"}))
+			})
+
+			It("uses the default when the flag is not provided", func() {
+				err := f.Parse([]string{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stringSliceVal).To(Equal([]string{"default-value"}))
+			})
+		})
 	})
 
 	Describe("Args", func() {
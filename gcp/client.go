@@ -12,6 +12,7 @@ type Client interface {
 	SetCommonInstanceMetadata(metadata *compute.Metadata) (*compute.Operation, error)
 	ListInstances() (*compute.InstanceList, error)
 	GetNetworks(name string) (*compute.NetworkList, error)
+	ListZones(region string) (*compute.ZoneList, error)
 }
 
 type GCPClient struct {
@@ -40,3 +41,8 @@ func (c GCPClient) GetNetworks(name string) (*compute.NetworkList, error) {
 	networksListCall := c.service.Networks.List(c.projectID)
 	return networksListCall.Filter(fmt.Sprintf("name eq %s", name)).Do()
 }
+
+func (c GCPClient) ListZones(region string) (*compute.ZoneList, error) {
+	zonesListCall := c.service.Zones.List(c.projectID)
+	return zonesListCall.Filter(fmt.Sprintf("region eq .*/regions/%s", region)).Do()
+}
@@ -1,20 +1,39 @@
 package gcp
 
-type Zones struct{}
+import (
+	"fmt"
+	"sort"
+)
 
-func NewZones() Zones {
-	return Zones{}
+type Zones struct {
+	clientProvider clientProvider
 }
 
-func (Zones) Get(region string) []string {
-	azs := map[string][]string{
-		"us-west1":        []string{"us-west1-a", "us-west1-b", "us-west1-a"},
-		"us-central1":     []string{"us-central1-a", "us-central1-b", "us-central1-c", "us-central1-f"},
-		"us-east1":        []string{"us-east1-b", "us-east1-c", "us-east1-d"},
-		"europe-west1":    []string{"europe-west1-b", "europe-west1-c", "europe-west1-d"},
-		"asia-east1":      []string{"asia-east1-a", "asia-east1-b", "asia-east1-c"},
-		"asia-northeast1": []string{"asia-northeast1-a", "asia-northeast1-b", "asia-northeast1-c"},
+func NewZones(clientProvider clientProvider) Zones {
+	return Zones{
+		clientProvider: clientProvider,
 	}
+}
+
+// Get returns the sorted names of the zones in the region that are up.
+func (z Zones) Get(region string) ([]string, error) {
+	zoneList, err := z.clientProvider.Client().ListZones(region)
+	if err != nil {
+		return []string{}, fmt.Errorf("failed to list zones for region %s: %s", region, err)
+	}
+
+	zones := []string{}
+	for _, zone := range zoneList.Items {
+		if zone.Status == "UP" {
+			zones = append(zones, zone.Name)
+		}
+	}
+
+	if len(zones) == 0 {
+		return []string{}, fmt.Errorf("no zones are available in region %s", region)
+	}
+
+	sort.Strings(zones)
 
-	return azs[region]
+	return zones, nil
 }
@@ -33,6 +33,7 @@ variable "credentials" {
 }
 
 provider "google" {
+	version = "~> 1.20"
 	credentials = "${file("${var.credentials}")}"
 	project = "${var.project_id}"
 	region = "${var.region}"
@@ -68,6 +68,10 @@ func (m Manager) Rotate(state storage.State) (storage.State, error) {
 	m.clientProvider.SetConfig(aws.Config{
 		AccessKeyID:     state.AWS.AccessKeyID,
 		SecretAccessKey: state.AWS.SecretAccessKey,
+		SessionToken:    state.AWS.SessionToken,
+		Profile:         state.AWS.Profile,
+		AssumeRoleARN:   state.AWS.AssumeRoleARN,
+		MFASerial:       state.AWS.MFASerial,
 		Region:          state.AWS.Region,
 	})
 
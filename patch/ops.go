@@ -0,0 +1,365 @@
+package patch
+
+import (
+	"fmt"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Op is a single go-patch operation.
+type Op struct {
+	Type  string      `yaml:"type"`
+	Path  string      `yaml:"path"`
+	Value interface{} `yaml:"value,omitempty"`
+}
+
+type Ops []Op
+
+// ParseOps reads an ops file in the format accepted by `bosh interpolate -o`.
+func ParseOps(contents string) (Ops, error) {
+	var ops Ops
+	err := yaml.Unmarshal([]byte(contents), &ops)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ops file: %s", err)
+	}
+
+	for _, op := range ops {
+		switch op.Type {
+		case "replace", "remove":
+		default:
+			return nil, fmt.Errorf("unknown operation %q for path %q", op.Type, op.Path)
+		}
+
+		_, err = parsePointer(op.Path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ops, nil
+}
+
+// Apply runs every operation in order against the document and returns the
+// result. The document may be modified in place.
+func (o Ops) Apply(document interface{}) (interface{}, error) {
+	for _, op := range o {
+		tokens, err := parsePointer(op.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		switch op.Type {
+		case "replace":
+			document, err = replace(document, tokens, copyValue(op.Value), op.Path)
+		case "remove":
+			document, err = remove(document, tokens, op.Path)
+		default:
+			err = fmt.Errorf("unknown operation %q for path %q", op.Type, op.Path)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return document, nil
+}
+
+// Interpolate applies each ops file, in order, to the YAML template and
+// returns the resulting YAML document.
+func Interpolate(template string, opsFiles ...string) (string, error) {
+	var document interface{}
+	err := yaml.Unmarshal([]byte(template), &document)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %s", err)
+	}
+
+	for _, opsFile := range opsFiles {
+		ops, err := ParseOps(opsFile)
+		if err != nil {
+			return "", err
+		}
+
+		document, err = ops.Apply(document)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	contents, err := yaml.Marshal(document)
+	if err != nil {
+		return "", err
+	}
+
+	return string(contents), nil
+}
+
+func replace(node interface{}, tokens []token, value interface{}, path string) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	rest := tokens[1:]
+
+	switch t := tokens[0].(type) {
+	case indexToken:
+		items, ok := node.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected to find an array at path %q but found %s", path, describe(node))
+		}
+
+		index, err := resolveIndex(t.index, len(items), path)
+		if err != nil {
+			return nil, err
+		}
+
+		items[index], err = replace(items[index], rest, value, path)
+		if err != nil {
+			return nil, err
+		}
+
+		return items, nil
+
+	case afterLastIndexToken:
+		if node == nil {
+			node = []interface{}{}
+		}
+
+		items, ok := node.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected to find an array at path %q but found %s", path, describe(node))
+		}
+
+		return append(items, value), nil
+
+	case matchingIndexToken:
+		if node == nil && t.optional {
+			node = []interface{}{}
+		}
+
+		items, ok := node.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected to find an array at path %q but found %s", path, describe(node))
+		}
+
+		index, err := findMatchingIndex(items, t, path)
+		if err != nil {
+			return nil, err
+		}
+
+		if index == -1 {
+			if len(rest) == 0 {
+				return append(items, value), nil
+			}
+
+			item, err := replace(map[interface{}]interface{}{t.key: t.value}, rest, value, path)
+			if err != nil {
+				return nil, err
+			}
+
+			return append(items, item), nil
+		}
+
+		items[index], err = replace(items[index], rest, value, path)
+		if err != nil {
+			return nil, err
+		}
+
+		return items, nil
+
+	case keyToken:
+		if node == nil && t.optional {
+			node = map[interface{}]interface{}{}
+		}
+
+		values, ok := node.(map[interface{}]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected to find a map at path %q but found %s", path, describe(node))
+		}
+
+		child, found := values[t.key]
+		if !found && !t.optional {
+			return nil, fmt.Errorf("expected to find a map key %q for path %q", t.key, path)
+		}
+
+		child, err := replace(child, rest, value, path)
+		if err != nil {
+			return nil, err
+		}
+		values[t.key] = child
+
+		return values, nil
+	}
+
+	return nil, fmt.Errorf("unexpected token in path %q", path)
+}
+
+func remove(node interface{}, tokens []token, path string) (interface{}, error) {
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("cannot remove the entire document for path %q", path)
+	}
+
+	rest := tokens[1:]
+
+	switch t := tokens[0].(type) {
+	case indexToken:
+		items, ok := node.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected to find an array at path %q but found %s", path, describe(node))
+		}
+
+		index, err := resolveIndex(t.index, len(items), path)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rest) == 0 {
+			return append(items[:index], items[index+1:]...), nil
+		}
+
+		items[index], err = remove(items[index], rest, path)
+		if err != nil {
+			return nil, err
+		}
+
+		return items, nil
+
+	case afterLastIndexToken:
+		return nil, fmt.Errorf("cannot remove the after last index token for path %q", path)
+
+	case matchingIndexToken:
+		items, ok := node.([]interface{})
+		if !ok {
+			if node == nil && t.optional {
+				return node, nil
+			}
+			return nil, fmt.Errorf("expected to find an array at path %q but found %s", path, describe(node))
+		}
+
+		index, err := findMatchingIndex(items, t, path)
+		if err != nil {
+			return nil, err
+		}
+
+		if index == -1 {
+			return items, nil
+		}
+
+		if len(rest) == 0 {
+			return append(items[:index], items[index+1:]...), nil
+		}
+
+		items[index], err = remove(items[index], rest, path)
+		if err != nil {
+			return nil, err
+		}
+
+		return items, nil
+
+	case keyToken:
+		values, ok := node.(map[interface{}]interface{})
+		if !ok {
+			if node == nil && t.optional {
+				return node, nil
+			}
+			return nil, fmt.Errorf("expected to find a map at path %q but found %s", path, describe(node))
+		}
+
+		child, found := values[t.key]
+		if !found {
+			if t.optional {
+				return values, nil
+			}
+			return nil, fmt.Errorf("expected to find a map key %q for path %q", t.key, path)
+		}
+
+		if len(rest) == 0 {
+			delete(values, t.key)
+			return values, nil
+		}
+
+		child, err := remove(child, rest, path)
+		if err != nil {
+			return nil, err
+		}
+		values[t.key] = child
+
+		return values, nil
+	}
+
+	return nil, fmt.Errorf("unexpected token in path %q", path)
+}
+
+// findMatchingIndex returns the index of the only item whose key matches the
+// token, or -1 when an optional token matches nothing.
+func findMatchingIndex(items []interface{}, t matchingIndexToken, path string) (int, error) {
+	matches := []int{}
+	for i, item := range items {
+		values, ok := item.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+
+		if value, ok := values[t.key]; ok && fmt.Sprint(value) == t.value {
+			matches = append(matches, i)
+		}
+	}
+
+	switch {
+	case len(matches) == 1:
+		return matches[0], nil
+	case len(matches) > 1:
+		return 0, fmt.Errorf("expected to find exactly one array item matching %s=%s for path %q but found %d", t.key, t.value, path, len(matches))
+	case t.optional:
+		return -1, nil
+	default:
+		return 0, fmt.Errorf("expected to find exactly one array item matching %s=%s for path %q but found 0", t.key, t.value, path)
+	}
+}
+
+func resolveIndex(index, length int, path string) (int, error) {
+	resolved := index
+	if resolved < 0 {
+		resolved = length + index
+	}
+
+	if resolved < 0 || resolved >= length {
+		return 0, fmt.Errorf("expected to find array index %d for path %q but found array of length %d", index, path, length)
+	}
+
+	return resolved, nil
+}
+
+func describe(node interface{}) string {
+	switch node.(type) {
+	case nil:
+		return "nothing"
+	case map[interface{}]interface{}:
+		return "a map"
+	case []interface{}:
+		return "an array"
+	default:
+		return fmt.Sprintf("%T", node)
+	}
+}
+
+// copyValue makes sure a value shared by several operations is never
+// modified through the document it was inserted into.
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		copied := map[interface{}]interface{}{}
+		for key, item := range v {
+			copied[key] = copyValue(item)
+		}
+		return copied
+	case []interface{}:
+		copied := []interface{}{}
+		for _, item := range v {
+			copied = append(copied, copyValue(item))
+		}
+		return copied
+	default:
+		return value
+	}
+}
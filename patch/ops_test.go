@@ -0,0 +1,246 @@
+package patch_test
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/patch"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Interpolate", func() {
+	var template string
+
+	BeforeEach(func() {
+		template = `
+name: some-name
+vm_types:
+- name: default
+- name: large
+  cloud_properties:
+    instance_type: m4.xlarge
+networks:
+- name: private
+  subnets:
+  - range: 10.0.16.0/20
+  - range: 10.0.32.0/20
+`
+	})
+
+	It("returns the template when there are no ops files", func() {
+		output, err := patch.Interpolate(template)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(MatchYAML(template))
+	})
+
+	Describe("replace", func() {
+		It("replaces an existing map key", func() {
+			output, err := patch.Interpolate(template, `
+- type: replace
+  path: /name
+  value: some-other-name
+`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring("name: some-other-name"))
+		})
+
+		It("creates missing keys along an optional path", func() {
+			output, err := patch.Interpolate(template, `
+- type: replace
+  path: /vm_types/name=default/cloud_properties?/instance_type
+  value: m3.medium
+`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(MatchYAML(`
+name: some-name
+vm_types:
+- name: default
+  cloud_properties:
+    instance_type: m3.medium
+- name: large
+  cloud_properties:
+    instance_type: m4.xlarge
+networks:
+- name: private
+  subnets:
+  - range: 10.0.16.0/20
+  - range: 10.0.32.0/20
+`))
+		})
+
+		It("replaces an array item selected by name", func() {
+			output, err := patch.Interpolate(template, `
+- type: replace
+  path: /vm_types/name=large/cloud_properties/instance_type
+  value: m4.2xlarge
+`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring("instance_type: m4.2xlarge"))
+		})
+
+		It("appends an item for an optional selector that matches nothing", func() {
+			output, err := patch.Interpolate(template, `
+- type: replace
+  path: /vm_types/name=huge?/cloud_properties
+  value:
+    instance_type: x1.16xlarge
+`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring("- cloud_properties:\n    instance_type: x1.16xlarge\n  name: huge\n"))
+		})
+
+		It("appends to an array with the after last index token", func() {
+			output, err := patch.Interpolate(template, `
+- type: replace
+  path: /networks/name=private/subnets/-
+  value:
+    range: 10.0.48.0/20
+`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring("  - range: 10.0.32.0/20\n  - range: 10.0.48.0/20\n"))
+		})
+
+		It("addresses array items by positive and negative index", func() {
+			output, err := patch.Interpolate(template, `
+- type: replace
+  path: /networks/0/subnets/0/range
+  value: 10.0.0.0/20
+- type: replace
+  path: /networks/0/subnets/-1/range
+  value: 10.0.64.0/20
+`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring("  - range: 10.0.0.0/20\n  - range: 10.0.64.0/20\n"))
+		})
+
+		It("unescapes slashes and tildes in keys", func() {
+			output, err := patch.Interpolate("{}", `
+- type: replace
+  path: /some~1key?/some~0key
+  value: some-value
+`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(MatchYAML(`some/key: {"some~key": some-value}`))
+		})
+
+		It("replaces the whole document with an empty path", func() {
+			output, err := patch.Interpolate(template, `
+- type: replace
+  path: ""
+  value:
+    name: some-new-document
+`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(MatchYAML("name: some-new-document"))
+		})
+
+		It("does not share values between the ops and the document", func() {
+			output, err := patch.Interpolate("{}", `
+- type: replace
+  path: /a?
+  value: {key: value}
+- type: replace
+  path: /b?
+  value: {key: value}
+- type: replace
+  path: /a/key
+  value: other-value
+`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(MatchYAML("{a: {key: other-value}, b: {key: value}}"))
+		})
+	})
+
+	Describe("remove", func() {
+		It("removes map keys and array items", func() {
+			output, err := patch.Interpolate(template, `
+- type: remove
+  path: /vm_types/name=large
+- type: remove
+  path: /networks/name=private/subnets/1
+- type: remove
+  path: /name
+`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(MatchYAML(`
+vm_types:
+- name: default
+networks:
+- name: private
+  subnets:
+  - range: 10.0.16.0/20
+`))
+		})
+
+		It("does nothing for optional paths that do not exist", func() {
+			output, err := patch.Interpolate(template, `
+- type: remove
+  path: /some-missing-key?/nested
+- type: remove
+  path: /vm_types/name=missing?
+`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(MatchYAML(template))
+		})
+	})
+
+	It("applies ops files in order", func() {
+		output, err := patch.Interpolate(template, `
+- type: replace
+  path: /name
+  value: first
+`, `
+- type: replace
+  path: /name
+  value: second
+`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(ContainSubstring("name: second"))
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when the template is not valid yaml", func() {
+			_, err := patch.Interpolate("%%%")
+			Expect(err).To(MatchError(ContainSubstring("failed to parse template")))
+		})
+
+		It("returns an error when the ops file is not valid yaml", func() {
+			_, err := patch.Interpolate(template, "%%%")
+			Expect(err).To(MatchError(ContainSubstring("failed to parse ops file")))
+		})
+
+		It("returns an error for an unknown operation", func() {
+			_, err := patch.Interpolate(template, "- {type: test, path: /name}")
+			Expect(err).To(MatchError(`unknown operation "test" for path "/name"`))
+		})
+
+		It("returns an error when a path does not start with a slash", func() {
+			_, err := patch.Interpolate(template, "- {type: remove, path: name}")
+			Expect(err).To(MatchError(`expected path "name" to start with a slash`))
+		})
+
+		It("returns an error when the after last index token is not last", func() {
+			_, err := patch.Interpolate(template, "- {type: replace, path: /vm_types/-/name, value: x}")
+			Expect(err).To(MatchError(`expected after last index token to be the last token of path "/vm_types/-/name"`))
+		})
+
+		It("returns an error when a required map key is missing", func() {
+			_, err := patch.Interpolate(template, "- {type: replace, path: /missing/key, value: x}")
+			Expect(err).To(MatchError(`expected to find a map key "missing" for path "/missing/key"`))
+		})
+
+		It("returns an error when a selector matches nothing", func() {
+			_, err := patch.Interpolate(template, "- {type: remove, path: /vm_types/name=missing}")
+			Expect(err).To(MatchError(`expected to find exactly one array item matching name=missing for path "/vm_types/name=missing" but found 0`))
+		})
+
+		It("returns an error when an index is out of range", func() {
+			_, err := patch.Interpolate(template, "- {type: remove, path: /vm_types/5}")
+			Expect(err).To(MatchError(`expected to find array index 5 for path "/vm_types/5" but found array of length 2`))
+		})
+
+		It("returns an error when a token does not fit the document", func() {
+			_, err := patch.Interpolate(template, "- {type: replace, path: /name/0, value: x}")
+			Expect(err).To(MatchError(`expected to find an array at path "/name/0" but found string`))
+		})
+	})
+})
@@ -0,0 +1,74 @@
+package patch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type token interface{}
+
+type indexToken struct {
+	index int
+}
+
+type afterLastIndexToken struct{}
+
+type keyToken struct {
+	key      string
+	optional bool
+}
+
+type matchingIndexToken struct {
+	key      string
+	value    string
+	optional bool
+}
+
+// parsePointer splits a go-patch path into tokens. Once a token is marked
+// optional with a trailing "?" every token after it is optional as well.
+func parsePointer(path string) ([]token, error) {
+	if path == "" {
+		return []token{}, nil
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("expected path %q to start with a slash", path)
+	}
+
+	tokens := []token{}
+	optional := false
+
+	segments := strings.Split(path[1:], "/")
+	for i, segment := range segments {
+		segment = strings.Replace(segment, "~1", "/", -1)
+		segment = strings.Replace(segment, "~0", "~", -1)
+
+		if strings.HasSuffix(segment, "?") {
+			optional = true
+			segment = strings.TrimSuffix(segment, "?")
+		}
+
+		if segment == "-" {
+			if i != len(segments)-1 {
+				return nil, fmt.Errorf("expected after last index token to be the last token of path %q", path)
+			}
+			tokens = append(tokens, afterLastIndexToken{})
+			continue
+		}
+
+		if index, err := strconv.Atoi(segment); err == nil {
+			tokens = append(tokens, indexToken{index: index})
+			continue
+		}
+
+		if parts := strings.SplitN(segment, "=", 2); len(parts) == 2 {
+			tokens = append(tokens, matchingIndexToken{key: parts[0], value: parts[1], optional: optional})
+			continue
+		}
+
+		tokens = append(tokens, keyToken{key: segment, optional: optional})
+	}
+
+	return tokens, nil
+}
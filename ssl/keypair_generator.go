@@ -5,6 +5,7 @@ import (
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
+	"fmt"
 	"io"
 	"net"
 
@@ -42,7 +43,26 @@ func NewKeyPairGenerator(
 	}
 }
 
+// Generate creates a CA and a certificate for commonName signed by it. The
+// common name is added to the certificate as an IP SAN when it is an IP
+// address and as a DNS SAN otherwise.
 func (g KeyPairGenerator) Generate(caCommonName, commonName string) (KeyPair, error) {
+	if ip := net.ParseIP(commonName); ip != nil {
+		return g.generate(caCommonName, commonName, []net.IP{ip}, nil)
+	}
+
+	return g.generate(caCommonName, commonName, nil, []string{commonName})
+}
+
+// GenerateWildcard creates a CA and a wildcard certificate signed by it that
+// is valid for domain and every name directly under it.
+func (g KeyPairGenerator) GenerateWildcard(caCommonName, domain string) (KeyPair, error) {
+	wildcard := fmt.Sprintf("*.%s", domain)
+
+	return g.generate(caCommonName, wildcard, nil, []string{wildcard, domain})
+}
+
+func (g KeyPairGenerator) generate(caCommonName, commonName string, ipList []net.IP, domainList []string) (KeyPair, error) {
 	caPrivateKey, err := g.generateKey(rand.Reader, 2048)
 	if err != nil {
 		return KeyPair{}, err
@@ -61,11 +81,7 @@ func (g KeyPairGenerator) Generate(caCommonName, commonName string) (KeyPair, er
 	}
 	certKey := certstrappkix.NewKey(&certPrivateKey.PublicKey, certPrivateKey)
 
-	ipList := []net.IP{
-		net.ParseIP(commonName),
-	}
-
-	csr, err := g.createCertificateSigningRequest(certKey, "Cloud Foundry", ipList, nil, "Cloud Foundry",
+	csr, err := g.createCertificateSigningRequest(certKey, "Cloud Foundry", ipList, domainList, "Cloud Foundry",
 		"USA", "CA", "San Francisco", commonName)
 	if err != nil {
 		return KeyPair{}, err
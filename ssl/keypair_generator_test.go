@@ -122,6 +122,15 @@ var _ = Describe("KeyPairGenerator", func() {
 			Expect(strings.TrimSpace(string(generatedKeyPair.PrivateKey))).To(Equal(privateKeyPEM))
 		})
 
+		It("adds a hostname common name to the certificate as a dns name", func() {
+			_, err := generator.Generate("BOSH Bootloader", "some-host.example.com")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeCertstrapPKIX.CreateCertificateSigningRequestCall.Receives.IpList).To(BeNil())
+			Expect(fakeCertstrapPKIX.CreateCertificateSigningRequestCall.Receives.DomainList).To(Equal([]string{"some-host.example.com"}))
+			Expect(fakeCertstrapPKIX.CreateCertificateSigningRequestCall.Receives.CommonName).To(Equal("some-host.example.com"))
+		})
+
 		Context("failure cases", func() {
 			Context("when private key generation fails for CA", func() {
 				It("returns error", func() {
@@ -170,6 +179,33 @@ var _ = Describe("KeyPairGenerator", func() {
 			})
 		})
 	})
+
+	Describe("GenerateWildcard", func() {
+		It("generates a wildcard certificate for the domain signed by generated CA", func() {
+			generatedKeyPair, err := generator.GenerateWildcard("BOSH Bootloader LB CA", "example.com")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeCertstrapPKIX.CreateCertificateAuthorityCall.Receives.CommonName).To(Equal("BOSH Bootloader LB CA"))
+
+			Expect(fakeCertstrapPKIX.CreateCertificateSigningRequestCall.Receives.IpList).To(BeNil())
+			Expect(fakeCertstrapPKIX.CreateCertificateSigningRequestCall.Receives.DomainList).To(Equal([]string{"*.example.com", "example.com"}))
+			Expect(fakeCertstrapPKIX.CreateCertificateSigningRequestCall.Receives.CommonName).To(Equal("*.example.com"))
+
+			Expect(fakeCertstrapPKIX.CreateCertificateHostCall.Receives.CrtAuth).To(Equal(ca))
+			Expect(fakeCertstrapPKIX.CreateCertificateHostCall.Receives.Csr).To(Equal(csr))
+
+			Expect(strings.TrimSpace(string(generatedKeyPair.CA))).To(Equal(caPEM))
+			Expect(strings.TrimSpace(string(generatedKeyPair.Certificate))).To(Equal(certificatePEM))
+			Expect(strings.TrimSpace(string(generatedKeyPair.PrivateKey))).To(Equal(privateKeyPEM))
+		})
+
+		It("returns an error when the certificate cannot be generated", func() {
+			fakeCertstrapPKIX.CreateCertificateHostCall.Returns.Error = errors.New("could not generate certificate host")
+
+			_, err := generator.GenerateWildcard("", "example.com")
+			Expect(err).To(MatchError("could not generate certificate host"))
+		})
+	})
 })
 
 func decodeAndParsePrivateKey(privateKeyPEM string) (*rsa.PrivateKey, *rsa.PublicKey, error) {
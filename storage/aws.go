@@ -0,0 +1,15 @@
+package storage
+
+type availabilityZoneRetriever interface {
+	Retrieve(region string) ([]string, error)
+}
+
+// AvailabilityZones returns the zones chosen on `bbl up`, falling back to
+// every zone in the region for environments created without a choice.
+func (a AWS) AvailabilityZones(retriever availabilityZoneRetriever) ([]string, error) {
+	if len(a.AZs) > 0 {
+		return a.AZs, nil
+	}
+
+	return retriever.Retrieve(a.Region)
+}
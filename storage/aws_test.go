@@ -0,0 +1,42 @@
+package storage_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AWS", func() {
+	Describe("AvailabilityZones", func() {
+		var retriever *fakes.AvailabilityZoneRetriever
+
+		BeforeEach(func() {
+			retriever = &fakes.AvailabilityZoneRetriever{}
+			retriever.RetrieveCall.Returns.AZs = []string{"some-zone-1", "some-zone-2", "some-zone-3"}
+		})
+
+		It("returns the zones chosen for the environment", func() {
+			azs, err := storage.AWS{Region: "some-region", AZs: []string{"some-zone-2"}}.AvailabilityZones(retriever)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(azs).To(Equal([]string{"some-zone-2"}))
+		})
+
+		It("returns every zone in the region when none were chosen", func() {
+			azs, err := storage.AWS{Region: "some-region"}.AvailabilityZones(retriever)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(azs).To(Equal([]string{"some-zone-1", "some-zone-2", "some-zone-3"}))
+			Expect(retriever.RetrieveCall.Receives.Region).To(Equal("some-region"))
+		})
+
+		It("returns an error when the zones of the region cannot be retrieved", func() {
+			retriever.RetrieveCall.Returns.Error = errors.New("failed to retrieve zones")
+
+			_, err := storage.AWS{Region: "some-region"}.AvailabilityZones(retriever)
+			Expect(err).To(MatchError("failed to retrieve zones"))
+		})
+	})
+})
@@ -0,0 +1,91 @@
+package storage
+
+type LB struct {
+	Type   string        `json:"type"`
+	Cert   string        `json:"cert"`
+	Key    string        `json:"key"`
+	Chain  string        `json:"chain"`
+	CA     string        `json:"ca,omitempty"`
+	Domain string        `json:"domain,omitempty"`
+	Spec   *CustomLBSpec `json:"spec,omitempty"`
+
+	// Kind is "alb" or "nlb" when the lb is an aws application or network
+	// load balancer rather than a classic elb.
+	Kind string `json:"kind,omitempty"`
+
+	// CertificateName is the name of the gcp ssl certificate serving Cert.
+	// A new name is generated whenever the certificate is rotated.
+	CertificateName string `json:"certificateName,omitempty"`
+}
+
+// CustomLBSpec describes a user-defined load balancer. A custom load
+// balancer is attached with the spec's name as its type.
+type CustomLBSpec struct {
+	Name        string              `json:"name" yaml:"name"`
+	Listeners   []CustomLBListener  `json:"listeners" yaml:"listeners"`
+	HealthCheck CustomLBHealthCheck `json:"health_check" yaml:"health_check"`
+	TLS         bool                `json:"tls,omitempty" yaml:"tls"`
+}
+
+type CustomLBListener struct {
+	Protocol   string `json:"protocol" yaml:"protocol"`
+	Port       int    `json:"port" yaml:"port"`
+	TargetPort int    `json:"target_port" yaml:"target_port"`
+}
+
+type CustomLBHealthCheck struct {
+	Protocol string `json:"protocol" yaml:"protocol"`
+	Port     int    `json:"port" yaml:"port"`
+	Path     string `json:"path,omitempty" yaml:"path"`
+}
+
+// IsCustom reports whether the load balancer was described by a spec
+// rather than being one of the built-in types.
+func (lb LB) IsCustom() bool {
+	return lb.Spec != nil
+}
+
+// GetLB returns the load balancer of the given type, if one is attached.
+func (s State) GetLB(lbType string) (LB, bool) {
+	for _, lb := range s.LBs {
+		if lb.Type == lbType {
+			return lb, true
+		}
+	}
+
+	return LB{}, false
+}
+
+// SetLB attaches the load balancer, replacing any load balancer of the
+// same type.
+func (s State) SetLB(lb LB) State {
+	lbs := []LB{}
+	replaced := false
+	for _, existing := range s.LBs {
+		if existing.Type == lb.Type {
+			existing = lb
+			replaced = true
+		}
+		lbs = append(lbs, existing)
+	}
+
+	if !replaced {
+		lbs = append(lbs, lb)
+	}
+
+	s.LBs = lbs
+	return s
+}
+
+// RemoveLB detaches the load balancer of the given type.
+func (s State) RemoveLB(lbType string) State {
+	var lbs []LB
+	for _, lb := range s.LBs {
+		if lb.Type != lbType {
+			lbs = append(lbs, lb)
+		}
+	}
+
+	s.LBs = lbs
+	return s
+}
@@ -0,0 +1,83 @@
+package storage_test
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LBs", func() {
+	var state storage.State
+
+	BeforeEach(func() {
+		state = storage.State{
+			LBs: []storage.LB{
+				{Type: "cf", Cert: "some-cf-cert"},
+				{Type: "concourse", Cert: "some-concourse-cert"},
+			},
+		}
+	})
+
+	Describe("GetLB", func() {
+		It("returns the lb of the given type", func() {
+			lb, ok := state.GetLB("concourse")
+			Expect(ok).To(BeTrue())
+			Expect(lb).To(Equal(storage.LB{Type: "concourse", Cert: "some-concourse-cert"}))
+		})
+
+		It("returns false when no lb of the given type is attached", func() {
+			_, ok := storage.State{}.GetLB("cf")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("SetLB", func() {
+		It("replaces the lb of the same type in place", func() {
+			state = state.SetLB(storage.LB{Type: "cf", Cert: "some-new-cert"})
+
+			Expect(state.LBs).To(Equal([]storage.LB{
+				{Type: "cf", Cert: "some-new-cert"},
+				{Type: "concourse", Cert: "some-concourse-cert"},
+			}))
+		})
+
+		It("appends an lb of a new type", func() {
+			state = storage.State{}.SetLB(storage.LB{Type: "concourse"})
+
+			Expect(state.LBs).To(Equal([]storage.LB{{Type: "concourse"}}))
+		})
+
+		It("does not modify the lbs of the original state", func() {
+			state.SetLB(storage.LB{Type: "cf", Cert: "some-new-cert"})
+
+			Expect(state.LBs[0].Cert).To(Equal("some-cf-cert"))
+		})
+	})
+
+	Describe("RemoveLB", func() {
+		It("removes the lb of the given type", func() {
+			state = state.RemoveLB("cf")
+
+			Expect(state.LBs).To(Equal([]storage.LB{{Type: "concourse", Cert: "some-concourse-cert"}}))
+		})
+
+		It("leaves no lbs when the last one is removed", func() {
+			state = state.RemoveLB("cf").RemoveLB("concourse")
+
+			Expect(state.LBs).To(BeNil())
+		})
+	})
+
+	Describe("IsCustom", func() {
+		It("returns true when the lb has a spec", func() {
+			lb := storage.LB{Type: "vault", Spec: &storage.CustomLBSpec{Name: "vault"}}
+
+			Expect(lb.IsCustom()).To(BeTrue())
+		})
+
+		It("returns false for the built-in lb types", func() {
+			Expect(storage.LB{Type: "cf"}.IsCustom()).To(BeFalse())
+		})
+	})
+})
@@ -23,16 +23,30 @@ type logger interface {
 }
 
 type AWS struct {
-	AccessKeyID     string `json:"accessKeyId"`
-	SecretAccessKey string `json:"secretAccessKey"`
-	Region          string `json:"region"`
+	AccessKeyID        string   `json:"accessKeyId"`
+	SecretAccessKey    string   `json:"secretAccessKey"`
+	SessionToken       string   `json:"sessionToken,omitempty"`
+	Profile            string   `json:"profile,omitempty"`
+	AssumeRoleARN      string   `json:"assumeRoleArn,omitempty"`
+	MFASerial          string   `json:"mfaSerial,omitempty"`
+	Region             string   `json:"region"`
+	NATType            string   `json:"natType,omitempty"`
+	IAMInstanceProfile bool     `json:"iamInstanceProfile,omitempty"`
+	AZs                []string `json:"azs,omitempty"`
+
+	// Environments migrated from cloudformation keep the iam user and the
+	// tags their stack created.
+	MigratedFromCloudFormation bool   `json:"migratedFromCloudFormation,omitempty"`
+	IAMUserName                string `json:"iamUserName,omitempty"`
 }
 
 type GCP struct {
-	ServiceAccountKey string `json:"serviceAccountKey"`
-	ProjectID         string `json:"projectID"`
-	Zone              string `json:"zone"`
-	Region            string `json:"region"`
+	ServiceAccountKey      string   `json:"serviceAccountKey"`
+	ProjectID              string   `json:"projectID"`
+	Zone                   string   `json:"zone"`
+	Region                 string   `json:"region"`
+	Zones                  []string `json:"zones,omitempty"`
+	DirectorServiceAccount bool     `json:"directorServiceAccount,omitempty"`
 }
 
 type Stack struct {
@@ -42,14 +56,6 @@ type Stack struct {
 	BOSHAZ          string `json:"boshAZ"`
 }
 
-type LB struct {
-	Type   string `json:"type"`
-	Cert   string `json:"cert"`
-	Key    string `json:"key"`
-	Chain  string `json:"chain"`
-	Domain string `json:"domain,omitempty"`
-}
-
 type State struct {
 	Version        int     `json:"version"`
 	IAAS           string  `json:"iaas"`
@@ -61,8 +67,18 @@ type State struct {
 	Stack          Stack   `json:"stack"`
 	EnvID          string  `json:"envID"`
 	TFState        string  `json:"tfState"`
-	LB             LB      `json:"lb"`
+	LBs            []LB    `json:"lbs,omitempty"`
 	LatestTFOutput string  `json:"latestTFOutput"`
+	RuntimeConfig  string  `json:"runtimeConfig,omitempty"`
+	CPIConfig      string  `json:"cpiConfig,omitempty"`
+
+	CloudConfigOpsFiles []string `json:"cloudConfigOpsFiles,omitempty"`
+	VMTypeCatalog       string   `json:"vmTypeCatalog,omitempty"`
+
+	DirectorAllowedCIDRs []string `json:"directorAllowedCIDRs,omitempty"`
+	LBAllowedCIDRs       []string `json:"lbAllowedCIDRs,omitempty"`
+
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 type Store struct {
@@ -128,11 +144,31 @@ func GetState(dir string) (State, error) {
 		return state, err
 	}
 
-	err = json.NewDecoder(file).Decode(&state)
+	contents, err := ioutil.ReadAll(file)
+	if err != nil {
+		return state, err
+	}
+
+	err = json.Unmarshal(contents, &state)
 	if err != nil {
 		return state, err
 	}
 
+	if len(state.LBs) == 0 {
+		var legacyState struct {
+			LB LB `json:"lb"`
+		}
+
+		err = json.Unmarshal(contents, &legacyState)
+		if err != nil {
+			return state, err
+		}
+
+		if legacyState.LB.Type != "" {
+			state.LBs = []LB{legacyState.LB}
+		}
+	}
+
 	emptyState := State{}
 	if reflect.DeepEqual(state, emptyState) {
 		state = State{
@@ -39,24 +39,33 @@ var _ = Describe("Store", func() {
 					AccessKeyID:     "some-aws-access-key-id",
 					SecretAccessKey: "some-aws-secret-access-key",
 					Region:          "some-region",
+					AZs:             []string{"some-az", "some-other-az"},
 				},
 				GCP: storage.GCP{
 					ServiceAccountKey: "some-service-account-key",
 					ProjectID:         "some-project-id",
 					Zone:              "some-zone",
 					Region:            "some-region",
+					Zones:             []string{"some-zone", "some-other-zone"},
 				},
 				KeyPair: storage.KeyPair{
 					Name:       "some-name",
 					PrivateKey: "some-private",
 					PublicKey:  "some-public",
 				},
-				LB: storage.LB{
-					Type:   "some-type",
-					Cert:   "some-cert",
-					Key:    "some-key",
-					Chain:  "some-chain",
-					Domain: "some-domain",
+				LBs: []storage.LB{
+					{
+						Type:   "some-type",
+						Cert:   "some-cert",
+						Key:    "some-key",
+						Chain:  "some-chain",
+						Domain: "some-domain",
+					},
+					{
+						Type: "some-other-type",
+						Cert: "some-other-cert",
+						Key:  "some-other-key",
+					},
 				},
 				BOSH: storage.BOSH{
 					DirectorName:           "some-director-name",
@@ -94,8 +103,12 @@ var _ = Describe("Store", func() {
 					CertificateName: "some-certificate-name",
 					BOSHAZ:          "some-bosh-az",
 				},
-				EnvID:   "some-env-id",
-				TFState: "some-tf-state",
+				EnvID:               "some-env-id",
+				TFState:             "some-tf-state",
+				RuntimeConfig:       "some-runtime-config",
+				CPIConfig:           "some-cpi-config",
+				CloudConfigOpsFiles: []string{"some-cloud-config-ops-file"},
+				VMTypeCatalog:       "some-vm-type-catalog",
 			})
 			Expect(err).NotTo(HaveOccurred())
 
@@ -108,26 +121,36 @@ var _ = Describe("Store", func() {
 				"aws": {
 					"accessKeyId": "some-aws-access-key-id",
 					"secretAccessKey": "some-aws-secret-access-key",
-					"region": "some-region"
+					"region": "some-region",
+					"azs": ["some-az", "some-other-az"]
 				},
 				"gcp": {
 					"serviceAccountKey": "some-service-account-key",
 					"projectID": "some-project-id",
 					"zone": "some-zone",
-					"region": "some-region"
+					"region": "some-region",
+					"zones": ["some-zone", "some-other-zone"]
 				},
 				"keyPair": {
 					"name": "some-name",
 					"privateKey": "some-private",
 					"publicKey": "some-public"
 				},
-				"lb": {
-					"type": "some-type",
-					"cert": "some-cert",
-					"key": "some-key",
-					"chain": "some-chain",
-					"domain": "some-domain"
-				},
+				"lbs": [
+					{
+						"type": "some-type",
+						"cert": "some-cert",
+						"key": "some-key",
+						"chain": "some-chain",
+						"domain": "some-domain"
+					},
+					{
+						"type": "some-other-type",
+						"cert": "some-other-cert",
+						"key": "some-other-key",
+						"chain": ""
+					}
+				],
 				"bosh":{
 					"directorName": "some-director-name",
 					"directorUsername": "some-director-username",
@@ -167,7 +190,11 @@ var _ = Describe("Store", func() {
 				},
 				"envID": "some-env-id",
 				"tfState": "some-tf-state",
-				"latestTFOutput": ""
+				"latestTFOutput": "",
+				"runtimeConfig": "some-runtime-config",
+				"cpiConfig": "some-cpi-config",
+				"cloudConfigOpsFiles": ["some-cloud-config-ops-file"],
+				"vmTypeCatalog": "some-vm-type-catalog"
 			}`))
 
 			fileInfo, err := os.Stat(filepath.Join(tempDir, "bbl-state.json"))
@@ -358,6 +385,56 @@ var _ = Describe("Store", func() {
 			})
 		})
 
+		Context("when the state file has a single lb", func() {
+			BeforeEach(func() {
+				err := ioutil.WriteFile(filepath.Join(tempDir, "bbl-state.json"), []byte(`{
+					"version": 3,
+					"iaas": "gcp",
+					"lb": {
+						"type": "cf",
+						"cert": "some-cert",
+						"key": "some-key",
+						"domain": "some-domain"
+					}
+				}`), os.ModePerm)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns it as the only lb", func() {
+				state, err := storage.GetState(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(state.LBs).To(Equal([]storage.LB{
+					{
+						Type:   "cf",
+						Cert:   "some-cert",
+						Key:    "some-key",
+						Domain: "some-domain",
+					},
+				}))
+			})
+		})
+
+		Context("when the state file has an empty single lb", func() {
+			BeforeEach(func() {
+				err := ioutil.WriteFile(filepath.Join(tempDir, "bbl-state.json"), []byte(`{
+					"version": 3,
+					"iaas": "gcp",
+					"lb": {
+						"type": ""
+					}
+				}`), os.ModePerm)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns no lbs", func() {
+				state, err := storage.GetState(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(state.LBs).To(BeEmpty())
+			})
+		})
+
 		Context("when the bbl-state.json file doesn't exist", func() {
 			It("returns an empty state object", func() {
 				state, err := storage.GetState(tempDir)
@@ -3,6 +3,8 @@ package aws
 const BaseTemplate = `resource "aws_eip" "bosh_eip" {
   depends_on = ["aws_internet_gateway.ig"]
   vpc      = true
+
+  tags = "${var.tags}"
 }
 
 output "bosh_eip" {
@@ -13,149 +15,6 @@ output "bosh_url" {
   value = "https://${aws_eip.bosh_eip.public_ip}:25555"
 }
 
-resource "aws_iam_user" "bosh" {
-  name = "${var.env_id}_bosh_user"
-}
-
-resource "aws_iam_user_policy" "bosh" {
-  name  = "${var.env_id}_bosh_user_policy"
-  user = "${aws_iam_user.bosh.name}"
-
-  policy = <<EOF
-{
-  "Version": "2012-10-17",
-  "Statement": [
-    {
-      "Action": [
-        "ec2:AssociateAddress",
-        "ec2:AttachVolume",
-        "ec2:CreateVolume",
-        "ec2:DeleteSnapshot",
-        "ec2:DeleteVolume",
-        "ec2:DescribeAddresses",
-        "ec2:DescribeImages",
-        "ec2:DescribeInstances",
-        "ec2:DescribeRegions",
-        "ec2:DescribeSecurityGroups",
-        "ec2:DescribeSnapshots",
-        "ec2:DescribeSubnets",
-        "ec2:DescribeVolumes",
-        "ec2:DetachVolume",
-        "ec2:CreateSnapshot",
-        "ec2:CreateTags",
-        "ec2:RunInstances",
-        "ec2:TerminateInstances",
-        "ec2:RegisterImage",
-        "ec2:DeregisterImage"
-      ],
-      "Effect": "Allow",
-      "Resource": "*"
-    },
-    {
-      "Action": [
-        "elasticloadbalancing:*"
-      ],
-      "Effect": "Allow",
-      "Resource": "*"
-    }
-  ]
-}
-EOF
-}
-
-resource "aws_iam_access_key" "bosh" {
-  user = "${aws_iam_user.bosh.name}"
-}
-
-output "bosh_user_access_key" {
-  value = "${aws_iam_access_key.bosh.id}"
-}
-
-output "bosh_user_secret_access_key" {
-  value = "${aws_iam_access_key.bosh.secret}"
-}
-
-variable "nat_ami_map" {
-  type = "map"
-
-  default = {
-    us-east-1      ="ami-68115b02"
-    us-west-1      ="ami-ef1a718f"
-    us-west-2      ="ami-77a4b816"
-    eu-west-1      ="ami-c0993ab3"
-    eu-central-1   ="ami-0b322e67"
-    ap-southeast-1 ="ami-e2fc3f81"
-    ap-southeast-2 ="ami-e3217a80"
-    ap-northeast-1 ="ami-f885ae96"
-    ap-northeast-2 ="ami-4118d72f"
-    sa-east-1      ="ami-8631b5ea"
-  }
-}
-
-resource "aws_security_group" "nat_security_group" {
-  name        = "nat_security_group"
-  description = "NAT"
-  vpc_id      = "${aws_vpc.vpc.id}"
-
-  ingress {
-    protocol    = "tcp"
-    from_port   = 0
-    to_port     = 65535
-    security_groups = ["${aws_security_group.internal_security_group.id}"]
-  }
-
-  ingress {
-    protocol    = "udp"
-    from_port   = 0
-    to_port     = 65535
-    security_groups = ["${aws_security_group.internal_security_group.id}"]
-  }
-
-  ingress {
-    protocol    = "icmp"
-    from_port   = -1
-    to_port     = -1
-    security_groups = ["${aws_security_group.internal_security_group.id}"]
-  }
-
-  egress {
-    from_port = 0
-    to_port = 0
-    protocol = "-1"
-    cidr_blocks = ["0.0.0.0/0"]
-  }
-
-  tags {
-    Name = "${var.env_id}-nat-security-group"
-  }
-}
-
-variable "nat_ssh_key_pair_name" {}
-
-resource "aws_instance" "nat" {
-  private_ip             = "10.0.0.7"
-  instance_type          = "t2.medium"
-  subnet_id              = "${aws_subnet.bosh_subnet.id}"
-  source_dest_check      = false
-  ami                    = "${lookup(var.nat_ami_map, var.region)}"
-  key_name               = "${var.nat_ssh_key_pair_name}"
-  vpc_security_group_ids = ["${aws_security_group.nat_security_group.id}"]
-
-  tags {
-    Name = "${var.env_id}-nat"
-  }
-}
-
-resource "aws_eip" "nat_eip" {
-  depends_on = ["aws_internet_gateway.ig"]
-  instance = "${aws_instance.nat.id}"
-  vpc      = true
-}
-
-output "nat_eip" {
-  value = "${aws_eip.nat_eip.public_ip}"
-}
-
 variable "access_key" {
   type = "string"
 }
@@ -164,13 +23,20 @@ variable "secret_key" {
   type = "string"
 }
 
+variable "session_token" {
+  type    = "string"
+  default = ""
+}
+
 variable "region" {
   type = "string"
 }
 
 provider "aws" {
+  version    = "~> 1.60"
   access_key = "${var.access_key}"
   secret_key = "${var.secret_key}"
+  token      = "${var.session_token}"
   region     = "${var.region}"
 }
 
@@ -179,8 +45,10 @@ resource "aws_security_group" "internal_security_group" {
   description = "Internal"
   vpc_id      = "${aws_vpc.vpc.id}"
 
-  tags {
-    Name = "${var.env_id}-internal-security-group"
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-internal-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
   }
 }
 
@@ -224,8 +92,9 @@ output "internal_security_group" {
   value="${aws_security_group.internal_security_group.id}"
 }
 
-variable "bosh_inbound_cidr" {
-  default = "0.0.0.0/0"
+variable "bosh_inbound_cidrs" {
+  type    = "list"
+  default = ["0.0.0.0/0"]
 }
 
 resource "aws_security_group" "bosh_security_group" {
@@ -233,8 +102,10 @@ resource "aws_security_group" "bosh_security_group" {
   description = "Bosh"
   vpc_id      = "${aws_vpc.vpc.id}"
 
-  tags {
-    Name = "${var.env_id}-bosh-security-group"
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-bosh-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
   }
 }
 
@@ -244,7 +115,7 @@ resource "aws_security_group_rule" "bosh_security_group_rule_tcp_ssh" {
   protocol                 = "tcp"
   from_port                = 22
   to_port                  = 22
-  cidr_blocks              = ["${var.bosh_inbound_cidr}"]
+  cidr_blocks              = ["${var.bosh_inbound_cidrs}"]
 }
 
 resource "aws_security_group_rule" "bosh_security_group_rule_tcp_bosh_agent" {
@@ -253,7 +124,7 @@ resource "aws_security_group_rule" "bosh_security_group_rule_tcp_bosh_agent" {
   protocol                 = "tcp"
   from_port                = 6868
   to_port                  = 6868
-  cidr_blocks              = ["${var.bosh_inbound_cidr}"]
+  cidr_blocks              = ["${var.bosh_inbound_cidrs}"]
 }
 
 resource "aws_security_group_rule" "bosh_security_group_rule_tcp_director_api" {
@@ -262,7 +133,7 @@ resource "aws_security_group_rule" "bosh_security_group_rule_tcp_director_api" {
   protocol                 = "tcp"
   from_port                = 25555
   to_port                  = 25555
-  cidr_blocks              = ["${var.bosh_inbound_cidr}"]
+  cidr_blocks              = ["${var.bosh_inbound_cidrs}"]
 }
 
 resource "aws_security_group_rule" "bosh_security_group_rule_tcp" {
@@ -328,9 +199,7 @@ resource "aws_subnet" "bosh_subnet" {
   cidr_block        = "${var.bosh_subnet_cidr}"
   availability_zone = "${var.bosh_availability_zone}"
 
-  tags {
-    Name = "${var.env_id}-bosh-subnet"
-  }
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-bosh-subnet"))}"
 }
 
 resource "aws_route_table" "bosh_route_table" {
@@ -340,6 +209,8 @@ resource "aws_route_table" "bosh_route_table" {
     cidr_block = "0.0.0.0/0"
     gateway_id = "${aws_internet_gateway.ig.id}"
   }
+
+  tags = "${var.tags}"
 }
 
 resource "aws_route_table_association" "route_bosh_subnets" {
@@ -365,24 +236,7 @@ resource "aws_subnet" "internal_subnets" {
   cidr_block        = "${cidrsubnet("10.0.0.0/16", 4, count.index+1)}"
   availability_zone = "${element(var.availability_zones, count.index)}"
 
-  tags {
-    Name = "${var.env_id}-internal-subnet${count.index}"
-  }
-}
-
-resource "aws_route_table" "internal_route_table" {
-  vpc_id = "${aws_vpc.vpc.id}"
-
-  route {
-    cidr_block = "0.0.0.0/0"
-    instance_id = "${aws_instance.nat.id}"
-  }
-}
-
-resource "aws_route_table_association" "route_internal_subnets" {
-  count          = "${length(var.availability_zones)}"
-  subnet_id      = "${element(aws_subnet.internal_subnets.*.id, count.index)}"
-  route_table_id = "${aws_route_table.internal_route_table.id}"
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-internal-subnet${count.index}"))}"
 }
 
 output "internal_subnet_ids" {
@@ -401,6 +255,11 @@ variable "env_id" {
   type = "string"
 }
 
+variable "tags" {
+  type    = "map"
+  default = {}
+}
+
 variable "short_env_id" {
   type = "string"
 }
@@ -415,13 +274,13 @@ resource "aws_vpc" "vpc" {
   instance_tenancy     = "default"
   enable_dns_hostnames = true
 
-  tags {
-    Name = "${var.env_id}-vpc"
-  }
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-vpc"))}"
 }
 
 resource "aws_internet_gateway" "ig" {
   vpc_id = "${aws_vpc.vpc.id}"
+
+  tags = "${var.tags}"
 }
 
 output "vpc_id" {
@@ -429,15 +288,289 @@ output "vpc_id" {
 }
 `
 
-const LBSubnetTemplate = `resource "aws_subnet" "lb_subnets" {
+// boshPolicy allows the director cpi to manage vms, disks and load
+// balancers, whether it is granted to a user or to an instance profile.
+const boshPolicy = `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Action": [
+        "ec2:AssociateAddress",
+        "ec2:AttachVolume",
+        "ec2:CreateVolume",
+        "ec2:DeleteSnapshot",
+        "ec2:DeleteVolume",
+        "ec2:DescribeAddresses",
+        "ec2:DescribeImages",
+        "ec2:DescribeInstances",
+        "ec2:DescribeRegions",
+        "ec2:DescribeSecurityGroups",
+        "ec2:DescribeSnapshots",
+        "ec2:DescribeSubnets",
+        "ec2:DescribeVolumes",
+        "ec2:DetachVolume",
+        "ec2:CreateSnapshot",
+        "ec2:CreateTags",
+        "ec2:RunInstances",
+        "ec2:TerminateInstances",
+        "ec2:RegisterImage",
+        "ec2:DeregisterImage"
+      ],
+      "Effect": "Allow",
+      "Resource": "*"
+    },
+    {
+      "Action": [
+        "elasticloadbalancing:*"
+      ],
+      "Effect": "Allow",
+      "Resource": "*"
+    }
+  ]
+}
+`
+
+const IAMUserTemplate = `variable "bosh_iam_user_name" {
+  type    = "string"
+  default = ""
+}
+
+variable "bosh_iam_user_policy_name" {
+  type    = "string"
+  default = ""
+}
+
+resource "aws_iam_user" "bosh" {
+  name = "${coalesce(var.bosh_iam_user_name, format("%s_bosh_user", var.env_id))}"
+}
+
+resource "aws_iam_user_policy" "bosh" {
+  name  = "${coalesce(var.bosh_iam_user_policy_name, format("%s_bosh_user_policy", var.env_id))}"
+  user = "${aws_iam_user.bosh.name}"
+
+  policy = <<EOF
+` + boshPolicy + `EOF
+}
+
+resource "aws_iam_access_key" "bosh" {
+  user = "${aws_iam_user.bosh.name}"
+}
+
+output "bosh_user_access_key" {
+  value = "${aws_iam_access_key.bosh.id}"
+}
+
+output "bosh_user_secret_access_key" {
+  value = "${aws_iam_access_key.bosh.secret}"
+}
+`
+
+const IAMInstanceProfileTemplate = `resource "aws_iam_role" "bosh" {
+  name = "${var.env_id}_bosh_role"
+  path = "/"
+
+  assume_role_policy = <<EOF
+{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Action": "sts:AssumeRole",
+      "Principal": {
+        "Service": "ec2.amazonaws.com"
+      },
+      "Effect": "Allow"
+    }
+  ]
+}
+EOF
+}
+
+resource "aws_iam_role_policy" "bosh" {
+  name = "${var.env_id}_bosh_role_policy"
+  role = "${aws_iam_role.bosh.id}"
+
+  policy = <<EOF
+` + boshPolicy + `EOF
+}
+
+resource "aws_iam_instance_profile" "bosh" {
+  name = "${var.env_id}_bosh_instance_profile"
+  role = "${aws_iam_role.bosh.name}"
+}
+
+output "bosh_iam_instance_profile" {
+  value = "${aws_iam_instance_profile.bosh.name}"
+}
+`
+
+const NATInstanceTemplate = `variable "nat_ami_map" {
+  type = "map"
+
+  default = {
+    us-east-1      ="ami-68115b02"
+    us-west-1      ="ami-ef1a718f"
+    us-west-2      ="ami-77a4b816"
+    eu-west-1      ="ami-c0993ab3"
+    eu-central-1   ="ami-0b322e67"
+    ap-southeast-1 ="ami-e2fc3f81"
+    ap-southeast-2 ="ami-e3217a80"
+    ap-northeast-1 ="ami-f885ae96"
+    ap-northeast-2 ="ami-4118d72f"
+    sa-east-1      ="ami-8631b5ea"
+  }
+}
+
+resource "aws_security_group" "nat_security_group" {
+  name        = "nat_security_group"
+  description = "NAT"
+  vpc_id      = "${aws_vpc.vpc.id}"
+
+  ingress {
+    protocol    = "tcp"
+    from_port   = 0
+    to_port     = 65535
+    security_groups = ["${aws_security_group.internal_security_group.id}"]
+  }
+
+  ingress {
+    protocol    = "udp"
+    from_port   = 0
+    to_port     = 65535
+    security_groups = ["${aws_security_group.internal_security_group.id}"]
+  }
+
+  ingress {
+    protocol    = "icmp"
+    from_port   = -1
+    to_port     = -1
+    security_groups = ["${aws_security_group.internal_security_group.id}"]
+  }
+
+  egress {
+    from_port = 0
+    to_port = 0
+    protocol = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-nat-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
+  }
+}
+
+variable "nat_ssh_key_pair_name" {}
+
+resource "aws_instance" "nat" {
+  private_ip             = "10.0.0.7"
+  instance_type          = "t2.medium"
+  subnet_id              = "${aws_subnet.bosh_subnet.id}"
+  source_dest_check      = false
+  ami                    = "${lookup(var.nat_ami_map, var.region)}"
+  key_name               = "${var.nat_ssh_key_pair_name}"
+  vpc_security_group_ids = ["${aws_security_group.nat_security_group.id}"]
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-nat"))}"
+}
+
+resource "aws_eip" "nat_eip" {
+  depends_on = ["aws_internet_gateway.ig"]
+  instance = "${aws_instance.nat.id}"
+  vpc      = true
+
+  tags = "${var.tags}"
+}
+
+output "nat_eip" {
+  value = "${aws_eip.nat_eip.public_ip}"
+}
+
+resource "aws_route_table" "internal_route_table" {
+  vpc_id = "${aws_vpc.vpc.id}"
+
+  route {
+    cidr_block = "0.0.0.0/0"
+    instance_id = "${aws_instance.nat.id}"
+  }
+
+  tags = "${var.tags}"
+}
+
+resource "aws_route_table_association" "route_internal_subnets" {
+  count          = "${length(var.availability_zones)}"
+  subnet_id      = "${element(aws_subnet.internal_subnets.*.id, count.index)}"
+  route_table_id = "${aws_route_table.internal_route_table.id}"
+}
+`
+
+const NATGatewayTemplate = `resource "aws_subnet" "nat_subnets" {
   count             = "${length(var.availability_zones)}"
   vpc_id            = "${aws_vpc.vpc.id}"
-  cidr_block        = "${cidrsubnet("10.0.0.0/20", 4, count.index+2)}"
+  cidr_block        = "${cidrsubnet("10.0.1.0/24", 4, count.index)}"
   availability_zone = "${element(var.availability_zones, count.index)}"
 
-  tags {
-    Name = "${var.env_id}-lb-subnet${count.index}"
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-nat-subnet${count.index}"))}"
+}
+
+resource "aws_route_table_association" "route_nat_subnets" {
+  count          = "${length(var.availability_zones)}"
+  subnet_id      = "${element(aws_subnet.nat_subnets.*.id, count.index)}"
+  route_table_id = "${aws_route_table.bosh_route_table.id}"
+}
+
+resource "aws_eip" "nat_eips" {
+  count      = "${length(var.availability_zones)}"
+  depends_on = ["aws_internet_gateway.ig"]
+  vpc        = true
+
+  tags = "${var.tags}"
+}
+
+resource "aws_nat_gateway" "nat" {
+  count         = "${length(var.availability_zones)}"
+  allocation_id = "${element(aws_eip.nat_eips.*.id, count.index)}"
+  subnet_id     = "${element(aws_subnet.nat_subnets.*.id, count.index)}"
+  depends_on    = ["aws_internet_gateway.ig"]
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-nat-gateway${count.index}"))}"
+}
+
+output "nat_eips" {
+  value = ["${aws_eip.nat_eips.*.public_ip}"]
+}
+
+resource "aws_route_table" "internal_route_tables" {
+  count  = "${length(var.availability_zones)}"
+  vpc_id = "${aws_vpc.vpc.id}"
+
+  route {
+    cidr_block     = "0.0.0.0/0"
+    nat_gateway_id = "${element(aws_nat_gateway.nat.*.id, count.index)}"
   }
+
+  tags = "${var.tags}"
+}
+
+resource "aws_route_table_association" "route_internal_subnets" {
+  count          = "${length(var.availability_zones)}"
+  subnet_id      = "${element(aws_subnet.internal_subnets.*.id, count.index)}"
+  route_table_id = "${element(aws_route_table.internal_route_tables.*.id, count.index)}"
+}
+`
+
+const LBSubnetTemplate = `variable "lb_inbound_cidrs" {
+  type    = "list"
+  default = ["0.0.0.0/0"]
+}
+
+resource "aws_subnet" "lb_subnets" {
+  count             = "${length(var.availability_zones)}"
+  vpc_id            = "${aws_vpc.vpc.id}"
+  cidr_block        = "${cidrsubnet("10.0.0.0/20", 4, count.index+2)}"
+  availability_zone = "${element(var.availability_zones, count.index)}"
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-lb-subnet${count.index}"))}"
 }
 
 resource "aws_route_table" "lb_route_table" {
@@ -447,6 +580,8 @@ resource "aws_route_table" "lb_route_table" {
     cidr_block = "0.0.0.0/0"
     gateway_id = "${aws_internet_gateway.ig.id}"
   }
+
+  tags = "${var.tags}"
 }
 
 resource "aws_route_table_association" "route_lb_subnets" {
@@ -468,29 +603,64 @@ output "lb_subnet_cidrs" {
 }
 `
 
-const SSLCertificateTemplate = `variable "ssl_certificate" {
+const ConcourseSSLCertificateTemplate = `variable "concourse_ssl_certificate" {
   type = "string"
 }
 
-variable "ssl_certificate_chain" {
+variable "concourse_ssl_certificate_chain" {
   type = "string"
 }
 
-variable "ssl_certificate_private_key" {
+variable "concourse_ssl_certificate_private_key" {
   type = "string"
 }
 
-resource "aws_iam_server_certificate" "lb_cert" {
+resource "aws_iam_server_certificate" "concourse_lb_cert" {
   name_prefix       = "${var.short_env_id}-"
 
-  certificate_body  = "${var.ssl_certificate}"
-  certificate_chain = "${var.ssl_certificate_chain}"
-  private_key       = "${var.ssl_certificate_private_key}"
+  certificate_body  = "${var.concourse_ssl_certificate}"
+  certificate_chain = "${var.concourse_ssl_certificate_chain}"
+  private_key       = "${var.concourse_ssl_certificate_private_key}"
 
   lifecycle {
     create_before_destroy = true
+    ignore_changes        = ["name_prefix", "private_key"]
   }
 }
+
+output "concourse_lb_certificate_name" {
+  value = "${aws_iam_server_certificate.concourse_lb_cert.name}"
+}
+`
+
+const CFSSLCertificateTemplate = `variable "cf_ssl_certificate" {
+  type = "string"
+}
+
+variable "cf_ssl_certificate_chain" {
+  type = "string"
+}
+
+variable "cf_ssl_certificate_private_key" {
+  type = "string"
+}
+
+resource "aws_iam_server_certificate" "cf_lb_cert" {
+  name_prefix       = "${var.short_env_id}-"
+
+  certificate_body  = "${var.cf_ssl_certificate}"
+  certificate_chain = "${var.cf_ssl_certificate_chain}"
+  private_key       = "${var.cf_ssl_certificate_private_key}"
+
+  lifecycle {
+    create_before_destroy = true
+    ignore_changes        = ["name_prefix", "private_key"]
+  }
+}
+
+output "cf_lb_certificate_name" {
+  value = "${aws_iam_server_certificate.cf_lb_cert.name}"
+}
 `
 
 const ConcourseLBTemplate = `resource "aws_security_group" "concourse_lb_security_group" {
@@ -499,21 +669,21 @@ const ConcourseLBTemplate = `resource "aws_security_group" "concourse_lb_securit
   vpc_id      = "${aws_vpc.vpc.id}"
 
   ingress {
-    cidr_blocks = ["0.0.0.0/0"]
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
     protocol    = "tcp"
     from_port   = 80
     to_port     = 80
   }
 
   ingress {
-    cidr_blocks = ["0.0.0.0/0"]
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
     protocol    = "tcp"
     from_port   = 2222
     to_port     = 2222
   }
 
   ingress {
-    cidr_blocks = ["0.0.0.0/0"]
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
     protocol    = "tcp"
     from_port   = 443
     to_port     = 443
@@ -526,8 +696,10 @@ const ConcourseLBTemplate = `resource "aws_security_group" "concourse_lb_securit
     cidr_blocks = ["0.0.0.0/0"]
   }
 
-  tags {
-    Name = "${var.env_id}-concourse-lb-security-group"
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-concourse-lb-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
   }
 }
 
@@ -557,8 +729,10 @@ resource "aws_security_group" "concourse_lb_internal_security_group" {
     cidr_blocks = ["0.0.0.0/0"]
   }
 
-  tags {
-    Name = "${var.env_id}-concourse-lb-internal-security-group"
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-concourse-lb-internal-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
   }
 }
 
@@ -597,11 +771,17 @@ resource "aws_elb" "concourse_lb" {
     instance_protocol  = "tcp"
     lb_port            = 443
     lb_protocol        = "ssl"
-    ssl_certificate_id = "${aws_iam_server_certificate.lb_cert.arn}"
+    ssl_certificate_id = "${aws_iam_server_certificate.concourse_lb_cert.arn}"
   }
 
   security_groups = ["${aws_security_group.concourse_lb_security_group.id}"]
   subnets         = ["${aws_subnet.lb_subnets.*.id}"]
+
+  tags = "${var.tags}"
+
+  lifecycle {
+    ignore_changes = ["name"]
+  }
 }
 
 output "concourse_lb_name" {
@@ -613,13 +793,13 @@ output "concourse_lb_url" {
 }
 `
 
-const CFLBTemplate = `resource "aws_security_group" "cf_ssh_lb_security_group" {
+const CFSSHLBTemplate = `resource "aws_security_group" "cf_ssh_lb_security_group" {
   name = "cf_ssh_lb_security_group"
   description = "CF SSH"
   vpc_id      = "${aws_vpc.vpc.id}"
 
   ingress {
-    cidr_blocks = ["0.0.0.0/0"]
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
     protocol    = "tcp"
     from_port   = 2222
     to_port     = 2222
@@ -632,8 +812,10 @@ const CFLBTemplate = `resource "aws_security_group" "cf_ssh_lb_security_group" {
     cidr_blocks = ["0.0.0.0/0"]
   }
 
-  tags {
-    Name = "${var.env_id}-cf-ssh-lb-security-group"
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-cf-ssh-lb-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
   }
 }
 
@@ -660,8 +842,10 @@ resource "aws_security_group" "cf_ssh_lb_internal_security_group" {
     cidr_blocks = ["0.0.0.0/0"]
   }
 
-  tags {
-    Name = "${var.env_id}-cf-ssh-lb-internal-security-group"
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-cf-ssh-lb-internal-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
   }
 }
 
@@ -690,6 +874,12 @@ resource "aws_elb" "cf_ssh_lb" {
 
   security_groups = ["${aws_security_group.cf_ssh_lb_security_group.id}"]
   subnets         = ["${aws_subnet.lb_subnets.*.id}"]
+
+  tags = "${var.tags}"
+
+  lifecycle {
+    ignore_changes = ["name"]
+  }
 }
 
 output "cf_ssh_lb_name" {
@@ -699,28 +889,29 @@ output "cf_ssh_lb_name" {
 output "cf_ssh_lb_url" {
   value = "${aws_elb.cf_ssh_lb.dns_name}"
 }
+`
 
-resource "aws_security_group" "cf_router_lb_security_group" {
+const CFRouterLBTemplate = `resource "aws_security_group" "cf_router_lb_security_group" {
   name = "cf_router_lb_security_group"
   description = "CF Router"
   vpc_id      = "${aws_vpc.vpc.id}"
 
   ingress {
-    cidr_blocks = ["0.0.0.0/0"]
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
     protocol    = "tcp"
     from_port   = 80
     to_port     = 80
   }
 
   ingress {
-    cidr_blocks = ["0.0.0.0/0"]
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
     protocol    = "tcp"
     from_port   = 443
     to_port     = 443
   }
 
   ingress {
-    cidr_blocks = ["0.0.0.0/0"]
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
     protocol    = "tcp"
     from_port   = 4443
     to_port     = 4443
@@ -733,8 +924,10 @@ resource "aws_security_group" "cf_router_lb_security_group" {
     cidr_blocks = ["0.0.0.0/0"]
   }
 
-  tags {
-    Name = "${var.env_id}-cf-router-lb-security-group"
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-cf-router-lb-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
   }
 }
 
@@ -761,8 +954,10 @@ resource "aws_security_group" "cf_router_lb_internal_security_group" {
     cidr_blocks = ["0.0.0.0/0"]
   }
 
-  tags {
-    Name = "${var.env_id}-cf-router-lb-internal-security-group"
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-cf-router-lb-internal-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
   }
 }
 
@@ -794,7 +989,7 @@ resource "aws_elb" "cf_router_lb" {
     instance_protocol  = "http"
     lb_port            = 443
     lb_protocol        = "https"
-    ssl_certificate_id = "${aws_iam_server_certificate.lb_cert.arn}"
+    ssl_certificate_id = "${aws_iam_server_certificate.cf_lb_cert.arn}"
   }
 
   listener {
@@ -802,11 +997,17 @@ resource "aws_elb" "cf_router_lb" {
     instance_protocol  = "tcp"
     lb_port            = 4443
     lb_protocol        = "ssl"
-    ssl_certificate_id = "${aws_iam_server_certificate.lb_cert.arn}"
+    ssl_certificate_id = "${aws_iam_server_certificate.cf_lb_cert.arn}"
   }
 
   security_groups = ["${aws_security_group.cf_router_lb_security_group.id}"]
   subnets         = ["${aws_subnet.lb_subnets.*.id}"]
+
+  tags = "${var.tags}"
+
+  lifecycle {
+    ignore_changes = ["name"]
+  }
 }
 
 output "cf_router_lb_name" {
@@ -816,14 +1017,15 @@ output "cf_router_lb_name" {
 output "cf_router_lb_url" {
   value = "${aws_elb.cf_router_lb.dns_name}"
 }
+`
 
-resource "aws_security_group" "cf_tcp_lb_security_group" {
+const CFTCPLBTemplate = `resource "aws_security_group" "cf_tcp_lb_security_group" {
   name = "cf_tcp_lb_security_group"
   description = "CF TCP"
   vpc_id      = "${aws_vpc.vpc.id}"
 
   ingress {
-    cidr_blocks = ["0.0.0.0/0"]
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
     protocol    = "tcp"
     from_port   = 1024
     to_port     = 1123
@@ -836,8 +1038,10 @@ resource "aws_security_group" "cf_tcp_lb_security_group" {
     cidr_blocks = ["0.0.0.0/0"]
   }
 
-  tags {
-    Name = "${var.env_id}-cf-tcp-lb-security-group"
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-cf-tcp-lb-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
   }
 }
 
@@ -864,8 +1068,10 @@ resource "aws_security_group" "cf_tcp_lb_internal_security_group" {
     cidr_blocks = ["0.0.0.0/0"]
   }
 
-  tags {
-    Name = "${var.env_id}-cf-tcp-lb-internal-security-group"
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-cf-tcp-lb-internal-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
   }
 }
 
@@ -1587,6 +1793,12 @@ resource "aws_elb" "cf_tcp_lb" {
 
   security_groups = ["${aws_security_group.cf_tcp_lb_security_group.id}"]
   subnets         = ["${aws_subnet.lb_subnets.*.id}"]
+
+  tags = "${var.tags}"
+
+  lifecycle {
+    ignore_changes = ["name"]
+  }
 }
 
 output "cf_tcp_lb_name" {
@@ -1598,6 +1810,8 @@ output "cf_tcp_lb_url" {
 }
 `
 
+// CFDNSTemplate is formatted with the terraform resource type of the cf
+// router lb, which differs between classic and application/network lbs.
 const CFDNSTemplate = `variable "system_domain" {
   type = "string"
 }
@@ -1605,9 +1819,7 @@ const CFDNSTemplate = `variable "system_domain" {
 resource "aws_route53_zone" "env_dns_zone" {
   name = "${var.system_domain}"
 
-  tags {
-    Name = "${var.env_id}-hosted-zone"
-  }
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-hosted-zone"))}"
 }
 
 output "env_dns_zone_name_servers" {
@@ -1620,7 +1832,7 @@ resource "aws_route53_record" "wildcard_dns" {
   type    = "CNAME"
   ttl     = 300
 
-  records = ["${aws_elb.cf_router_lb.dns_name}"]
+  records = ["${%s.cf_router_lb.dns_name}"]
 }
 
 resource "aws_route53_record" "ssh" {
@@ -1650,3 +1862,45 @@ resource "aws_route53_record" "tcp" {
   records = ["${aws_elb.cf_tcp_lb.dns_name}"]
 }
 `
+
+// ConcourseDNSTemplate is formatted with the resource type of the concourse
+// lb, aws_elb for classic elbs and aws_lb for application and network lbs.
+const ConcourseDNSTemplate = `variable "concourse_domain" {
+  type = "string"
+}
+
+resource "aws_route53_zone" "concourse_dns_zone" {
+  name = "${var.concourse_domain}"
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-concourse-hosted-zone"))}"
+}
+
+output "concourse_dns_zone_name_servers" {
+  value = "${aws_route53_zone.concourse_dns_zone.name_servers}"
+}
+
+resource "aws_route53_record" "concourse" {
+  zone_id = "${aws_route53_zone.concourse_dns_zone.id}"
+  name    = "${var.concourse_domain}"
+  type    = "A"
+
+  alias {
+    name                   = "${%[1]s.concourse_lb.dns_name}"
+    zone_id                = "${%[1]s.concourse_lb.zone_id}"
+    evaluate_target_health = false
+  }
+}
+`
+
+const ConcourseTSADNSTemplate = `resource "aws_route53_record" "concourse_tsa" {
+  zone_id = "${aws_route53_zone.concourse_dns_zone.id}"
+  name    = "tsa.${var.concourse_domain}"
+  type    = "A"
+
+  alias {
+    name                   = "${aws_lb.concourse_tsa_lb.dns_name}"
+    zone_id                = "${aws_lb.concourse_tsa_lb.zone_id}"
+    evaluate_target_health = false
+  }
+}
+`
@@ -0,0 +1,188 @@
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+const customSSLCertificateTemplate = `variable "%[1]s_ssl_certificate" {
+  type = "string"
+}
+
+variable "%[1]s_ssl_certificate_chain" {
+  type = "string"
+}
+
+variable "%[1]s_ssl_certificate_private_key" {
+  type = "string"
+}
+
+resource "aws_iam_server_certificate" "%[1]s_lb_cert" {
+  name_prefix       = "${var.short_env_id}-"
+
+  certificate_body  = "${var.%[1]s_ssl_certificate}"
+  certificate_chain = "${var.%[1]s_ssl_certificate_chain}"
+  private_key       = "${var.%[1]s_ssl_certificate_private_key}"
+
+  lifecycle {
+    create_before_destroy = true
+    ignore_changes        = ["name_prefix", "private_key"]
+  }
+}
+
+output "%[1]s_lb_certificate_name" {
+  value = "${aws_iam_server_certificate.%[1]s_lb_cert.name}"
+}
+`
+
+const customLBTemplate = `resource "aws_security_group" "%[1]s_lb_security_group" {
+  name = "%[1]s_lb_security_group"
+  description = "%[1]s"
+  vpc_id      = "${aws_vpc.vpc.id}"
+%[2]s
+  egress {
+    from_port = 0
+    to_port = 0
+    protocol = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-%[1]s-lb-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
+  }
+}
+
+resource "aws_security_group" "%[1]s_lb_internal_security_group" {
+  name = "%[1]s_lb_internal_security_group"
+  description = "%[1]s Internal"
+  vpc_id      = "${aws_vpc.vpc.id}"
+%[3]s
+  egress {
+    from_port = 0
+    to_port = 0
+    protocol = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-%[1]s-lb-internal-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
+  }
+}
+
+output "%[1]s_lb_internal_security_group" {
+  value="${aws_security_group.%[1]s_lb_internal_security_group.id}"
+}
+
+resource "aws_elb" "%[1]s_lb" {
+  name                      = "${var.short_env_id}-%[1]s-lb"
+  cross_zone_load_balancing = true
+
+  health_check {
+    healthy_threshold   = 2
+    unhealthy_threshold = 10
+    interval            = 30
+    target              = "%[4]s"
+    timeout             = 5
+  }
+%[5]s
+  security_groups = ["${aws_security_group.%[1]s_lb_security_group.id}"]
+  subnets         = ["${aws_subnet.lb_subnets.*.id}"]
+
+  tags = "${var.tags}"
+
+  lifecycle {
+    ignore_changes = ["name"]
+  }
+}
+
+output "%[1]s_lb_name" {
+  value = "${aws_elb.%[1]s_lb.name}"
+}
+
+output "%[1]s_lb_url" {
+  value = "${aws_elb.%[1]s_lb.dns_name}"
+}
+`
+
+// CustomLBTemplate renders the terraform for a load balancer described by a
+// custom lb spec.
+func CustomLBTemplate(spec storage.CustomLBSpec) string {
+	var publicPorts, internalPorts []int
+	var listeners string
+	for _, listener := range spec.Listeners {
+		publicPorts = appendPort(publicPorts, listener.Port)
+		internalPorts = appendPort(internalPorts, listener.TargetPort)
+
+		lbProtocol := listener.Protocol
+		var sslCertificateID string
+		if spec.TLS {
+			lbProtocol = map[string]string{"tcp": "ssl", "http": "https"}[listener.Protocol]
+			sslCertificateID = fmt.Sprintf("\n    ssl_certificate_id = \"${aws_iam_server_certificate.%s_lb_cert.arn}\"", spec.Name)
+		}
+
+		listeners = fmt.Sprintf(`%s
+  listener {
+    instance_port      = %d
+    instance_protocol  = "%s"
+    lb_port            = %d
+    lb_protocol        = "%s"%s
+  }
+`, listeners, listener.TargetPort, listener.Protocol, listener.Port, lbProtocol, sslCertificateID)
+	}
+	internalPorts = appendPort(internalPorts, spec.HealthCheck.Port)
+
+	var publicIngress string
+	for _, port := range publicPorts {
+		publicIngress = fmt.Sprintf(`%s
+  ingress {
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
+    protocol    = "tcp"
+    from_port   = %[2]d
+    to_port     = %[2]d
+  }
+`, publicIngress, port)
+	}
+
+	var internalIngress string
+	for _, port := range internalPorts {
+		internalIngress = fmt.Sprintf(`%s
+  ingress {
+    security_groups = ["${aws_security_group.%s_lb_security_group.id}"]
+    protocol    = "tcp"
+    from_port   = %[3]d
+    to_port     = %[3]d
+  }
+`, internalIngress, spec.Name, port)
+	}
+
+	template := fmt.Sprintf(customLBTemplate, spec.Name, publicIngress, internalIngress, healthCheckTarget(spec.HealthCheck), listeners)
+	if spec.TLS {
+		template = strings.Join([]string{fmt.Sprintf(customSSLCertificateTemplate, spec.Name), template}, "\n")
+	}
+
+	return template
+}
+
+func healthCheckTarget(healthCheck storage.CustomLBHealthCheck) string {
+	if healthCheck.Protocol == "tcp" {
+		return fmt.Sprintf("TCP:%d", healthCheck.Port)
+	}
+
+	return fmt.Sprintf("%s:%d%s", strings.ToUpper(healthCheck.Protocol), healthCheck.Port, healthCheck.Path)
+}
+
+func appendPort(ports []int, port int) []int {
+	for _, existing := range ports {
+		if existing == port {
+			return ports
+		}
+	}
+
+	return append(ports, port)
+}
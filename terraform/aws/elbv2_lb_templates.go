@@ -0,0 +1,586 @@
+package aws
+
+// The templates below replace the classic ELBs of the cf router and
+// concourse when create-lbs is given --aws-lb-kind. Network load balancers
+// have no security groups and preserve the client address, so the internal
+// security groups of their targets allow traffic from anywhere.
+
+const ConcourseALBTemplate = `resource "aws_security_group" "concourse_lb_security_group" {
+  name = "concourse_lb_security_group"
+  description = "Concourse"
+  vpc_id      = "${aws_vpc.vpc.id}"
+
+  ingress {
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
+    protocol    = "tcp"
+    from_port   = 80
+    to_port     = 80
+  }
+
+  ingress {
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
+    protocol    = "tcp"
+    from_port   = 443
+    to_port     = 443
+  }
+
+  egress {
+    from_port = 0
+    to_port = 0
+    protocol = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-concourse-lb-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
+  }
+}
+
+resource "aws_security_group" "concourse_lb_internal_security_group" {
+  name = "concourse_lb_internal_security_group"
+  description = "Concourse Internal"
+  vpc_id      = "${aws_vpc.vpc.id}"
+
+  ingress {
+    security_groups = ["${aws_security_group.concourse_lb_security_group.id}"]
+    protocol    = "tcp"
+    from_port   = 8080
+    to_port     = 8080
+  }
+
+  ingress {
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
+    protocol    = "tcp"
+    from_port   = 2222
+    to_port     = 2222
+  }
+
+  egress {
+    from_port = 0
+    to_port = 0
+    protocol = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-concourse-lb-internal-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
+  }
+}
+
+output "concourse_lb_internal_security_group" {
+  value="${aws_security_group.concourse_lb_internal_security_group.id}"
+}
+
+resource "aws_lb" "concourse_lb" {
+  name               = "${var.short_env_id}-concourse-lb"
+  load_balancer_type = "application"
+  idle_timeout       = 3600
+
+  security_groups = ["${aws_security_group.concourse_lb_security_group.id}"]
+  subnets         = ["${aws_subnet.lb_subnets.*.id}"]
+
+  tags = "${var.tags}"
+}
+
+resource "aws_lb_target_group" "concourse_lb_target_group" {
+  name     = "${var.short_env_id}-concourse"
+  port     = 8080
+  protocol = "HTTP"
+  vpc_id   = "${aws_vpc.vpc.id}"
+
+  health_check {
+    healthy_threshold   = 2
+    unhealthy_threshold = 10
+    interval            = 30
+    protocol            = "HTTP"
+    path                = "/"
+    timeout             = 5
+  }
+
+  tags = "${var.tags}"
+}
+
+resource "aws_lb_listener" "concourse_lb_80" {
+  load_balancer_arn = "${aws_lb.concourse_lb.arn}"
+  port              = 80
+  protocol          = "HTTP"
+
+  default_action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.concourse_lb_target_group.arn}"
+  }
+}
+
+resource "aws_lb_listener" "concourse_lb_443" {
+  load_balancer_arn = "${aws_lb.concourse_lb.arn}"
+  port              = 443
+  protocol          = "HTTPS"
+  ssl_policy        = "ELBSecurityPolicy-2016-08"
+  certificate_arn   = "${aws_iam_server_certificate.concourse_lb_cert.arn}"
+
+  default_action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.concourse_lb_target_group.arn}"
+  }
+}
+
+resource "aws_lb" "concourse_tsa_lb" {
+  name               = "${var.short_env_id}-concourse-tsa"
+  load_balancer_type = "network"
+
+  subnets = ["${aws_subnet.lb_subnets.*.id}"]
+
+  tags = "${var.tags}"
+}
+
+resource "aws_lb_target_group" "concourse_lb_tsa_target_group" {
+  name     = "${var.short_env_id}-concourse-tsa"
+  port     = 2222
+  protocol = "TCP"
+  vpc_id   = "${aws_vpc.vpc.id}"
+
+  health_check {
+    healthy_threshold   = 3
+    unhealthy_threshold = 3
+    interval            = 30
+    protocol            = "TCP"
+  }
+
+  tags = "${var.tags}"
+}
+
+resource "aws_lb_listener" "concourse_tsa_lb_2222" {
+  load_balancer_arn = "${aws_lb.concourse_tsa_lb.arn}"
+  port              = 2222
+  protocol          = "TCP"
+
+  default_action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.concourse_lb_tsa_target_group.arn}"
+  }
+}
+
+output "concourse_lb_name" {
+  value = "${aws_lb.concourse_lb.name}"
+}
+
+output "concourse_lb_url" {
+  value = "${aws_lb.concourse_lb.dns_name}"
+}
+
+output "concourse_lb_target_group" {
+  value = "${aws_lb_target_group.concourse_lb_target_group.name}"
+}
+
+output "concourse_lb_tsa_target_group" {
+  value = "${aws_lb_target_group.concourse_lb_tsa_target_group.name}"
+}
+
+output "concourse_tsa_lb_name" {
+  value = "${aws_lb.concourse_tsa_lb.name}"
+}
+
+output "concourse_tsa_lb_url" {
+  value = "${aws_lb.concourse_tsa_lb.dns_name}"
+}
+`
+
+const ConcourseNLBTemplate = `resource "aws_security_group" "concourse_lb_internal_security_group" {
+  name = "concourse_lb_internal_security_group"
+  description = "Concourse Internal"
+  vpc_id      = "${aws_vpc.vpc.id}"
+
+  ingress {
+    cidr_blocks = ["${concat(var.lb_inbound_cidrs, aws_subnet.lb_subnets.*.cidr_block)}"]
+    protocol    = "tcp"
+    from_port   = 8080
+    to_port     = 8080
+  }
+
+  ingress {
+    cidr_blocks = ["${concat(var.lb_inbound_cidrs, aws_subnet.lb_subnets.*.cidr_block)}"]
+    protocol    = "tcp"
+    from_port   = 2222
+    to_port     = 2222
+  }
+
+  egress {
+    from_port = 0
+    to_port = 0
+    protocol = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-concourse-lb-internal-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
+  }
+}
+
+output "concourse_lb_internal_security_group" {
+  value="${aws_security_group.concourse_lb_internal_security_group.id}"
+}
+
+resource "aws_lb" "concourse_lb" {
+  name               = "${var.short_env_id}-concourse-lb"
+  load_balancer_type = "network"
+
+  subnets = ["${aws_subnet.lb_subnets.*.id}"]
+
+  tags = "${var.tags}"
+}
+
+resource "aws_lb_target_group" "concourse_lb_target_group" {
+  name     = "${var.short_env_id}-concourse"
+  port     = 8080
+  protocol = "TCP"
+  vpc_id   = "${aws_vpc.vpc.id}"
+
+  health_check {
+    healthy_threshold   = 3
+    unhealthy_threshold = 3
+    interval            = 30
+    protocol            = "TCP"
+  }
+
+  tags = "${var.tags}"
+}
+
+resource "aws_lb_target_group" "concourse_lb_tsa_target_group" {
+  name     = "${var.short_env_id}-concourse-tsa"
+  port     = 2222
+  protocol = "TCP"
+  vpc_id   = "${aws_vpc.vpc.id}"
+
+  health_check {
+    healthy_threshold   = 3
+    unhealthy_threshold = 3
+    interval            = 30
+    protocol            = "TCP"
+  }
+
+  tags = "${var.tags}"
+}
+
+resource "aws_lb_listener" "concourse_lb_80" {
+  load_balancer_arn = "${aws_lb.concourse_lb.arn}"
+  port              = 80
+  protocol          = "TCP"
+
+  default_action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.concourse_lb_target_group.arn}"
+  }
+}
+
+resource "aws_lb_listener" "concourse_lb_443" {
+  load_balancer_arn = "${aws_lb.concourse_lb.arn}"
+  port              = 443
+  protocol          = "TLS"
+  ssl_policy        = "ELBSecurityPolicy-2016-08"
+  certificate_arn   = "${aws_iam_server_certificate.concourse_lb_cert.arn}"
+
+  default_action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.concourse_lb_target_group.arn}"
+  }
+}
+
+resource "aws_lb_listener" "concourse_lb_2222" {
+  load_balancer_arn = "${aws_lb.concourse_lb.arn}"
+  port              = 2222
+  protocol          = "TCP"
+
+  default_action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.concourse_lb_tsa_target_group.arn}"
+  }
+}
+
+output "concourse_lb_name" {
+  value = "${aws_lb.concourse_lb.name}"
+}
+
+output "concourse_lb_url" {
+  value = "${aws_lb.concourse_lb.dns_name}"
+}
+
+output "concourse_lb_target_group" {
+  value = "${aws_lb_target_group.concourse_lb_target_group.name}"
+}
+
+output "concourse_lb_tsa_target_group" {
+  value = "${aws_lb_target_group.concourse_lb_tsa_target_group.name}"
+}
+`
+
+const CFRouterALBTemplate = `resource "aws_security_group" "cf_router_lb_security_group" {
+  name = "cf_router_lb_security_group"
+  description = "CF Router"
+  vpc_id      = "${aws_vpc.vpc.id}"
+
+  ingress {
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
+    protocol    = "tcp"
+    from_port   = 80
+    to_port     = 80
+  }
+
+  ingress {
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
+    protocol    = "tcp"
+    from_port   = 443
+    to_port     = 443
+  }
+
+  ingress {
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
+    protocol    = "tcp"
+    from_port   = 4443
+    to_port     = 4443
+  }
+
+  egress {
+    from_port = 0
+    to_port = 0
+    protocol = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-cf-router-lb-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
+  }
+}
+
+output "cf_router_lb_security_group" {
+  value="${aws_security_group.cf_router_lb_security_group.id}"
+}
+
+resource "aws_security_group" "cf_router_lb_internal_security_group" {
+  name = "cf_router_lb_internal_security_group"
+  description = "CF Router Internal"
+  vpc_id      = "${aws_vpc.vpc.id}"
+
+  ingress {
+    security_groups = ["${aws_security_group.cf_router_lb_security_group.id}"]
+    protocol    = "tcp"
+    from_port   = 80
+    to_port     = 80
+  }
+
+  ingress {
+    security_groups = ["${aws_security_group.cf_router_lb_security_group.id}"]
+    protocol    = "tcp"
+    from_port   = 8080
+    to_port     = 8080
+  }
+
+  egress {
+    from_port = 0
+    to_port = 0
+    protocol = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-cf-router-lb-internal-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
+  }
+}
+
+output "cf_router_lb_internal_security_group" {
+  value="${aws_security_group.cf_router_lb_internal_security_group.id}"
+}
+
+resource "aws_lb" "cf_router_lb" {
+  name               = "${var.short_env_id}-cf-router-lb"
+  load_balancer_type = "application"
+  idle_timeout       = 3600
+
+  security_groups = ["${aws_security_group.cf_router_lb_security_group.id}"]
+  subnets         = ["${aws_subnet.lb_subnets.*.id}"]
+
+  tags = "${var.tags}"
+}
+
+resource "aws_lb_target_group" "cf_router_lb_target_group" {
+  name     = "${var.short_env_id}-cf-router"
+  port     = 80
+  protocol = "HTTP"
+  vpc_id   = "${aws_vpc.vpc.id}"
+
+  health_check {
+    healthy_threshold   = 5
+    unhealthy_threshold = 2
+    interval            = 12
+    protocol            = "HTTP"
+    port                = 8080
+    path                = "/health"
+    timeout             = 2
+  }
+
+  tags = "${var.tags}"
+}
+
+resource "aws_lb_listener" "cf_router_lb_80" {
+  load_balancer_arn = "${aws_lb.cf_router_lb.arn}"
+  port              = 80
+  protocol          = "HTTP"
+
+  default_action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.cf_router_lb_target_group.arn}"
+  }
+}
+
+resource "aws_lb_listener" "cf_router_lb_443" {
+  load_balancer_arn = "${aws_lb.cf_router_lb.arn}"
+  port              = 443
+  protocol          = "HTTPS"
+  ssl_policy        = "ELBSecurityPolicy-2016-08"
+  certificate_arn   = "${aws_iam_server_certificate.cf_lb_cert.arn}"
+
+  default_action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.cf_router_lb_target_group.arn}"
+  }
+}
+
+resource "aws_lb_listener" "cf_router_lb_4443" {
+  load_balancer_arn = "${aws_lb.cf_router_lb.arn}"
+  port              = 4443
+  protocol          = "HTTPS"
+  ssl_policy        = "ELBSecurityPolicy-2016-08"
+  certificate_arn   = "${aws_iam_server_certificate.cf_lb_cert.arn}"
+
+  default_action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.cf_router_lb_target_group.arn}"
+  }
+}
+
+output "cf_router_lb_name" {
+  value = "${aws_lb.cf_router_lb.name}"
+}
+
+output "cf_router_lb_url" {
+  value = "${aws_lb.cf_router_lb.dns_name}"
+}
+
+output "cf_router_lb_target_group" {
+  value = "${aws_lb_target_group.cf_router_lb_target_group.name}"
+}
+`
+
+const CFRouterNLBTemplate = `resource "aws_security_group" "cf_router_lb_internal_security_group" {
+  name = "cf_router_lb_internal_security_group"
+  description = "CF Router Internal"
+  vpc_id      = "${aws_vpc.vpc.id}"
+
+  ingress {
+    cidr_blocks = ["${concat(var.lb_inbound_cidrs, aws_subnet.lb_subnets.*.cidr_block)}"]
+    protocol    = "tcp"
+    from_port   = 80
+    to_port     = 80
+  }
+
+  egress {
+    from_port = 0
+    to_port = 0
+    protocol = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-cf-router-lb-internal-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
+  }
+}
+
+output "cf_router_lb_internal_security_group" {
+  value="${aws_security_group.cf_router_lb_internal_security_group.id}"
+}
+
+resource "aws_lb" "cf_router_lb" {
+  name               = "${var.short_env_id}-cf-router-lb"
+  load_balancer_type = "network"
+
+  subnets = ["${aws_subnet.lb_subnets.*.id}"]
+
+  tags = "${var.tags}"
+}
+
+resource "aws_lb_target_group" "cf_router_lb_target_group" {
+  name     = "${var.short_env_id}-cf-router"
+  port     = 80
+  protocol = "TCP"
+  vpc_id   = "${aws_vpc.vpc.id}"
+
+  health_check {
+    healthy_threshold   = 3
+    unhealthy_threshold = 3
+    interval            = 10
+    protocol            = "TCP"
+  }
+
+  tags = "${var.tags}"
+}
+
+resource "aws_lb_listener" "cf_router_lb_80" {
+  load_balancer_arn = "${aws_lb.cf_router_lb.arn}"
+  port              = 80
+  protocol          = "TCP"
+
+  default_action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.cf_router_lb_target_group.arn}"
+  }
+}
+
+resource "aws_lb_listener" "cf_router_lb_443" {
+  load_balancer_arn = "${aws_lb.cf_router_lb.arn}"
+  port              = 443
+  protocol          = "TLS"
+  ssl_policy        = "ELBSecurityPolicy-2016-08"
+  certificate_arn   = "${aws_iam_server_certificate.cf_lb_cert.arn}"
+
+  default_action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.cf_router_lb_target_group.arn}"
+  }
+}
+
+resource "aws_lb_listener" "cf_router_lb_4443" {
+  load_balancer_arn = "${aws_lb.cf_router_lb.arn}"
+  port              = 4443
+  protocol          = "TLS"
+  ssl_policy        = "ELBSecurityPolicy-2016-08"
+  certificate_arn   = "${aws_iam_server_certificate.cf_lb_cert.arn}"
+
+  default_action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.cf_router_lb_target_group.arn}"
+  }
+}
+
+output "cf_router_lb_name" {
+  value = "${aws_lb.cf_router_lb.name}"
+}
+
+output "cf_router_lb_url" {
+  value = "${aws_lb.cf_router_lb.dns_name}"
+}
+
+output "cf_router_lb_target_group" {
+  value = "${aws_lb_target_group.cf_router_lb_target_group.name}"
+}
+`
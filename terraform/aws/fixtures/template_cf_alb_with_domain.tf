@@ -0,0 +1,1627 @@
+resource "aws_eip" "bosh_eip" {
+  depends_on = ["aws_internet_gateway.ig"]
+  vpc      = true
+
+  tags = "${var.tags}"
+}
+
+output "bosh_eip" {
+  value = "${aws_eip.bosh_eip.public_ip}"
+}
+
+output "bosh_url" {
+  value = "https://${aws_eip.bosh_eip.public_ip}:25555"
+}
+
+variable "access_key" {
+  type = "string"
+}
+
+variable "secret_key" {
+  type = "string"
+}
+
+variable "session_token" {
+  type    = "string"
+  default = ""
+}
+
+variable "region" {
+  type = "string"
+}
+
+provider "aws" {
+  version    = "~> 1.60"
+  access_key = "${var.access_key}"
+  secret_key = "${var.secret_key}"
+  token      = "${var.session_token}"
+  region     = "${var.region}"
+}
+
+resource "aws_security_group" "internal_security_group" {
+  name        = "internal_security_group"
+  description = "Internal"
+  vpc_id      = "${aws_vpc.vpc.id}"
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-internal-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
+  }
+}
+
+resource "aws_security_group_rule" "internal_security_group_rule_tcp" {
+  security_group_id        = "${aws_security_group.internal_security_group.id}"
+  type                     = "ingress"
+  protocol                 = "tcp"
+  from_port                = 0
+  to_port                  = 65535
+  self                     = true
+}
+
+resource "aws_security_group_rule" "internal_security_group_rule_udp" {
+  security_group_id        = "${aws_security_group.internal_security_group.id}"
+  type                     = "ingress"
+  protocol                 = "udp"
+  from_port                = 0
+  to_port                  = 65535
+  self                     = true
+}
+
+resource "aws_security_group_rule" "internal_security_group_rule_icmp" {
+  security_group_id        = "${aws_security_group.internal_security_group.id}"
+  type                     = "ingress"
+  protocol                 = "icmp"
+  from_port                = -1
+  to_port                  = -1
+  cidr_blocks              = ["0.0.0.0/0"]
+}
+
+resource "aws_security_group_rule" "internal_security_group_rule_allow_internet" {
+  security_group_id        = "${aws_security_group.internal_security_group.id}"
+  type                     = "egress"
+  protocol                 = "-1"
+  from_port                = 0
+  to_port                  = 0
+  cidr_blocks              = ["0.0.0.0/0"]
+}
+
+output "internal_security_group" {
+  value="${aws_security_group.internal_security_group.id}"
+}
+
+variable "bosh_inbound_cidrs" {
+  type    = "list"
+  default = ["0.0.0.0/0"]
+}
+
+resource "aws_security_group" "bosh_security_group" {
+  name        = "bosh_security_group"
+  description = "Bosh"
+  vpc_id      = "${aws_vpc.vpc.id}"
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-bosh-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
+  }
+}
+
+resource "aws_security_group_rule" "bosh_security_group_rule_tcp_ssh" {
+  security_group_id        = "${aws_security_group.bosh_security_group.id}"
+  type                     = "ingress"
+  protocol                 = "tcp"
+  from_port                = 22
+  to_port                  = 22
+  cidr_blocks              = ["${var.bosh_inbound_cidrs}"]
+}
+
+resource "aws_security_group_rule" "bosh_security_group_rule_tcp_bosh_agent" {
+  security_group_id        = "${aws_security_group.bosh_security_group.id}"
+  type                     = "ingress"
+  protocol                 = "tcp"
+  from_port                = 6868
+  to_port                  = 6868
+  cidr_blocks              = ["${var.bosh_inbound_cidrs}"]
+}
+
+resource "aws_security_group_rule" "bosh_security_group_rule_tcp_director_api" {
+  security_group_id        = "${aws_security_group.bosh_security_group.id}"
+  type                     = "ingress"
+  protocol                 = "tcp"
+  from_port                = 25555
+  to_port                  = 25555
+  cidr_blocks              = ["${var.bosh_inbound_cidrs}"]
+}
+
+resource "aws_security_group_rule" "bosh_security_group_rule_tcp" {
+  security_group_id        = "${aws_security_group.bosh_security_group.id}"
+  type                     = "ingress"
+  protocol                 = "tcp"
+  from_port                = 0
+  to_port                  = 65535
+  source_security_group_id = "${aws_security_group.internal_security_group.id}"
+}
+
+resource "aws_security_group_rule" "bosh_security_group_rule_udp" {
+  security_group_id        = "${aws_security_group.bosh_security_group.id}"
+  type                     = "ingress"
+  protocol                 = "udp"
+  from_port                = 0
+  to_port                  = 65535
+  source_security_group_id = "${aws_security_group.internal_security_group.id}"
+}
+
+resource "aws_security_group_rule" "bosh_security_group_rule_allow_internet" {
+  security_group_id        = "${aws_security_group.bosh_security_group.id}"
+  type                     = "egress"
+  protocol                 = "-1"
+  from_port                = 0
+  to_port                  = 0
+  cidr_blocks              = ["0.0.0.0/0"]
+}
+
+output "bosh_security_group" {
+  value="${aws_security_group.bosh_security_group.id}"
+}
+
+resource "aws_security_group_rule" "bosh_internal_security_rule_tcp" {
+  security_group_id        = "${aws_security_group.internal_security_group.id}"
+  type                     = "ingress"
+  protocol                 = "tcp"
+  from_port                = 0
+  to_port                  = 65535
+  source_security_group_id = "${aws_security_group.bosh_security_group.id}"
+}
+
+resource "aws_security_group_rule" "bosh_internal_security_rule_udp" {
+  security_group_id        = "${aws_security_group.internal_security_group.id}"
+  type                     = "ingress"
+  protocol                 = "udp"
+  from_port                = 0
+  to_port                  = 65535
+  source_security_group_id = "${aws_security_group.bosh_security_group.id}"
+}
+
+variable "bosh_subnet_cidr" {
+  type    = "string"
+  default = "10.0.0.0/24"
+}
+
+variable "bosh_availability_zone" {
+  type = "string"
+}
+
+resource "aws_subnet" "bosh_subnet" {
+  vpc_id            = "${aws_vpc.vpc.id}"
+  cidr_block        = "${var.bosh_subnet_cidr}"
+  availability_zone = "${var.bosh_availability_zone}"
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-bosh-subnet"))}"
+}
+
+resource "aws_route_table" "bosh_route_table" {
+  vpc_id = "${aws_vpc.vpc.id}"
+
+  route {
+    cidr_block = "0.0.0.0/0"
+    gateway_id = "${aws_internet_gateway.ig.id}"
+  }
+
+  tags = "${var.tags}"
+}
+
+resource "aws_route_table_association" "route_bosh_subnets" {
+  subnet_id      = "${aws_subnet.bosh_subnet.id}"
+  route_table_id = "${aws_route_table.bosh_route_table.id}"
+}
+
+output "bosh_subnet_id" {
+  value = "${aws_subnet.bosh_subnet.id}"
+}
+
+output "bosh_subnet_availability_zone" {
+  value = "${aws_subnet.bosh_subnet.availability_zone}"
+}
+
+variable "availability_zones" {
+  type = "list"
+}
+
+resource "aws_subnet" "internal_subnets" {
+  count             = "${length(var.availability_zones)}"
+  vpc_id            = "${aws_vpc.vpc.id}"
+  cidr_block        = "${cidrsubnet("10.0.0.0/16", 4, count.index+1)}"
+  availability_zone = "${element(var.availability_zones, count.index)}"
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-internal-subnet${count.index}"))}"
+}
+
+output "internal_subnet_ids" {
+  value = ["${aws_subnet.internal_subnets.*.id}"]
+}
+
+output "internal_subnet_availability_zones" {
+  value = ["${aws_subnet.internal_subnets.*.availability_zone}"]
+}
+
+output "internal_subnet_cidrs" {
+  value = ["${aws_subnet.internal_subnets.*.cidr_block}"]
+}
+
+variable "env_id" {
+  type = "string"
+}
+
+variable "tags" {
+  type    = "map"
+  default = {}
+}
+
+variable "short_env_id" {
+  type = "string"
+}
+
+variable "vpc_cidr" {
+  type = "string"
+  default = "10.0.0.0/16"
+}
+
+resource "aws_vpc" "vpc" {
+  cidr_block           = "${var.vpc_cidr}"
+  instance_tenancy     = "default"
+  enable_dns_hostnames = true
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-vpc"))}"
+}
+
+resource "aws_internet_gateway" "ig" {
+  vpc_id = "${aws_vpc.vpc.id}"
+
+  tags = "${var.tags}"
+}
+
+output "vpc_id" {
+  value = "${aws_vpc.vpc.id}"
+}
+
+variable "bosh_iam_user_name" {
+  type    = "string"
+  default = ""
+}
+
+variable "bosh_iam_user_policy_name" {
+  type    = "string"
+  default = ""
+}
+
+resource "aws_iam_user" "bosh" {
+  name = "${coalesce(var.bosh_iam_user_name, format("%s_bosh_user", var.env_id))}"
+}
+
+resource "aws_iam_user_policy" "bosh" {
+  name  = "${coalesce(var.bosh_iam_user_policy_name, format("%s_bosh_user_policy", var.env_id))}"
+  user = "${aws_iam_user.bosh.name}"
+
+  policy = <<EOF
+{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Action": [
+        "ec2:AssociateAddress",
+        "ec2:AttachVolume",
+        "ec2:CreateVolume",
+        "ec2:DeleteSnapshot",
+        "ec2:DeleteVolume",
+        "ec2:DescribeAddresses",
+        "ec2:DescribeImages",
+        "ec2:DescribeInstances",
+        "ec2:DescribeRegions",
+        "ec2:DescribeSecurityGroups",
+        "ec2:DescribeSnapshots",
+        "ec2:DescribeSubnets",
+        "ec2:DescribeVolumes",
+        "ec2:DetachVolume",
+        "ec2:CreateSnapshot",
+        "ec2:CreateTags",
+        "ec2:RunInstances",
+        "ec2:TerminateInstances",
+        "ec2:RegisterImage",
+        "ec2:DeregisterImage"
+      ],
+      "Effect": "Allow",
+      "Resource": "*"
+    },
+    {
+      "Action": [
+        "elasticloadbalancing:*"
+      ],
+      "Effect": "Allow",
+      "Resource": "*"
+    }
+  ]
+}
+EOF
+}
+
+resource "aws_iam_access_key" "bosh" {
+  user = "${aws_iam_user.bosh.name}"
+}
+
+output "bosh_user_access_key" {
+  value = "${aws_iam_access_key.bosh.id}"
+}
+
+output "bosh_user_secret_access_key" {
+  value = "${aws_iam_access_key.bosh.secret}"
+}
+
+variable "nat_ami_map" {
+  type = "map"
+
+  default = {
+    us-east-1      ="ami-68115b02"
+    us-west-1      ="ami-ef1a718f"
+    us-west-2      ="ami-77a4b816"
+    eu-west-1      ="ami-c0993ab3"
+    eu-central-1   ="ami-0b322e67"
+    ap-southeast-1 ="ami-e2fc3f81"
+    ap-southeast-2 ="ami-e3217a80"
+    ap-northeast-1 ="ami-f885ae96"
+    ap-northeast-2 ="ami-4118d72f"
+    sa-east-1      ="ami-8631b5ea"
+  }
+}
+
+resource "aws_security_group" "nat_security_group" {
+  name        = "nat_security_group"
+  description = "NAT"
+  vpc_id      = "${aws_vpc.vpc.id}"
+
+  ingress {
+    protocol    = "tcp"
+    from_port   = 0
+    to_port     = 65535
+    security_groups = ["${aws_security_group.internal_security_group.id}"]
+  }
+
+  ingress {
+    protocol    = "udp"
+    from_port   = 0
+    to_port     = 65535
+    security_groups = ["${aws_security_group.internal_security_group.id}"]
+  }
+
+  ingress {
+    protocol    = "icmp"
+    from_port   = -1
+    to_port     = -1
+    security_groups = ["${aws_security_group.internal_security_group.id}"]
+  }
+
+  egress {
+    from_port = 0
+    to_port = 0
+    protocol = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-nat-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
+  }
+}
+
+variable "nat_ssh_key_pair_name" {}
+
+resource "aws_instance" "nat" {
+  private_ip             = "10.0.0.7"
+  instance_type          = "t2.medium"
+  subnet_id              = "${aws_subnet.bosh_subnet.id}"
+  source_dest_check      = false
+  ami                    = "${lookup(var.nat_ami_map, var.region)}"
+  key_name               = "${var.nat_ssh_key_pair_name}"
+  vpc_security_group_ids = ["${aws_security_group.nat_security_group.id}"]
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-nat"))}"
+}
+
+resource "aws_eip" "nat_eip" {
+  depends_on = ["aws_internet_gateway.ig"]
+  instance = "${aws_instance.nat.id}"
+  vpc      = true
+
+  tags = "${var.tags}"
+}
+
+output "nat_eip" {
+  value = "${aws_eip.nat_eip.public_ip}"
+}
+
+resource "aws_route_table" "internal_route_table" {
+  vpc_id = "${aws_vpc.vpc.id}"
+
+  route {
+    cidr_block = "0.0.0.0/0"
+    instance_id = "${aws_instance.nat.id}"
+  }
+
+  tags = "${var.tags}"
+}
+
+resource "aws_route_table_association" "route_internal_subnets" {
+  count          = "${length(var.availability_zones)}"
+  subnet_id      = "${element(aws_subnet.internal_subnets.*.id, count.index)}"
+  route_table_id = "${aws_route_table.internal_route_table.id}"
+}
+
+variable "lb_inbound_cidrs" {
+  type    = "list"
+  default = ["0.0.0.0/0"]
+}
+
+resource "aws_subnet" "lb_subnets" {
+  count             = "${length(var.availability_zones)}"
+  vpc_id            = "${aws_vpc.vpc.id}"
+  cidr_block        = "${cidrsubnet("10.0.0.0/20", 4, count.index+2)}"
+  availability_zone = "${element(var.availability_zones, count.index)}"
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-lb-subnet${count.index}"))}"
+}
+
+resource "aws_route_table" "lb_route_table" {
+  vpc_id = "${aws_vpc.vpc.id}"
+
+  route {
+    cidr_block = "0.0.0.0/0"
+    gateway_id = "${aws_internet_gateway.ig.id}"
+  }
+
+  tags = "${var.tags}"
+}
+
+resource "aws_route_table_association" "route_lb_subnets" {
+  count          = "${length(var.availability_zones)}"
+  subnet_id      = "${element(aws_subnet.lb_subnets.*.id, count.index)}"
+  route_table_id = "${aws_route_table.lb_route_table.id}"
+}
+
+output "lb_subnet_ids" {
+  value = ["${aws_subnet.lb_subnets.*.id}"]
+}
+
+output "lb_subnet_availability_zones" {
+  value = ["${aws_subnet.lb_subnets.*.availability_zone}"]
+}
+
+output "lb_subnet_cidrs" {
+  value = ["${aws_subnet.lb_subnets.*.cidr_block}"]
+}
+
+variable "cf_ssl_certificate" {
+  type = "string"
+}
+
+variable "cf_ssl_certificate_chain" {
+  type = "string"
+}
+
+variable "cf_ssl_certificate_private_key" {
+  type = "string"
+}
+
+resource "aws_iam_server_certificate" "cf_lb_cert" {
+  name_prefix       = "${var.short_env_id}-"
+
+  certificate_body  = "${var.cf_ssl_certificate}"
+  certificate_chain = "${var.cf_ssl_certificate_chain}"
+  private_key       = "${var.cf_ssl_certificate_private_key}"
+
+  lifecycle {
+    create_before_destroy = true
+    ignore_changes        = ["name_prefix", "private_key"]
+  }
+}
+
+output "cf_lb_certificate_name" {
+  value = "${aws_iam_server_certificate.cf_lb_cert.name}"
+}
+
+resource "aws_security_group" "cf_ssh_lb_security_group" {
+  name = "cf_ssh_lb_security_group"
+  description = "CF SSH"
+  vpc_id      = "${aws_vpc.vpc.id}"
+
+  ingress {
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
+    protocol    = "tcp"
+    from_port   = 2222
+    to_port     = 2222
+  }
+
+  egress {
+    from_port = 0
+    to_port = 0
+    protocol = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-cf-ssh-lb-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
+  }
+}
+
+output "cf_ssh_lb_security_group" {
+  value="${aws_security_group.cf_ssh_lb_security_group.id}"
+}
+
+resource "aws_security_group" "cf_ssh_lb_internal_security_group" {
+  name = "cf_ssh_lb_internal_security_group"
+  description = "CF SSH Internal"
+  vpc_id      = "${aws_vpc.vpc.id}"
+
+  ingress {
+    security_groups = ["${aws_security_group.cf_ssh_lb_security_group.id}"]
+    protocol    = "tcp"
+    from_port   = 2222
+    to_port     = 2222
+  }
+
+  egress {
+    from_port = 0
+    to_port = 0
+    protocol = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-cf-ssh-lb-internal-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
+  }
+}
+
+output "cf_ssh_lb_internal_security_group" {
+  value="${aws_security_group.cf_ssh_lb_internal_security_group.id}"
+}
+
+resource "aws_elb" "cf_ssh_lb" {
+  name                      = "${var.short_env_id}-cf-ssh-lb"
+  cross_zone_load_balancing = true
+
+  health_check {
+    healthy_threshold   = 5
+    unhealthy_threshold = 2
+    interval            = 6
+    target              = "TCP:2222"
+    timeout             = 2
+  }
+
+  listener {
+    instance_port     = 2222
+    instance_protocol = "tcp"
+    lb_port           = 2222
+    lb_protocol       = "tcp"
+  }
+
+  security_groups = ["${aws_security_group.cf_ssh_lb_security_group.id}"]
+  subnets         = ["${aws_subnet.lb_subnets.*.id}"]
+
+  tags = "${var.tags}"
+
+  lifecycle {
+    ignore_changes = ["name"]
+  }
+}
+
+output "cf_ssh_lb_name" {
+  value = "${aws_elb.cf_ssh_lb.name}"
+}
+
+output "cf_ssh_lb_url" {
+  value = "${aws_elb.cf_ssh_lb.dns_name}"
+}
+
+resource "aws_security_group" "cf_router_lb_security_group" {
+  name = "cf_router_lb_security_group"
+  description = "CF Router"
+  vpc_id      = "${aws_vpc.vpc.id}"
+
+  ingress {
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
+    protocol    = "tcp"
+    from_port   = 80
+    to_port     = 80
+  }
+
+  ingress {
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
+    protocol    = "tcp"
+    from_port   = 443
+    to_port     = 443
+  }
+
+  ingress {
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
+    protocol    = "tcp"
+    from_port   = 4443
+    to_port     = 4443
+  }
+
+  egress {
+    from_port = 0
+    to_port = 0
+    protocol = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-cf-router-lb-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
+  }
+}
+
+output "cf_router_lb_security_group" {
+  value="${aws_security_group.cf_router_lb_security_group.id}"
+}
+
+resource "aws_security_group" "cf_router_lb_internal_security_group" {
+  name = "cf_router_lb_internal_security_group"
+  description = "CF Router Internal"
+  vpc_id      = "${aws_vpc.vpc.id}"
+
+  ingress {
+    security_groups = ["${aws_security_group.cf_router_lb_security_group.id}"]
+    protocol    = "tcp"
+    from_port   = 80
+    to_port     = 80
+  }
+
+  ingress {
+    security_groups = ["${aws_security_group.cf_router_lb_security_group.id}"]
+    protocol    = "tcp"
+    from_port   = 8080
+    to_port     = 8080
+  }
+
+  egress {
+    from_port = 0
+    to_port = 0
+    protocol = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-cf-router-lb-internal-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
+  }
+}
+
+output "cf_router_lb_internal_security_group" {
+  value="${aws_security_group.cf_router_lb_internal_security_group.id}"
+}
+
+resource "aws_lb" "cf_router_lb" {
+  name               = "${var.short_env_id}-cf-router-lb"
+  load_balancer_type = "application"
+  idle_timeout       = 3600
+
+  security_groups = ["${aws_security_group.cf_router_lb_security_group.id}"]
+  subnets         = ["${aws_subnet.lb_subnets.*.id}"]
+
+  tags = "${var.tags}"
+}
+
+resource "aws_lb_target_group" "cf_router_lb_target_group" {
+  name     = "${var.short_env_id}-cf-router"
+  port     = 80
+  protocol = "HTTP"
+  vpc_id   = "${aws_vpc.vpc.id}"
+
+  health_check {
+    healthy_threshold   = 5
+    unhealthy_threshold = 2
+    interval            = 12
+    protocol            = "HTTP"
+    port                = 8080
+    path                = "/health"
+    timeout             = 2
+  }
+
+  tags = "${var.tags}"
+}
+
+resource "aws_lb_listener" "cf_router_lb_80" {
+  load_balancer_arn = "${aws_lb.cf_router_lb.arn}"
+  port              = 80
+  protocol          = "HTTP"
+
+  default_action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.cf_router_lb_target_group.arn}"
+  }
+}
+
+resource "aws_lb_listener" "cf_router_lb_443" {
+  load_balancer_arn = "${aws_lb.cf_router_lb.arn}"
+  port              = 443
+  protocol          = "HTTPS"
+  ssl_policy        = "ELBSecurityPolicy-2016-08"
+  certificate_arn   = "${aws_iam_server_certificate.cf_lb_cert.arn}"
+
+  default_action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.cf_router_lb_target_group.arn}"
+  }
+}
+
+resource "aws_lb_listener" "cf_router_lb_4443" {
+  load_balancer_arn = "${aws_lb.cf_router_lb.arn}"
+  port              = 4443
+  protocol          = "HTTPS"
+  ssl_policy        = "ELBSecurityPolicy-2016-08"
+  certificate_arn   = "${aws_iam_server_certificate.cf_lb_cert.arn}"
+
+  default_action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.cf_router_lb_target_group.arn}"
+  }
+}
+
+output "cf_router_lb_name" {
+  value = "${aws_lb.cf_router_lb.name}"
+}
+
+output "cf_router_lb_url" {
+  value = "${aws_lb.cf_router_lb.dns_name}"
+}
+
+output "cf_router_lb_target_group" {
+  value = "${aws_lb_target_group.cf_router_lb_target_group.name}"
+}
+
+resource "aws_security_group" "cf_tcp_lb_security_group" {
+  name = "cf_tcp_lb_security_group"
+  description = "CF TCP"
+  vpc_id      = "${aws_vpc.vpc.id}"
+
+  ingress {
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
+    protocol    = "tcp"
+    from_port   = 1024
+    to_port     = 1123
+  }
+
+  egress {
+    from_port = 0
+    to_port = 0
+    protocol = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-cf-tcp-lb-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
+  }
+}
+
+output "cf_tcp_lb_security_group" {
+  value="${aws_security_group.cf_tcp_lb_security_group.id}"
+}
+
+resource "aws_security_group" "cf_tcp_lb_internal_security_group" {
+  name = "cf_tcp_lb_internal_security_group"
+  description = "CF TCP Internal"
+  vpc_id      = "${aws_vpc.vpc.id}"
+
+  ingress {
+    security_groups = ["${aws_security_group.cf_tcp_lb_security_group.id}"]
+    protocol    = "tcp"
+    from_port   = 1024
+    to_port     = 1123
+  }
+
+  egress {
+    from_port = 0
+    to_port = 0
+    protocol = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-cf-tcp-lb-internal-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
+  }
+}
+
+output "cf_tcp_lb_internal_security_group" {
+  value="${aws_security_group.cf_tcp_lb_internal_security_group.id}"
+}
+
+resource "aws_elb" "cf_tcp_lb" {
+  name                      = "${var.short_env_id}-cf-tcp-lb"
+  cross_zone_load_balancing = true
+
+  health_check {
+    healthy_threshold   = 6
+    unhealthy_threshold = 3
+    interval            = 5
+    target              = "TCP:80"
+    timeout             = 3
+  }
+
+  listener {
+    instance_port     = 1024
+    instance_protocol = "tcp"
+    lb_port           = 1024
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1025
+    instance_protocol = "tcp"
+    lb_port           = 1025
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1026
+    instance_protocol = "tcp"
+    lb_port           = 1026
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1027
+    instance_protocol = "tcp"
+    lb_port           = 1027
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1028
+    instance_protocol = "tcp"
+    lb_port           = 1028
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1029
+    instance_protocol = "tcp"
+    lb_port           = 1029
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1030
+    instance_protocol = "tcp"
+    lb_port           = 1030
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1031
+    instance_protocol = "tcp"
+    lb_port           = 1031
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1032
+    instance_protocol = "tcp"
+    lb_port           = 1032
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1033
+    instance_protocol = "tcp"
+    lb_port           = 1033
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1034
+    instance_protocol = "tcp"
+    lb_port           = 1034
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1035
+    instance_protocol = "tcp"
+    lb_port           = 1035
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1036
+    instance_protocol = "tcp"
+    lb_port           = 1036
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1037
+    instance_protocol = "tcp"
+    lb_port           = 1037
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1038
+    instance_protocol = "tcp"
+    lb_port           = 1038
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1039
+    instance_protocol = "tcp"
+    lb_port           = 1039
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1040
+    instance_protocol = "tcp"
+    lb_port           = 1040
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1041
+    instance_protocol = "tcp"
+    lb_port           = 1041
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1042
+    instance_protocol = "tcp"
+    lb_port           = 1042
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1043
+    instance_protocol = "tcp"
+    lb_port           = 1043
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1044
+    instance_protocol = "tcp"
+    lb_port           = 1044
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1045
+    instance_protocol = "tcp"
+    lb_port           = 1045
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1046
+    instance_protocol = "tcp"
+    lb_port           = 1046
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1047
+    instance_protocol = "tcp"
+    lb_port           = 1047
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1048
+    instance_protocol = "tcp"
+    lb_port           = 1048
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1049
+    instance_protocol = "tcp"
+    lb_port           = 1049
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1050
+    instance_protocol = "tcp"
+    lb_port           = 1050
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1051
+    instance_protocol = "tcp"
+    lb_port           = 1051
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1052
+    instance_protocol = "tcp"
+    lb_port           = 1052
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1053
+    instance_protocol = "tcp"
+    lb_port           = 1053
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1054
+    instance_protocol = "tcp"
+    lb_port           = 1054
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1055
+    instance_protocol = "tcp"
+    lb_port           = 1055
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1056
+    instance_protocol = "tcp"
+    lb_port           = 1056
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1057
+    instance_protocol = "tcp"
+    lb_port           = 1057
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1058
+    instance_protocol = "tcp"
+    lb_port           = 1058
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1059
+    instance_protocol = "tcp"
+    lb_port           = 1059
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1060
+    instance_protocol = "tcp"
+    lb_port           = 1060
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1061
+    instance_protocol = "tcp"
+    lb_port           = 1061
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1062
+    instance_protocol = "tcp"
+    lb_port           = 1062
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1063
+    instance_protocol = "tcp"
+    lb_port           = 1063
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1064
+    instance_protocol = "tcp"
+    lb_port           = 1064
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1065
+    instance_protocol = "tcp"
+    lb_port           = 1065
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1066
+    instance_protocol = "tcp"
+    lb_port           = 1066
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1067
+    instance_protocol = "tcp"
+    lb_port           = 1067
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1068
+    instance_protocol = "tcp"
+    lb_port           = 1068
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1069
+    instance_protocol = "tcp"
+    lb_port           = 1069
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1070
+    instance_protocol = "tcp"
+    lb_port           = 1070
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1071
+    instance_protocol = "tcp"
+    lb_port           = 1071
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1072
+    instance_protocol = "tcp"
+    lb_port           = 1072
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1073
+    instance_protocol = "tcp"
+    lb_port           = 1073
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1074
+    instance_protocol = "tcp"
+    lb_port           = 1074
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1075
+    instance_protocol = "tcp"
+    lb_port           = 1075
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1076
+    instance_protocol = "tcp"
+    lb_port           = 1076
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1077
+    instance_protocol = "tcp"
+    lb_port           = 1077
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1078
+    instance_protocol = "tcp"
+    lb_port           = 1078
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1079
+    instance_protocol = "tcp"
+    lb_port           = 1079
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1080
+    instance_protocol = "tcp"
+    lb_port           = 1080
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1081
+    instance_protocol = "tcp"
+    lb_port           = 1081
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1082
+    instance_protocol = "tcp"
+    lb_port           = 1082
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1083
+    instance_protocol = "tcp"
+    lb_port           = 1083
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1084
+    instance_protocol = "tcp"
+    lb_port           = 1084
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1085
+    instance_protocol = "tcp"
+    lb_port           = 1085
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1086
+    instance_protocol = "tcp"
+    lb_port           = 1086
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1087
+    instance_protocol = "tcp"
+    lb_port           = 1087
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1088
+    instance_protocol = "tcp"
+    lb_port           = 1088
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1089
+    instance_protocol = "tcp"
+    lb_port           = 1089
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1090
+    instance_protocol = "tcp"
+    lb_port           = 1090
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1091
+    instance_protocol = "tcp"
+    lb_port           = 1091
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1092
+    instance_protocol = "tcp"
+    lb_port           = 1092
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1093
+    instance_protocol = "tcp"
+    lb_port           = 1093
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1094
+    instance_protocol = "tcp"
+    lb_port           = 1094
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1095
+    instance_protocol = "tcp"
+    lb_port           = 1095
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1096
+    instance_protocol = "tcp"
+    lb_port           = 1096
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1097
+    instance_protocol = "tcp"
+    lb_port           = 1097
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1098
+    instance_protocol = "tcp"
+    lb_port           = 1098
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1099
+    instance_protocol = "tcp"
+    lb_port           = 1099
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1100
+    instance_protocol = "tcp"
+    lb_port           = 1100
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1101
+    instance_protocol = "tcp"
+    lb_port           = 1101
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1102
+    instance_protocol = "tcp"
+    lb_port           = 1102
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1103
+    instance_protocol = "tcp"
+    lb_port           = 1103
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1104
+    instance_protocol = "tcp"
+    lb_port           = 1104
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1105
+    instance_protocol = "tcp"
+    lb_port           = 1105
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1106
+    instance_protocol = "tcp"
+    lb_port           = 1106
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1107
+    instance_protocol = "tcp"
+    lb_port           = 1107
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1108
+    instance_protocol = "tcp"
+    lb_port           = 1108
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1109
+    instance_protocol = "tcp"
+    lb_port           = 1109
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1110
+    instance_protocol = "tcp"
+    lb_port           = 1110
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1111
+    instance_protocol = "tcp"
+    lb_port           = 1111
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1112
+    instance_protocol = "tcp"
+    lb_port           = 1112
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1113
+    instance_protocol = "tcp"
+    lb_port           = 1113
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1114
+    instance_protocol = "tcp"
+    lb_port           = 1114
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1115
+    instance_protocol = "tcp"
+    lb_port           = 1115
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1116
+    instance_protocol = "tcp"
+    lb_port           = 1116
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1117
+    instance_protocol = "tcp"
+    lb_port           = 1117
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1118
+    instance_protocol = "tcp"
+    lb_port           = 1118
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1119
+    instance_protocol = "tcp"
+    lb_port           = 1119
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1120
+    instance_protocol = "tcp"
+    lb_port           = 1120
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1121
+    instance_protocol = "tcp"
+    lb_port           = 1121
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1122
+    instance_protocol = "tcp"
+    lb_port           = 1122
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 1123
+    instance_protocol = "tcp"
+    lb_port           = 1123
+    lb_protocol       = "tcp"
+  }
+
+  security_groups = ["${aws_security_group.cf_tcp_lb_security_group.id}"]
+  subnets         = ["${aws_subnet.lb_subnets.*.id}"]
+
+  tags = "${var.tags}"
+
+  lifecycle {
+    ignore_changes = ["name"]
+  }
+}
+
+output "cf_tcp_lb_name" {
+  value = "${aws_elb.cf_tcp_lb.name}"
+}
+
+output "cf_tcp_lb_url" {
+  value = "${aws_elb.cf_tcp_lb.dns_name}"
+}
+
+variable "system_domain" {
+  type = "string"
+}
+
+resource "aws_route53_zone" "env_dns_zone" {
+  name = "${var.system_domain}"
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-hosted-zone"))}"
+}
+
+output "env_dns_zone_name_servers" {
+  value = "${aws_route53_zone.env_dns_zone.name_servers}"
+}
+
+resource "aws_route53_record" "wildcard_dns" {
+  zone_id = "${aws_route53_zone.env_dns_zone.id}"
+  name    = "*.${var.system_domain}"
+  type    = "CNAME"
+  ttl     = 300
+
+  records = ["${aws_lb.cf_router_lb.dns_name}"]
+}
+
+resource "aws_route53_record" "ssh" {
+  zone_id = "${aws_route53_zone.env_dns_zone.id}"
+  name    = "ssh.${var.system_domain}"
+  type    = "CNAME"
+  ttl     = 300
+
+  records = ["${aws_elb.cf_ssh_lb.dns_name}"]
+}
+
+resource "aws_route53_record" "bosh" {
+  zone_id = "${aws_route53_zone.env_dns_zone.id}"
+  name    = "bosh.${var.system_domain}"
+  type    = "A"
+  ttl     = 300
+
+  records = ["${aws_eip.bosh_eip.public_ip}"]
+}
+
+resource "aws_route53_record" "tcp" {
+  zone_id = "${aws_route53_zone.env_dns_zone.id}"
+  name    = "tcp.${var.system_domain}"
+  type    = "CNAME"
+  ttl     = 300
+
+  records = ["${aws_elb.cf_tcp_lb.dns_name}"]
+}
@@ -1,6 +1,8 @@
 resource "aws_eip" "bosh_eip" {
   depends_on = ["aws_internet_gateway.ig"]
   vpc      = true
+
+  tags = "${var.tags}"
 }
 
 output "bosh_eip" {
@@ -11,149 +13,6 @@ output "bosh_url" {
   value = "https://${aws_eip.bosh_eip.public_ip}:25555"
 }
 
-resource "aws_iam_user" "bosh" {
-  name = "${var.env_id}_bosh_user"
-}
-
-resource "aws_iam_user_policy" "bosh" {
-  name  = "${var.env_id}_bosh_user_policy"
-  user = "${aws_iam_user.bosh.name}"
-
-  policy = <<EOF
-{
-  "Version": "2012-10-17",
-  "Statement": [
-    {
-      "Action": [
-        "ec2:AssociateAddress",
-        "ec2:AttachVolume",
-        "ec2:CreateVolume",
-        "ec2:DeleteSnapshot",
-        "ec2:DeleteVolume",
-        "ec2:DescribeAddresses",
-        "ec2:DescribeImages",
-        "ec2:DescribeInstances",
-        "ec2:DescribeRegions",
-        "ec2:DescribeSecurityGroups",
-        "ec2:DescribeSnapshots",
-        "ec2:DescribeSubnets",
-        "ec2:DescribeVolumes",
-        "ec2:DetachVolume",
-        "ec2:CreateSnapshot",
-        "ec2:CreateTags",
-        "ec2:RunInstances",
-        "ec2:TerminateInstances",
-        "ec2:RegisterImage",
-        "ec2:DeregisterImage"
-      ],
-      "Effect": "Allow",
-      "Resource": "*"
-    },
-    {
-      "Action": [
-        "elasticloadbalancing:*"
-      ],
-      "Effect": "Allow",
-      "Resource": "*"
-    }
-  ]
-}
-EOF
-}
-
-resource "aws_iam_access_key" "bosh" {
-  user = "${aws_iam_user.bosh.name}"
-}
-
-output "bosh_user_access_key" {
-  value = "${aws_iam_access_key.bosh.id}"
-}
-
-output "bosh_user_secret_access_key" {
-  value = "${aws_iam_access_key.bosh.secret}"
-}
-
-variable "nat_ami_map" {
-  type = "map"
-
-  default = {
-    us-east-1      ="ami-68115b02"
-    us-west-1      ="ami-ef1a718f"
-    us-west-2      ="ami-77a4b816"
-    eu-west-1      ="ami-c0993ab3"
-    eu-central-1   ="ami-0b322e67"
-    ap-southeast-1 ="ami-e2fc3f81"
-    ap-southeast-2 ="ami-e3217a80"
-    ap-northeast-1 ="ami-f885ae96"
-    ap-northeast-2 ="ami-4118d72f"
-    sa-east-1      ="ami-8631b5ea"
-  }
-}
-
-resource "aws_security_group" "nat_security_group" {
-  name        = "nat_security_group"
-  description = "NAT"
-  vpc_id      = "${aws_vpc.vpc.id}"
-
-  ingress {
-    protocol    = "tcp"
-    from_port   = 0
-    to_port     = 65535
-    security_groups = ["${aws_security_group.internal_security_group.id}"]
-  }
-
-  ingress {
-    protocol    = "udp"
-    from_port   = 0
-    to_port     = 65535
-    security_groups = ["${aws_security_group.internal_security_group.id}"]
-  }
-
-  ingress {
-    protocol    = "icmp"
-    from_port   = -1
-    to_port     = -1
-    security_groups = ["${aws_security_group.internal_security_group.id}"]
-  }
-
-  egress {
-    from_port = 0
-    to_port = 0
-    protocol = "-1"
-    cidr_blocks = ["0.0.0.0/0"]
-  }
-
-  tags {
-    Name = "${var.env_id}-nat-security-group"
-  }
-}
-
-variable "nat_ssh_key_pair_name" {}
-
-resource "aws_instance" "nat" {
-  private_ip             = "10.0.0.7"
-  instance_type          = "t2.medium"
-  subnet_id              = "${aws_subnet.bosh_subnet.id}"
-  source_dest_check      = false
-  ami                    = "${lookup(var.nat_ami_map, var.region)}"
-  key_name               = "${var.nat_ssh_key_pair_name}"
-  vpc_security_group_ids = ["${aws_security_group.nat_security_group.id}"]
-
-  tags {
-    Name = "${var.env_id}-nat"
-  }
-}
-
-resource "aws_eip" "nat_eip" {
-  depends_on = ["aws_internet_gateway.ig"]
-  instance = "${aws_instance.nat.id}"
-  vpc      = true
-}
-
-output "nat_eip" {
-  value = "${aws_eip.nat_eip.public_ip}"
-}
-
 variable "access_key" {
   type = "string"
 }
@@ -162,13 +21,20 @@ variable "secret_key" {
   type = "string"
 }
 
+variable "session_token" {
+  type    = "string"
+  default = ""
+}
+
 variable "region" {
   type = "string"
 }
 
 provider "aws" {
+  version    = "~> 1.60"
   access_key = "${var.access_key}"
   secret_key = "${var.secret_key}"
+  token      = "${var.session_token}"
   region     = "${var.region}"
 }
 
@@ -177,8 +43,10 @@ resource "aws_security_group" "internal_security_group" {
   description = "Internal"
   vpc_id      = "${aws_vpc.vpc.id}"
 
-  tags {
-    Name = "${var.env_id}-internal-security-group"
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-internal-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
   }
 }
 
@@ -222,8 +90,9 @@ output "internal_security_group" {
   value="${aws_security_group.internal_security_group.id}"
 }
 
-variable "bosh_inbound_cidr" {
-  default = "0.0.0.0/0"
+variable "bosh_inbound_cidrs" {
+  type    = "list"
+  default = ["0.0.0.0/0"]
 }
 
 resource "aws_security_group" "bosh_security_group" {
@@ -231,8 +100,10 @@ resource "aws_security_group" "bosh_security_group" {
   description = "Bosh"
   vpc_id      = "${aws_vpc.vpc.id}"
 
-  tags {
-    Name = "${var.env_id}-bosh-security-group"
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-bosh-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
   }
 }
 
@@ -242,7 +113,7 @@ resource "aws_security_group_rule" "bosh_security_group_rule_tcp_ssh" {
   protocol                 = "tcp"
   from_port                = 22
   to_port                  = 22
-  cidr_blocks              = ["${var.bosh_inbound_cidr}"]
+  cidr_blocks              = ["${var.bosh_inbound_cidrs}"]
 }
 
 resource "aws_security_group_rule" "bosh_security_group_rule_tcp_bosh_agent" {
@@ -251,7 +122,7 @@ resource "aws_security_group_rule" "bosh_security_group_rule_tcp_bosh_agent" {
   protocol                 = "tcp"
   from_port                = 6868
   to_port                  = 6868
-  cidr_blocks              = ["${var.bosh_inbound_cidr}"]
+  cidr_blocks              = ["${var.bosh_inbound_cidrs}"]
 }
 
 resource "aws_security_group_rule" "bosh_security_group_rule_tcp_director_api" {
@@ -260,7 +131,7 @@ resource "aws_security_group_rule" "bosh_security_group_rule_tcp_director_api" {
   protocol                 = "tcp"
   from_port                = 25555
   to_port                  = 25555
-  cidr_blocks              = ["${var.bosh_inbound_cidr}"]
+  cidr_blocks              = ["${var.bosh_inbound_cidrs}"]
 }
 
 resource "aws_security_group_rule" "bosh_security_group_rule_tcp" {
@@ -326,9 +197,7 @@ resource "aws_subnet" "bosh_subnet" {
   cidr_block        = "${var.bosh_subnet_cidr}"
   availability_zone = "${var.bosh_availability_zone}"
 
-  tags {
-    Name = "${var.env_id}-bosh-subnet"
-  }
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-bosh-subnet"))}"
 }
 
 resource "aws_route_table" "bosh_route_table" {
@@ -338,6 +207,8 @@ resource "aws_route_table" "bosh_route_table" {
     cidr_block = "0.0.0.0/0"
     gateway_id = "${aws_internet_gateway.ig.id}"
   }
+
+  tags = "${var.tags}"
 }
 
 resource "aws_route_table_association" "route_bosh_subnets" {
@@ -363,24 +234,7 @@ resource "aws_subnet" "internal_subnets" {
   cidr_block        = "${cidrsubnet("10.0.0.0/16", 4, count.index+1)}"
   availability_zone = "${element(var.availability_zones, count.index)}"
 
-  tags {
-    Name = "${var.env_id}-internal-subnet${count.index}"
-  }
-}
-
-resource "aws_route_table" "internal_route_table" {
-  vpc_id = "${aws_vpc.vpc.id}"
-
-  route {
-    cidr_block = "0.0.0.0/0"
-    instance_id = "${aws_instance.nat.id}"
-  }
-}
-
-resource "aws_route_table_association" "route_internal_subnets" {
-  count          = "${length(var.availability_zones)}"
-  subnet_id      = "${element(aws_subnet.internal_subnets.*.id, count.index)}"
-  route_table_id = "${aws_route_table.internal_route_table.id}"
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-internal-subnet${count.index}"))}"
 }
 
 output "internal_subnet_ids" {
@@ -399,6 +253,11 @@ variable "env_id" {
   type = "string"
 }
 
+variable "tags" {
+  type    = "map"
+  default = {}
+}
+
 variable "short_env_id" {
   type = "string"
 }
@@ -413,28 +272,203 @@ resource "aws_vpc" "vpc" {
   instance_tenancy     = "default"
   enable_dns_hostnames = true
 
-  tags {
-    Name = "${var.env_id}-vpc"
-  }
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-vpc"))}"
 }
 
 resource "aws_internet_gateway" "ig" {
   vpc_id = "${aws_vpc.vpc.id}"
+
+  tags = "${var.tags}"
 }
 
 output "vpc_id" {
   value = "${aws_vpc.vpc.id}"
 }
 
+variable "bosh_iam_user_name" {
+  type    = "string"
+  default = ""
+}
+
+variable "bosh_iam_user_policy_name" {
+  type    = "string"
+  default = ""
+}
+
+resource "aws_iam_user" "bosh" {
+  name = "${coalesce(var.bosh_iam_user_name, format("%s_bosh_user", var.env_id))}"
+}
+
+resource "aws_iam_user_policy" "bosh" {
+  name  = "${coalesce(var.bosh_iam_user_policy_name, format("%s_bosh_user_policy", var.env_id))}"
+  user = "${aws_iam_user.bosh.name}"
+
+  policy = <<EOF
+{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Action": [
+        "ec2:AssociateAddress",
+        "ec2:AttachVolume",
+        "ec2:CreateVolume",
+        "ec2:DeleteSnapshot",
+        "ec2:DeleteVolume",
+        "ec2:DescribeAddresses",
+        "ec2:DescribeImages",
+        "ec2:DescribeInstances",
+        "ec2:DescribeRegions",
+        "ec2:DescribeSecurityGroups",
+        "ec2:DescribeSnapshots",
+        "ec2:DescribeSubnets",
+        "ec2:DescribeVolumes",
+        "ec2:DetachVolume",
+        "ec2:CreateSnapshot",
+        "ec2:CreateTags",
+        "ec2:RunInstances",
+        "ec2:TerminateInstances",
+        "ec2:RegisterImage",
+        "ec2:DeregisterImage"
+      ],
+      "Effect": "Allow",
+      "Resource": "*"
+    },
+    {
+      "Action": [
+        "elasticloadbalancing:*"
+      ],
+      "Effect": "Allow",
+      "Resource": "*"
+    }
+  ]
+}
+EOF
+}
+
+resource "aws_iam_access_key" "bosh" {
+  user = "${aws_iam_user.bosh.name}"
+}
+
+output "bosh_user_access_key" {
+  value = "${aws_iam_access_key.bosh.id}"
+}
+
+output "bosh_user_secret_access_key" {
+  value = "${aws_iam_access_key.bosh.secret}"
+}
+
+variable "nat_ami_map" {
+  type = "map"
+
+  default = {
+    us-east-1      ="ami-68115b02"
+    us-west-1      ="ami-ef1a718f"
+    us-west-2      ="ami-77a4b816"
+    eu-west-1      ="ami-c0993ab3"
+    eu-central-1   ="ami-0b322e67"
+    ap-southeast-1 ="ami-e2fc3f81"
+    ap-southeast-2 ="ami-e3217a80"
+    ap-northeast-1 ="ami-f885ae96"
+    ap-northeast-2 ="ami-4118d72f"
+    sa-east-1      ="ami-8631b5ea"
+  }
+}
+
+resource "aws_security_group" "nat_security_group" {
+  name        = "nat_security_group"
+  description = "NAT"
+  vpc_id      = "${aws_vpc.vpc.id}"
+
+  ingress {
+    protocol    = "tcp"
+    from_port   = 0
+    to_port     = 65535
+    security_groups = ["${aws_security_group.internal_security_group.id}"]
+  }
+
+  ingress {
+    protocol    = "udp"
+    from_port   = 0
+    to_port     = 65535
+    security_groups = ["${aws_security_group.internal_security_group.id}"]
+  }
+
+  ingress {
+    protocol    = "icmp"
+    from_port   = -1
+    to_port     = -1
+    security_groups = ["${aws_security_group.internal_security_group.id}"]
+  }
+
+  egress {
+    from_port = 0
+    to_port = 0
+    protocol = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-nat-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
+  }
+}
+
+variable "nat_ssh_key_pair_name" {}
+
+resource "aws_instance" "nat" {
+  private_ip             = "10.0.0.7"
+  instance_type          = "t2.medium"
+  subnet_id              = "${aws_subnet.bosh_subnet.id}"
+  source_dest_check      = false
+  ami                    = "${lookup(var.nat_ami_map, var.region)}"
+  key_name               = "${var.nat_ssh_key_pair_name}"
+  vpc_security_group_ids = ["${aws_security_group.nat_security_group.id}"]
+
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-nat"))}"
+}
+
+resource "aws_eip" "nat_eip" {
+  depends_on = ["aws_internet_gateway.ig"]
+  instance = "${aws_instance.nat.id}"
+  vpc      = true
+
+  tags = "${var.tags}"
+}
+
+output "nat_eip" {
+  value = "${aws_eip.nat_eip.public_ip}"
+}
+
+resource "aws_route_table" "internal_route_table" {
+  vpc_id = "${aws_vpc.vpc.id}"
+
+  route {
+    cidr_block = "0.0.0.0/0"
+    instance_id = "${aws_instance.nat.id}"
+  }
+
+  tags = "${var.tags}"
+}
+
+resource "aws_route_table_association" "route_internal_subnets" {
+  count          = "${length(var.availability_zones)}"
+  subnet_id      = "${element(aws_subnet.internal_subnets.*.id, count.index)}"
+  route_table_id = "${aws_route_table.internal_route_table.id}"
+}
+
+variable "lb_inbound_cidrs" {
+  type    = "list"
+  default = ["0.0.0.0/0"]
+}
+
 resource "aws_subnet" "lb_subnets" {
   count             = "${length(var.availability_zones)}"
   vpc_id            = "${aws_vpc.vpc.id}"
   cidr_block        = "${cidrsubnet("10.0.0.0/20", 4, count.index+2)}"
   availability_zone = "${element(var.availability_zones, count.index)}"
 
-  tags {
-    Name = "${var.env_id}-lb-subnet${count.index}"
-  }
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-lb-subnet${count.index}"))}"
 }
 
 resource "aws_route_table" "lb_route_table" {
@@ -444,6 +478,8 @@ resource "aws_route_table" "lb_route_table" {
     cidr_block = "0.0.0.0/0"
     gateway_id = "${aws_internet_gateway.ig.id}"
   }
+
+  tags = "${var.tags}"
 }
 
 resource "aws_route_table_association" "route_lb_subnets" {
@@ -464,37 +500,42 @@ output "lb_subnet_cidrs" {
   value = ["${aws_subnet.lb_subnets.*.cidr_block}"]
 }
 
-variable "ssl_certificate" {
+variable "cf_ssl_certificate" {
   type = "string"
 }
 
-variable "ssl_certificate_chain" {
+variable "cf_ssl_certificate_chain" {
   type = "string"
 }
 
-variable "ssl_certificate_private_key" {
+variable "cf_ssl_certificate_private_key" {
   type = "string"
 }
 
-resource "aws_iam_server_certificate" "lb_cert" {
+resource "aws_iam_server_certificate" "cf_lb_cert" {
   name_prefix       = "${var.short_env_id}-"
 
-  certificate_body  = "${var.ssl_certificate}"
-  certificate_chain = "${var.ssl_certificate_chain}"
-  private_key       = "${var.ssl_certificate_private_key}"
+  certificate_body  = "${var.cf_ssl_certificate}"
+  certificate_chain = "${var.cf_ssl_certificate_chain}"
+  private_key       = "${var.cf_ssl_certificate_private_key}"
 
   lifecycle {
     create_before_destroy = true
+    ignore_changes        = ["name_prefix", "private_key"]
   }
 }
 
+output "cf_lb_certificate_name" {
+  value = "${aws_iam_server_certificate.cf_lb_cert.name}"
+}
+
 resource "aws_security_group" "cf_ssh_lb_security_group" {
   name = "cf_ssh_lb_security_group"
   description = "CF SSH"
   vpc_id      = "${aws_vpc.vpc.id}"
 
   ingress {
-    cidr_blocks = ["0.0.0.0/0"]
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
     protocol    = "tcp"
     from_port   = 2222
     to_port     = 2222
@@ -507,8 +548,10 @@ resource "aws_security_group" "cf_ssh_lb_security_group" {
     cidr_blocks = ["0.0.0.0/0"]
   }
 
-  tags {
-    Name = "${var.env_id}-cf-ssh-lb-security-group"
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-cf-ssh-lb-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
   }
 }
 
@@ -535,8 +578,10 @@ resource "aws_security_group" "cf_ssh_lb_internal_security_group" {
     cidr_blocks = ["0.0.0.0/0"]
   }
 
-  tags {
-    Name = "${var.env_id}-cf-ssh-lb-internal-security-group"
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-cf-ssh-lb-internal-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
   }
 }
 
@@ -565,6 +610,12 @@ resource "aws_elb" "cf_ssh_lb" {
 
   security_groups = ["${aws_security_group.cf_ssh_lb_security_group.id}"]
   subnets         = ["${aws_subnet.lb_subnets.*.id}"]
+
+  tags = "${var.tags}"
+
+  lifecycle {
+    ignore_changes = ["name"]
+  }
 }
 
 output "cf_ssh_lb_name" {
@@ -581,21 +632,21 @@ resource "aws_security_group" "cf_router_lb_security_group" {
   vpc_id      = "${aws_vpc.vpc.id}"
 
   ingress {
-    cidr_blocks = ["0.0.0.0/0"]
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
     protocol    = "tcp"
     from_port   = 80
     to_port     = 80
   }
 
   ingress {
-    cidr_blocks = ["0.0.0.0/0"]
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
     protocol    = "tcp"
     from_port   = 443
     to_port     = 443
   }
 
   ingress {
-    cidr_blocks = ["0.0.0.0/0"]
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
     protocol    = "tcp"
     from_port   = 4443
     to_port     = 4443
@@ -608,8 +659,10 @@ resource "aws_security_group" "cf_router_lb_security_group" {
     cidr_blocks = ["0.0.0.0/0"]
   }
 
-  tags {
-    Name = "${var.env_id}-cf-router-lb-security-group"
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-cf-router-lb-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
   }
 }
 
@@ -636,8 +689,10 @@ resource "aws_security_group" "cf_router_lb_internal_security_group" {
     cidr_blocks = ["0.0.0.0/0"]
   }
 
-  tags {
-    Name = "${var.env_id}-cf-router-lb-internal-security-group"
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-cf-router-lb-internal-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
   }
 }
 
@@ -669,7 +724,7 @@ resource "aws_elb" "cf_router_lb" {
     instance_protocol  = "http"
     lb_port            = 443
     lb_protocol        = "https"
-    ssl_certificate_id = "${aws_iam_server_certificate.lb_cert.arn}"
+    ssl_certificate_id = "${aws_iam_server_certificate.cf_lb_cert.arn}"
   }
 
   listener {
@@ -677,11 +732,17 @@ resource "aws_elb" "cf_router_lb" {
     instance_protocol  = "tcp"
     lb_port            = 4443
     lb_protocol        = "ssl"
-    ssl_certificate_id = "${aws_iam_server_certificate.lb_cert.arn}"
+    ssl_certificate_id = "${aws_iam_server_certificate.cf_lb_cert.arn}"
   }
 
   security_groups = ["${aws_security_group.cf_router_lb_security_group.id}"]
   subnets         = ["${aws_subnet.lb_subnets.*.id}"]
+
+  tags = "${var.tags}"
+
+  lifecycle {
+    ignore_changes = ["name"]
+  }
 }
 
 output "cf_router_lb_name" {
@@ -698,7 +759,7 @@ resource "aws_security_group" "cf_tcp_lb_security_group" {
   vpc_id      = "${aws_vpc.vpc.id}"
 
   ingress {
-    cidr_blocks = ["0.0.0.0/0"]
+    cidr_blocks = ["${var.lb_inbound_cidrs}"]
     protocol    = "tcp"
     from_port   = 1024
     to_port     = 1123
@@ -711,8 +772,10 @@ resource "aws_security_group" "cf_tcp_lb_security_group" {
     cidr_blocks = ["0.0.0.0/0"]
   }
 
-  tags {
-    Name = "${var.env_id}-cf-tcp-lb-security-group"
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-cf-tcp-lb-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
   }
 }
 
@@ -739,8 +802,10 @@ resource "aws_security_group" "cf_tcp_lb_internal_security_group" {
     cidr_blocks = ["0.0.0.0/0"]
   }
 
-  tags {
-    Name = "${var.env_id}-cf-tcp-lb-internal-security-group"
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-cf-tcp-lb-internal-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name", "description"]
   }
 }
 
@@ -1462,6 +1527,12 @@ resource "aws_elb" "cf_tcp_lb" {
 
   security_groups = ["${aws_security_group.cf_tcp_lb_security_group.id}"]
   subnets         = ["${aws_subnet.lb_subnets.*.id}"]
+
+  tags = "${var.tags}"
+
+  lifecycle {
+    ignore_changes = ["name"]
+  }
 }
 
 output "cf_tcp_lb_name" {
@@ -1479,9 +1550,7 @@ variable "system_domain" {
 resource "aws_route53_zone" "env_dns_zone" {
   name = "${var.system_domain}"
 
-  tags {
-    Name = "${var.env_id}-hosted-zone"
-  }
+  tags = "${merge(var.tags, map("Name", "${var.env_id}-hosted-zone"))}"
 }
 
 output "env_dns_zone_name_servers" {
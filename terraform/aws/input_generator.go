@@ -4,30 +4,46 @@ import (
 	"crypto/sha1"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/cloudfoundry/bosh-bootloader/aws"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
 type InputGenerator struct {
 	availabilityZoneRetriever availabilityZoneRetriever
+	credentialsProvider       credentialsProvider
 }
 
 type availabilityZoneRetriever interface {
 	Retrieve(string) ([]string, error)
 }
 
+type credentialsProvider interface {
+	GetCredentials() (aws.Credentials, error)
+}
+
 const terraformNameCharLimit = 18
 
+// cloudFormationIAMUserPolicyName and cloudFormationTagKey are the name of
+// the policy and the tag the cloudformation stacks of bbl gave to resources.
+const (
+	cloudFormationIAMUserPolicyName = "aws-cpi"
+	cloudFormationTagKey            = "bbl-env-id"
+)
+
 var jsonMarshal = json.Marshal
 
-func NewInputGenerator(availabilityZoneRetriever availabilityZoneRetriever) InputGenerator {
+func NewInputGenerator(availabilityZoneRetriever availabilityZoneRetriever, credentialsProvider credentialsProvider) InputGenerator {
 	return InputGenerator{
 		availabilityZoneRetriever: availabilityZoneRetriever,
+		credentialsProvider:       credentialsProvider,
 	}
 }
 
 func (i InputGenerator) Generate(state storage.State) (map[string]string, error) {
-	azs, err := i.availabilityZoneRetriever.Retrieve(state.AWS.Region)
+	azs, err := state.AWS.AvailabilityZones(i.availabilityZoneRetriever)
 	if err != nil {
 		return map[string]string{}, err
 	}
@@ -37,6 +53,13 @@ func (i InputGenerator) Generate(state storage.State) (map[string]string, error)
 		return map[string]string{}, err
 	}
 
+	// Profiles and roles are resolved by bbl, so terraform is only ever
+	// given the resulting, possibly temporary, access keys.
+	credentials, err := i.credentialsProvider.GetCredentials()
+	if err != nil {
+		return map[string]string{}, err
+	}
+
 	shortEnvID := state.EnvID
 	if len(shortEnvID) > terraformNameCharLimit {
 		sha1 := fmt.Sprintf("%x", sha1.Sum([]byte(state.EnvID)))
@@ -46,23 +69,88 @@ func (i InputGenerator) Generate(state storage.State) (map[string]string, error)
 	inputs := map[string]string{
 		"env_id":                 state.EnvID,
 		"short_env_id":           shortEnvID,
-		"nat_ssh_key_pair_name":  state.KeyPair.Name,
-		"access_key":             state.AWS.AccessKeyID,
-		"secret_key":             state.AWS.SecretAccessKey,
+		"access_key":             credentials.AccessKeyID,
+		"secret_key":             credentials.SecretAccessKey,
 		"region":                 state.AWS.Region,
 		"bosh_availability_zone": state.Stack.BOSHAZ,
 		"availability_zones":     string(azsString),
 	}
 
-	if state.LB.Type == "cf" || state.LB.Type == "concourse" {
-		inputs["ssl_certificate"] = state.LB.Cert
-		inputs["ssl_certificate_private_key"] = state.LB.Key
-		inputs["ssl_certificate_chain"] = state.LB.Chain
+	if state.AWS.NATType != "gateway" {
+		inputs["nat_ssh_key_pair_name"] = state.KeyPair.Name
+	}
+
+	if credentials.SessionToken != "" {
+		inputs["session_token"] = credentials.SessionToken
+	}
+
+	tags := state.Tags
+	if state.AWS.MigratedFromCloudFormation {
+		tags = map[string]string{cloudFormationTagKey: state.EnvID}
+		for key, value := range state.Tags {
+			tags[key] = value
+		}
+
+		if !state.AWS.IAMInstanceProfile {
+			inputs["bosh_iam_user_name"] = state.AWS.IAMUserName
+			inputs["bosh_iam_user_policy_name"] = cloudFormationIAMUserPolicyName
+		}
+	}
+
+	if len(tags) > 0 {
+		inputs["tags"] = hclMap(tags)
+	}
 
-		if state.LB.Domain != "" {
-			inputs["system_domain"] = state.LB.Domain
+	if len(state.DirectorAllowedCIDRs) > 0 {
+		boshInboundCIDRs, err := jsonMarshal(state.DirectorAllowedCIDRs)
+		if err != nil {
+			return map[string]string{}, err
+		}
+		inputs["bosh_inbound_cidrs"] = string(boshInboundCIDRs)
+	}
+
+	if len(state.LBs) > 0 && len(state.LBAllowedCIDRs) > 0 {
+		lbInboundCIDRs, err := jsonMarshal(state.LBAllowedCIDRs)
+		if err != nil {
+			return map[string]string{}, err
+		}
+		inputs["lb_inbound_cidrs"] = string(lbInboundCIDRs)
+	}
+
+	for _, lb := range state.LBs {
+		if lb.Type != "cf" && lb.Type != "concourse" && !(lb.IsCustom() && lb.Spec.TLS) {
+			continue
+		}
+
+		inputs[fmt.Sprintf("%s_ssl_certificate", lb.Type)] = lb.Cert
+		inputs[fmt.Sprintf("%s_ssl_certificate_private_key", lb.Type)] = lb.Key
+		inputs[fmt.Sprintf("%s_ssl_certificate_chain", lb.Type)] = lb.Chain
+
+		if lb.Type == "cf" && lb.Domain != "" {
+			inputs["system_domain"] = lb.Domain
+		}
+
+		if lb.Type == "concourse" && lb.Domain != "" {
+			inputs["concourse_domain"] = lb.Domain
 		}
 	}
 
 	return inputs, nil
 }
+
+// hclMap formats a map as an hcl literal so that it can be passed to
+// terraform with -var.
+func hclMap(values map[string]string) string {
+	keys := []string{}
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := []string{}
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%q=%q", key, values[key]))
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(pairs, ", "))
+}
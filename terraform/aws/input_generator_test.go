@@ -3,6 +3,7 @@ package aws_test
 import (
 	"errors"
 
+	bblaws "github.com/cloudfoundry/bosh-bootloader/aws"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 	"github.com/cloudfoundry/bosh-bootloader/terraform/aws"
@@ -14,6 +15,7 @@ import (
 var _ = Describe("InputGenerator", func() {
 	var (
 		availabilityZoneRetriever *fakes.AvailabilityZoneRetriever
+		credentialsProvider       *fakes.AWSClientProvider
 
 		inputGenerator aws.InputGenerator
 	)
@@ -22,7 +24,13 @@ var _ = Describe("InputGenerator", func() {
 		availabilityZoneRetriever = &fakes.AvailabilityZoneRetriever{}
 		availabilityZoneRetriever.RetrieveCall.Returns.AZs = []string{"z1", "z2", "z3"}
 
-		inputGenerator = aws.NewInputGenerator(availabilityZoneRetriever)
+		credentialsProvider = &fakes.AWSClientProvider{}
+		credentialsProvider.GetCredentialsCall.Returns.Credentials = bblaws.Credentials{
+			AccessKeyID:     "some-access-key-id",
+			SecretAccessKey: "some-secret-access-key",
+		}
+
+		inputGenerator = aws.NewInputGenerator(availabilityZoneRetriever, credentialsProvider)
 	})
 
 	Context("when env-id is greater than 18 characters", func() {
@@ -42,10 +50,6 @@ var _ = Describe("InputGenerator", func() {
 				Stack: storage.Stack{
 					BOSHAZ: "some-zone",
 				},
-				LB: storage.LB{
-					Type:   "",
-					Domain: "",
-				},
 			})
 			Expect(err).NotTo(HaveOccurred())
 
@@ -73,10 +77,6 @@ var _ = Describe("InputGenerator", func() {
 				Stack: storage.Stack{
 					BOSHAZ: "some-zone",
 				},
-				LB: storage.LB{
-					Type:   "",
-					Domain: "",
-				},
 			})
 			Expect(err).NotTo(HaveOccurred())
 
@@ -116,11 +116,13 @@ var _ = Describe("InputGenerator", func() {
 				Stack: storage.Stack{
 					BOSHAZ: "some-zone",
 				},
-				LB: storage.LB{
-					Type:  "cf",
-					Cert:  "some-cert",
-					Chain: "some-chain",
-					Key:   "some-key",
+				LBs: []storage.LB{
+					{
+						Type:  "cf",
+						Cert:  "some-cert",
+						Chain: "some-chain",
+						Key:   "some-key",
+					},
 				},
 			}
 		})
@@ -132,23 +134,23 @@ var _ = Describe("InputGenerator", func() {
 			Expect(availabilityZoneRetriever.RetrieveCall.Receives.Region).To(Equal("some-region"))
 
 			Expect(inputs).To(Equal(map[string]string{
-				"env_id":                      "some-env-id",
-				"short_env_id":                "some-env-id",
-				"nat_ssh_key_pair_name":       "some-key-pair-name",
-				"access_key":                  "some-access-key-id",
-				"secret_key":                  "some-secret-access-key",
-				"region":                      "some-region",
-				"bosh_availability_zone":      "some-zone",
-				"availability_zones":          `["z1","z2","z3"]`,
-				"ssl_certificate":             "some-cert",
-				"ssl_certificate_chain":       "some-chain",
-				"ssl_certificate_private_key": "some-key",
+				"env_id":                         "some-env-id",
+				"short_env_id":                   "some-env-id",
+				"nat_ssh_key_pair_name":          "some-key-pair-name",
+				"access_key":                     "some-access-key-id",
+				"secret_key":                     "some-secret-access-key",
+				"region":                         "some-region",
+				"bosh_availability_zone":         "some-zone",
+				"availability_zones":             `["z1","z2","z3"]`,
+				"cf_ssl_certificate":             "some-cert",
+				"cf_ssl_certificate_chain":       "some-chain",
+				"cf_ssl_certificate_private_key": "some-key",
 			}))
 		})
 
 		Context("when a domain name is supplied", func() {
 			BeforeEach(func() {
-				state.LB.Domain = "some-domain"
+				state.LBs[0].Domain = "some-domain"
 			})
 
 			It("returns a map with additional domain input", func() {
@@ -158,18 +160,18 @@ var _ = Describe("InputGenerator", func() {
 				Expect(availabilityZoneRetriever.RetrieveCall.Receives.Region).To(Equal("some-region"))
 
 				Expect(inputs).To(Equal(map[string]string{
-					"env_id":                      "some-env-id",
-					"short_env_id":                "some-env-id",
-					"nat_ssh_key_pair_name":       "some-key-pair-name",
-					"access_key":                  "some-access-key-id",
-					"secret_key":                  "some-secret-access-key",
-					"region":                      "some-region",
-					"bosh_availability_zone":      "some-zone",
-					"availability_zones":          `["z1","z2","z3"]`,
-					"ssl_certificate":             "some-cert",
-					"ssl_certificate_chain":       "some-chain",
-					"ssl_certificate_private_key": "some-key",
-					"system_domain":               "some-domain",
+					"env_id":                         "some-env-id",
+					"short_env_id":                   "some-env-id",
+					"nat_ssh_key_pair_name":          "some-key-pair-name",
+					"access_key":                     "some-access-key-id",
+					"secret_key":                     "some-secret-access-key",
+					"region":                         "some-region",
+					"bosh_availability_zone":         "some-zone",
+					"availability_zones":             `["z1","z2","z3"]`,
+					"cf_ssl_certificate":             "some-cert",
+					"cf_ssl_certificate_chain":       "some-chain",
+					"cf_ssl_certificate_private_key": "some-key",
+					"system_domain":                  "some-domain",
 				}))
 			})
 		})
@@ -196,11 +198,13 @@ var _ = Describe("InputGenerator", func() {
 				Stack: storage.Stack{
 					BOSHAZ: "some-zone",
 				},
-				LB: storage.LB{
-					Type:  "concourse",
-					Cert:  "some-cert",
-					Chain: "some-chain",
-					Key:   "some-key",
+				LBs: []storage.LB{
+					{
+						Type:  "concourse",
+						Cert:  "some-cert",
+						Chain: "some-chain",
+						Key:   "some-key",
+					},
 				},
 			}
 		})
@@ -212,21 +216,240 @@ var _ = Describe("InputGenerator", func() {
 			Expect(availabilityZoneRetriever.RetrieveCall.Receives.Region).To(Equal("some-region"))
 
 			Expect(inputs).To(Equal(map[string]string{
-				"env_id":                      "some-env-id",
-				"short_env_id":                "some-env-id",
-				"nat_ssh_key_pair_name":       "some-key-pair-name",
-				"access_key":                  "some-access-key-id",
-				"secret_key":                  "some-secret-access-key",
-				"region":                      "some-region",
-				"bosh_availability_zone":      "some-zone",
-				"availability_zones":          `["z1","z2","z3"]`,
-				"ssl_certificate":             "some-cert",
-				"ssl_certificate_chain":       "some-chain",
-				"ssl_certificate_private_key": "some-key",
+				"env_id":                                "some-env-id",
+				"short_env_id":                          "some-env-id",
+				"nat_ssh_key_pair_name":                 "some-key-pair-name",
+				"access_key":                            "some-access-key-id",
+				"secret_key":                            "some-secret-access-key",
+				"region":                                "some-region",
+				"bosh_availability_zone":                "some-zone",
+				"availability_zones":                    `["z1","z2","z3"]`,
+				"concourse_ssl_certificate":             "some-cert",
+				"concourse_ssl_certificate_chain":       "some-chain",
+				"concourse_ssl_certificate_private_key": "some-key",
 			}))
 		})
 	})
 
+	Context("when cf and concourse lbs exist", func() {
+		It("returns a map with the certificate inputs of each load balancer", func() {
+			inputs, err := inputGenerator.Generate(storage.State{
+				EnvID: "some-env-id",
+				LBs: []storage.LB{
+					{
+						Type:   "cf",
+						Cert:   "some-cf-cert",
+						Key:    "some-cf-key",
+						Domain: "some-domain",
+					},
+					{
+						Type:  "concourse",
+						Cert:  "some-concourse-cert",
+						Chain: "some-concourse-chain",
+						Key:   "some-concourse-key",
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(inputs).To(HaveKeyWithValue("cf_ssl_certificate", "some-cf-cert"))
+			Expect(inputs).To(HaveKeyWithValue("cf_ssl_certificate_private_key", "some-cf-key"))
+			Expect(inputs).To(HaveKeyWithValue("cf_ssl_certificate_chain", ""))
+			Expect(inputs).To(HaveKeyWithValue("system_domain", "some-domain"))
+			Expect(inputs).To(HaveKeyWithValue("concourse_ssl_certificate", "some-concourse-cert"))
+			Expect(inputs).To(HaveKeyWithValue("concourse_ssl_certificate_private_key", "some-concourse-key"))
+			Expect(inputs).To(HaveKeyWithValue("concourse_ssl_certificate_chain", "some-concourse-chain"))
+			Expect(inputs).NotTo(HaveKey("concourse_domain"))
+		})
+
+		It("returns the concourse domain when it is supplied", func() {
+			inputs, err := inputGenerator.Generate(storage.State{
+				EnvID: "some-env-id",
+				LBs: []storage.LB{
+					{Type: "cf", Domain: "some-domain"},
+					{Type: "concourse", Domain: "ci.example.com"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(inputs).To(HaveKeyWithValue("system_domain", "some-domain"))
+			Expect(inputs).To(HaveKeyWithValue("concourse_domain", "ci.example.com"))
+		})
+	})
+
+	Context("when custom lbs exist", func() {
+		It("returns the certificate inputs only for custom lbs with tls", func() {
+			inputs, err := inputGenerator.Generate(storage.State{
+				EnvID: "some-env-id",
+				LBs: []storage.LB{
+					{
+						Type: "vault",
+						Cert: "some-vault-cert",
+						Key:  "some-vault-key",
+						Spec: &storage.CustomLBSpec{
+							Name: "vault",
+							TLS:  true,
+						},
+					},
+					{
+						Type: "kafka",
+						Spec: &storage.CustomLBSpec{
+							Name: "kafka",
+						},
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(inputs).To(HaveKeyWithValue("vault_ssl_certificate", "some-vault-cert"))
+			Expect(inputs).To(HaveKeyWithValue("vault_ssl_certificate_private_key", "some-vault-key"))
+			Expect(inputs).To(HaveKeyWithValue("vault_ssl_certificate_chain", ""))
+			Expect(inputs).NotTo(HaveKey("kafka_ssl_certificate"))
+		})
+	})
+
+	Context("when the state has availability zones", func() {
+		It("uses them instead of every zone in the region", func() {
+			inputs, err := inputGenerator.Generate(storage.State{
+				AWS: storage.AWS{
+					Region: "some-region",
+					AZs:    []string{"z2", "z3"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(availabilityZoneRetriever.RetrieveCall.Receives.Region).To(BeEmpty())
+			Expect(inputs["availability_zones"]).To(Equal(`["z2","z3"]`))
+		})
+	})
+
+	Context("when the nat type is gateway", func() {
+		It("does not pass the nat ssh key pair name", func() {
+			inputs, err := inputGenerator.Generate(storage.State{
+				AWS: storage.AWS{
+					Region:  "some-region",
+					NATType: "gateway",
+				},
+				KeyPair: storage.KeyPair{
+					Name: "some-key-pair-name",
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(inputs).NotTo(HaveKey("nat_ssh_key_pair_name"))
+		})
+	})
+
+	Context("when the state has a profile and a role", func() {
+		It("passes the temporary credentials they resolve to instead", func() {
+			credentialsProvider.GetCredentialsCall.Returns.Credentials = bblaws.Credentials{
+				AccessKeyID:     "some-temporary-access-key-id",
+				SecretAccessKey: "some-temporary-secret-access-key",
+				SessionToken:    "some-session-token",
+			}
+
+			inputs, err := inputGenerator.Generate(storage.State{
+				AWS: storage.AWS{
+					Profile:       "some-profile",
+					AssumeRoleARN: "some-role-arn",
+					Region:        "some-region",
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(credentialsProvider.GetCredentialsCall.CallCount).To(Equal(1))
+			Expect(inputs["access_key"]).To(Equal("some-temporary-access-key-id"))
+			Expect(inputs["secret_key"]).To(Equal("some-temporary-secret-access-key"))
+			Expect(inputs["session_token"]).To(Equal("some-session-token"))
+			Expect(inputs).NotTo(HaveKey("profile"))
+			Expect(inputs).NotTo(HaveKey("assume_role_arn"))
+		})
+	})
+
+	Context("when the state has tags", func() {
+		It("passes them as an hcl map in key order", func() {
+			inputs, err := inputGenerator.Generate(storage.State{
+				AWS: storage.AWS{Region: "some-region"},
+				Tags: map[string]string{
+					"owner":       "some-owner",
+					"cost-center": "CC-123",
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(inputs["tags"]).To(Equal(`{"cost-center"="CC-123", "owner"="some-owner"}`))
+		})
+	})
+
+	Context("when the environment was migrated from cloudformation", func() {
+		It("keeps the iam user and the tags of the stack", func() {
+			inputs, err := inputGenerator.Generate(storage.State{
+				EnvID: "some-env-id",
+				AWS: storage.AWS{
+					Region:                     "some-region",
+					MigratedFromCloudFormation: true,
+					IAMUserName:                "bosh-iam-user-some-env-id",
+				},
+				Tags: map[string]string{
+					"owner": "some-owner",
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(inputs["bosh_iam_user_name"]).To(Equal("bosh-iam-user-some-env-id"))
+			Expect(inputs["bosh_iam_user_policy_name"]).To(Equal("aws-cpi"))
+			Expect(inputs["tags"]).To(Equal(`{"bbl-env-id"="some-env-id", "owner"="some-owner"}`))
+		})
+
+		It("does not name an iam user when the director uses an instance profile", func() {
+			inputs, err := inputGenerator.Generate(storage.State{
+				EnvID: "some-env-id",
+				AWS: storage.AWS{
+					Region:                     "some-region",
+					MigratedFromCloudFormation: true,
+					IAMInstanceProfile:         true,
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(inputs).NotTo(HaveKey("bosh_iam_user_name"))
+			Expect(inputs).NotTo(HaveKey("bosh_iam_user_policy_name"))
+			Expect(inputs["tags"]).To(Equal(`{"bbl-env-id"="some-env-id"}`))
+		})
+	})
+
+	Context("when the state has allowed cidrs", func() {
+		It("restricts director ingress to the director allowed cidrs", func() {
+			inputs, err := inputGenerator.Generate(storage.State{
+				DirectorAllowedCIDRs: []string{"10.1.0.0/16", "192.168.0.1/32"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(inputs["bosh_inbound_cidrs"]).To(Equal(`["10.1.0.0/16","192.168.0.1/32"]`))
+			Expect(inputs).NotTo(HaveKey("lb_inbound_cidrs"))
+		})
+
+		It("restricts lb ingress to the lb allowed cidrs when lbs exist", func() {
+			inputs, err := inputGenerator.Generate(storage.State{
+				LBAllowedCIDRs: []string{"10.2.0.0/16"},
+				LBs:            []storage.LB{{Type: "concourse"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(inputs["lb_inbound_cidrs"]).To(Equal(`["10.2.0.0/16"]`))
+			Expect(inputs).NotTo(HaveKey("bosh_inbound_cidrs"))
+		})
+
+		It("does not set lb ingress when no lbs exist", func() {
+			inputs, err := inputGenerator.Generate(storage.State{
+				LBAllowedCIDRs: []string{"10.2.0.0/16"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(inputs).NotTo(HaveKey("lb_inbound_cidrs"))
+		})
+	})
+
 	Context("failure cases", func() {
 		Context("when the availability zone retriever fails", func() {
 			It("returns an error", func() {
@@ -237,6 +460,15 @@ var _ = Describe("InputGenerator", func() {
 			})
 		})
 
+		Context("when the credentials cannot be resolved", func() {
+			It("returns an error", func() {
+				credentialsProvider.GetCredentialsCall.Returns.Error = errors.New("failed to assume role")
+
+				_, err := inputGenerator.Generate(storage.State{})
+				Expect(err).To(MatchError("failed to assume role"))
+			})
+		})
+
 		Context("when the azs failed to marshal", func() {
 			BeforeEach(func() {
 				aws.SetJSONMarshal(func(interface{}) ([]byte, error) {
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"fmt"
+
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
@@ -31,6 +33,7 @@ func (o OutputGenerator) Generate(state storage.State) (map[string]interface{},
 		"bosh_url":                      "director_address",
 		"bosh_user_access_key":          "access_key_id",
 		"bosh_user_secret_access_key":   "secret_access_key",
+		"bosh_iam_instance_profile":     "iam_instance_profile",
 		"bosh_subnet_id":                "subnet_id",
 		"bosh_subnet_availability_zone": "az",
 		"bosh_security_group":           "default_security_groups",
@@ -40,27 +43,43 @@ func (o OutputGenerator) Generate(state storage.State) (map[string]interface{},
 		"vpc_id":                        "vpc_id",
 	}
 
-	switch state.LB.Type {
-	case "cf":
-		outputMapping["cf_router_lb_name"] = "cf_router_load_balancer"
-		outputMapping["cf_router_lb_url"] = "cf_router_load_balancer_url"
-		outputMapping["cf_router_lb_internal_security_group"] = "cf_router_internal_security_group"
-		outputMapping["cf_ssh_lb_name"] = "cf_ssh_proxy_load_balancer"
-		outputMapping["cf_ssh_lb_url"] = "cf_ssh_proxy_load_balancer_url"
-		outputMapping["cf_ssh_lb_internal_security_group"] = "cf_ssh_proxy_internal_security_group"
-
-		if state.LB.Domain != "" {
-			systemDomainDNSServersRaw := tfOutputs["env_dns_zone_name_servers"]
-			servers := []string{}
-			for _, server := range systemDomainDNSServersRaw.([]interface{}) {
-				servers = append(servers, server.(string))
+	for _, lb := range state.LBs {
+		outputMapping[fmt.Sprintf("%s_lb_certificate_name", lb.Type)] = fmt.Sprintf("%s_certificate_name", lb.Type)
+
+		switch lb.Type {
+		case "cf":
+			outputMapping["cf_router_lb_name"] = "cf_router_load_balancer"
+			outputMapping["cf_router_lb_url"] = "cf_router_load_balancer_url"
+			outputMapping["cf_router_lb_internal_security_group"] = "cf_router_internal_security_group"
+			outputMapping["cf_router_lb_target_group"] = "cf_router_target_group"
+			outputMapping["cf_ssh_lb_name"] = "cf_ssh_proxy_load_balancer"
+			outputMapping["cf_ssh_lb_url"] = "cf_ssh_proxy_load_balancer_url"
+			outputMapping["cf_ssh_lb_internal_security_group"] = "cf_ssh_proxy_internal_security_group"
+			outputMapping["cf_tcp_lb_name"] = "cf_tcp_router_load_balancer"
+			outputMapping["cf_tcp_lb_url"] = "cf_tcp_router_load_balancer_url"
+
+			if lb.Domain != "" {
+				outputs["cf_system_domain_dns_servers"] = nameServers(tfOutputs["env_dns_zone_name_servers"])
+			}
+		case "concourse":
+			outputMapping["concourse_lb_name"] = "concourse_load_balancer"
+			outputMapping["concourse_lb_url"] = "concourse_load_balancer_url"
+			outputMapping["concourse_lb_internal_security_group"] = "concourse_internal_security_group"
+			outputMapping["concourse_lb_target_group"] = "concourse_target_group"
+			outputMapping["concourse_lb_tsa_target_group"] = "concourse_tsa_target_group"
+			outputMapping["concourse_tsa_lb_name"] = "concourse_tsa_load_balancer"
+			outputMapping["concourse_tsa_lb_url"] = "concourse_tsa_load_balancer_url"
+
+			if lb.Domain != "" {
+				outputs["concourse_domain_dns_servers"] = nameServers(tfOutputs["concourse_dns_zone_name_servers"])
+			}
+		default:
+			if lb.IsCustom() {
+				outputMapping[fmt.Sprintf("%s_lb_name", lb.Type)] = fmt.Sprintf("%s_load_balancer", lb.Type)
+				outputMapping[fmt.Sprintf("%s_lb_url", lb.Type)] = fmt.Sprintf("%s_load_balancer_url", lb.Type)
+				outputMapping[fmt.Sprintf("%s_lb_internal_security_group", lb.Type)] = fmt.Sprintf("%s_internal_security_group", lb.Type)
 			}
-			outputs["cf_system_domain_dns_servers"] = servers
 		}
-	case "concourse":
-		outputMapping["concourse_lb_name"] = "concourse_load_balancer"
-		outputMapping["concourse_lb_url"] = "concourse_load_balancer_url"
-		outputMapping["concourse_lb_internal_security_group"] = "concourse_internal_security_group"
 	}
 
 	for tfKey, outputKey := range outputMapping {
@@ -71,3 +90,14 @@ func (o OutputGenerator) Generate(state storage.State) (map[string]interface{},
 
 	return outputs, nil
 }
+
+func nameServers(nameServersRaw interface{}) []string {
+	rawServers, _ := nameServersRaw.([]interface{})
+
+	servers := []string{}
+	for _, server := range rawServers {
+		servers = append(servers, server.(string))
+	}
+
+	return servers
+}
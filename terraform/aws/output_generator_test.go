@@ -48,6 +48,7 @@ var _ = Describe("OutputGenerator", func() {
 			"cf_router_lb_url":                     "some-cf-router-lb-url",
 			"cf_tcp_lb_name":                       "some-cf-tcp-lb-name",
 			"cf_tcp_lb_url":                        "some-cf-tcp-lb-url",
+			"cf_lb_certificate_name":               "some-cf-certificate-name",
 			"env_dns_zone_name_servers":            []interface{}{"some-name-server-1", "some-name-server-2"},
 			"nat_eip":                              "some-nat-eip",
 			"vpc_id":                               "some-vpc-id",
@@ -62,10 +63,6 @@ var _ = Describe("OutputGenerator", func() {
 				IAAS:    "aws",
 				EnvID:   "some-env-id",
 				TFState: "some-tf-state",
-				LB: storage.LB{
-					Type:   "",
-					Domain: "",
-				},
 			})
 			Expect(err).NotTo(HaveOccurred())
 
@@ -87,15 +84,36 @@ var _ = Describe("OutputGenerator", func() {
 		})
 	})
 
+	Context("when the director uses an instance profile", func() {
+		It("returns the instance profile instead of the bosh user keys", func() {
+			executor.OutputsCall.Returns.Outputs = map[string]interface{}{
+				"bosh_iam_instance_profile": "some-instance-profile",
+			}
+
+			outputs, err := outputGenerator.Generate(storage.State{
+				IAAS:    "aws",
+				TFState: "some-tf-state",
+				AWS:     storage.AWS{IAMInstanceProfile: true},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(outputs).To(Equal(map[string]interface{}{
+				"iam_instance_profile": "some-instance-profile",
+			}))
+		})
+	})
+
 	Context("when cf lbs exist", func() {
 		It("returns all terraform outputs including cf lb related outputs", func() {
 			outputs, err := outputGenerator.Generate(storage.State{
 				IAAS:    "aws",
 				EnvID:   "some-env-id",
 				TFState: "some-tf-state",
-				LB: storage.LB{
-					Type:   "cf",
-					Domain: "some-domain",
+				LBs: []storage.LB{
+					{
+						Type:   "cf",
+						Domain: "some-domain",
+					},
 				},
 			})
 			Expect(err).NotTo(HaveOccurred())
@@ -119,6 +137,9 @@ var _ = Describe("OutputGenerator", func() {
 				"cf_ssh_proxy_load_balancer":           "some-cf-ssh-proxy-lb",
 				"cf_ssh_proxy_load_balancer_url":       "some-cf-ssh-proxy-lb-url",
 				"cf_ssh_proxy_internal_security_group": "some-cf-ssh-proxy-internal-security-group",
+				"cf_tcp_router_load_balancer":          "some-cf-tcp-lb-name",
+				"cf_tcp_router_load_balancer_url":      "some-cf-tcp-lb-url",
+				"cf_certificate_name":                  "some-cf-certificate-name",
 				"cf_system_domain_dns_servers":         []string{"some-name-server-1", "some-name-server-2"},
 				"vpc_id":                               "some-vpc-id",
 			}))
@@ -131,9 +152,11 @@ var _ = Describe("OutputGenerator", func() {
 				IAAS:    "aws",
 				EnvID:   "some-env-id",
 				TFState: "some-tf-state",
-				LB: storage.LB{
-					Type:   "concourse",
-					Domain: "",
+				LBs: []storage.LB{
+					{
+						Type:   "concourse",
+						Domain: "",
+					},
 				},
 			})
 			Expect(err).NotTo(HaveOccurred())
@@ -154,7 +177,105 @@ var _ = Describe("OutputGenerator", func() {
 				"concourse_load_balancer":           "some-concourse-lb-name",
 				"concourse_load_balancer_url":       "some-concourse-lb-url",
 				"concourse_internal_security_group": "some-concourse-internal-security-group",
-				"vpc_id": "some-vpc-id",
+				"vpc_id":                            "some-vpc-id",
+			}))
+		})
+	})
+
+	Context("when the concourse lb has a domain", func() {
+		It("returns the name servers of the concourse zone", func() {
+			executor.OutputsCall.Returns.Outputs = map[string]interface{}{
+				"concourse_dns_zone_name_servers": []interface{}{"some-name-server-1", "some-name-server-2"},
+			}
+
+			outputs, err := outputGenerator.Generate(storage.State{
+				IAAS:    "aws",
+				TFState: "some-tf-state",
+				LBs: []storage.LB{
+					{Type: "concourse", Domain: "ci.example.com"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(outputs).To(Equal(map[string]interface{}{
+				"concourse_domain_dns_servers": []string{"some-name-server-1", "some-name-server-2"},
+			}))
+		})
+	})
+
+	Context("when the cf and concourse lbs exist", func() {
+		It("returns the terraform outputs of both lbs", func() {
+			outputs, err := outputGenerator.Generate(storage.State{
+				IAAS:    "aws",
+				TFState: "some-tf-state",
+				LBs: []storage.LB{
+					{Type: "cf"},
+					{Type: "concourse"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(outputs).To(HaveKeyWithValue("cf_router_load_balancer", "some-cf-router-lb"))
+			Expect(outputs).To(HaveKeyWithValue("cf_ssh_proxy_load_balancer", "some-cf-ssh-proxy-lb"))
+			Expect(outputs).To(HaveKeyWithValue("concourse_load_balancer", "some-concourse-lb-name"))
+			Expect(outputs).To(HaveKeyWithValue("concourse_internal_security_group", "some-concourse-internal-security-group"))
+		})
+	})
+
+	Context("when application and network lbs exist", func() {
+		It("returns the target groups of the lbs", func() {
+			executor.OutputsCall.Returns.Outputs = map[string]interface{}{
+				"cf_router_lb_target_group":     "some-cf-router-target-group",
+				"concourse_lb_target_group":     "some-concourse-target-group",
+				"concourse_lb_tsa_target_group": "some-concourse-tsa-target-group",
+				"concourse_tsa_lb_name":         "some-concourse-tsa-lb-name",
+				"concourse_tsa_lb_url":          "some-concourse-tsa-lb-url",
+			}
+
+			outputs, err := outputGenerator.Generate(storage.State{
+				IAAS:    "aws",
+				TFState: "some-tf-state",
+				LBs: []storage.LB{
+					{Type: "cf", Kind: "nlb"},
+					{Type: "concourse", Kind: "alb"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(outputs).To(Equal(map[string]interface{}{
+				"cf_router_target_group":          "some-cf-router-target-group",
+				"concourse_target_group":          "some-concourse-target-group",
+				"concourse_tsa_target_group":      "some-concourse-tsa-target-group",
+				"concourse_tsa_load_balancer":     "some-concourse-tsa-lb-name",
+				"concourse_tsa_load_balancer_url": "some-concourse-tsa-lb-url",
+			}))
+		})
+	})
+
+	Context("when a custom lb exists", func() {
+		It("returns the terraform outputs named after the custom lb", func() {
+			executor.OutputsCall.Returns.Outputs = map[string]interface{}{
+				"vault_lb_name":                    "some-vault-lb-name",
+				"vault_lb_url":                     "some-vault-lb-url",
+				"vault_lb_internal_security_group": "some-vault-internal-security-group",
+			}
+
+			outputs, err := outputGenerator.Generate(storage.State{
+				IAAS:    "aws",
+				TFState: "some-tf-state",
+				LBs: []storage.LB{
+					{
+						Type: "vault",
+						Spec: &storage.CustomLBSpec{Name: "vault"},
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(outputs).To(Equal(map[string]interface{}{
+				"vault_load_balancer":           "some-vault-lb-name",
+				"vault_load_balancer_url":       "some-vault-lb-url",
+				"vault_internal_security_group": "some-vault-internal-security-group",
 			}))
 		})
 	})
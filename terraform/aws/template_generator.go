@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/cloudfoundry/bosh-bootloader/storage"
@@ -14,16 +15,66 @@ func NewTemplateGenerator() TemplateGenerator {
 }
 
 func (t TemplateGenerator) Generate(state storage.State) string {
-	template := BaseTemplate
-
-	switch state.LB.Type {
-	case "concourse":
-		template = strings.Join([]string{template, LBSubnetTemplate, SSLCertificateTemplate, ConcourseLBTemplate}, "\n")
-	case "cf":
-		if state.LB.Domain != "" {
-			template = strings.Join([]string{template, LBSubnetTemplate, SSLCertificateTemplate, CFLBTemplate, CFDNSTemplate}, "\n")
-		} else {
-			template = strings.Join([]string{template, LBSubnetTemplate, SSLCertificateTemplate, CFLBTemplate}, "\n")
+	natTemplate := NATInstanceTemplate
+	if state.AWS.NATType == "gateway" {
+		natTemplate = NATGatewayTemplate
+	}
+
+	iamTemplate := IAMUserTemplate
+	if state.AWS.IAMInstanceProfile {
+		iamTemplate = IAMInstanceProfileTemplate
+	}
+
+	template := strings.Join([]string{BaseTemplate, iamTemplate, natTemplate}, "\n")
+
+	if len(state.LBs) > 0 {
+		template = strings.Join([]string{template, LBSubnetTemplate}, "\n")
+	}
+
+	for _, lb := range state.LBs {
+		switch lb.Type {
+		case "concourse":
+			concourseLBTemplate := ConcourseLBTemplate
+			concourseLBResource := "aws_elb"
+			switch lb.Kind {
+			case "alb":
+				concourseLBTemplate = ConcourseALBTemplate
+				concourseLBResource = "aws_lb"
+			case "nlb":
+				concourseLBTemplate = ConcourseNLBTemplate
+				concourseLBResource = "aws_lb"
+			}
+
+			template = strings.Join([]string{template, ConcourseSSLCertificateTemplate, concourseLBTemplate}, "\n")
+
+			if lb.Domain != "" {
+				template = strings.Join([]string{template, fmt.Sprintf(ConcourseDNSTemplate, concourseLBResource)}, "\n")
+
+				if lb.Kind == "alb" {
+					template = strings.Join([]string{template, ConcourseTSADNSTemplate}, "\n")
+				}
+			}
+		case "cf":
+			cfRouterLBTemplate := CFRouterLBTemplate
+			cfRouterLBResource := "aws_elb"
+			switch lb.Kind {
+			case "alb":
+				cfRouterLBTemplate = CFRouterALBTemplate
+				cfRouterLBResource = "aws_lb"
+			case "nlb":
+				cfRouterLBTemplate = CFRouterNLBTemplate
+				cfRouterLBResource = "aws_lb"
+			}
+
+			template = strings.Join([]string{template, CFSSLCertificateTemplate, CFSSHLBTemplate, cfRouterLBTemplate, CFTCPLBTemplate}, "\n")
+
+			if lb.Domain != "" {
+				template = strings.Join([]string{template, fmt.Sprintf(CFDNSTemplate, cfRouterLBResource)}, "\n")
+			}
+		default:
+			if lb.IsCustom() {
+				template = strings.Join([]string{template, CustomLBTemplate(*lb.Spec)}, "\n")
+			}
 		}
 	}
 
@@ -26,18 +26,95 @@ var _ = Describe("TemplateGenerator", func() {
 				expectedTemplate, err := ioutil.ReadFile(fixtureFilename)
 				Expect(err).NotTo(HaveOccurred())
 
-				template := templateGenerator.Generate(storage.State{
-					LB: storage.LB{
-						Type:   lbType,
-						Domain: domain,
-					},
-				})
+				state := storage.State{}
+				if lbType != "" {
+					state.LBs = []storage.LB{{Type: lbType, Domain: domain}}
+				}
+
+				template := templateGenerator.Generate(state)
 				Expect(template).To(Equal(string(expectedTemplate)))
 			},
 			Entry("when no lb type is provided", "fixtures/template_no_lb.tf", "", ""),
 			Entry("when a concourse lb type is provided", "fixtures/template_concourse_lb.tf", "concourse", ""),
 			Entry("when a cf lb type is provided", "fixtures/template_cf_lb.tf", "cf", ""),
 			Entry("when a cf lb type is provided with a system domain", "fixtures/template_cf_lb_with_domain.tf", "cf", "some-domain"),
+			Entry("when a concourse lb type is provided with a domain", "fixtures/template_concourse_lb_with_domain.tf", "concourse", "ci.example.com"),
+		)
+
+		DescribeTable("generates application and network lbs for the given lb kind",
+			func(fixtureFilename, lbType, kind string) {
+				expectedTemplate, err := ioutil.ReadFile(fixtureFilename)
+				Expect(err).NotTo(HaveOccurred())
+
+				template := templateGenerator.Generate(storage.State{
+					LBs: []storage.LB{{Type: lbType, Domain: "some-domain", Kind: kind}},
+				})
+				Expect(template).To(Equal(string(expectedTemplate)))
+			},
+			Entry("when a concourse alb is provided", "fixtures/template_concourse_alb_with_domain.tf", "concourse", "alb"),
+			Entry("when a concourse nlb is provided", "fixtures/template_concourse_nlb_with_domain.tf", "concourse", "nlb"),
+			Entry("when a cf alb is provided", "fixtures/template_cf_alb_with_domain.tf", "cf", "alb"),
+			Entry("when a cf nlb is provided", "fixtures/template_cf_nlb_with_domain.tf", "cf", "nlb"),
 		)
+
+		It("uses nat gateways when the nat type is gateway", func() {
+			expectedTemplate, err := ioutil.ReadFile("fixtures/template_no_lb_nat_gateway.tf")
+			Expect(err).NotTo(HaveOccurred())
+
+			template := templateGenerator.Generate(storage.State{
+				AWS: storage.AWS{NATType: "gateway"},
+			})
+			Expect(template).To(Equal(string(expectedTemplate)))
+		})
+
+		It("grants the policy to an instance profile instead of a user when requested", func() {
+			expectedTemplate, err := ioutil.ReadFile("fixtures/template_no_lb_iam_instance_profile.tf")
+			Expect(err).NotTo(HaveOccurred())
+
+			template := templateGenerator.Generate(storage.State{
+				AWS: storage.AWS{IAMInstanceProfile: true},
+			})
+			Expect(template).To(Equal(string(expectedTemplate)))
+		})
+
+		It("composes the templates of every attached lb", func() {
+			expectedTemplate, err := ioutil.ReadFile("fixtures/template_concourse_and_cf_lb.tf")
+			Expect(err).NotTo(HaveOccurred())
+
+			template := templateGenerator.Generate(storage.State{
+				LBs: []storage.LB{
+					{Type: "concourse"},
+					{Type: "cf", Domain: "some-domain"},
+				},
+			})
+			Expect(template).To(Equal(string(expectedTemplate)))
+		})
+
+		It("renders custom lbs from their spec", func() {
+			expectedTemplate, err := ioutil.ReadFile("fixtures/template_custom_lb.tf")
+			Expect(err).NotTo(HaveOccurred())
+
+			template := templateGenerator.Generate(storage.State{
+				LBs: []storage.LB{
+					{
+						Type: "vault",
+						Spec: &storage.CustomLBSpec{
+							Name: "vault",
+							Listeners: []storage.CustomLBListener{
+								{Protocol: "tcp", Port: 8200, TargetPort: 8200},
+								{Protocol: "http", Port: 80, TargetPort: 8080},
+							},
+							HealthCheck: storage.CustomLBHealthCheck{
+								Protocol: "http",
+								Port:     8200,
+								Path:     "/v1/sys/health",
+							},
+							TLS: true,
+						},
+					},
+				},
+			})
+			Expect(template).To(Equal(string(expectedTemplate)))
+		})
 	})
 })
@@ -8,9 +8,12 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"syscall"
 )
 
 var tempDir func(dir, prefix string) (string, error) = ioutil.TempDir
@@ -54,6 +57,11 @@ func (e Executor) Apply(input map[string]string, template, prevTFState string) (
 		}
 	}
 
+	err = e.initialize(tempDir)
+	if err != nil {
+		return "", NewExecutorError(filepath.Join(tempDir, "terraform.tfstate"), err, e.debug)
+	}
+
 	args := []string{"apply"}
 	for k, v := range input {
 		args = append(args, makeVar(k, v)...)
@@ -89,6 +97,11 @@ func (e Executor) Destroy(input map[string]string, template, prevTFState string)
 		}
 	}
 
+	err = e.initialize(tempDir)
+	if err != nil {
+		return "", NewExecutorError(filepath.Join(tempDir, "terraform.tfstate"), err, e.debug)
+	}
+
 	args := []string{"destroy", "-force"}
 	for k, v := range input {
 		args = append(args, makeVar(k, v)...)
@@ -106,6 +119,185 @@ func (e Executor) Destroy(input map[string]string, template, prevTFState string)
 	return string(tfState), nil
 }
 
+// Import adopts existing infrastructure into the terraform state. The
+// resources map a terraform resource address to the id of the resource.
+func (e Executor) Import(input map[string]string, template, prevTFState string, resources map[string]string) (string, error) {
+	tempDir, err := tempDir("", "")
+	if err != nil {
+		return "", err
+	}
+
+	err = writeFile(filepath.Join(tempDir, "template.tf"), []byte(template), os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+
+	if prevTFState != "" {
+		err = writeFile(filepath.Join(tempDir, "terraform.tfstate"), []byte(prevTFState), os.ModePerm)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = e.initialize(tempDir)
+	if err != nil {
+		return "", NewExecutorError(filepath.Join(tempDir, "terraform.tfstate"), err, e.debug)
+	}
+
+	var addresses []string
+	for address := range resources {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	for _, address := range addresses {
+		args := []string{"import"}
+		for k, v := range input {
+			args = append(args, makeVar(k, v)...)
+		}
+		args = append(args, address, resources[address])
+
+		err = e.cmd.Run(os.Stdout, tempDir, args, e.debug)
+		if err != nil {
+			return "", NewExecutorError(filepath.Join(tempDir, "terraform.tfstate"), err, e.debug)
+		}
+	}
+
+	tfState, err := readFile(filepath.Join(tempDir, "terraform.tfstate"))
+	if err != nil {
+		return "", err
+	}
+
+	return string(tfState), nil
+}
+
+// MoveState renames a resource in the tf state, so that terraform keeps
+// managing it under its new address instead of replacing it.
+func (e Executor) MoveState(tfState, from, to string) (string, error) {
+	tempDir, err := tempDir("", "")
+	if err != nil {
+		return "", err
+	}
+
+	err = writeFile(filepath.Join(tempDir, "terraform.tfstate"), []byte(tfState), os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+
+	err = e.cmd.Run(os.Stdout, tempDir, []string{"state", "mv", from, to}, e.debug)
+	if err != nil {
+		return "", err
+	}
+
+	movedTFState, err := readFile(filepath.Join(tempDir, "terraform.tfstate"))
+	if err != nil {
+		return "", err
+	}
+
+	return string(movedTFState), nil
+}
+
+// ApplyTargets applies the template to the targeted resources only.
+func (e Executor) ApplyTargets(input map[string]string, template, tfState string, targets []string) (string, error) {
+	tempDir, err := tempDir("", "")
+	if err != nil {
+		return "", err
+	}
+
+	err = writeFile(filepath.Join(tempDir, "template.tf"), []byte(template), os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+
+	err = writeFile(filepath.Join(tempDir, "terraform.tfstate"), []byte(tfState), os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+
+	err = e.initialize(tempDir)
+	if err != nil {
+		return "", NewExecutorError(filepath.Join(tempDir, "terraform.tfstate"), err, e.debug)
+	}
+
+	args := []string{"apply"}
+	for k, v := range input {
+		args = append(args, makeVar(k, v)...)
+	}
+	args = append(args, makeTargets(targets)...)
+
+	err = e.cmd.Run(os.Stdout, tempDir, args, e.debug)
+	if err != nil {
+		return "", NewExecutorError(filepath.Join(tempDir, "terraform.tfstate"), err, e.debug)
+	}
+
+	appliedTFState, err := readFile(filepath.Join(tempDir, "terraform.tfstate"))
+	if err != nil {
+		return "", err
+	}
+
+	return string(appliedTFState), nil
+}
+
+// Plan reports whether applying the template to the tf state would change
+// any infrastructure. With targets only the targeted resources are planned.
+func (e Executor) Plan(input map[string]string, template, tfState string, targets []string) (bool, error) {
+	tempDir, err := tempDir("", "")
+	if err != nil {
+		return false, err
+	}
+
+	err = writeFile(filepath.Join(tempDir, "template.tf"), []byte(template), os.ModePerm)
+	if err != nil {
+		return false, err
+	}
+
+	err = writeFile(filepath.Join(tempDir, "terraform.tfstate"), []byte(tfState), os.ModePerm)
+	if err != nil {
+		return false, err
+	}
+
+	err = e.initialize(tempDir)
+	if err != nil {
+		return false, err
+	}
+
+	args := []string{"plan", "-detailed-exitcode"}
+	for k, v := range input {
+		args = append(args, makeVar(k, v)...)
+	}
+	args = append(args, makeTargets(targets)...)
+
+	// With -detailed-exitcode terraform exits with 2 when the plan has changes.
+	err = e.cmd.Run(os.Stdout, tempDir, args, e.debug)
+	if exitStatus(err) == 2 {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return false, nil
+}
+
+func exitStatus(err error) int {
+	exitError, ok := err.(*exec.ExitError)
+	if !ok {
+		return -1
+	}
+
+	status, ok := exitError.Sys().(syscall.WaitStatus)
+	if !ok {
+		return -1
+	}
+
+	return status.ExitStatus()
+}
+
+// initialize installs the provider versions pinned in the template.
+func (e Executor) initialize(dir string) error {
+	return e.cmd.Run(os.Stdout, dir, []string{"init", "-input=false"}, e.debug)
+}
+
 func (e Executor) Version() (string, error) {
 	buffer := bytes.NewBuffer([]byte{})
 	err := e.cmd.Run(buffer, "/tmp", []string{"version"}, true)
@@ -180,3 +372,11 @@ func (e Executor) Outputs(tfState string) (map[string]interface{}, error) {
 func makeVar(name string, value string) []string {
 	return []string{"-var", fmt.Sprintf("%s=%s", name, value)}
 }
+
+func makeTargets(addresses []string) []string {
+	targets := []string{}
+	for _, address := range addresses {
+		targets = append(targets, fmt.Sprintf("-target=%s", address))
+	}
+	return targets
+}
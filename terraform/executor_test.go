@@ -6,6 +6,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -90,6 +91,14 @@ var _ = Describe("Executor", func() {
 			Expect(cmd.RunCall.Receives.Debug).To(BeTrue())
 		})
 
+		It("installs the pinned providers before applying", func() {
+			_, err := executor.Apply(input, "some-template", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cmd.RunCall.CallCount).To(Equal(2))
+			Expect(cmd.RunCall.ArgsForCall[0]).To(Equal([]string{"init", "-input=false"}))
+		})
+
 		It("reads and returns the terraform state written by the command", func() {
 			var actualFilename string
 
@@ -242,6 +251,14 @@ var _ = Describe("Executor", func() {
 			Expect(cmd.RunCall.Receives.Debug).To(BeTrue())
 		})
 
+		It("installs the pinned providers before destroying", func() {
+			_, err := executor.Destroy(input, "some-template", "some-tf-state")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cmd.RunCall.CallCount).To(Equal(2))
+			Expect(cmd.RunCall.ArgsForCall[0]).To(Equal([]string{"init", "-input=false"}))
+		})
+
 		It("reads and returns the tf state", func() {
 			terraform.SetReadFile(func(filename string) ([]byte, error) {
 				return []byte{}, nil
@@ -333,6 +350,198 @@ var _ = Describe("Executor", func() {
 		})
 	})
 
+	Describe("Import", func() {
+		var resources map[string]string
+
+		BeforeEach(func() {
+			input = map[string]string{"env_id": "some-env-id"}
+			resources = map[string]string{
+				"aws_vpc.vpc": "some-vpc-id",
+			}
+		})
+
+		It("writes the template and the previous tf state", func() {
+			_, err := executor.Import(input, "some-template", "some-tf-state", resources)
+			Expect(err).NotTo(HaveOccurred())
+
+			fileContents, err := ioutil.ReadFile(filepath.Join(tempDir, "template.tf"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(fileContents)).To(Equal("some-template"))
+
+			fileContents, err = ioutil.ReadFile(filepath.Join(tempDir, "terraform.tfstate"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(fileContents)).To(Equal("some-tf-state"))
+		})
+
+		It("imports each resource and returns the tf state", func() {
+			terraform.SetReadFile(func(filename string) ([]byte, error) {
+				return []byte("some-imported-tf-state"), nil
+			})
+
+			resources["aws_internet_gateway.ig"] = "some-internet-gateway-id"
+
+			tfState, err := executor.Import(input, "some-template", "", resources)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cmd.RunCall.CallCount).To(Equal(3))
+			Expect(cmd.RunCall.ArgsForCall[0]).To(Equal([]string{"init", "-input=false"}))
+			Expect(cmd.RunCall.Receives.WorkingDirectory).To(Equal(tempDir))
+			Expect(cmd.RunCall.Receives.Args).To(Equal([]string{
+				"import",
+				"-var", "env_id=some-env-id",
+				"aws_vpc.vpc", "some-vpc-id",
+			}))
+			Expect(tfState).To(Equal("some-imported-tf-state"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when it fails to write the template file", func() {
+				terraform.SetWriteFile(func(file string, data []byte, perm os.FileMode) error {
+					return errors.New("failed to write template file")
+				})
+
+				_, err := executor.Import(input, "some-template", "", resources)
+				Expect(err).To(MatchError("failed to write template file"))
+			})
+
+			It("returns an executor error when an import fails", func() {
+				cmd.RunCall.Returns.Error = errors.New("failed to import")
+
+				_, err := executor.Import(input, "some-template", "", resources)
+				Expect(err).To(BeAssignableToTypeOf(terraform.ExecutorError{}))
+				Expect(err).To(MatchError("failed to import"))
+			})
+		})
+	})
+
+	Describe("ApplyTargets", func() {
+		BeforeEach(func() {
+			input = map[string]string{"env_id": "some-env-id"}
+		})
+
+		It("applies the template to the targeted resources and returns the tf state", func() {
+			terraform.SetReadFile(func(filename string) ([]byte, error) {
+				return []byte("some-applied-tf-state"), nil
+			})
+
+			tfState, err := executor.ApplyTargets(input, "some-template", "some-tf-state", []string{"aws_iam_access_key.bosh", "aws_elb.cf_tcp_lb"})
+			Expect(err).NotTo(HaveOccurred())
+
+			fileContents, err := ioutil.ReadFile(filepath.Join(tempDir, "template.tf"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(fileContents)).To(Equal("some-template"))
+
+			fileContents, err = ioutil.ReadFile(filepath.Join(tempDir, "terraform.tfstate"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(fileContents)).To(Equal("some-tf-state"))
+
+			Expect(cmd.RunCall.ArgsForCall[0]).To(Equal([]string{"init", "-input=false"}))
+			Expect(cmd.RunCall.Receives.WorkingDirectory).To(Equal(tempDir))
+			Expect(cmd.RunCall.Receives.Args).To(Equal([]string{
+				"apply",
+				"-var", "env_id=some-env-id",
+				"-target=aws_iam_access_key.bosh",
+				"-target=aws_elb.cf_tcp_lb",
+			}))
+			Expect(tfState).To(Equal("some-applied-tf-state"))
+		})
+
+		It("returns an executor error when the apply fails", func() {
+			cmd.RunCall.ErrorsForCall = map[int]error{1: errors.New("failed to apply")}
+
+			_, err := executor.ApplyTargets(input, "some-template", "some-tf-state", []string{"aws_iam_access_key.bosh"})
+			Expect(err).To(BeAssignableToTypeOf(terraform.ExecutorError{}))
+			Expect(err).To(MatchError("failed to apply"))
+		})
+	})
+
+	Describe("MoveState", func() {
+		It("moves the resource in the tf state and returns the new tf state", func() {
+			terraform.SetReadFile(func(filename string) ([]byte, error) {
+				return []byte("some-moved-tf-state"), nil
+			})
+
+			tfState, err := executor.MoveState("some-tf-state", "some.from", "some.to")
+			Expect(err).NotTo(HaveOccurred())
+
+			fileContents, err := ioutil.ReadFile(filepath.Join(tempDir, "terraform.tfstate"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(fileContents)).To(Equal("some-tf-state"))
+
+			Expect(cmd.RunCall.Receives.WorkingDirectory).To(Equal(tempDir))
+			Expect(cmd.RunCall.Receives.Args).To(Equal([]string{"state", "mv", "some.from", "some.to"}))
+			Expect(tfState).To(Equal("some-moved-tf-state"))
+		})
+
+		It("returns an error when the move fails", func() {
+			cmd.RunCall.Returns.Error = errors.New("failed to move")
+
+			_, err := executor.MoveState("some-tf-state", "some.from", "some.to")
+			Expect(err).To(MatchError("failed to move"))
+		})
+	})
+
+	Describe("Plan", func() {
+		BeforeEach(func() {
+			input = map[string]string{"env_id": "some-env-id"}
+		})
+
+		It("writes the template and tf state and runs a plan", func() {
+			_, err := executor.Plan(input, "some-template", "some-tf-state", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			fileContents, err := ioutil.ReadFile(filepath.Join(tempDir, "terraform.tfstate"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(fileContents)).To(Equal("some-tf-state"))
+
+			Expect(cmd.RunCall.ArgsForCall[0]).To(Equal([]string{"init", "-input=false"}))
+			Expect(cmd.RunCall.Receives.Args).To(Equal([]string{
+				"plan", "-detailed-exitcode",
+				"-var", "env_id=some-env-id",
+			}))
+		})
+
+		It("only plans the targeted resources", func() {
+			_, err := executor.Plan(input, "some-template", "some-tf-state", []string{"aws_vpc.vpc"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cmd.RunCall.Receives.Args).To(Equal([]string{
+				"plan", "-detailed-exitcode",
+				"-var", "env_id=some-env-id",
+				"-target=aws_vpc.vpc",
+			}))
+		})
+
+		It("reports no changes when terraform exits with 0", func() {
+			hasChanges, err := executor.Plan(input, "some-template", "some-tf-state", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hasChanges).To(BeFalse())
+		})
+
+		It("reports changes when terraform exits with 2", func() {
+			cmd.RunCall.ErrorsForCall = map[int]error{1: exec.Command("sh", "-c", "exit 2").Run()}
+
+			hasChanges, err := executor.Plan(input, "some-template", "some-tf-state", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hasChanges).To(BeTrue())
+		})
+
+		It("returns an error when terraform exits with 1", func() {
+			planErr := exec.Command("sh", "-c", "exit 1").Run()
+			cmd.RunCall.ErrorsForCall = map[int]error{1: planErr}
+
+			_, err := executor.Plan(input, "some-template", "some-tf-state", nil)
+			Expect(err).To(Equal(planErr))
+		})
+
+		It("returns an error when the plan fails", func() {
+			cmd.RunCall.Returns.Error = errors.New("failed to plan")
+
+			_, err := executor.Plan(input, "some-template", "some-tf-state", nil)
+			Expect(err).To(MatchError("failed to plan"))
+		})
+	})
+
 	Describe("Version", func() {
 		BeforeEach(func() {
 			cmd.RunCall.Stub = func(stdout io.Writer) {
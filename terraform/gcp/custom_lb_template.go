@@ -0,0 +1,236 @@
+package gcp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+const customLBTemplate = `output "%[1]s_target_pool" {
+  value = "${google_compute_target_pool.%[1]s.name}"
+}
+
+output "%[1]s_lb_ip" {
+    value = "${google_compute_address.%[1]s.address}"
+}
+
+resource "google_compute_address" "%[1]s" {
+  name = "${var.env_id}-%[1]s"
+
+  labels = "${var.labels}"
+}
+
+resource "google_compute_firewall" "%[1]s" {
+  name       = "${var.env_id}-%[1]s-open"
+  depends_on = ["google_compute_network.bbl-network"]
+  network    = "${google_compute_network.bbl-network.name}"
+
+  allow {
+    protocol = "tcp"
+    ports    = [%[2]s]
+  }
+
+  source_ranges = ["${var.lb_inbound_cidrs}"]
+
+  target_tags = ["${google_compute_target_pool.%[1]s.name}"]
+}
+%[3]s
+resource "google_compute_target_pool" "%[1]s" {
+  name = "${var.env_id}-%[1]s"
+%[4]s}
+`
+
+const customLBHealthCheckTemplate = `
+resource "google_compute_http_health_check" "%[1]s" {
+  name                = "${var.env_id}-%[1]s"
+  port                = %[2]d
+  request_path        = "%[3]s"
+}
+`
+
+const customLBForwardingRuleTemplate = `
+resource "google_compute_forwarding_rule" "%[1]s-%[2]d" {
+  name        = "${var.env_id}-%[1]s-%[2]d"
+  target      = "${google_compute_target_pool.%[1]s.self_link}"
+  port_range  = "%[2]d"
+  ip_protocol = "TCP"
+  ip_address  = "${google_compute_address.%[1]s.address}"
+
+  labels = "${var.labels}"
+}
+`
+
+const customTLSLBTemplate = `variable "%[1]s_ssl_certificate" {
+  type = "string"
+}
+
+variable "%[1]s_ssl_certificate_private_key" {
+  type = "string"
+}
+
+variable "%[1]s_ssl_certificate_name" {
+  type = "string"
+}
+
+output "%[1]s_backend_service" {
+  value = "${google_compute_backend_service.%[1]s.name}"
+}
+
+output "%[1]s_lb_ip" {
+    value = "${google_compute_global_address.%[1]s.address}"
+}
+
+resource "google_compute_global_address" "%[1]s" {
+  name = "${var.env_id}-%[1]s"
+
+  labels = "${var.labels}"
+}
+
+resource "google_compute_firewall" "%[1]s" {
+  name       = "${var.env_id}-%[1]s-open"
+  depends_on = ["google_compute_network.bbl-network"]
+  network    = "${google_compute_network.bbl-network.name}"
+
+  allow {
+    protocol = "tcp"
+    ports    = [%[2]s]
+  }
+
+  source_ranges = ["130.211.0.0/22", "35.191.0.0/16"]
+
+  target_tags = ["${google_compute_backend_service.%[1]s.name}"]
+}
+
+resource "google_compute_global_forwarding_rule" "%[1]s-443" {
+  name       = "${var.env_id}-%[1]s-443"
+  ip_address = "${google_compute_global_address.%[1]s.address}"
+  target     = "${google_compute_target_https_proxy.%[1]s.self_link}"
+  port_range = "443"
+
+  labels = "${var.labels}"
+}
+
+resource "google_compute_target_https_proxy" "%[1]s" {
+  name             = "${var.env_id}-%[1]s"
+  url_map          = "${google_compute_url_map.%[1]s.self_link}"
+  ssl_certificates = ["${google_compute_ssl_certificate.%[1]s.self_link}"]
+}
+
+resource "google_compute_ssl_certificate" "%[1]s" {
+  name        = "${var.%[1]s_ssl_certificate_name}"
+  private_key = "${file(var.%[1]s_ssl_certificate_private_key)}"
+  certificate = "${file(var.%[1]s_ssl_certificate)}"
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+
+resource "google_compute_url_map" "%[1]s" {
+  name = "${var.env_id}-%[1]s"
+
+  default_service = "${google_compute_backend_service.%[1]s.self_link}"
+}
+
+resource "google_compute_health_check" "%[1]s" {
+  name = "${var.env_id}-%[1]s"
+%[3]s}
+
+resource "google_compute_backend_service" "%[1]s" {
+  name      = "${var.env_id}-%[1]s"
+  port_name = "%[1]s"
+  protocol  = "HTTP"
+%[4]s
+  health_checks = ["${google_compute_health_check.%[1]s.self_link}"]
+}
+`
+
+const customTLSLBInstanceGroupTemplate = `
+resource "google_compute_instance_group" "%[1]s-%[2]d" {
+  name = "${var.env_id}-%[1]s-%[2]d-%[3]s"
+  zone = "%[3]s"
+
+  named_port {
+    name = "%[1]s"
+    port = %[4]d
+  }
+}
+`
+
+// GenerateCustomLB renders a network load balancer for a custom lb spec.
+// Target pools only support legacy http health checks, so tcp health
+// checks are left to the load balancer's default behaviour. Specs with tls
+// are rendered as an https load balancer instead, like the cf router.
+func (t TemplateGenerator) GenerateCustomLB(spec storage.CustomLBSpec, zones []string) string {
+	if spec.TLS {
+		return t.generateCustomTLSLB(spec, zones)
+	}
+
+	var ports []string
+	for _, listener := range spec.Listeners {
+		ports = appendPort(ports, listener.Port)
+	}
+	ports = appendPort(ports, spec.HealthCheck.Port)
+
+	var healthCheck, targetPoolHealthChecks string
+	if spec.HealthCheck.Protocol == "http" {
+		healthCheck = fmt.Sprintf(customLBHealthCheckTemplate, spec.Name, spec.HealthCheck.Port, spec.HealthCheck.Path)
+		targetPoolHealthChecks = fmt.Sprintf(`
+  health_checks = [
+    "${google_compute_http_health_check.%s.name}",
+  ]
+`, spec.Name)
+	}
+
+	template := fmt.Sprintf(customLBTemplate, spec.Name, strings.Join(ports, ", "), healthCheck, targetPoolHealthChecks)
+	for _, listener := range spec.Listeners {
+		template = fmt.Sprintf("%s%s", template, fmt.Sprintf(customLBForwardingRuleTemplate, spec.Name, listener.Port))
+	}
+
+	return template
+}
+
+// generateCustomTLSLB renders an https load balancer that terminates tls at
+// google's front ends and forwards http to the target port. Bosh adds the
+// vms to the instance groups of the backend service in their zone.
+func (t TemplateGenerator) generateCustomTLSLB(spec storage.CustomLBSpec, zones []string) string {
+	targetPort := spec.Listeners[0].TargetPort
+	ports := appendPort(appendPort(nil, targetPort), spec.HealthCheck.Port)
+
+	healthCheck := fmt.Sprintf(`
+  tcp_health_check {
+    port = %d
+  }
+`, spec.HealthCheck.Port)
+	if spec.HealthCheck.Protocol == "http" {
+		healthCheck = fmt.Sprintf(`
+  http_health_check {
+    port         = %d
+    request_path = "%s"
+  }
+`, spec.HealthCheck.Port, spec.HealthCheck.Path)
+	}
+
+	var backends, instanceGroups string
+	for i, zone := range zones {
+		backends = fmt.Sprintf(`%s
+  backend {
+    group = "${google_compute_instance_group.%s-%d.self_link}"
+  }
+`, backends, spec.Name, i)
+		instanceGroups = fmt.Sprintf("%s%s", instanceGroups, fmt.Sprintf(customTLSLBInstanceGroupTemplate, spec.Name, i, zone, targetPort))
+	}
+
+	return fmt.Sprintf(customTLSLBTemplate, spec.Name, strings.Join(ports, ", "), healthCheck, backends) + instanceGroups
+}
+
+func appendPort(ports []string, port int) []string {
+	quoted := fmt.Sprintf("%q", fmt.Sprint(port))
+	for _, existing := range ports {
+		if existing == quoted {
+			return ports
+		}
+	}
+
+	return append(ports, quoted)
+}
@@ -0,0 +1,62 @@
+output "vault_target_pool" {
+  value = "${google_compute_target_pool.vault.name}"
+}
+
+output "vault_lb_ip" {
+    value = "${google_compute_address.vault.address}"
+}
+
+resource "google_compute_address" "vault" {
+  name = "${var.env_id}-vault"
+
+  labels = "${var.labels}"
+}
+
+resource "google_compute_firewall" "vault" {
+  name       = "${var.env_id}-vault-open"
+  depends_on = ["google_compute_network.bbl-network"]
+  network    = "${google_compute_network.bbl-network.name}"
+
+  allow {
+    protocol = "tcp"
+    ports    = ["8200", "80"]
+  }
+
+  source_ranges = ["${var.lb_inbound_cidrs}"]
+
+  target_tags = ["${google_compute_target_pool.vault.name}"]
+}
+
+resource "google_compute_http_health_check" "vault" {
+  name                = "${var.env_id}-vault"
+  port                = 8200
+  request_path        = "/v1/sys/health"
+}
+
+resource "google_compute_target_pool" "vault" {
+  name = "${var.env_id}-vault"
+
+  health_checks = [
+    "${google_compute_http_health_check.vault.name}",
+  ]
+}
+
+resource "google_compute_forwarding_rule" "vault-8200" {
+  name        = "${var.env_id}-vault-8200"
+  target      = "${google_compute_target_pool.vault.self_link}"
+  port_range  = "8200"
+  ip_protocol = "TCP"
+  ip_address  = "${google_compute_address.vault.address}"
+
+  labels = "${var.labels}"
+}
+
+resource "google_compute_forwarding_rule" "vault-80" {
+  name        = "${var.env_id}-vault-80"
+  target      = "${google_compute_target_pool.vault.self_link}"
+  port_range  = "80"
+  ip_protocol = "TCP"
+  ip_address  = "${google_compute_address.vault.address}"
+
+  labels = "${var.labels}"
+}
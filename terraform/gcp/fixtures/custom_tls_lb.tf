@@ -0,0 +1,115 @@
+variable "vault_ssl_certificate" {
+  type = "string"
+}
+
+variable "vault_ssl_certificate_private_key" {
+  type = "string"
+}
+
+variable "vault_ssl_certificate_name" {
+  type = "string"
+}
+
+output "vault_backend_service" {
+  value = "${google_compute_backend_service.vault.name}"
+}
+
+output "vault_lb_ip" {
+    value = "${google_compute_global_address.vault.address}"
+}
+
+resource "google_compute_global_address" "vault" {
+  name = "${var.env_id}-vault"
+
+  labels = "${var.labels}"
+}
+
+resource "google_compute_firewall" "vault" {
+  name       = "${var.env_id}-vault-open"
+  depends_on = ["google_compute_network.bbl-network"]
+  network    = "${google_compute_network.bbl-network.name}"
+
+  allow {
+    protocol = "tcp"
+    ports    = ["8200"]
+  }
+
+  source_ranges = ["130.211.0.0/22", "35.191.0.0/16"]
+
+  target_tags = ["${google_compute_backend_service.vault.name}"]
+}
+
+resource "google_compute_global_forwarding_rule" "vault-443" {
+  name       = "${var.env_id}-vault-443"
+  ip_address = "${google_compute_global_address.vault.address}"
+  target     = "${google_compute_target_https_proxy.vault.self_link}"
+  port_range = "443"
+
+  labels = "${var.labels}"
+}
+
+resource "google_compute_target_https_proxy" "vault" {
+  name             = "${var.env_id}-vault"
+  url_map          = "${google_compute_url_map.vault.self_link}"
+  ssl_certificates = ["${google_compute_ssl_certificate.vault.self_link}"]
+}
+
+resource "google_compute_ssl_certificate" "vault" {
+  name        = "${var.vault_ssl_certificate_name}"
+  private_key = "${file(var.vault_ssl_certificate_private_key)}"
+  certificate = "${file(var.vault_ssl_certificate)}"
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+
+resource "google_compute_url_map" "vault" {
+  name = "${var.env_id}-vault"
+
+  default_service = "${google_compute_backend_service.vault.self_link}"
+}
+
+resource "google_compute_health_check" "vault" {
+  name = "${var.env_id}-vault"
+
+  http_health_check {
+    port         = 8200
+    request_path = "/v1/sys/health"
+  }
+}
+
+resource "google_compute_backend_service" "vault" {
+  name      = "${var.env_id}-vault"
+  port_name = "vault"
+  protocol  = "HTTP"
+
+  backend {
+    group = "${google_compute_instance_group.vault-0.self_link}"
+  }
+
+  backend {
+    group = "${google_compute_instance_group.vault-1.self_link}"
+  }
+
+  health_checks = ["${google_compute_health_check.vault.self_link}"]
+}
+
+resource "google_compute_instance_group" "vault-0" {
+  name = "${var.env_id}-vault-0-z1"
+  zone = "z1"
+
+  named_port {
+    name = "vault"
+    port = 8200
+  }
+}
+
+resource "google_compute_instance_group" "vault-1" {
+  name = "${var.env_id}-vault-1-z2"
+  zone = "z2"
+
+  named_port {
+    name = "vault"
+    port = 8200
+  }
+}
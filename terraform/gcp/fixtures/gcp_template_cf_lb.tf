@@ -14,11 +14,27 @@ variable "env_id" {
 	type = "string"
 }
 
+variable "labels" {
+	type = "map"
+	default = {}
+}
+
 variable "credentials" {
 	type = "string"
 }
 
+variable "bosh_inbound_cidrs" {
+	type = "list"
+	default = ["0.0.0.0/0"]
+}
+
+variable "lb_inbound_cidrs" {
+	type = "list"
+	default = ["0.0.0.0/0"]
+}
+
 provider "google" {
+	version = "~> 1.20"
 	credentials = "${file("${var.credentials}")}"
 	project = "${var.project_id}"
 	region = "${var.region}"
@@ -60,13 +76,15 @@ resource "google_compute_subnetwork" "bbl-subnet" {
 
 resource "google_compute_address" "bosh-external-ip" {
   name = "${var.env_id}-bosh-external-ip"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_firewall" "bosh-open" {
   name    = "${var.env_id}-bosh-open"
   network = "${google_compute_network.bbl-network.name}"
 
-  source_ranges = ["0.0.0.0/0"]
+  source_ranges = ["${var.bosh_inbound_cidrs}"]
 
   allow {
     protocol = "icmp"
@@ -107,6 +125,10 @@ variable "ssl_certificate_private_key" {
   type = "string"
 }
 
+variable "ssl_certificate_name" {
+  type = "string"
+}
+
 output "router_backend_service" {
   value = "${google_compute_backend_service.router-lb-backend-service.name}"
 }
@@ -137,13 +159,15 @@ resource "google_compute_firewall" "firewall-cf" {
     ports    = ["80", "443"]
   }
 
-  source_ranges = ["0.0.0.0/0"]
+  source_ranges = ["130.211.0.0/22", "35.191.0.0/16"]
 
   target_tags = ["${google_compute_backend_service.router-lb-backend-service.name}"]
 }
 
 resource "google_compute_global_address" "cf-address" {
   name = "${var.env_id}-cf"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_global_forwarding_rule" "cf-http-forwarding-rule" {
@@ -151,6 +175,8 @@ resource "google_compute_global_forwarding_rule" "cf-http-forwarding-rule" {
   ip_address = "${google_compute_global_address.cf-address.address}"
   target     = "${google_compute_target_http_proxy.cf-http-lb-proxy.self_link}"
   port_range = "80"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_global_forwarding_rule" "cf-https-forwarding-rule" {
@@ -158,6 +184,8 @@ resource "google_compute_global_forwarding_rule" "cf-https-forwarding-rule" {
   ip_address = "${google_compute_global_address.cf-address.address}"
   target     = "${google_compute_target_https_proxy.cf-https-lb-proxy.self_link}"
   port_range = "443"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_target_http_proxy" "cf-http-lb-proxy" {
@@ -174,7 +202,7 @@ resource "google_compute_target_https_proxy" "cf-https-lb-proxy" {
 }
 
 resource "google_compute_ssl_certificate" "cf-cert" {
-  name_prefix = "${var.env_id}"
+  name        = "${var.ssl_certificate_name}"
   description = "user provided ssl private key / ssl certificate pair"
   private_key = "${file(var.ssl_certificate_private_key)}"
   certificate = "${file(var.ssl_certificate)}"
@@ -215,6 +243,8 @@ output "ssh_proxy_target_pool" {
 
 resource "google_compute_address" "cf-ssh-proxy" {
   name = "${var.env_id}-cf-ssh-proxy"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_firewall" "cf-ssh-proxy" {
@@ -227,6 +257,8 @@ resource "google_compute_firewall" "cf-ssh-proxy" {
     ports    = ["2222"]
   }
 
+  source_ranges = ["${var.lb_inbound_cidrs}"]
+
   target_tags = ["${google_compute_target_pool.cf-ssh-proxy.name}"]
 }
 
@@ -240,6 +272,8 @@ resource "google_compute_forwarding_rule" "cf-ssh-proxy" {
   port_range  = "2222"
   ip_protocol = "TCP"
   ip_address  = "${google_compute_address.cf-ssh-proxy.address}"
+
+  labels = "${var.labels}"
 }
 
 output "tcp_router_target_pool" {
@@ -256,11 +290,15 @@ resource "google_compute_firewall" "cf-tcp-router" {
     ports    = ["1024-32768"]
   }
 
+  source_ranges = ["${var.lb_inbound_cidrs}"]
+
   target_tags = ["${google_compute_target_pool.cf-tcp-router.name}"]
 }
 
 resource "google_compute_address" "cf-tcp-router" {
   name = "${var.env_id}-cf-tcp-router"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_http_health_check" "cf-tcp-router" {
@@ -283,6 +321,8 @@ resource "google_compute_forwarding_rule" "cf-tcp-router" {
   port_range  = "1024-32768"
   ip_protocol = "TCP"
   ip_address  = "${google_compute_address.cf-tcp-router.address}"
+
+  labels = "${var.labels}"
 }
 
 output "ws_target_pool" {
@@ -291,6 +331,8 @@ output "ws_target_pool" {
 
 resource "google_compute_address" "cf-ws" {
   name = "${var.env_id}-cf-ws"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_target_pool" "cf-ws" {
@@ -305,6 +347,8 @@ resource "google_compute_forwarding_rule" "cf-ws-https" {
   port_range  = "443"
   ip_protocol = "TCP"
   ip_address  = "${google_compute_address.cf-ws.address}"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_forwarding_rule" "cf-ws-http" {
@@ -313,6 +357,8 @@ resource "google_compute_forwarding_rule" "cf-ws-http" {
   port_range  = "80"
   ip_protocol = "TCP"
   ip_address  = "${google_compute_address.cf-ws.address}"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_instance_group" "router-lb-0" {
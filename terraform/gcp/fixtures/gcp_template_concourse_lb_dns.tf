@@ -0,0 +1,197 @@
+variable "project_id" {
+	type = "string"
+}
+
+variable "region" {
+	type = "string"
+}
+
+variable "zone" {
+	type = "string"
+}
+
+variable "env_id" {
+	type = "string"
+}
+
+variable "labels" {
+	type = "map"
+	default = {}
+}
+
+variable "credentials" {
+	type = "string"
+}
+
+variable "bosh_inbound_cidrs" {
+	type = "list"
+	default = ["0.0.0.0/0"]
+}
+
+variable "lb_inbound_cidrs" {
+	type = "list"
+	default = ["0.0.0.0/0"]
+}
+
+provider "google" {
+	version = "~> 1.20"
+	credentials = "${file("${var.credentials}")}"
+	project = "${var.project_id}"
+	region = "${var.region}"
+}
+
+output "external_ip" {
+    value = "${google_compute_address.bosh-external-ip.address}"
+}
+
+output "network_name" {
+    value = "${google_compute_network.bbl-network.name}"
+}
+
+output "subnetwork_name" {
+    value = "${google_compute_subnetwork.bbl-subnet.name}"
+}
+
+output "bosh_open_tag_name" {
+    value = "${google_compute_firewall.bosh-open.name}"
+}
+
+output "internal_tag_name" {
+    value = "${google_compute_firewall.internal.name}"
+}
+
+output "director_address" {
+	value = "https://${google_compute_address.bosh-external-ip.address}:25555"
+}
+
+resource "google_compute_network" "bbl-network" {
+  name		 = "${var.env_id}-network"
+}
+
+resource "google_compute_subnetwork" "bbl-subnet" {
+  name			= "${var.env_id}-subnet"
+  ip_cidr_range = "10.0.0.0/16"
+  network		= "${google_compute_network.bbl-network.self_link}"
+}
+
+resource "google_compute_address" "bosh-external-ip" {
+  name = "${var.env_id}-bosh-external-ip"
+
+  labels = "${var.labels}"
+}
+
+resource "google_compute_firewall" "bosh-open" {
+  name    = "${var.env_id}-bosh-open"
+  network = "${google_compute_network.bbl-network.name}"
+
+  source_ranges = ["${var.bosh_inbound_cidrs}"]
+
+  allow {
+    protocol = "icmp"
+  }
+
+  allow {
+    ports = ["22", "6868", "25555"]
+    protocol = "tcp"
+  }
+
+  target_tags = ["${var.env_id}-bosh-open"]
+}
+
+resource "google_compute_firewall" "internal" {
+  name    = "${var.env_id}-internal"
+  network = "${google_compute_network.bbl-network.name}"
+
+  allow {
+    protocol = "icmp"
+  }
+
+  allow {
+    protocol = "tcp"
+  }
+
+  allow {
+    protocol = "udp"
+  }
+
+  source_tags = ["${var.env_id}-bosh-open","${var.env_id}-internal"]
+}
+
+output "concourse_target_pool" {
+	value = "${google_compute_target_pool.target-pool.name}"
+}
+
+output "concourse_lb_ip" {
+    value = "${google_compute_address.concourse-address.address}"
+}
+
+resource "google_compute_firewall" "firewall-concourse" {
+  name    = "${var.env_id}-concourse-open"
+  network = "${google_compute_network.bbl-network.name}"
+
+  allow {
+    protocol = "tcp"
+    ports    = ["443", "2222"]
+  }
+
+  source_ranges = ["${var.lb_inbound_cidrs}"]
+
+  target_tags = ["concourse"]
+}
+
+resource "google_compute_address" "concourse-address" {
+  name = "${var.env_id}-concourse"
+
+  labels = "${var.labels}"
+}
+
+resource "google_compute_target_pool" "target-pool" {
+  name = "${var.env_id}-concourse"
+}
+
+resource "google_compute_forwarding_rule" "ssh-forwarding-rule" {
+  name        = "${var.env_id}-concourse-ssh"
+  target      = "${google_compute_target_pool.target-pool.self_link}"
+  port_range  = "2222"
+  ip_protocol = "TCP"
+  ip_address  = "${google_compute_address.concourse-address.address}"
+
+  labels = "${var.labels}"
+}
+
+resource "google_compute_forwarding_rule" "https-forwarding-rule" {
+  name        = "${var.env_id}-concourse-https"
+  target      = "${google_compute_target_pool.target-pool.self_link}"
+  port_range  = "443"
+  ip_protocol = "TCP"
+  ip_address  = "${google_compute_address.concourse-address.address}"
+
+  labels = "${var.labels}"
+}
+
+variable "concourse_domain" {
+  type = "string"
+}
+
+resource "google_dns_managed_zone" "concourse_dns_zone" {
+  name        = "${var.env_id}-concourse-zone"
+  dns_name    = "${var.concourse_domain}."
+  description = "DNS zone for the ${var.env_id} concourse"
+
+  labels = "${var.labels}"
+}
+
+output "concourse_domain_dns_servers" {
+  value = "${google_dns_managed_zone.concourse_dns_zone.name_servers}"
+}
+
+resource "google_dns_record_set" "concourse-dns" {
+  name       = "${google_dns_managed_zone.concourse_dns_zone.dns_name}"
+  depends_on = ["google_compute_address.concourse-address"]
+  type       = "A"
+  ttl        = 300
+
+  managed_zone = "${google_dns_managed_zone.concourse_dns_zone.name}"
+
+  rrdatas = ["${google_compute_address.concourse-address.address}"]
+}
@@ -14,11 +14,27 @@ variable "env_id" {
 	type = "string"
 }
 
+variable "labels" {
+	type = "map"
+	default = {}
+}
+
 variable "credentials" {
 	type = "string"
 }
 
+variable "bosh_inbound_cidrs" {
+	type = "list"
+	default = ["0.0.0.0/0"]
+}
+
+variable "lb_inbound_cidrs" {
+	type = "list"
+	default = ["0.0.0.0/0"]
+}
+
 provider "google" {
+	version = "~> 1.20"
 	credentials = "${file("${var.credentials}")}"
 	project = "${var.project_id}"
 	region = "${var.region}"
@@ -60,13 +76,15 @@ resource "google_compute_subnetwork" "bbl-subnet" {
 
 resource "google_compute_address" "bosh-external-ip" {
   name = "${var.env_id}-bosh-external-ip"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_firewall" "bosh-open" {
   name    = "${var.env_id}-bosh-open"
   network = "${google_compute_network.bbl-network.name}"
 
-  source_ranges = ["0.0.0.0/0"]
+  source_ranges = ["${var.bosh_inbound_cidrs}"]
 
   allow {
     protocol = "icmp"
@@ -0,0 +1,149 @@
+variable "project_id" {
+	type = "string"
+}
+
+variable "region" {
+	type = "string"
+}
+
+variable "zone" {
+	type = "string"
+}
+
+variable "env_id" {
+	type = "string"
+}
+
+variable "labels" {
+	type = "map"
+	default = {}
+}
+
+variable "credentials" {
+	type = "string"
+}
+
+variable "bosh_inbound_cidrs" {
+	type = "list"
+	default = ["0.0.0.0/0"]
+}
+
+variable "lb_inbound_cidrs" {
+	type = "list"
+	default = ["0.0.0.0/0"]
+}
+
+provider "google" {
+	version = "~> 1.20"
+	credentials = "${file("${var.credentials}")}"
+	project = "${var.project_id}"
+	region = "${var.region}"
+}
+
+output "external_ip" {
+    value = "${google_compute_address.bosh-external-ip.address}"
+}
+
+output "network_name" {
+    value = "${google_compute_network.bbl-network.name}"
+}
+
+output "subnetwork_name" {
+    value = "${google_compute_subnetwork.bbl-subnet.name}"
+}
+
+output "bosh_open_tag_name" {
+    value = "${google_compute_firewall.bosh-open.name}"
+}
+
+output "internal_tag_name" {
+    value = "${google_compute_firewall.internal.name}"
+}
+
+output "director_address" {
+	value = "https://${google_compute_address.bosh-external-ip.address}:25555"
+}
+
+resource "google_compute_network" "bbl-network" {
+  name		 = "${var.env_id}-network"
+}
+
+resource "google_compute_subnetwork" "bbl-subnet" {
+  name			= "${var.env_id}-subnet"
+  ip_cidr_range = "10.0.0.0/16"
+  network		= "${google_compute_network.bbl-network.self_link}"
+}
+
+resource "google_compute_address" "bosh-external-ip" {
+  name = "${var.env_id}-bosh-external-ip"
+
+  labels = "${var.labels}"
+}
+
+resource "google_compute_firewall" "bosh-open" {
+  name    = "${var.env_id}-bosh-open"
+  network = "${google_compute_network.bbl-network.name}"
+
+  source_ranges = ["${var.bosh_inbound_cidrs}"]
+
+  allow {
+    protocol = "icmp"
+  }
+
+  allow {
+    ports = ["22", "6868", "25555"]
+    protocol = "tcp"
+  }
+
+  target_tags = ["${var.env_id}-bosh-open"]
+}
+
+resource "google_compute_firewall" "internal" {
+  name    = "${var.env_id}-internal"
+  network = "${google_compute_network.bbl-network.name}"
+
+  allow {
+    protocol = "icmp"
+  }
+
+  allow {
+    protocol = "tcp"
+  }
+
+  allow {
+    protocol = "udp"
+  }
+
+  source_tags = ["${var.env_id}-bosh-open","${var.env_id}-internal"]
+}
+
+variable "director_service_account_id" {
+  type = "string"
+}
+
+resource "google_service_account" "bosh" {
+  account_id   = "${var.director_service_account_id}"
+  display_name = "${var.env_id} bosh director"
+}
+
+resource "google_project_iam_member" "bosh-instance-admin" {
+  project = "${var.project_id}"
+  role    = "roles/compute.instanceAdmin"
+  member  = "serviceAccount:${google_service_account.bosh.email}"
+}
+
+resource "google_project_iam_member" "bosh-storage-admin" {
+  project = "${var.project_id}"
+  role    = "roles/compute.storageAdmin"
+  member  = "serviceAccount:${google_service_account.bosh.email}"
+}
+
+resource "google_project_iam_member" "bosh-network-admin" {
+  project = "${var.project_id}"
+  role    = "roles/compute.networkAdmin"
+  member  = "serviceAccount:${google_service_account.bosh.email}"
+}
+
+output "director_service_account_email" {
+  value = "${google_service_account.bosh.email}"
+}
@@ -16,11 +16,27 @@ variable "env_id" {
 	type = "string"
 }
 
+variable "labels" {
+	type = "map"
+	default = {}
+}
+
 variable "credentials" {
 	type = "string"
 }
 
+variable "bosh_inbound_cidrs" {
+	type = "list"
+	default = ["0.0.0.0/0"]
+}
+
+variable "lb_inbound_cidrs" {
+	type = "list"
+	default = ["0.0.0.0/0"]
+}
+
 provider "google" {
+	version = "~> 1.20"
 	credentials = "${file("${var.credentials}")}"
 	project = "${var.project_id}"
 	region = "${var.region}"
@@ -63,13 +79,15 @@ resource "google_compute_subnetwork" "bbl-subnet" {
 
 resource "google_compute_address" "bosh-external-ip" {
   name = "${var.env_id}-bosh-external-ip"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_firewall" "bosh-open" {
   name    = "${var.env_id}-bosh-open"
   network = "${google_compute_network.bbl-network.name}"
 
-  source_ranges = ["0.0.0.0/0"]
+  source_ranges = ["${var.bosh_inbound_cidrs}"]
 
   allow {
     protocol = "icmp"
@@ -103,6 +121,40 @@ resource "google_compute_firewall" "internal" {
 }
 `
 
+// DirectorServiceAccountTemplate creates the service account that is attached
+// to the director vm, with only the compute roles the cpi needs.
+const DirectorServiceAccountTemplate = `variable "director_service_account_id" {
+  type = "string"
+}
+
+resource "google_service_account" "bosh" {
+  account_id   = "${var.director_service_account_id}"
+  display_name = "${var.env_id} bosh director"
+}
+
+resource "google_project_iam_member" "bosh-instance-admin" {
+  project = "${var.project_id}"
+  role    = "roles/compute.instanceAdmin"
+  member  = "serviceAccount:${google_service_account.bosh.email}"
+}
+
+resource "google_project_iam_member" "bosh-storage-admin" {
+  project = "${var.project_id}"
+  role    = "roles/compute.storageAdmin"
+  member  = "serviceAccount:${google_service_account.bosh.email}"
+}
+
+resource "google_project_iam_member" "bosh-network-admin" {
+  project = "${var.project_id}"
+  role    = "roles/compute.networkAdmin"
+  member  = "serviceAccount:${google_service_account.bosh.email}"
+}
+
+output "director_service_account_email" {
+  value = "${google_service_account.bosh.email}"
+}
+`
+
 const terraformConcourseLBTemplate = `output "concourse_target_pool" {
 	value = "${google_compute_target_pool.target-pool.name}"
 }
@@ -125,6 +177,8 @@ resource "google_compute_firewall" "firewall-concourse" {
 
 resource "google_compute_address" "concourse-address" {
   name = "${var.env_id}-concourse"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_target_pool" "target-pool" {
@@ -137,6 +191,8 @@ resource "google_compute_forwarding_rule" "ssh-forwarding-rule" {
   port_range  = "2222"
   ip_protocol = "TCP"
   ip_address  = "${google_compute_address.concourse-address.address}"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_forwarding_rule" "https-forwarding-rule" {
@@ -145,6 +201,8 @@ resource "google_compute_forwarding_rule" "https-forwarding-rule" {
   port_range  = "443"
   ip_protocol = "TCP"
   ip_address  = "${google_compute_address.concourse-address.address}"
+
+  labels = "${var.labels}"
 }
 `
 
@@ -193,6 +251,8 @@ resource "google_compute_firewall" "firewall-cf" {
 
 resource "google_compute_global_address" "cf-address" {
   name = "${var.env_id}-cf"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_global_forwarding_rule" "cf-http-forwarding-rule" {
@@ -200,6 +260,8 @@ resource "google_compute_global_forwarding_rule" "cf-http-forwarding-rule" {
   ip_address = "${google_compute_global_address.cf-address.address}"
   target     = "${google_compute_target_http_proxy.cf-http-lb-proxy.self_link}"
   port_range = "80"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_global_forwarding_rule" "cf-https-forwarding-rule" {
@@ -207,6 +269,8 @@ resource "google_compute_global_forwarding_rule" "cf-https-forwarding-rule" {
   ip_address = "${google_compute_global_address.cf-address.address}"
   target     = "${google_compute_target_https_proxy.cf-https-lb-proxy.self_link}"
   port_range = "443"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_target_http_proxy" "cf-http-lb-proxy" {
@@ -264,6 +328,8 @@ output "ssh_proxy_target_pool" {
 
 resource "google_compute_address" "cf-ssh-proxy" {
   name = "${var.env_id}-cf-ssh-proxy"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_firewall" "cf-ssh-proxy" {
@@ -289,6 +355,8 @@ resource "google_compute_forwarding_rule" "cf-ssh-proxy" {
   port_range  = "2222"
   ip_protocol = "TCP"
   ip_address  = "${google_compute_address.cf-ssh-proxy.address}"
+
+  labels = "${var.labels}"
 }
 
 output "tcp_router_target_pool" {
@@ -310,6 +378,8 @@ resource "google_compute_firewall" "cf-tcp-router" {
 
 resource "google_compute_address" "cf-tcp-router" {
   name = "${var.env_id}-cf-tcp-router"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_http_health_check" "cf-tcp-router" {
@@ -332,6 +402,8 @@ resource "google_compute_forwarding_rule" "cf-tcp-router" {
   port_range  = "1024-32768"
   ip_protocol = "TCP"
   ip_address  = "${google_compute_address.cf-tcp-router.address}"
+
+  labels = "${var.labels}"
 }
 
 output "ws_target_pool" {
@@ -340,6 +412,8 @@ output "ws_target_pool" {
 
 resource "google_compute_address" "cf-ws" {
   name = "${var.env_id}-cf-ws"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_target_pool" "cf-ws" {
@@ -354,6 +428,8 @@ resource "google_compute_forwarding_rule" "cf-ws-https" {
   port_range  = "443"
   ip_protocol = "TCP"
   ip_address  = "${google_compute_address.cf-ws.address}"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_forwarding_rule" "cf-ws-http" {
@@ -362,6 +438,8 @@ resource "google_compute_forwarding_rule" "cf-ws-http" {
   port_range  = "80"
   ip_protocol = "TCP"
   ip_address  = "${google_compute_address.cf-ws.address}"
+
+  labels = "${var.labels}"
 }
 `
 
@@ -374,6 +452,8 @@ resource "google_dns_managed_zone" "env_dns_zone" {
   name        = "${var.env_id}-zone"
   dns_name    = "${var.system_domain}."
   description = "DNS zone for the ${var.env_id} environment"
+
+  labels = "${var.labels}"
 }
 
 output "system_domain_dns_servers" {
@@ -475,11 +555,15 @@ resource "google_compute_firewall" "firewall-concourse" {
     ports    = ["443", "2222"]
   }
 
+  source_ranges = ["${var.lb_inbound_cidrs}"]
+
   target_tags = ["concourse"]
 }
 
 resource "google_compute_address" "concourse-address" {
   name = "${var.env_id}-concourse"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_target_pool" "target-pool" {
@@ -492,6 +576,8 @@ resource "google_compute_forwarding_rule" "ssh-forwarding-rule" {
   port_range  = "2222"
   ip_protocol = "TCP"
   ip_address  = "${google_compute_address.concourse-address.address}"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_forwarding_rule" "https-forwarding-rule" {
@@ -500,6 +586,8 @@ resource "google_compute_forwarding_rule" "https-forwarding-rule" {
   port_range  = "443"
   ip_protocol = "TCP"
   ip_address  = "${google_compute_address.concourse-address.address}"
+
+  labels = "${var.labels}"
 }
 `
 
@@ -511,6 +599,10 @@ variable "ssl_certificate_private_key" {
   type = "string"
 }
 
+variable "ssl_certificate_name" {
+  type = "string"
+}
+
 output "router_backend_service" {
   value = "${google_compute_backend_service.router-lb-backend-service.name}"
 }
@@ -541,13 +633,15 @@ resource "google_compute_firewall" "firewall-cf" {
     ports    = ["80", "443"]
   }
 
-  source_ranges = ["0.0.0.0/0"]
+  source_ranges = ["130.211.0.0/22", "35.191.0.0/16"]
 
   target_tags = ["${google_compute_backend_service.router-lb-backend-service.name}"]
 }
 
 resource "google_compute_global_address" "cf-address" {
   name = "${var.env_id}-cf"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_global_forwarding_rule" "cf-http-forwarding-rule" {
@@ -555,6 +649,8 @@ resource "google_compute_global_forwarding_rule" "cf-http-forwarding-rule" {
   ip_address = "${google_compute_global_address.cf-address.address}"
   target     = "${google_compute_target_http_proxy.cf-http-lb-proxy.self_link}"
   port_range = "80"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_global_forwarding_rule" "cf-https-forwarding-rule" {
@@ -562,6 +658,8 @@ resource "google_compute_global_forwarding_rule" "cf-https-forwarding-rule" {
   ip_address = "${google_compute_global_address.cf-address.address}"
   target     = "${google_compute_target_https_proxy.cf-https-lb-proxy.self_link}"
   port_range = "443"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_target_http_proxy" "cf-http-lb-proxy" {
@@ -578,7 +676,7 @@ resource "google_compute_target_https_proxy" "cf-https-lb-proxy" {
 }
 
 resource "google_compute_ssl_certificate" "cf-cert" {
-  name_prefix = "${var.env_id}"
+  name        = "${var.ssl_certificate_name}"
   description = "user provided ssl private key / ssl certificate pair"
   private_key = "${file(var.ssl_certificate_private_key)}"
   certificate = "${file(var.ssl_certificate)}"
@@ -619,6 +717,8 @@ output "ssh_proxy_target_pool" {
 
 resource "google_compute_address" "cf-ssh-proxy" {
   name = "${var.env_id}-cf-ssh-proxy"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_firewall" "cf-ssh-proxy" {
@@ -631,6 +731,8 @@ resource "google_compute_firewall" "cf-ssh-proxy" {
     ports    = ["2222"]
   }
 
+  source_ranges = ["${var.lb_inbound_cidrs}"]
+
   target_tags = ["${google_compute_target_pool.cf-ssh-proxy.name}"]
 }
 
@@ -644,6 +746,8 @@ resource "google_compute_forwarding_rule" "cf-ssh-proxy" {
   port_range  = "2222"
   ip_protocol = "TCP"
   ip_address  = "${google_compute_address.cf-ssh-proxy.address}"
+
+  labels = "${var.labels}"
 }
 
 output "tcp_router_target_pool" {
@@ -660,11 +764,15 @@ resource "google_compute_firewall" "cf-tcp-router" {
     ports    = ["1024-32768"]
   }
 
+  source_ranges = ["${var.lb_inbound_cidrs}"]
+
   target_tags = ["${google_compute_target_pool.cf-tcp-router.name}"]
 }
 
 resource "google_compute_address" "cf-tcp-router" {
   name = "${var.env_id}-cf-tcp-router"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_http_health_check" "cf-tcp-router" {
@@ -687,6 +795,8 @@ resource "google_compute_forwarding_rule" "cf-tcp-router" {
   port_range  = "1024-32768"
   ip_protocol = "TCP"
   ip_address  = "${google_compute_address.cf-tcp-router.address}"
+
+  labels = "${var.labels}"
 }
 
 output "ws_target_pool" {
@@ -695,6 +805,8 @@ output "ws_target_pool" {
 
 resource "google_compute_address" "cf-ws" {
   name = "${var.env_id}-cf-ws"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_target_pool" "cf-ws" {
@@ -709,6 +821,8 @@ resource "google_compute_forwarding_rule" "cf-ws-https" {
   port_range  = "443"
   ip_protocol = "TCP"
   ip_address  = "${google_compute_address.cf-ws.address}"
+
+  labels = "${var.labels}"
 }
 
 resource "google_compute_forwarding_rule" "cf-ws-http" {
@@ -717,6 +831,8 @@ resource "google_compute_forwarding_rule" "cf-ws-http" {
   port_range  = "80"
   ip_protocol = "TCP"
   ip_address  = "${google_compute_address.cf-ws.address}"
+
+  labels = "${var.labels}"
 }
 `
 
@@ -728,6 +844,8 @@ resource "google_dns_managed_zone" "env_dns_zone" {
   name        = "${var.env_id}-zone"
   dns_name    = "${var.system_domain}."
   description = "DNS zone for the ${var.env_id} environment"
+
+  labels = "${var.labels}"
 }
 
 output "system_domain_dns_servers" {
@@ -811,3 +929,31 @@ resource "google_dns_record_set" "wildcard-ws-dns" {
   rrdatas = ["${google_compute_address.cf-ws.address}"]
 }
 `
+
+const ConcourseDNSTemplate = `variable "concourse_domain" {
+  type = "string"
+}
+
+resource "google_dns_managed_zone" "concourse_dns_zone" {
+  name        = "${var.env_id}-concourse-zone"
+  dns_name    = "${var.concourse_domain}."
+  description = "DNS zone for the ${var.env_id} concourse"
+
+  labels = "${var.labels}"
+}
+
+output "concourse_domain_dns_servers" {
+  value = "${google_dns_managed_zone.concourse_dns_zone.name_servers}"
+}
+
+resource "google_dns_record_set" "concourse-dns" {
+  name       = "${google_dns_managed_zone.concourse_dns_zone.dns_name}"
+  depends_on = ["google_compute_address.concourse-address"]
+  type       = "A"
+  ttl        = 300
+
+  managed_zone = "${google_dns_managed_zone.concourse_dns_zone.name}"
+
+  rrdatas = ["${google_compute_address.concourse-address.address}"]
+}
+`
@@ -1,9 +1,14 @@
 package gcp
 
 import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
@@ -30,30 +35,117 @@ func (i InputGenerator) Generate(state storage.State) (map[string]string, error)
 		return map[string]string{}, err
 	}
 
+	cfLB, _ := state.GetLB("cf")
+
 	input := map[string]string{
 		"env_id":        state.EnvID,
 		"project_id":    state.GCP.ProjectID,
 		"region":        state.GCP.Region,
 		"zone":          state.GCP.Zone,
 		"credentials":   credentialsPath,
-		"system_domain": state.LB.Domain,
+		"system_domain": cfLB.Domain,
+	}
+
+	if concourseLB, ok := state.GetLB("concourse"); ok && concourseLB.Domain != "" {
+		input["concourse_domain"] = concourseLB.Domain
 	}
 
-	if state.LB.Cert != "" && state.LB.Key != "" {
+	if len(state.DirectorAllowedCIDRs) > 0 {
+		boshInboundCIDRs, err := json.Marshal(state.DirectorAllowedCIDRs)
+		if err != nil {
+			return map[string]string{}, err
+		}
+		input["bosh_inbound_cidrs"] = string(boshInboundCIDRs)
+	}
+
+	if len(state.LBAllowedCIDRs) > 0 {
+		lbInboundCIDRs, err := json.Marshal(state.LBAllowedCIDRs)
+		if err != nil {
+			return map[string]string{}, err
+		}
+		input["lb_inbound_cidrs"] = string(lbInboundCIDRs)
+	}
+
+	if len(state.Tags) > 0 {
+		input["labels"] = hclMap(state.Tags)
+	}
+
+	if state.GCP.DirectorServiceAccount {
+		input["director_service_account_id"] = directorServiceAccountID(state.EnvID)
+	}
+
+	if cfLB.Cert != "" && cfLB.Key != "" {
 		certPath := filepath.Join(dir, "cert")
-		err = writeFile(certPath, []byte(state.LB.Cert), os.ModePerm)
+		err = writeFile(certPath, []byte(cfLB.Cert), os.ModePerm)
 		if err != nil {
 			return map[string]string{}, err
 		}
 		input["ssl_certificate"] = certPath
 
 		keyPath := filepath.Join(dir, "key")
-		err = writeFile(keyPath, []byte(state.LB.Key), os.ModePerm)
+		err = writeFile(keyPath, []byte(cfLB.Key), os.ModePerm)
 		if err != nil {
 			return map[string]string{}, err
 		}
 		input["ssl_certificate_private_key"] = keyPath
+
+		input["ssl_certificate_name"] = cfLB.CertificateName
+		if input["ssl_certificate_name"] == "" {
+			input["ssl_certificate_name"] = fmt.Sprintf("%s-cf-cert", state.EnvID)
+		}
+	}
+
+	for _, lb := range state.LBs {
+		if !lb.IsCustom() || !lb.Spec.TLS {
+			continue
+		}
+
+		certPath := filepath.Join(dir, fmt.Sprintf("%s-cert", lb.Type))
+		err = writeFile(certPath, []byte(lb.Cert), os.ModePerm)
+		if err != nil {
+			return map[string]string{}, err
+		}
+		input[fmt.Sprintf("%s_ssl_certificate", lb.Type)] = certPath
+
+		keyPath := filepath.Join(dir, fmt.Sprintf("%s-key", lb.Type))
+		err = writeFile(keyPath, []byte(lb.Key), os.ModePerm)
+		if err != nil {
+			return map[string]string{}, err
+		}
+		input[fmt.Sprintf("%s_ssl_certificate_private_key", lb.Type)] = keyPath
+
+		input[fmt.Sprintf("%s_ssl_certificate_name", lb.Type)] = lb.CertificateName
 	}
 
 	return input, nil
 }
+
+// hclMap formats a map as an hcl literal so that it can be passed to
+// terraform with -var.
+func hclMap(values map[string]string) string {
+	keys := []string{}
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := []string{}
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%q=%q", key, values[key]))
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(pairs, ", "))
+}
+
+// directorServiceAccountID derives the account id of the director service
+// account from the env id. Account ids are limited to 30 characters, so long
+// env ids are shortened and kept unique with a hash of the full env id.
+func directorServiceAccountID(envID string) string {
+	accountID := fmt.Sprintf("%s-bosh", envID)
+	if len(accountID) <= 30 {
+		return accountID
+	}
+
+	sum := sha1.Sum([]byte(envID))
+	return fmt.Sprintf("%s-%x", strings.TrimRight(envID[:21], "-"), sum[:4])
+}
@@ -40,9 +40,11 @@ var _ = Describe("InputGenerator", func() {
 				Region:            "some-region",
 			},
 			TFState: "some-tf-state",
-			LB: storage.LB{
-				Type:   "cf",
-				Domain: "some-domain",
+			LBs: []storage.LB{
+				{
+					Type:   "cf",
+					Domain: "some-domain",
+				},
 			},
 		}
 
@@ -64,7 +66,7 @@ var _ = Describe("InputGenerator", func() {
 			"region":        state.GCP.Region,
 			"zone":          state.GCP.Zone,
 			"credentials":   filepath.Join(tempDir, "credentials.json"),
-			"system_domain": state.LB.Domain,
+			"system_domain": state.LBs[0].Domain,
 		}))
 
 		credentials, err := ioutil.ReadFile(inputs["credentials"])
@@ -73,8 +75,9 @@ var _ = Describe("InputGenerator", func() {
 	})
 
 	It("returns a map containing cert and key variables when cert/key are provided", func() {
-		state.LB.Cert = "some-cert"
-		state.LB.Key = "some-key"
+		state.LBs[0].Cert = "some-cert"
+		state.LBs[0].Key = "some-key"
+		state.LBs[0].CertificateName = "cf-cert-some-guid"
 
 		inputs, err := inputGenerator.Generate(state)
 		Expect(err).NotTo(HaveOccurred())
@@ -87,7 +90,8 @@ var _ = Describe("InputGenerator", func() {
 			"credentials":                 filepath.Join(tempDir, "credentials.json"),
 			"ssl_certificate":             filepath.Join(tempDir, "cert"),
 			"ssl_certificate_private_key": filepath.Join(tempDir, "key"),
-			"system_domain":               state.LB.Domain,
+			"ssl_certificate_name":        "cf-cert-some-guid",
+			"system_domain":               state.LBs[0].Domain,
 		}))
 
 		sslCertificate, err := ioutil.ReadFile(inputs["ssl_certificate"])
@@ -99,6 +103,98 @@ var _ = Describe("InputGenerator", func() {
 		Expect(string(sslCertificatePrivateKey)).To(Equal("some-key"))
 	})
 
+	It("names the certificate after the env id when the lb has no certificate name", func() {
+		state.LBs[0].Cert = "some-cert"
+		state.LBs[0].Key = "some-key"
+
+		inputs, err := inputGenerator.Generate(state)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(inputs).To(HaveKeyWithValue("ssl_certificate_name", "some-env-id-cf-cert"))
+	})
+
+	It("returns a map containing the cert and key variables of custom lbs with tls", func() {
+		state.LBs = append(state.LBs, storage.LB{
+			Type:            "vault",
+			Cert:            "some-vault-cert",
+			Key:             "some-vault-key",
+			CertificateName: "vault-cert-some-guid",
+			Spec:            &storage.CustomLBSpec{Name: "vault", TLS: true},
+		})
+
+		inputs, err := inputGenerator.Generate(state)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(inputs).To(HaveKeyWithValue("vault_ssl_certificate", filepath.Join(tempDir, "vault-cert")))
+		Expect(inputs).To(HaveKeyWithValue("vault_ssl_certificate_private_key", filepath.Join(tempDir, "vault-key")))
+		Expect(inputs).To(HaveKeyWithValue("vault_ssl_certificate_name", "vault-cert-some-guid"))
+
+		sslCertificate, err := ioutil.ReadFile(inputs["vault_ssl_certificate"])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(sslCertificate)).To(Equal("some-vault-cert"))
+
+		sslCertificatePrivateKey, err := ioutil.ReadFile(inputs["vault_ssl_certificate_private_key"])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(sslCertificatePrivateKey)).To(Equal("some-vault-key"))
+	})
+
+	It("returns a map containing the concourse domain when it is provided", func() {
+		state.LBs = append(state.LBs, storage.LB{
+			Type:   "concourse",
+			Domain: "ci.example.com",
+		})
+
+		inputs, err := inputGenerator.Generate(state)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(inputs).To(HaveKeyWithValue("concourse_domain", "ci.example.com"))
+	})
+
+	It("returns a map containing the allowed cidrs when they are provided", func() {
+		state.DirectorAllowedCIDRs = []string{"10.1.0.0/16", "192.168.0.1/32"}
+		state.LBAllowedCIDRs = []string{"10.2.0.0/16"}
+
+		inputs, err := inputGenerator.Generate(state)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(inputs).To(HaveKeyWithValue("bosh_inbound_cidrs", `["10.1.0.0/16","192.168.0.1/32"]`))
+		Expect(inputs).To(HaveKeyWithValue("lb_inbound_cidrs", `["10.2.0.0/16"]`))
+	})
+
+	It("returns a map containing the labels when tags are provided", func() {
+		state.Tags = map[string]string{
+			"owner":       "some-owner",
+			"cost-center": "cc-123",
+		}
+
+		inputs, err := inputGenerator.Generate(state)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(inputs).To(HaveKeyWithValue("labels", `{"cost-center"="cc-123", "owner"="some-owner"}`))
+	})
+
+	Context("when the environment has a director service account", func() {
+		BeforeEach(func() {
+			state.GCP.DirectorServiceAccount = true
+		})
+
+		It("names the service account after the env id", func() {
+			inputs, err := inputGenerator.Generate(state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(inputs).To(HaveKeyWithValue("director_service_account_id", "some-env-id-bosh"))
+		})
+
+		It("shortens the service account id of long env ids", func() {
+			state.EnvID = "bbl-env-lake-2017-09-10t10-10z"
+
+			inputs, err := inputGenerator.Generate(state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(inputs["director_service_account_id"]).To(MatchRegexp(`^bbl-env-lake-2017-09-[0-9a-f]{8}$`))
+		})
+	})
+
 	Context("failure cases", func() {
 		It("returns an error if temp dir cannot be created", func() {
 			gcp.SetTempDir(func(dir, prefix string) (string, error) {
@@ -121,8 +217,8 @@ var _ = Describe("InputGenerator", func() {
 
 		Context("when cert and key are provided", func() {
 			BeforeEach(func() {
-				state.LB.Cert = "some-cert"
-				state.LB.Key = "some-cert"
+				state.LBs[0].Cert = "some-cert"
+				state.LBs[0].Key = "some-cert"
 			})
 
 			It("returns an error if the cert cannot be written", func() {
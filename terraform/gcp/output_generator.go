@@ -1,6 +1,7 @@
 package gcp
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/cloudfoundry/bosh-bootloader/storage"
@@ -62,6 +63,14 @@ func (g OutputGenerator) Generate(bblState storage.State) (map[string]interface{
 	}
 	outputs["director_address"] = directorAddress
 
+	if bblState.GCP.DirectorServiceAccount {
+		directorServiceAccount, err := g.executor.Output(bblState.TFState, "director_service_account_email")
+		if err != nil {
+			return map[string]interface{}{}, err
+		}
+		outputs["director_service_account"] = directorServiceAccount
+	}
+
 	var (
 		routerBackendService      string
 		sshProxyTargetPool        string
@@ -74,7 +83,7 @@ func (g OutputGenerator) Generate(bblState storage.State) (map[string]interface{
 		systemDomainDNSServersRaw string
 	)
 
-	if bblState.LB.Type == "cf" {
+	if cfLB, ok := bblState.GetLB("cf"); ok {
 		routerBackendService, err = g.executor.Output(bblState.TFState, "router_backend_service")
 		if err != nil {
 			return map[string]interface{}{}, err
@@ -123,7 +132,7 @@ func (g OutputGenerator) Generate(bblState storage.State) (map[string]interface{
 		}
 		outputs["ws_lb_ip"] = webSocketLBIP
 
-		if bblState.LB.Domain != "" {
+		if cfLB.Domain != "" {
 			systemDomainDNSServersRaw, err = g.executor.Output(bblState.TFState, "system_domain_dns_servers")
 			if err != nil {
 				return map[string]interface{}{}, err
@@ -133,7 +142,7 @@ func (g OutputGenerator) Generate(bblState storage.State) (map[string]interface{
 		}
 	}
 
-	if bblState.LB.Type == "concourse" {
+	if concourseLB, ok := bblState.GetLB("concourse"); ok {
 		concourseTargetPool, err := g.executor.Output(bblState.TFState, "concourse_target_pool")
 		if err != nil {
 			return map[string]interface{}{}, err
@@ -145,6 +154,37 @@ func (g OutputGenerator) Generate(bblState storage.State) (map[string]interface{
 			return map[string]interface{}{}, err
 		}
 		outputs["concourse_lb_ip"] = concourseLBIP
+
+		if concourseLB.Domain != "" {
+			concourseDomainDNSServersRaw, err := g.executor.Output(bblState.TFState, "concourse_domain_dns_servers")
+			if err != nil {
+				return map[string]interface{}{}, err
+			}
+
+			outputs["concourse_domain_dns_servers"] = strings.Split(concourseDomainDNSServersRaw, ",\n")
+		}
+	}
+
+	for _, lb := range bblState.LBs {
+		if !lb.IsCustom() {
+			continue
+		}
+
+		// Custom lbs with tls are fronted by a backend service instead of a
+		// target pool.
+		backend := "target_pool"
+		if lb.Spec.TLS {
+			backend = "backend_service"
+		}
+
+		for _, name := range []string{backend, "lb_ip"} {
+			outputName := fmt.Sprintf("%s_%s", lb.Type, name)
+			value, err := g.executor.Output(bblState.TFState, outputName)
+			if err != nil {
+				return map[string]interface{}{}, err
+			}
+			outputs[outputName] = value
+		}
 	}
 
 	return outputs, nil
@@ -57,10 +57,6 @@ var _ = Describe("OutputGenerator", func() {
 					Region:            "some-region",
 				},
 				TFState: "some-tf-state",
-				LB: storage.LB{
-					Type:   "",
-					Domain: "",
-				},
 			})
 			Expect(err).NotTo(HaveOccurred())
 
@@ -125,9 +121,11 @@ var _ = Describe("OutputGenerator", func() {
 						Region:            "some-region",
 					},
 					TFState: "some-tf-state",
-					LB: storage.LB{
-						Type:   "cf",
-						Domain: "",
+					LBs: []storage.LB{
+						{
+							Type:   "cf",
+							Domain: "",
+						},
 					},
 				})
 				Expect(err).NotTo(HaveOccurred())
@@ -202,9 +200,11 @@ var _ = Describe("OutputGenerator", func() {
 						Region:            "some-region",
 					},
 					TFState: "some-tf-state",
-					LB: storage.LB{
-						Type:   "cf",
-						Domain: "some-domain",
+					LBs: []storage.LB{
+						{
+							Type:   "cf",
+							Domain: "some-domain",
+						},
 					},
 				})
 				Expect(err).NotTo(HaveOccurred())
@@ -250,12 +250,30 @@ var _ = Describe("OutputGenerator", func() {
 					return "some-concourse-target-pool", nil
 				case "concourse_lb_ip":
 					return "some-concourse-lb-ip", nil
+				case "concourse_domain_dns_servers":
+					return "name-server-1.,\nname-server-2.", nil
 				default:
 					return "", fmt.Errorf("unexpected output requested: %s", output)
 				}
 			}
 		})
 
+		It("returns the concourse domain dns servers when the domain is specified", func() {
+			outputs, err := outputGenerator.Generate(storage.State{
+				IAAS:    "gcp",
+				TFState: "some-tf-state",
+				LBs: []storage.LB{
+					{
+						Type:   "concourse",
+						Domain: "ci.example.com",
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(outputs).To(HaveKeyWithValue("concourse_domain_dns_servers", []string{"name-server-1.", "name-server-2."}))
+		})
+
 		It("returns terraform outputs related to concourse lb", func() {
 			outputs, err := outputGenerator.Generate(storage.State{
 				IAAS:  "gcp",
@@ -267,9 +285,11 @@ var _ = Describe("OutputGenerator", func() {
 					Region:            "some-region",
 				},
 				TFState: "some-tf-state",
-				LB: storage.LB{
-					Type:   "concourse",
-					Domain: "",
+				LBs: []storage.LB{
+					{
+						Type:   "concourse",
+						Domain: "",
+					},
 				},
 			})
 			Expect(err).NotTo(HaveOccurred())
@@ -287,6 +307,99 @@ var _ = Describe("OutputGenerator", func() {
 		})
 	})
 
+	Context("when cf and concourse lbs exist", func() {
+		BeforeEach(func() {
+			executor.OutputCall.Stub = func(output string) (string, error) {
+				return fmt.Sprintf("some-%s", output), nil
+			}
+		})
+
+		It("returns terraform outputs related to both lbs", func() {
+			outputs, err := outputGenerator.Generate(storage.State{
+				IAAS:    "gcp",
+				TFState: "some-tf-state",
+				LBs: []storage.LB{
+					{Type: "cf"},
+					{Type: "concourse"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(outputs).To(HaveKeyWithValue("router_backend_service", "some-router_backend_service"))
+			Expect(outputs).To(HaveKeyWithValue("ws_lb_ip", "some-ws_lb_ip"))
+			Expect(outputs).To(HaveKeyWithValue("concourse_target_pool", "some-concourse_target_pool"))
+			Expect(outputs).To(HaveKeyWithValue("concourse_lb_ip", "some-concourse_lb_ip"))
+		})
+	})
+
+	Context("when a custom lb exists", func() {
+		BeforeEach(func() {
+			executor.OutputCall.Stub = func(output string) (string, error) {
+				return fmt.Sprintf("some-%s", output), nil
+			}
+		})
+
+		It("returns terraform outputs named after the custom lb", func() {
+			outputs, err := outputGenerator.Generate(storage.State{
+				IAAS:    "gcp",
+				TFState: "some-tf-state",
+				LBs: []storage.LB{
+					{
+						Type: "vault",
+						Spec: &storage.CustomLBSpec{Name: "vault"},
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(outputs).To(HaveKeyWithValue("vault_target_pool", "some-vault_target_pool"))
+			Expect(outputs).To(HaveKeyWithValue("vault_lb_ip", "some-vault_lb_ip"))
+		})
+
+		It("returns the backend service instead of a target pool when the custom lb uses tls", func() {
+			outputNames := []string{}
+			executor.OutputCall.Stub = func(output string) (string, error) {
+				outputNames = append(outputNames, output)
+				return fmt.Sprintf("some-%s", output), nil
+			}
+
+			outputs, err := outputGenerator.Generate(storage.State{
+				IAAS:    "gcp",
+				TFState: "some-tf-state",
+				LBs: []storage.LB{
+					{
+						Type: "vault",
+						Spec: &storage.CustomLBSpec{Name: "vault", TLS: true},
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(outputs).To(HaveKeyWithValue("vault_backend_service", "some-vault_backend_service"))
+			Expect(outputs).To(HaveKeyWithValue("vault_lb_ip", "some-vault_lb_ip"))
+			Expect(outputNames).NotTo(ContainElement("vault_target_pool"))
+		})
+	})
+
+	Context("when the environment has a director service account", func() {
+		BeforeEach(func() {
+			executor.OutputCall.Stub = func(output string) (string, error) {
+				return fmt.Sprintf("some-%s", output), nil
+			}
+		})
+
+		It("returns the email of the service account", func() {
+			outputs, err := outputGenerator.Generate(storage.State{
+				IAAS:    "gcp",
+				TFState: "some-tf-state",
+				GCP:     storage.GCP{DirectorServiceAccount: true},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(outputs).To(HaveKeyWithValue("director_service_account", "some-director_service_account_email"))
+		})
+	})
+
 	Context("when tfState is empty", func() {
 		BeforeEach(func() {
 			executor.OutputCall.Stub = func(output string) (string, error) {
@@ -305,10 +418,6 @@ var _ = Describe("OutputGenerator", func() {
 					Region:            "some-region",
 				},
 				TFState: "",
-				LB: storage.LB{
-					Type:   "",
-					Domain: "",
-				},
 			})
 			Expect(err).NotTo(HaveOccurred())
 
@@ -338,9 +447,11 @@ var _ = Describe("OutputGenerator", func() {
 						Region:            "some-region",
 					},
 					TFState: "some-tf-state",
-					LB: storage.LB{
-						Type:   lbType,
-						Domain: "some-domain",
+					LBs: []storage.LB{
+						{
+							Type:   lbType,
+							Domain: "some-domain",
+						},
 					},
 				})
 				Expect(err).To(MatchError(expectedError))
@@ -7,13 +7,7 @@ import (
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
-type TemplateGenerator struct {
-	zones zones
-}
-
-type zones interface {
-	Get(region string) []string
-}
+type TemplateGenerator struct{}
 
 const backendBase = `resource "google_compute_backend_service" "router-lb-backend-service" {
   name        = "${var.env_id}-router-lb"
@@ -26,32 +20,43 @@ const backendBase = `resource "google_compute_backend_service" "router-lb-backen
 }
 `
 
-func NewTemplateGenerator(zones zones) TemplateGenerator {
-	return TemplateGenerator{
-		zones: zones,
-	}
+func NewTemplateGenerator() TemplateGenerator {
+	return TemplateGenerator{}
 }
 
 func (t TemplateGenerator) Generate(state storage.State) string {
 	template := strings.Join([]string{VarsTemplate, BOSHDirectorTemplate}, "\n")
-	switch state.LB.Type {
-	case "concourse":
-		template = strings.Join([]string{template, ConcourseLBTemplate}, "\n")
-	case "cf":
-		instanceGroups := t.GenerateInstanceGroups(state.GCP.Region)
-		backendService := t.GenerateBackendService(state.GCP.Region)
+	if state.GCP.DirectorServiceAccount {
+		template = strings.Join([]string{template, DirectorServiceAccountTemplate}, "\n")
+	}
+
+	for _, lb := range state.LBs {
+		switch lb.Type {
+		case "concourse":
+			template = strings.Join([]string{template, ConcourseLBTemplate}, "\n")
+
+			if lb.Domain != "" {
+				template = strings.Join([]string{template, ConcourseDNSTemplate}, "\n")
+			}
+		case "cf":
+			instanceGroups := t.GenerateInstanceGroups(state.GCP.Zones)
+			backendService := t.GenerateBackendService(state.GCP.Zones)
 
-		template = strings.Join([]string{template, CFLBTemplate, instanceGroups, backendService}, "\n")
+			template = strings.Join([]string{template, CFLBTemplate, instanceGroups, backendService}, "\n")
 
-		if state.LB.Domain != "" {
-			template = strings.Join([]string{template, CFDNSTemplate}, "\n")
+			if lb.Domain != "" {
+				template = strings.Join([]string{template, CFDNSTemplate}, "\n")
+			}
+		default:
+			if lb.IsCustom() {
+				template = strings.Join([]string{template, t.GenerateCustomLB(*lb.Spec, state.GCP.Zones)}, "\n")
+			}
 		}
 	}
 	return template
 }
 
-func (t TemplateGenerator) GenerateBackendService(region string) string {
-	zones := t.zones.Get(region)
+func (t TemplateGenerator) GenerateBackendService(zones []string) string {
 	var backends string
 	for i := 0; i < len(zones); i++ {
 		backends = fmt.Sprintf(`%s
@@ -64,8 +69,7 @@ func (t TemplateGenerator) GenerateBackendService(region string) string {
 	return fmt.Sprintf(backendBase, backends)
 }
 
-func (t TemplateGenerator) GenerateInstanceGroups(region string) string {
-	zones := t.zones.Get(region)
+func (t TemplateGenerator) GenerateInstanceGroups(zones []string) string {
 	var groups []string
 	for i, zone := range zones {
 		groups = append(groups, fmt.Sprintf(`resource "google_compute_instance_group" "router-lb-%[1]d" {
@@ -3,7 +3,6 @@ package gcp_test
 import (
 	"io/ioutil"
 
-	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 	"github.com/cloudfoundry/bosh-bootloader/terraform/gcp"
 
@@ -15,17 +14,13 @@ import (
 var _ = Describe("TemplateGenerator", func() {
 
 	var (
-		zones             *fakes.Zones
 		templateGenerator gcp.TemplateGenerator
 
 		expectedTemplate []byte
 	)
 
 	BeforeEach(func() {
-		zones = &fakes.Zones{}
-		templateGenerator = gcp.NewTemplateGenerator(zones)
-
-		zones.GetCall.Returns.Zones = []string{"z1", "z2", "z3"}
+		templateGenerator = gcp.NewTemplateGenerator()
 	})
 
 	Describe("Generate", func() {
@@ -33,22 +28,101 @@ var _ = Describe("TemplateGenerator", func() {
 			expectedTemplate, err := ioutil.ReadFile(fixtureFilename)
 			Expect(err).NotTo(HaveOccurred())
 
-			template := templateGenerator.Generate(storage.State{
+			state := storage.State{
 				GCP: storage.GCP{
 					Region: region,
+					Zones:  []string{"z1", "z2", "z3"},
 				},
-				LB: storage.LB{
-					Type:   lbType,
-					Domain: domain,
-				},
-			})
+			}
+			if lbType != "" {
+				state.LBs = []storage.LB{{Type: lbType, Domain: domain}}
+			}
+
+			template := templateGenerator.Generate(state)
 			Expect(template).To(Equal(string(expectedTemplate)))
 		},
 			Entry("when no lb type is provided", "fixtures/gcp_template_no_lb.tf", "some-region", "", ""),
 			Entry("when a concourse lb type is provided", "fixtures/gcp_template_concourse_lb.tf", "some-region", "concourse", ""),
 			Entry("when a cf lb type is provided", "fixtures/gcp_template_cf_lb.tf", "some-region", "cf", ""),
 			Entry("when a cf lb type is provided with a domain", "fixtures/gcp_template_cf_lb_dns.tf", "some-region", "cf", "some-domain"),
+			Entry("when a concourse lb type is provided with a domain", "fixtures/gcp_template_concourse_lb_dns.tf", "some-region", "concourse", "ci.example.com"),
 		)
+
+		It("adds the director service account when the environment has one", func() {
+			expectedTemplate, err := ioutil.ReadFile("fixtures/gcp_template_no_lb_director_service_account.tf")
+			Expect(err).NotTo(HaveOccurred())
+
+			template := templateGenerator.Generate(storage.State{
+				GCP: storage.GCP{
+					Region:                 "some-region",
+					Zones:                  []string{"z1", "z2", "z3"},
+					DirectorServiceAccount: true,
+				},
+			})
+			Expect(template).To(Equal(string(expectedTemplate)))
+		})
+
+		It("composes the templates of every attached lb", func() {
+			expectedTemplate, err := ioutil.ReadFile("fixtures/gcp_template_concourse_and_cf_lb.tf")
+			Expect(err).NotTo(HaveOccurred())
+
+			template := templateGenerator.Generate(storage.State{
+				GCP: storage.GCP{
+					Region: "some-region",
+					Zones:  []string{"z1", "z2", "z3"},
+				},
+				LBs: []storage.LB{
+					{Type: "concourse"},
+					{Type: "cf", Domain: "some-domain"},
+				},
+			})
+			Expect(template).To(Equal(string(expectedTemplate)))
+		})
+	})
+
+	Describe("GenerateCustomLB", func() {
+		BeforeEach(func() {
+			var err error
+			expectedTemplate, err = ioutil.ReadFile("fixtures/custom_lb.tf")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns a network load balancer terraform template for the spec", func() {
+			template := templateGenerator.GenerateCustomLB(storage.CustomLBSpec{
+				Name: "vault",
+				Listeners: []storage.CustomLBListener{
+					{Protocol: "tcp", Port: 8200, TargetPort: 8200},
+					{Protocol: "http", Port: 80, TargetPort: 80},
+				},
+				HealthCheck: storage.CustomLBHealthCheck{
+					Protocol: "http",
+					Port:     8200,
+					Path:     "/v1/sys/health",
+				},
+			}, []string{"z1", "z2"})
+
+			Expect(template).To(Equal(string(expectedTemplate)))
+		})
+
+		It("returns an https load balancer terraform template for a spec with tls", func() {
+			expectedTemplate, err := ioutil.ReadFile("fixtures/custom_tls_lb.tf")
+			Expect(err).NotTo(HaveOccurred())
+
+			template := templateGenerator.GenerateCustomLB(storage.CustomLBSpec{
+				Name: "vault",
+				Listeners: []storage.CustomLBListener{
+					{Protocol: "http", Port: 443, TargetPort: 8200},
+				},
+				HealthCheck: storage.CustomLBHealthCheck{
+					Protocol: "http",
+					Port:     8200,
+					Path:     "/v1/sys/health",
+				},
+				TLS: true,
+			}, []string{"z1", "z2"})
+
+			Expect(template).To(Equal(string(expectedTemplate)))
+		})
 	})
 
 	Describe("GenerateBackendService", func() {
@@ -59,9 +133,8 @@ var _ = Describe("TemplateGenerator", func() {
 		})
 
 		It("returns a backend service terraform template", func() {
-			template := templateGenerator.GenerateBackendService("some-region")
+			template := templateGenerator.GenerateBackendService([]string{"z1", "z2", "z3"})
 
-			Expect(zones.GetCall.Receives.Region).To(Equal("some-region"))
 			Expect(template).To(Equal(string(expectedTemplate)))
 		})
 	})
@@ -74,9 +147,8 @@ var _ = Describe("TemplateGenerator", func() {
 		})
 
 		It("returns a backend service terraform template", func() {
-			template := templateGenerator.GenerateInstanceGroups("some-region")
+			template := templateGenerator.GenerateInstanceGroups([]string{"z1", "z2", "z3"})
 
-			Expect(zones.GetCall.Receives.Region).To(Equal("some-region"))
 			Expect(template).To(Equal(string(expectedTemplate)))
 		})
 	})
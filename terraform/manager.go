@@ -2,7 +2,10 @@ package terraform
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"sort"
 
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 	"github.com/coreos/go-semver/semver"
@@ -21,6 +24,10 @@ type executor interface {
 	Version() (string, error)
 	Destroy(inputs map[string]string, terraformTemplate, tfState string) (string, error)
 	Apply(inputs map[string]string, terraformTemplate, tfState string) (string, error)
+	Import(inputs map[string]string, terraformTemplate, tfState string, resources map[string]string) (string, error)
+	ApplyTargets(inputs map[string]string, terraformTemplate, tfState string, targets []string) (string, error)
+	Plan(inputs map[string]string, terraformTemplate, tfState string, targets []string) (bool, error)
+	MoveState(tfState, from, to string) (string, error)
 }
 
 type templateGenerator interface {
@@ -75,29 +82,24 @@ func (m Manager) ValidateVersion() error {
 	}
 
 	// This shouldn't fail, so there is no test for capturing the error.
-	minimumVersion, err := semver.NewVersion("0.8.5")
+	minimumVersion, err := semver.NewVersion("0.10.0")
 	if err != nil {
 		return err
 	}
 
 	if currentVersion.LessThan(*minimumVersion) {
-		return errors.New("Terraform version must be at least v0.8.5")
-	}
-
-	// This shouldn't fail, so there is no test for capturing the error.
-	blacklistedVersion, err := semver.NewVersion("0.9.0")
-	if err != nil {
-		return err
-	}
-
-	if currentVersion.Equal(*blacklistedVersion) {
-		return errors.New("Version 0.9.0 of terraform is incompatible with bbl, please try a later version.")
+		return errors.New("Terraform version must be at least v0.10.0")
 	}
 
 	return nil
 }
 
 func (m Manager) Apply(bblState storage.State) (storage.State, error) {
+	bblState, err := m.migrateTFState(bblState)
+	if err != nil {
+		return storage.State{}, err
+	}
+
 	m.logger.Step("generating terraform template")
 	template := m.templateGenerator.Generate(bblState)
 
@@ -157,6 +159,143 @@ func (m Manager) Destroy(bblState storage.State) (storage.State, error) {
 	return bblState, nil
 }
 
+// Import adopts existing infrastructure into a new tf state. The resources
+// map terraform resource addresses to the ids of the resources to import, the
+// created resources are the addresses that cannot be imported. They are only
+// created once the template describes the imported resources exactly, and the
+// import fails unless the template then describes all of the infrastructure.
+// Failures after the created resources exist return the tf state holding
+// them, and an import given that tf state only finishes the remaining steps.
+func (m Manager) Import(bblState storage.State, resources map[string]string, createdResources []string) (storage.State, error) {
+	m.logger.Step("generating terraform template")
+	template := m.templateGenerator.Generate(bblState)
+
+	input, err := m.inputGenerator.Generate(bblState)
+	if err != nil {
+		return storage.State{}, err
+	}
+
+	// Nothing has been created before the import completes, so a failed
+	// import leaves no tf state to keep.
+	tfState := bblState.TFState
+	if tfState == "" {
+		m.logger.Step("importing infrastructure into terraform")
+		tfState, err = m.executor.Import(input, template, "", resources)
+
+		bblState.LatestTFOutput = readAndReset(m.terraformOutputBuffer)
+
+		if err != nil {
+			return storage.State{}, err
+		}
+	}
+
+	if len(createdResources) > 0 {
+		var importedResources []string
+		for address := range resources {
+			importedResources = append(importedResources, address)
+		}
+		sort.Strings(importedResources)
+
+		hasChanges, err := m.executor.Plan(input, template, tfState, importedResources)
+		if err != nil {
+			return storage.State{}, err
+		}
+
+		if hasChanges {
+			return storage.State{}, errors.New("imported infrastructure does not match the terraform template")
+		}
+
+		m.logger.Step("creating infrastructure that cannot be imported")
+		tfState, err = m.executor.ApplyTargets(input, template, tfState, createdResources)
+
+		bblState.LatestTFOutput = readAndReset(m.terraformOutputBuffer)
+
+		switch err.(type) {
+		case executorError:
+			return storage.State{}, NewManagerError(bblState, err.(executorError))
+		case error:
+			return storage.State{}, err
+		}
+	}
+
+	hasChanges, err := m.executor.Plan(input, template, tfState, nil)
+	if err == nil && hasChanges {
+		err = errors.New("imported infrastructure does not match the terraform template")
+	}
+
+	if err != nil {
+		if len(createdResources) > 0 {
+			return storage.State{}, NewManagerError(bblState, tfStateError{error: err, tfState: tfState})
+		}
+		return storage.State{}, err
+	}
+	m.logger.Step("imported infrastructure into terraform")
+
+	bblState.TFState = tfState
+	return bblState, nil
+}
+
+// tfStateError carries the tf state of infrastructure that was created
+// before the error.
+type tfStateError struct {
+	error
+	tfState string
+}
+
+func (e tfStateError) TFState() (string, error) {
+	return e.tfState, nil
+}
+
+// legacyLBCertAddress is the address of the aws lb certificate in the tf
+// state of environments created when only one lb could be attached.
+const legacyLBCertAddress = "aws_iam_server_certificate.lb_cert"
+
+// migrateTFState moves the certificate of the single aws lb of older
+// environments to the address named after its lb type. Without the move,
+// terraform would destroy the certificate the live lb is still using.
+func (m Manager) migrateTFState(bblState storage.State) (storage.State, error) {
+	if bblState.IAAS != "aws" || !hasResource(bblState.TFState, legacyLBCertAddress) {
+		return bblState, nil
+	}
+
+	for _, lb := range bblState.LBs {
+		if lb.Type != "cf" && lb.Type != "concourse" {
+			continue
+		}
+
+		m.logger.Step("migrating terraform state")
+		tfState, err := m.executor.MoveState(bblState.TFState, legacyLBCertAddress, fmt.Sprintf("aws_iam_server_certificate.%s_lb_cert", lb.Type))
+		if err != nil {
+			return storage.State{}, err
+		}
+
+		bblState.TFState = tfState
+		return bblState, nil
+	}
+
+	return bblState, nil
+}
+
+func hasResource(tfState, address string) bool {
+	var state struct {
+		Modules []struct {
+			Resources map[string]json.RawMessage `json:"resources"`
+		} `json:"modules"`
+	}
+
+	if err := json.Unmarshal([]byte(tfState), &state); err != nil {
+		return false
+	}
+
+	for _, module := range state.Modules {
+		if _, ok := module.Resources[address]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (m Manager) GetOutputs(bblState storage.State) (map[string]interface{}, error) {
 	outputs, err := m.outputGenerator.Generate(bblState)
 	if err != nil {
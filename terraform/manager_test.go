@@ -66,9 +66,11 @@ var _ = Describe("Manager", func() {
 					Region:            "some-region",
 				},
 				TFState: "some-tf-state",
-				LB: storage.LB{
-					Type:   "cf",
-					Domain: "some-domain",
+				LBs: []storage.LB{
+					{
+						Type:   "cf",
+						Domain: "some-domain",
+					},
 				},
 			}
 
@@ -85,7 +87,7 @@ var _ = Describe("Manager", func() {
 				"region":        incomingState.GCP.Region,
 				"zone":          incomingState.GCP.Zone,
 				"credentials":   "some-path",
-				"system_domain": incomingState.LB.Domain,
+				"system_domain": incomingState.LBs[0].Domain,
 			}
 		})
 
@@ -114,13 +116,62 @@ var _ = Describe("Manager", func() {
 				"region":        incomingState.GCP.Region,
 				"zone":          incomingState.GCP.Zone,
 				"credentials":   "some-path",
-				"system_domain": incomingState.LB.Domain,
+				"system_domain": incomingState.LBs[0].Domain,
 			}))
 			Expect(executor.ApplyCall.Receives.TFState).To(Equal("some-tf-state"))
 			Expect(executor.ApplyCall.Receives.Template).To(Equal(string("some-gcp-terraform-template")))
 			Expect(state).To(Equal(expectedState))
 		})
 
+		Context("when the tf state has the certificate of a single aws lb", func() {
+			BeforeEach(func() {
+				tfState, err := ioutil.ReadFile("fixtures/aws_single_lb.tfstate")
+				Expect(err).NotTo(HaveOccurred())
+
+				incomingState.IAAS = "aws"
+				incomingState.TFState = string(tfState)
+				executor.MoveStateCall.Returns.TFState = "some-migrated-tf-state"
+			})
+
+			It("moves the certificate to the address of the lb type before applying", func() {
+				_, err := manager.Apply(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(executor.MoveStateCall.CallCount).To(Equal(1))
+				Expect(executor.MoveStateCall.Receives.TFState).To(Equal(incomingState.TFState))
+				Expect(executor.MoveStateCall.Receives.From).To(Equal("aws_iam_server_certificate.lb_cert"))
+				Expect(executor.MoveStateCall.Receives.To).To(Equal("aws_iam_server_certificate.cf_lb_cert"))
+				Expect(executor.ApplyCall.Receives.TFState).To(Equal("some-migrated-tf-state"))
+			})
+
+			It("does not move the certificate when no cf or concourse lb is attached", func() {
+				incomingState.LBs = nil
+
+				_, err := manager.Apply(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(executor.MoveStateCall.CallCount).To(Equal(0))
+				Expect(executor.ApplyCall.Receives.TFState).To(Equal(incomingState.TFState))
+			})
+
+			It("returns an error when the certificate cannot be moved", func() {
+				executor.MoveStateCall.Returns.Error = errors.New("failed to move state")
+
+				_, err := manager.Apply(incomingState)
+				Expect(err).To(MatchError("failed to move state"))
+				Expect(executor.ApplyCall.CallCount).To(Equal(0))
+			})
+		})
+
+		It("does not move anything when the tf state has no legacy certificate", func() {
+			incomingState.IAAS = "aws"
+
+			_, err := manager.Apply(incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.MoveStateCall.CallCount).To(Equal(0))
+		})
+
 		Context("failure cases", func() {
 			Context("when InputGenerator.Generate returns an error", func() {
 				BeforeEach(func() {
@@ -202,9 +253,11 @@ var _ = Describe("Manager", func() {
 						Zone:              "some-zone",
 						Region:            "some-region",
 					},
-					LB: storage.LB{
-						Type:   "cf",
-						Domain: "some-domain",
+					LBs: []storage.LB{
+						{
+							Type:   "cf",
+							Domain: "some-domain",
+						},
 					},
 					TFState: "some-tf-state",
 				}
@@ -221,7 +274,7 @@ var _ = Describe("Manager", func() {
 					"region":        incomingState.GCP.Region,
 					"zone":          incomingState.GCP.Zone,
 					"credentials":   "some-path",
-					"system_domain": incomingState.LB.Domain,
+					"system_domain": incomingState.LBs[0].Domain,
 				}
 			})
 
@@ -248,7 +301,7 @@ var _ = Describe("Manager", func() {
 					"region":        incomingState.GCP.Region,
 					"zone":          incomingState.GCP.Zone,
 					"credentials":   "some-path",
-					"system_domain": incomingState.LB.Domain,
+					"system_domain": incomingState.LBs[0].Domain,
 				}))
 				Expect(executor.DestroyCall.Receives.Template).To(Equal(templateGenerator.GenerateCall.Returns.Template))
 				Expect(executor.DestroyCall.Receives.TFState).To(Equal(incomingState.TFState))
@@ -343,6 +396,179 @@ var _ = Describe("Manager", func() {
 		})
 	})
 
+	Describe("Import", func() {
+		var (
+			incomingState    storage.State
+			resources        map[string]string
+			createdResources []string
+		)
+
+		BeforeEach(func() {
+			incomingState = storage.State{
+				IAAS:  "aws",
+				EnvID: "some-env-id",
+			}
+			resources = map[string]string{
+				"aws_vpc.vpc": "some-vpc-id",
+			}
+			createdResources = nil
+
+			templateGenerator.GenerateCall.Returns.Template = "some-aws-terraform-template"
+			inputGenerator.GenerateCall.Returns.Inputs = map[string]string{
+				"env_id": "some-env-id",
+			}
+			executor.ImportCall.Returns.TFState = expectedTFState
+		})
+
+		It("imports the resources and returns a state with the new tf state", func() {
+			terraformOutputBuffer.Write([]byte(expectedTFOutput))
+
+			state, err := manager.Import(incomingState, resources, createdResources)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.ImportCall.Receives.Inputs).To(Equal(map[string]string{"env_id": "some-env-id"}))
+			Expect(executor.ImportCall.Receives.Template).To(Equal("some-aws-terraform-template"))
+			Expect(executor.ImportCall.Receives.Resources).To(Equal(resources))
+
+			Expect(executor.PlanCall.CallCount).To(Equal(1))
+			Expect(executor.PlanCall.Receives.Template).To(Equal("some-aws-terraform-template"))
+			Expect(executor.PlanCall.Receives.TFState).To(Equal(expectedTFState))
+			Expect(executor.PlanCall.Receives.Targets).To(BeNil())
+
+			expectedState := incomingState
+			expectedState.TFState = expectedTFState
+			expectedState.LatestTFOutput = expectedTFOutput
+			Expect(state).To(Equal(expectedState))
+
+			Expect(logger.StepCall.Messages).To(ContainSequence([]string{
+				"importing infrastructure into terraform", "imported infrastructure into terraform",
+			}))
+		})
+
+		Context("when some resources cannot be imported", func() {
+			var plannedTargets [][]string
+
+			BeforeEach(func() {
+				createdResources = []string{"aws_iam_access_key.bosh"}
+				resources["aws_iam_user.bosh"] = "some-user-name"
+
+				plannedTargets = [][]string{}
+				executor.PlanCall.Stub = func(tfState string, targets []string) (bool, error) {
+					plannedTargets = append(plannedTargets, targets)
+					return false, nil
+				}
+				executor.ApplyTargetsCall.Returns.TFState = "some-applied-tf-state"
+			})
+
+			It("creates them once the imported resources match the template", func() {
+				state, err := manager.Import(incomingState, resources, createdResources)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(plannedTargets).To(Equal([][]string{
+					{"aws_iam_user.bosh", "aws_vpc.vpc"},
+					nil,
+				}))
+
+				Expect(executor.ApplyTargetsCall.Receives.TFState).To(Equal(expectedTFState))
+				Expect(executor.ApplyTargetsCall.Receives.Targets).To(Equal([]string{"aws_iam_access_key.bosh"}))
+				Expect(executor.PlanCall.Receives.TFState).To(Equal("some-applied-tf-state"))
+				Expect(state.TFState).To(Equal("some-applied-tf-state"))
+			})
+
+			It("creates nothing when the imported resources do not match the template", func() {
+				executor.PlanCall.Stub = func(tfState string, targets []string) (bool, error) {
+					return true, nil
+				}
+
+				_, err := manager.Import(incomingState, resources, createdResources)
+				Expect(err).To(MatchError("imported infrastructure does not match the terraform template"))
+				Expect(executor.ApplyTargetsCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when they cannot be created", func() {
+				executor.ApplyTargetsCall.Returns.Error = errors.New("failed to apply")
+
+				_, err := manager.Import(incomingState, resources, createdResources)
+				Expect(err).To(MatchError("failed to apply"))
+				Expect(executor.PlanCall.CallCount).To(Equal(1))
+			})
+
+			It("returns the tf state with the created resources when the final plan reports changes", func() {
+				executor.PlanCall.Stub = func(tfState string, targets []string) (bool, error) {
+					return targets == nil, nil
+				}
+
+				_, err := manager.Import(incomingState, resources, createdResources)
+				Expect(err).To(MatchError("imported infrastructure does not match the terraform template"))
+
+				managerError, ok := err.(terraform.ManagerError)
+				Expect(ok).To(BeTrue())
+
+				bblState, err := managerError.BBLState()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(bblState.TFState).To(Equal("some-applied-tf-state"))
+			})
+
+			It("returns the tf state with the created resources when the final plan fails", func() {
+				executor.PlanCall.Stub = func(tfState string, targets []string) (bool, error) {
+					if targets == nil {
+						return false, errors.New("failed to plan")
+					}
+					return false, nil
+				}
+
+				_, err := manager.Import(incomingState, resources, createdResources)
+				Expect(err).To(MatchError("failed to plan"))
+
+				bblState, err := err.(terraform.ManagerError).BBLState()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(bblState.TFState).To(Equal("some-applied-tf-state"))
+			})
+
+			It("finishes an import whose tf state was kept without importing again", func() {
+				incomingState.TFState = "some-kept-tf-state"
+
+				state, err := manager.Import(incomingState, resources, createdResources)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(executor.ImportCall.CallCount).To(Equal(0))
+				Expect(executor.ApplyTargetsCall.Receives.TFState).To(Equal("some-kept-tf-state"))
+				Expect(state.TFState).To(Equal("some-applied-tf-state"))
+			})
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the inputs cannot be generated", func() {
+				inputGenerator.GenerateCall.Returns.Error = errors.New("failed to generate inputs")
+
+				_, err := manager.Import(incomingState, resources, createdResources)
+				Expect(err).To(MatchError("failed to generate inputs"))
+			})
+
+			It("returns an error when the import fails", func() {
+				executor.ImportCall.Returns.Error = errors.New("failed to import")
+
+				_, err := manager.Import(incomingState, resources, createdResources)
+				Expect(err).To(MatchError("failed to import"))
+				Expect(executor.PlanCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the plan fails", func() {
+				executor.PlanCall.Returns.Error = errors.New("failed to plan")
+
+				_, err := manager.Import(incomingState, resources, createdResources)
+				Expect(err).To(MatchError("failed to plan"))
+			})
+
+			It("returns an error when the template would change the imported infrastructure", func() {
+				executor.PlanCall.Returns.HasChanges = true
+
+				_, err := manager.Import(incomingState, resources, createdResources)
+				Expect(err).To(MatchError("imported infrastructure does not match the terraform template"))
+			})
+		})
+	})
+
 	Describe("GetOutputs", func() {
 		BeforeEach(func() {
 			outputGenerator.GenerateCall.Returns.Outputs = map[string]interface{}{
@@ -364,9 +590,11 @@ var _ = Describe("Manager", func() {
 					Zone:              "some-zone",
 					Region:            "some-region",
 				},
-				LB: storage.LB{
-					Type:   "some-lb-type",
-					Domain: "some-domain",
+				LBs: []storage.LB{
+					{
+						Type:   "some-lb-type",
+						Domain: "some-domain",
+					},
 				},
 				TFState: "some-tf-state",
 			}
@@ -425,9 +653,9 @@ var _ = Describe("Manager", func() {
 	})
 
 	Describe("ValidateVersion", func() {
-		Context("when terraform version is greater than v0.8.5", func() {
+		Context("when terraform version is greater than v0.10.0", func() {
 			BeforeEach(func() {
-				executor.VersionCall.Returns.Version = "0.9.1"
+				executor.VersionCall.Returns.Version = "0.10.8"
 			})
 
 			It("validates the version of terraform and returns no error", func() {
@@ -436,23 +664,12 @@ var _ = Describe("Manager", func() {
 			})
 		})
 
-		Context("when terraform version is v0.9.0", func() {
-			BeforeEach(func() {
-				executor.VersionCall.Returns.Version = "0.9.0"
-			})
-
-			It("returns a helpful error message", func() {
-				err := manager.ValidateVersion()
-				Expect(err).To(MatchError("Version 0.9.0 of terraform is incompatible with bbl, please try a later version."))
-			})
-		})
-
 		Context("failure cases", func() {
-			It("returns an error when the terraform installed is less than v0.8.5", func() {
-				executor.VersionCall.Returns.Version = "0.8.4"
+			It("returns an error when the terraform installed is less than v0.10.0", func() {
+				executor.VersionCall.Returns.Version = "0.9.11"
 
 				err := manager.ValidateVersion()
-				Expect(err).To(MatchError("Terraform version must be at least v0.8.5"))
+				Expect(err).To(MatchError("Terraform version must be at least v0.10.0"))
 			})
 
 			It("fast fails if the terraform executor fails to get the version", func() {